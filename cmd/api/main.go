@@ -2,20 +2,32 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
 	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/idgen"
 	"github.com/DiegoSantos90/chargeback-api/internal/infra/logging"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/oidc"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/ratelimit"
 	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
 	"github.com/DiegoSantos90/chargeback-api/internal/server"
 	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
@@ -23,9 +35,87 @@ import (
 
 // Config holds the application configuration
 type Config struct {
-	Port     string
-	DynamoDB db.DynamoDBConfig
-	Logging  LoggingConfig
+	Port string
+	// StorageConfig selects the stores, the same way for the importer and the maintenance commands
+	bootstrap.StorageConfig
+	Logging   LoggingConfig
+	Health    HealthConfig
+	Auth      AuthConfig
+	CORS      server.CORSConfig
+	RateLimit RateLimitConfig
+	TLS       server.TLSConfig
+	// LegacyRoutes is the deprecation schedule of the unversioned aliases of the /v1 routes
+	LegacyRoutes server.Deprecation
+	// ShutdownDrainDelay is how long /readyz reports shutting_down before the server
+	// stops accepting connections
+	ShutdownDrainDelay time.Duration
+	// BatchMaxItems is the largest number of chargebacks accepted by POST /chargebacks/batch
+	BatchMaxItems int
+	// ExportPageSize is how many chargebacks GET /chargebacks/export reads at a time
+	ExportPageSize int
+	Inbox          InboxConfig
+}
+
+// InboxConfig holds the dispute file inbox configuration
+// The inbox is watched when Dir is set
+type InboxConfig struct {
+	Dir          string
+	PollInterval time.Duration
+	// SettleTime is how long a file must go unmodified before it is picked up
+	SettleTime time.Duration
+	// Ledger is the file ingested files are recorded in, <Dir>/.ingestions.jsonl unless set
+	Ledger string
+	// CSVMapping maps chargeback fields to the columns of CSV files, as field=column pairs
+	CSVMapping string
+	// Layout is the JSON layout of fixed-width .txt and .dat files, which are only
+	// ingested when it is set
+	Layout string
+}
+
+// RateLimitConfig holds the rate limiting configuration
+type RateLimitConfig struct {
+	Enabled bool
+	// Store selects where request counters are kept: "memory" (per instance) or "dynamodb" (shared)
+	Store  string
+	Table  string
+	Policy server.RateLimitConfig
+}
+
+// AuthConfig holds the API key authentication configuration
+type AuthConfig struct {
+	Enabled bool
+	// KeyStore selects where API keys are kept: "dynamodb", "sql" (the postgres or sqlite
+	// storage backend's database) or "memory"
+	KeyStore     string
+	APIKeysTable string
+	// AdminAPIKey is a bootstrap credential with the admin scope used to issue the first keys
+	AdminAPIKey string
+	JWT         JWTConfig
+	// HighValueThreshold is the amount above which only supervisors may approve chargebacks
+	HighValueThreshold float64
+	// ClientCertMerchants maps mutual TLS client certificate common names to merchant IDs
+	ClientCertMerchants map[string]string
+	// ClientCertScopes are granted to clients authenticated by certificate
+	ClientCertScopes []auth.Scope
+}
+
+// JWTConfig holds the bearer token validation configuration
+// Bearer authentication is enabled when JWKSSource is set
+type JWTConfig struct {
+	// JWKSSource is a file path or http(s) URL of the identity provider's JSON Web Key Set
+	JWKSSource      string
+	RefreshInterval time.Duration
+	Issuer          string
+	Audience        string
+	RoleClaim       string
+	// RoleMapping maps identity provider role or group names to service roles
+	RoleMapping map[string]auth.Role
+}
+
+// HealthConfig holds the readiness check configuration
+type HealthConfig struct {
+	Timeout  time.Duration
+	CacheTTL time.Duration
 }
 
 // LoggingConfig holds the logging configuration
@@ -38,11 +128,25 @@ type LoggingConfig struct {
 
 // Dependencies holds all initialized dependencies
 type Dependencies struct {
-	Logger             service.Logger
-	DynamoClient       *dynamodb.Client
-	ChargebackRepo     repository.ChargebackRepository
-	CreateChargebackUC *usecase.CreateChargebackUseCase
-	HTTPServer         *server.Server
+	Logger       service.Logger
+	DynamoClient *dynamodb.Client
+	// DynamoDB is the resilient client the DynamoDB stores share
+	DynamoDB *dynamoRepo.ResilientDynamoDB
+	// SQLDB is nil unless the postgres or sqlite storage backend is selected
+	SQLDB               *sql.DB
+	ChargebackRepo      repository.ChargebackRepository
+	APIKeyRepo          repository.APIKeyRepository
+	CreateChargebackUC  *usecase.CreateChargebackUseCase
+	GetChargebackUC     *usecase.GetChargebackUseCase
+	ApproveChargebackUC *usecase.ApproveChargebackUseCase
+	RejectChargebackUC  *usecase.RejectChargebackUseCase
+	HTTPServer          *server.Server
+	// Inbox is nil unless the inbox is configured
+	Inbox *importer.Inbox
+	// ChargebackCache is nil unless the chargeback cache is enabled
+	ChargebackCache *dynamoRepo.CachedChargebackRepository
+	// Redis is nil unless the chargeback cache uses the redis backend
+	Redis *cache.RedisCache
 }
 
 func main() {
@@ -62,7 +166,7 @@ func main() {
 		deps.Logger.Info(ctx, "Chargeback API starting", map[string]interface{}{
 			"port": config.Port,
 		})
-		if err := deps.HTTPServer.Start(); err != nil {
+		if err := deps.HTTPServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			deps.Logger.Error(ctx, "Failed to start server", map[string]interface{}{
 				"error": err.Error(),
 			})
@@ -70,30 +174,139 @@ func main() {
 		}
 	}()
 
+	inboxCtx, stopInbox := context.WithCancel(ctx)
+	inboxDone := make(chan struct{})
+	go func() {
+		defer close(inboxDone)
+		if deps.Inbox != nil {
+			deps.Logger.Info(ctx, "Watching dispute file inbox", map[string]interface{}{
+				"dir": config.Inbox.Dir,
+			})
+			deps.Inbox.Run(inboxCtx)
+		}
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
+	// An import in progress stops after its current row and resumes on the next start
+	stopInbox()
+	<-inboxDone
+
 	deps.Logger.Info(ctx, "Shutting down server", nil)
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	_ = shutdownCtx
+	if err := deps.HTTPServer.Shutdown(shutdownCtx); err != nil {
+		deps.Logger.Error(ctx, "Server shutdown failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	if deps.SQLDB != nil {
+		deps.SQLDB.Close()
+	}
+	if deps.ChargebackCache != nil {
+		stats := deps.ChargebackCache.Stats()
+		deps.Logger.Info(ctx, "Chargeback cache statistics", map[string]interface{}{
+			"hits":          stats.Hits,
+			"negative_hits": stats.NegativeHits,
+			"misses":        stats.Misses,
+			"errors":        stats.Errors,
+		})
+	}
+	if deps.DynamoDB != nil {
+		if stats := deps.DynamoDB.Stats(); stats.Calls > 0 {
+			deps.Logger.Info(ctx, "DynamoDB call statistics", map[string]interface{}{
+				"calls":         stats.Calls,
+				"retries":       stats.Retries,
+				"failures":      stats.Failures,
+				"rejected":      stats.Rejected,
+				"breaker_opens": stats.BreakerOpens,
+				"breaker_state": stats.BreakerState,
+			})
+		}
+	}
+	if deps.Redis != nil {
+		deps.Redis.Close()
+	}
 	deps.Logger.Info(ctx, "Server shutdown complete", nil)
 }
 
 func loadConfiguration() Config {
+	storage := bootstrap.LoadStorageConfig()
 	return Config{
-		Port: getEnvOrDefault("PORT", "8080"),
-		DynamoDB: db.DynamoDBConfig{
-			Endpoint:  getEnvOrDefault("DYNAMODB_ENDPOINT", ""),
-			Region:    getEnvOrDefault("AWS_REGION", "us-east-1"),
-			TableName: getEnvOrDefault("DYNAMODB_TABLE", "chargebacks"),
-		},
+		Port:          bootstrap.GetEnvOrDefault("PORT", "8080"),
+		StorageConfig: storage,
 		Logging: LoggingConfig{
-			Level:   parseLogLevel(getEnvOrDefault("LOG_LEVEL", "info")),
-			Format:  parseLogFormat(getEnvOrDefault("LOG_FORMAT", "json")),
+			Level:   parseLogLevel(bootstrap.GetEnvOrDefault("LOG_LEVEL", "info")),
+			Format:  parseLogFormat(bootstrap.GetEnvOrDefault("LOG_FORMAT", "json")),
 			Service: "chargeback-api",
-			Version: getEnvOrDefault("APP_VERSION", "dev"),
+			Version: bootstrap.GetEnvOrDefault("APP_VERSION", "dev"),
+		},
+		Health: HealthConfig{
+			Timeout:  bootstrap.GetDurationOrDefault("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			CacheTTL: bootstrap.GetDurationOrDefault("HEALTH_CHECK_CACHE_TTL", 5*time.Second),
+		},
+		Auth: AuthConfig{
+			Enabled:      bootstrap.GetBoolOrDefault("AUTH_ENABLED", true),
+			KeyStore:     strings.ToLower(bootstrap.GetEnvOrDefault("API_KEY_STORE", bootstrap.DefaultStore(storage.StorageBackend))),
+			APIKeysTable: bootstrap.GetEnvOrDefault("API_KEYS_TABLE", "api_keys"),
+			AdminAPIKey:  bootstrap.GetEnvOrDefault("ADMIN_API_KEY", ""),
+			JWT: JWTConfig{
+				JWKSSource:      bootstrap.GetEnvOrDefault("JWT_JWKS_SOURCE", ""),
+				RefreshInterval: bootstrap.GetDurationOrDefault("JWT_JWKS_REFRESH_INTERVAL", time.Hour),
+				Issuer:          bootstrap.GetEnvOrDefault("JWT_ISSUER", ""),
+				Audience:        bootstrap.GetEnvOrDefault("JWT_AUDIENCE", ""),
+				RoleClaim:       bootstrap.GetEnvOrDefault("JWT_ROLE_CLAIM", "roles"),
+				RoleMapping:     parseRoleMapping(bootstrap.GetEnvOrDefault("JWT_ROLE_MAPPING", "")),
+			},
+			HighValueThreshold:  getFloatOrDefault("APPROVAL_HIGH_VALUE_THRESHOLD", 10000),
+			ClientCertMerchants: parseMapping(bootstrap.GetEnvOrDefault("TLS_CLIENT_MERCHANTS", "")),
+			ClientCertScopes:    parseScopes(bootstrap.GetEnvOrDefault("TLS_CLIENT_SCOPES", "chargebacks:read,chargebacks:write")),
+		},
+		CORS: server.CORSConfig{
+			AllowedOrigins:   getListOrDefault("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods:   getListOrDefault("CORS_ALLOWED_METHODS", nil),
+			AllowedHeaders:   getListOrDefault("CORS_ALLOWED_HEADERS", nil),
+			ExposedHeaders:   getListOrDefault("CORS_EXPOSED_HEADERS", nil),
+			AllowCredentials: bootstrap.GetBoolOrDefault("CORS_ALLOW_CREDENTIALS", false),
+			MaxAge:           bootstrap.GetDurationOrDefault("CORS_MAX_AGE", 10*time.Minute),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: bootstrap.GetBoolOrDefault("RATE_LIMIT_ENABLED", true),
+			Store:   strings.ToLower(bootstrap.GetEnvOrDefault("RATE_LIMIT_STORE", "memory")),
+			Table:   bootstrap.GetEnvOrDefault("RATE_LIMIT_TABLE", "rate_limits"),
+			Policy: server.RateLimitConfig{
+				KeyBy:             server.RateLimitKey(strings.ToLower(bootstrap.GetEnvOrDefault("RATE_LIMIT_KEY_BY", "api_key"))),
+				Default:           getRateLimitOrDefault("RATE_LIMIT_DEFAULT", service.RateLimit{Requests: 600, Window: time.Minute}),
+				Routes:            parseRateLimitRoutes(bootstrap.GetEnvOrDefault("RATE_LIMIT_ROUTES", "POST /chargebacks=60/1m")),
+				PerIP:             getRateLimitOrDefault("RATE_LIMIT_PER_IP", service.RateLimit{Requests: 1200, Window: time.Minute}),
+				TrustForwardedFor: bootstrap.GetBoolOrDefault("RATE_LIMIT_TRUST_FORWARDED_FOR", false),
+			},
+		},
+		TLS: server.TLSConfig{
+			CertFile:       bootstrap.GetEnvOrDefault("TLS_CERT_FILE", ""),
+			KeyFile:        bootstrap.GetEnvOrDefault("TLS_KEY_FILE", ""),
+			MinVersion:     bootstrap.GetEnvOrDefault("TLS_MIN_VERSION", "1.2"),
+			CipherSuites:   getListOrDefault("TLS_CIPHER_SUITES", nil),
+			ClientCAFile:   bootstrap.GetEnvOrDefault("TLS_CLIENT_CA_FILE", ""),
+			ClientAuth:     strings.ToLower(bootstrap.GetEnvOrDefault("TLS_CLIENT_AUTH", server.ClientAuthRequire)),
+			ReloadInterval: bootstrap.GetDurationOrDefault("TLS_RELOAD_INTERVAL", 30*time.Second),
+		},
+		LegacyRoutes: server.Deprecation{
+			Since:  getTimeOrDefault("LEGACY_ROUTES_DEPRECATED_AT", time.Time{}),
+			Sunset: getTimeOrDefault("LEGACY_ROUTES_SUNSET", time.Time{}),
+		},
+		ShutdownDrainDelay: bootstrap.GetDurationOrDefault("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		BatchMaxItems:      bootstrap.GetIntOrDefault("BATCH_MAX_ITEMS", 500),
+		ExportPageSize:     bootstrap.GetIntOrDefault("EXPORT_PAGE_SIZE", 500),
+		Inbox: InboxConfig{
+			Dir:          bootstrap.GetEnvOrDefault("INBOX_DIR", ""),
+			PollInterval: bootstrap.GetDurationOrDefault("INBOX_POLL_INTERVAL", 30*time.Second),
+			SettleTime:   bootstrap.GetDurationOrDefault("INBOX_SETTLE_TIME", 10*time.Second),
+			Ledger:       bootstrap.GetEnvOrDefault("INBOX_LEDGER", ""),
+			CSVMapping:   bootstrap.GetEnvOrDefault("INBOX_CSV_MAP", ""),
+			Layout:       bootstrap.GetEnvOrDefault("INBOX_LAYOUT", ""),
 		},
 	}
 }
@@ -102,11 +315,67 @@ func validateConfiguration(config Config) error {
 	if config.Port == "" {
 		return fmt.Errorf("port is required")
 	}
-	if config.DynamoDB.Region == "" {
-		return fmt.Errorf("AWS region is required")
+	if err := config.StorageConfig.Validate(); err != nil {
+		return err
+	}
+	if config.Auth.Enabled {
+		if err := config.StorageConfig.ValidateStore("API key", config.Auth.KeyStore); err != nil {
+			return err
+		}
+		if config.Auth.KeyStore == "dynamodb" && config.Auth.APIKeysTable == "" {
+			return fmt.Errorf("API keys table name is required")
+		}
+		// Without them any token the identity provider signs, for any client, would be accepted
+		if jwt := config.Auth.JWT; jwt.JWKSSource != "" && (jwt.Issuer == "" || jwt.Audience == "") {
+			return fmt.Errorf("JWT issuer and audience are required when JWKS source is set")
+		}
+		for name, role := range config.Auth.JWT.RoleMapping {
+			if !role.IsValid() {
+				return fmt.Errorf("JWT role mapping for '%s' has unknown role '%s'", name, role)
+			}
+		}
+		for _, scope := range config.Auth.ClientCertScopes {
+			if !scope.IsValid() {
+				return fmt.Errorf("TLS client scope '%s' is not a valid scope", scope)
+			}
+		}
+	}
+	if err := config.TLS.Validate(); err != nil {
+		return err
+	}
+	if err := config.CORS.Validate(); err != nil {
+		return err
+	}
+	if config.RateLimit.Enabled {
+		if config.RateLimit.Store != "memory" && config.RateLimit.Store != "dynamodb" {
+			return fmt.Errorf("rate limit store must be 'memory' or 'dynamodb', got '%s'", config.RateLimit.Store)
+		}
+		if config.RateLimit.Store == "dynamodb" && config.RateLimit.Table == "" {
+			return fmt.Errorf("rate limit table name is required")
+		}
+		if err := config.RateLimit.Policy.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := config.LegacyRoutes.Validate(); err != nil {
+		return fmt.Errorf("legacy routes: %w", err)
+	}
+	if config.ShutdownDrainDelay < 0 {
+		return fmt.Errorf("shutdown drain delay must not be negative, got %v", config.ShutdownDrainDelay)
 	}
-	if config.DynamoDB.TableName == "" {
-		return fmt.Errorf("DynamoDB table name is required")
+	if config.BatchMaxItems <= 0 {
+		return fmt.Errorf("batch max items must be positive, got %d", config.BatchMaxItems)
+	}
+	if config.ExportPageSize <= 0 {
+		return fmt.Errorf("export page size must be positive, got %d", config.ExportPageSize)
+	}
+	if config.Inbox.Dir != "" {
+		if config.Inbox.PollInterval <= 0 {
+			return fmt.Errorf("inbox poll interval must be positive")
+		}
+		if _, err := importer.ParseMapping(config.Inbox.CSVMapping); err != nil {
+			return fmt.Errorf("inbox CSV mapping: %w", err)
+		}
 	}
 
 	// Validate AWS credentials availability (except for local DynamoDB)
@@ -146,42 +415,350 @@ func initializeDependencies(ctx context.Context, config Config) (*Dependencies,
 		return nil, fmt.Errorf("failed to log application startup: %w", err)
 	}
 
-	dynamoClient, err := db.NewDynamoDBClient(ctx, config.DynamoDB)
+	stores, err := bootstrap.OpenStores(ctx, config.StorageConfig, logger)
 	if err != nil {
-		logger.Error(ctx, "Failed to initialize DynamoDB client", map[string]interface{}{
-			"error": err.Error(),
+		return nil, err
+	}
+	chargebackRepo, dynamoClient, sqlDB := stores.Chargebacks, stores.DynamoClient, stores.SQLDB
+	// The stores call DynamoDB through the resilient client; the health checker describes
+	// the table with the raw one
+	resilientDynamoDB := stores.DynamoDB
+
+	var featureOptions []server.Option
+	if stores.MerchantStats != nil {
+		thresholds := config.MerchantStats.Thresholds
+		featureOptions = append(featureOptions, server.WithMerchantStatsUseCases(
+			usecase.NewRecordTransactionVolumeUseCase(stores.MerchantStats, thresholds, logger, stores.AlertSink),
+			usecase.NewGetMerchantStatsUseCase(stores.MerchantStats, thresholds),
+		))
+	}
+	if stores.ReportCounters != nil {
+		featureOptions = append(featureOptions, server.WithReportSummaryUseCase(usecase.NewGetReportSummaryUseCase(stores.ReportCounters)))
+	}
+
+	chargebackIDs := idgen.NewULIDGenerator()
+	createChargebackUC := usecase.NewCreateChargebackUseCase(chargebackRepo, chargebackIDs)
+	getChargebackUC := usecase.NewGetChargebackUseCase(chargebackRepo)
+	approveChargebackUC := usecase.NewApproveChargebackUseCase(chargebackRepo, config.Auth.HighValueThreshold)
+	rejectChargebackUC := usecase.NewRejectChargebackUseCase(chargebackRepo)
+	batchCreateChargebacksUC := usecase.NewBatchCreateChargebacksUseCase(chargebackRepo, chargebackIDs, config.BatchMaxItems)
+	exportChargebacksUC := usecase.NewExportChargebacksUseCase(chargebackRepo, config.ExportPageSize)
+
+	serverOptions := []server.Option{
+		server.WithGetChargebackUseCase(getChargebackUC),
+		server.WithBatchCreateUseCase(batchCreateChargebacksUC, config.BatchMaxItems),
+		server.WithExportUseCase(exportChargebacksUC),
+		server.WithReviewUseCases(approveChargebackUC, rejectChargebackUC),
+		server.WithImportJobs(importer.NewJobManager(importer.NewImporter(createChargebackUC))),
+	}
+	serverOptions = append(serverOptions, featureOptions...)
+
+	var apiKeyRepo repository.APIKeyRepository
+	if config.Auth.Enabled {
+		switch config.Auth.KeyStore {
+		case "memory":
+			apiKeyRepo = dynamoRepo.NewMemoryAPIKeyRepository()
+		case "sql":
+			apiKeyRepo = dynamoRepo.NewSQLAPIKeyRepository(sqlDB)
+		default:
+			apiKeyRepo = dynamoRepo.NewDynamoDBAPIKeyRepository(resilientDynamoDB, config.Auth.APIKeysTable)
+		}
+
+		if config.TLS.ClientCAFile != "" {
+			serverOptions = append(serverOptions, server.WithAuthenticator(
+				server.NewClientCertAuthenticator(config.Auth.ClientCertMerchants, config.Auth.ClientCertScopes),
+			))
+
+			logger.Info(ctx, "Client certificate authentication enabled", map[string]interface{}{
+				"client_auth":     config.TLS.ClientAuth,
+				"mapped_subjects": len(config.Auth.ClientCertMerchants),
+			})
+		}
+
+		serverOptions = append(serverOptions,
+			server.WithAuthenticator(server.NewAPIKeyAuthenticator(
+				usecase.NewAuthenticateAPIKeyUseCase(apiKeyRepo, config.Auth.AdminAPIKey),
+			)),
+			server.WithAPIKeyAdmin(
+				usecase.NewIssueAPIKeyUseCase(apiKeyRepo),
+				usecase.NewRotateAPIKeyUseCase(apiKeyRepo),
+				usecase.NewRevokeAPIKeyUseCase(apiKeyRepo),
+			),
+		)
+
+		logger.Info(ctx, "API key authentication enabled", map[string]interface{}{
+			"key_store":       config.Auth.KeyStore,
+			"bootstrap_admin": config.Auth.AdminAPIKey != "",
 		})
-		return nil, fmt.Errorf("failed to initialize DynamoDB client: %w", err)
+
+		if jwtConfig := config.Auth.JWT; jwtConfig.JWKSSource != "" {
+			verifier := oidc.NewVerifier(
+				oidc.NewKeySet(jwtConfig.JWKSSource, jwtConfig.RefreshInterval),
+				oidc.VerifierConfig{
+					Issuer:      jwtConfig.Issuer,
+					Audience:    jwtConfig.Audience,
+					RoleClaim:   jwtConfig.RoleClaim,
+					RoleMapping: jwtConfig.RoleMapping,
+					ClockSkew:   time.Minute,
+				},
+			)
+			serverOptions = append(serverOptions, server.WithAuthenticator(server.NewBearerTokenAuthenticator(verifier)))
+
+			logger.Info(ctx, "Bearer token authentication enabled", map[string]interface{}{
+				"jwks_source": jwtConfig.JWKSSource,
+				"issuer":      jwtConfig.Issuer,
+				"role_claim":  jwtConfig.RoleClaim,
+			})
+		}
+	} else {
+		logger.Warn(ctx, "Authentication is disabled; all routes are open", nil)
 	}
 
-	// Test DynamoDB connection
-	if err := testDynamoDBConnection(ctx, dynamoClient, config.DynamoDB.TableName, logger); err != nil {
-		logger.Error(ctx, "Failed to connect to DynamoDB", map[string]interface{}{
-			"error":      err.Error(),
-			"table_name": config.DynamoDB.TableName,
+	if config.RateLimit.Enabled {
+		var limiter service.RateLimiter
+		if config.RateLimit.Store == "dynamodb" {
+			limiter = ratelimit.NewDynamoDBLimiter(resilientDynamoDB, config.RateLimit.Table)
+		} else {
+			limiter = ratelimit.NewMemoryLimiter()
+		}
+		serverOptions = append(serverOptions, server.WithRateLimiter(limiter, config.RateLimit.Policy))
+
+		logger.Info(ctx, "Rate limiting enabled", map[string]interface{}{
+			"store":   config.RateLimit.Store,
+			"key_by":  string(config.RateLimit.Policy.KeyBy),
+			"default": config.RateLimit.Policy.Default.String(),
+			"routes":  len(config.RateLimit.Policy.Routes),
 		})
-		return nil, fmt.Errorf("failed to connect to DynamoDB: %w", err)
 	}
 
-	chargebackRepo := dynamoRepo.NewDynamoDBChargebackRepository(dynamoClient, config.DynamoDB.TableName)
-	createChargebackUC := usecase.NewCreateChargebackUseCase(chargebackRepo)
+	serverConfig := server.ServerConfig{
+		Port:               config.Port,
+		CORS:               config.CORS,
+		TLS:                config.TLS,
+		LegacyRoutes:       config.LegacyRoutes,
+		ShutdownDrainDelay: config.ShutdownDrainDelay,
+	}
+	httpServer := server.NewServer(serverConfig, createChargebackUC, logger, serverOptions...)
+	switch config.StorageBackend {
+	case "dynamodb":
+		httpServer.RegisterHealthChecker(db.NewDynamoDBHealthChecker(
+			dynamoClient, config.DynamoDB.TableName, config.Health.Timeout, config.Health.CacheTTL,
+		))
+	case "postgres":
+		httpServer.RegisterHealthChecker(db.NewSQLHealthChecker("postgres", sqlDB, config.Health.Timeout))
+	case "sqlite":
+		httpServer.RegisterHealthChecker(db.NewSQLHealthChecker("sqlite", sqlDB, config.Health.Timeout))
+	}
 
-	serverConfig := server.ServerConfig{Port: config.Port}
-	httpServer := server.NewServer(serverConfig, createChargebackUC, logger)
+	var inbox *importer.Inbox
+	if config.Inbox.Dir != "" {
+		inbox, err = newInbox(config.Inbox, createChargebackUC, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize inbox: %w", err)
+		}
+	}
 
 	return &Dependencies{
-		Logger:             logger,
-		DynamoClient:       dynamoClient,
-		ChargebackRepo:     chargebackRepo,
-		CreateChargebackUC: createChargebackUC,
-		HTTPServer:         httpServer,
+		Logger:              logger,
+		DynamoClient:        dynamoClient,
+		DynamoDB:            resilientDynamoDB,
+		SQLDB:               sqlDB,
+		ChargebackRepo:      chargebackRepo,
+		APIKeyRepo:          apiKeyRepo,
+		CreateChargebackUC:  createChargebackUC,
+		GetChargebackUC:     getChargebackUC,
+		ApproveChargebackUC: approveChargebackUC,
+		RejectChargebackUC:  rejectChargebackUC,
+		HTTPServer:          httpServer,
+		Inbox:               inbox,
+		ChargebackCache:     stores.ChargebackCache,
+		Redis:               stores.Redis,
 	}, nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// newInbox creates the inbox watcher with a parser per supported file extension
+func newInbox(config InboxConfig, createChargebackUC importer.CreateChargebackUseCase, logger service.Logger) (*importer.Inbox, error) {
+	mapping, err := importer.ParseMapping(config.CSVMapping)
+	if err != nil {
+		return nil, err
+	}
+	parsers := map[string]importer.DisputeFileParser{
+		".csv": importer.NewCSVParser(importer.CSVConfig{Mapping: mapping, Format: importer.DefaultFormat()}),
+	}
+
+	if config.Layout != "" {
+		layout, err := importer.LoadFixedWidthLayout(config.Layout)
+		if err != nil {
+			return nil, err
+		}
+		parser, err := importer.NewFixedWidthParser(layout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid layout: %w", err)
+		}
+		parsers[".txt"] = parser
+		parsers[".dat"] = parser
+	}
+
+	ledgerPath := config.Ledger
+	if ledgerPath == "" {
+		ledgerPath = filepath.Join(config.Dir, ".ingestions.jsonl")
+	}
+	ledger, err := importer.NewFileLedger(ledgerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return importer.NewInbox(importer.NewImporter(createChargebackUC), importer.InboxConfig{
+		Dir:          config.Dir,
+		PollInterval: config.PollInterval,
+		SettleTime:   config.SettleTime,
+		Parsers:      parsers,
+	}, ledger, logger)
+}
+
+// getRateLimitOrDefault parses a rate limit environment variable, falling back to the default
+func getRateLimitOrDefault(key string, defaultValue service.RateLimit) service.RateLimit {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	limit, err := parseRateLimit(value)
+	if err != nil {
+		log.Printf("⚠️  Warning: invalid rate limit for %s: %v, using default %s", key, err, defaultValue)
+		return defaultValue
+	}
+	return limit
+}
+
+// parseRateLimitRoutes parses "ROUTE=LIMIT" pairs separated by commas, e.g.
+// "POST /chargebacks=60/1m,GET /chargebacks/{id}=600/1m"; invalid entries are skipped
+func parseRateLimitRoutes(value string) map[string]service.RateLimit {
+	routes := make(map[string]service.RateLimit)
+	for _, pair := range strings.Split(value, ",") {
+		route, rawLimit, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(route) == "" {
+			continue
+		}
+
+		limit, err := parseRateLimit(rawLimit)
+		if err != nil {
+			log.Printf("⚠️  Warning: invalid rate limit for route %q: %v, ignoring", route, err)
+			continue
+		}
+		routes[strings.Join(strings.Fields(route), " ")] = limit
+	}
+	return routes
+}
+
+// parseRateLimit parses a "requests/window[/burst]" limit such as "60/1m" or "60/1m/10"
+func parseRateLimit(value string) (service.RateLimit, error) {
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return service.RateLimit{}, fmt.Errorf("expected requests/window[/burst], got %q", value)
+	}
+
+	requests, err := strconv.Atoi(parts[0])
+	if err != nil || requests < 0 {
+		return service.RateLimit{}, fmt.Errorf("invalid request count %q", parts[0])
+	}
+
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return service.RateLimit{}, fmt.Errorf("invalid window %q", parts[1])
+	}
+
+	limit := service.RateLimit{Requests: requests, Window: window}
+	if len(parts) == 3 {
+		if limit.Burst, err = strconv.Atoi(parts[2]); err != nil || limit.Burst < 0 {
+			return service.RateLimit{}, fmt.Errorf("invalid burst %q", parts[2])
+		}
+	}
+	return limit, nil
+}
+
+// getListOrDefault parses a comma separated environment variable, falling back to the default
+func getListOrDefault(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
 	}
+
+	return getListFromString(value)
+}
+
+// getListFromString splits a comma separated value, dropping empty items
+func getListFromString(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getFloatOrDefault parses a numeric environment variable, falling back to the default
+func getFloatOrDefault(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("⚠️  Warning: invalid number for %s: %q, using default %g", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// parseRoleMapping parses "idp-name=role" pairs separated by commas
+func parseRoleMapping(value string) map[string]auth.Role {
+	mapping := make(map[string]auth.Role)
+	for name, role := range parseMapping(value) {
+		mapping[name] = auth.Role(strings.ToLower(role))
+	}
+	return mapping
+}
+
+// parseScopes parses a comma separated list of scopes
+func parseScopes(value string) []auth.Scope {
+	var scopes []auth.Scope
+	for _, scope := range getListFromString(value) {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+	return scopes
+}
+
+// parseMapping parses "name=value" pairs separated by commas
+func parseMapping(value string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		name, mapped, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+		mapping[strings.TrimSpace(name)] = strings.TrimSpace(mapped)
+	}
+	return mapping
+}
+
+// getTimeOrDefault parses an RFC 3339 timestamp or YYYY-MM-DD date environment variable,
+// falling back to the default
+func getTimeOrDefault(key string, defaultValue time.Time) time.Time {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	log.Printf("⚠️  Warning: invalid time for %s: %q, using default", key, value)
 	return defaultValue
 }
 
@@ -212,27 +789,3 @@ func parseLogFormat(format string) logging.LogFormat {
 		return logging.FormatJSON
 	}
 }
-
-func testDynamoDBConnection(ctx context.Context, client *dynamodb.Client, tableName string, logger service.Logger) error {
-	logger.Info(ctx, "Testing DynamoDB connection", map[string]interface{}{
-		"table_name": tableName,
-	})
-
-	// Try to describe the table to verify it exists and we have access
-	_, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
-		TableName: &tableName,
-	})
-
-	if err != nil {
-		logger.Error(ctx, "DynamoDB connection test failed", map[string]interface{}{
-			"error":      err.Error(),
-			"table_name": tableName,
-		})
-		return fmt.Errorf("table '%s' not accessible: %w", tableName, err)
-	}
-
-	logger.Info(ctx, "DynamoDB connection test successful", map[string]interface{}{
-		"table_name": tableName,
-	})
-	return nil
-}
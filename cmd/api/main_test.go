@@ -3,63 +3,22 @@ package main
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
 	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/server"
+	"github.com/alicebob/miniredis/v2"
 )
 
-func TestGetEnvOrDefault(t *testing.T) {
-	tests := []struct {
-		name         string
-		key          string
-		defaultValue string
-		envValue     string
-		expected     string
-	}{
-		{
-			name:         "returns environment variable when set",
-			key:          "TEST_KEY",
-			defaultValue: "default",
-			envValue:     "environment_value",
-			expected:     "environment_value",
-		},
-		{
-			name:         "returns default when environment variable not set",
-			key:          "UNSET_KEY",
-			defaultValue: "default_value",
-			envValue:     "",
-			expected:     "default_value",
-		},
-		{
-			name:         "returns default when environment variable is empty",
-			key:          "EMPTY_KEY",
-			defaultValue: "default",
-			envValue:     "",
-			expected:     "default",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Setup
-			if tt.envValue != "" {
-				os.Setenv(tt.key, tt.envValue)
-				defer os.Unsetenv(tt.key)
-			}
-
-			// Act
-			result := getEnvOrDefault(tt.key, tt.defaultValue)
-
-			// Assert
-			if result != tt.expected {
-				t.Errorf("getEnvOrDefault(%s, %s) = %s, want %s", tt.key, tt.defaultValue, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestLoadConfiguration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -71,27 +30,35 @@ func TestLoadConfiguration(t *testing.T) {
 			envVars: map[string]string{},
 			expected: Config{
 				Port: "8080",
-				DynamoDB: db.DynamoDBConfig{
-					Endpoint:  "",
-					Region:    "us-east-1",
-					TableName: "chargebacks",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Endpoint:  "",
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+						Layout:    db.LayoutFlat,
+					},
 				},
 			},
 		},
 		{
 			name: "loads configuration from environment variables",
 			envVars: map[string]string{
-				"PORT":              "3000",
-				"AWS_REGION":        "us-west-2",
-				"DYNAMODB_TABLE":    "test-chargebacks",
-				"DYNAMODB_ENDPOINT": "http://localhost:8000",
+				"PORT":                  "3000",
+				"AWS_REGION":            "us-west-2",
+				"DYNAMODB_TABLE":        "test-chargebacks",
+				"DYNAMODB_ENDPOINT":     "http://localhost:8000",
+				"DYNAMODB_TABLE_LAYOUT": "Single",
 			},
 			expected: Config{
 				Port: "3000",
-				DynamoDB: db.DynamoDBConfig{
-					Endpoint:  "http://localhost:8000",
-					Region:    "us-west-2",
-					TableName: "test-chargebacks",
+				StorageConfig: bootstrap.StorageConfig{
+					DynamoDB: db.DynamoDBConfig{
+						Endpoint:  "http://localhost:8000",
+						Region:    "us-west-2",
+						TableName: "test-chargebacks",
+						Layout:    db.LayoutSingleTable,
+					},
 				},
 			},
 		},
@@ -121,6 +88,9 @@ func TestLoadConfiguration(t *testing.T) {
 			if config.DynamoDB.Endpoint != tt.expected.DynamoDB.Endpoint {
 				t.Errorf("Expected Endpoint %s, got %s", tt.expected.DynamoDB.Endpoint, config.DynamoDB.Endpoint)
 			}
+			if config.DynamoDB.Layout != tt.expected.DynamoDB.Layout {
+				t.Errorf("Expected Layout %s, got %s", tt.expected.DynamoDB.Layout, config.DynamoDB.Layout)
+			}
 		})
 	}
 }
@@ -134,10 +104,13 @@ func TestInitializeDependencies(t *testing.T) {
 	// Setup
 	config := Config{
 		Port: "8080",
-		DynamoDB: db.DynamoDBConfig{
-			Endpoint:  "",
-			Region:    "us-east-1",
-			TableName: "test-chargebacks",
+		StorageConfig: bootstrap.StorageConfig{
+			StorageBackend: "dynamodb",
+			DynamoDB: db.DynamoDBConfig{
+				Endpoint:  "",
+				Region:    "us-east-1",
+				TableName: "test-chargebacks",
+			},
 		},
 	}
 
@@ -153,6 +126,7 @@ func TestInitializeDependencies(t *testing.T) {
 		// Check if it's the expected DynamoDB table not found error
 		if containsError(err.Error(), "ResourceNotFoundException") ||
 			containsError(err.Error(), "test-chargebacks not found") ||
+			containsError(err.Error(), "does not exist") ||
 			containsError(err.Error(), "not accessible") {
 			t.Skipf("Skipping test - DynamoDB table 'test-chargebacks' not available: %v", err)
 		} else {
@@ -190,14 +164,100 @@ func containsError(errorMsg, searchText string) bool {
 			strings.Contains(strings.ToLower(errorMsg), strings.ToLower(searchText)))
 }
 
+func TestInitializeDependencies_SQLite(t *testing.T) {
+	// Setup
+	config := Config{
+		Port: "8080",
+		StorageConfig: bootstrap.StorageConfig{
+			StorageBackend: "sqlite",
+			DynamoDB: db.DynamoDBConfig{
+				Region:    "us-east-1",
+				TableName: "chargebacks",
+			},
+			SQLite: db.SQLiteConfig{Path: filepath.Join(t.TempDir(), "chargebacks.db")},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	deps, err := initializeDependencies(ctx, config)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error during initialization: %v", err)
+	}
+	defer deps.SQLDB.Close()
+
+	chargebacks, err := deps.ChargebackRepo.List(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("Expected the schema to be migrated, got %v", err)
+	}
+	if len(chargebacks) != 0 {
+		t.Errorf("Expected no chargebacks, got %d", len(chargebacks))
+	}
+}
+
+func TestInitializeDependencies_RedisCache(t *testing.T) {
+	// Setup
+	redisServer := miniredis.RunT(t)
+	config := Config{
+		Port: "8080",
+		StorageConfig: bootstrap.StorageConfig{
+			StorageBackend: "memory",
+			DynamoDB: db.DynamoDBConfig{
+				Region:    "us-east-1",
+				TableName: "chargebacks",
+			},
+			Cache: bootstrap.CacheConfig{
+				Enabled: true,
+				Backend: "redis",
+				TTL:     time.Minute,
+				Redis:   cache.RedisConfig{Addr: redisServer.Addr(), KeyPrefix: "test:"},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Act
+	deps, err := initializeDependencies(ctx, config)
+	if err != nil {
+		t.Fatalf("Unexpected error during initialization: %v", err)
+	}
+	defer deps.Redis.Close()
+
+	chargeback := &entity.Chargeback{ID: "cb_1", TransactionID: "txn_1", MerchantID: "merchant_1", Status: entity.StatusPending}
+	if err := deps.ChargebackRepo.Save(ctx, chargeback); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	found, err := deps.ChargebackRepo.FindByID(ctx, chargeback.ID)
+
+	// Assert
+	if err != nil || found == nil {
+		t.Fatalf("Expected the chargeback, got %+v, %v", found, err)
+	}
+	if !redisServer.Exists("test:chargeback:id:cb_1") {
+		t.Error("Expected the chargeback to be cached in Redis")
+	}
+	if stats := deps.ChargebackCache.Stats(); stats.Misses != 1 {
+		t.Errorf("Expected a single miss, got %+v", stats)
+	}
+}
+
 func TestInitializeDependencies_InvalidDynamoDBConfig(t *testing.T) {
 	// Setup - invalid region should cause AWS config to fail in some cases
 	config := Config{
 		Port: "8080",
-		DynamoDB: db.DynamoDBConfig{
-			Endpoint:  "invalid://endpoint",
-			Region:    "",
-			TableName: "",
+		StorageConfig: bootstrap.StorageConfig{
+			StorageBackend: "dynamodb",
+			DynamoDB: db.DynamoDBConfig{
+				Endpoint:  "invalid://endpoint",
+				Region:    "",
+				TableName: "",
+			},
 		},
 	}
 
@@ -232,10 +292,15 @@ func TestValidateConfiguration(t *testing.T) {
 			name: "valid configuration",
 			config: Config{
 				Port: "8080",
-				DynamoDB: db.DynamoDBConfig{
-					Region:    "us-east-1",
-					TableName: "chargebacks",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
 				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
 			},
 			shouldErr: false,
 		},
@@ -243,9 +308,11 @@ func TestValidateConfiguration(t *testing.T) {
 			name: "empty port",
 			config: Config{
 				Port: "",
-				DynamoDB: db.DynamoDBConfig{
-					Region:    "us-east-1",
-					TableName: "chargebacks",
+				StorageConfig: bootstrap.StorageConfig{
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
 				},
 			},
 			shouldErr: true,
@@ -254,9 +321,12 @@ func TestValidateConfiguration(t *testing.T) {
 			name: "empty region",
 			config: Config{
 				Port: "8080",
-				DynamoDB: db.DynamoDBConfig{
-					Region:    "",
-					TableName: "chargebacks",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "",
+						TableName: "chargebacks",
+					},
 				},
 			},
 			shouldErr: true,
@@ -265,9 +335,428 @@ func TestValidateConfiguration(t *testing.T) {
 			name: "empty table name",
 			config: Config{
 				Port: "8080",
-				DynamoDB: db.DynamoDBConfig{
-					Region:    "us-east-1",
-					TableName: "",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "",
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown role in JWT role mapping",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				Auth: AuthConfig{
+					Enabled:  true,
+					KeyStore: "memory",
+					JWT: JWTConfig{
+						RoleMapping: map[string]auth.Role{"cb-auditors": "auditor"},
+					},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "JWKS source without audience",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				Auth: AuthConfig{
+					Enabled:  true,
+					KeyStore: "memory",
+					JWT: JWTConfig{
+						JWKSSource: "https://idp.example.com/.well-known/jwks.json",
+						Issuer:     "https://idp.example.com",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "TLS certificate without key",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				TLS: server.TLSConfig{CertFile: "server.crt"},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown rate limit key",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				RateLimit: RateLimitConfig{
+					Enabled: true,
+					Store:   "memory",
+					Policy:  server.RateLimitConfig{KeyBy: "session"},
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "legacy routes sunset before deprecation",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				LegacyRoutes: server.Deprecation{
+					Since:  time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+					Sunset: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "batch without items",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				BatchMaxItems: 0,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "export without page size",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				BatchMaxItems: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "merchant ratio threshold above one",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					MerchantStats: bootstrap.MerchantStatsConfig{
+						Enabled:    true,
+						Store:      "memory",
+						Thresholds: []entity.RatioThreshold{{Name: "excessive", Ratio: 9}},
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "alert webhook without scheme",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					MerchantStats: bootstrap.MerchantStatsConfig{
+						Enabled:         true,
+						Store:           "memory",
+						AlertWebhookURL: "alerts.example.com/hooks",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown storage backend",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "cassandra",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "single-table layout",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+						Layout:    db.LayoutSingleTable,
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: false,
+		},
+		{
+			name: "unknown table layout",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+						Layout:    "nested",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "retry delays out of order",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					DynamoDBResilience: bootstrap.ResilienceConfig{
+						Retry: dynamoRepo.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Millisecond},
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "circuit breaker without open timeout",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					DynamoDBResilience: bootstrap.ResilienceConfig{
+						Breaker: dynamoRepo.CircuitBreakerPolicy{FailureThreshold: 5},
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "memory chargeback cache without size",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "memory",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Cache: bootstrap.CacheConfig{Enabled: true, Backend: "memory", TTL: time.Minute},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "unknown chargeback cache backend",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "memory",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Cache: bootstrap.CacheConfig{Enabled: true, Backend: "memcached", TTL: time.Minute},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "postgres storage backend without DSN",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "postgres",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Postgres: bootstrap.PostgresConfig{MaxOpenConns: 10},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "sqlite storage backend without path",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "sqlite",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "sqlite storage backend",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "sqlite",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					SQLite: db.SQLiteConfig{Path: "chargebacks.db"},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: false,
+		},
+		{
+			name: "postgres storage backend",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "postgres",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Postgres: bootstrap.PostgresConfig{
+						DSN:          "postgres://localhost:5432/chargebacks",
+						MaxOpenConns: 10,
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: false,
+		},
+		{
+			name: "unknown reports store",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Reports: bootstrap.ReportsConfig{Enabled: true, Store: "redis"},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "dynamodb reports store without table",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+					Reports: bootstrap.ReportsConfig{Enabled: true, Store: "dynamodb"},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+			},
+			shouldErr: true,
+		},
+		{
+			name: "inbox with invalid CSV mapping",
+			config: Config{
+				Port: "8080",
+				StorageConfig: bootstrap.StorageConfig{
+					StorageBackend: "dynamodb",
+					DynamoDB: db.DynamoDBConfig{
+						Region:    "us-east-1",
+						TableName: "chargebacks",
+					},
+				},
+				BatchMaxItems:  500,
+				ExportPageSize: 500,
+				Inbox: InboxConfig{
+					Dir:          "/var/spool/disputes",
+					PollInterval: 30 * time.Second,
+					CSVMapping:   "acquirer=Acq",
 				},
 			},
 			shouldErr: true,
@@ -289,3 +778,206 @@ func TestValidateConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTimeOrDefault(t *testing.T) {
+	defaultValue := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		envValue string
+		expected time.Time
+	}{
+		{
+			name:     "parses RFC 3339 timestamp",
+			envValue: "2026-06-30T12:00:00Z",
+			expected: time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "parses date",
+			envValue: "2026-06-30",
+			expected: time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "returns default when not set",
+			envValue: "",
+			expected: defaultValue,
+		},
+		{
+			name:     "returns default when invalid",
+			envValue: "next summer",
+			expected: defaultValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv("TEST_TIME", tt.envValue)
+				defer os.Unsetenv("TEST_TIME")
+			}
+
+			// Act
+			result := getTimeOrDefault("TEST_TIME", defaultValue)
+
+			// Assert
+			if !result.Equal(tt.expected) {
+				t.Errorf("getTimeOrDefault() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetFloatOrDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue float64
+		expected     float64
+	}{
+		{"returns parsed number when set", "2500.50", 10000, 2500.50},
+		{"returns default when not set", "", 10000, 10000},
+		{"returns default when invalid", "lots", 10000, 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv("TEST_FLOAT", tt.envValue)
+				defer os.Unsetenv("TEST_FLOAT")
+			}
+
+			// Act
+			result := getFloatOrDefault("TEST_FLOAT", tt.defaultValue)
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("getFloatOrDefault() = %g, want %g", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRoleMapping(t *testing.T) {
+	// Act
+	mapping := parseRoleMapping("cb-viewers=viewer, cb-supervisors = Supervisor,malformed,=admin")
+
+	// Assert
+	expected := map[string]auth.Role{
+		"cb-viewers":     auth.RoleViewer,
+		"cb-supervisors": auth.RoleSupervisor,
+	}
+	if len(mapping) != len(expected) {
+		t.Fatalf("Expected %d mappings, got %v", len(expected), mapping)
+	}
+	for name, role := range expected {
+		if mapping[name] != role {
+			t.Errorf("Expected %s to map to %s, got %s", name, role, mapping[name])
+		}
+	}
+}
+
+func TestGetListOrDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue []string
+		expected     []string
+	}{
+		{"splits and trims values", "https://a.example.com, https://b.example.com,,", nil, []string{"https://a.example.com", "https://b.example.com"}},
+		{"returns default when not set", "", []string{"GET"}, []string{"GET"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv("TEST_LIST", tt.envValue)
+				defer os.Unsetenv("TEST_LIST")
+			}
+
+			// Act
+			result := getListOrDefault("TEST_LIST", tt.defaultValue)
+
+			// Assert
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("getListOrDefault() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  service.RateLimit
+		shouldErr bool
+	}{
+		{value: "60/1m", expected: service.RateLimit{Requests: 60, Window: time.Minute}},
+		{value: "1000/24h/50", expected: service.RateLimit{Requests: 1000, Window: 24 * time.Hour, Burst: 50}},
+		{value: "60", shouldErr: true},
+		{value: "sixty/1m", shouldErr: true},
+		{value: "60/0s", shouldErr: true},
+		{value: "60/1m/many", shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			limit, err := parseRateLimit(tt.value)
+
+			// Assert
+			if tt.shouldErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if limit != tt.expected {
+				t.Errorf("parseRateLimit() = %+v, want %+v", limit, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRateLimitRoutes(t *testing.T) {
+	// Act
+	routes := parseRateLimitRoutes("POST  /chargebacks=60/1m, /chargebacks/{id}=600/1m,GET /admin=bad,malformed")
+
+	// Assert
+	expected := map[string]service.RateLimit{
+		"POST /chargebacks": {Requests: 60, Window: time.Minute},
+		"/chargebacks/{id}": {Requests: 600, Window: time.Minute},
+	}
+	if len(routes) != len(expected) {
+		t.Fatalf("Expected %d routes, got %v", len(expected), routes)
+	}
+	for route, limit := range expected {
+		if routes[route] != limit {
+			t.Errorf("Expected %s to be limited to %+v, got %+v", route, limit, routes[route])
+		}
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	// Act
+	mapping := parseMapping("acquirer-1=merchant-789, acquirer-2 = merchant-456,malformed,=merchant-000")
+
+	// Assert
+	expected := map[string]string{
+		"acquirer-1": "merchant-789",
+		"acquirer-2": "merchant-456",
+	}
+	if len(mapping) != len(expected) {
+		t.Fatalf("Expected %d mappings, got %v", len(expected), mapping)
+	}
+	for name, value := range expected {
+		if mapping[name] != value {
+			t.Errorf("Expected %s to map to %s, got %s", name, value, mapping[name])
+		}
+	}
+}
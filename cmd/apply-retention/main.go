@@ -0,0 +1,174 @@
+// Command apply-retention purges chargebacks kept longer than the retention period
+//
+// Chargebacks, soft deleted ones included, are kept for -years years after they are
+// created. Older ones are archived a page at a time to a local directory or an
+// S3-compatible bucket, as gzipped newline-delimited JSON, then purged: SQL backends
+// delete the rows; on DynamoDB the table's time to live attribute is set, so DynamoDB
+// removes them, and they are hidden until it does. Run it with -dry-run first to count
+// what would be purged.
+//
+// The chargeback store is opened from the same settings as the API's, with the same
+// cache and observers, so purged chargebacks leave the cache, the merchant statistics
+// and the report counters.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/archive"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/logging"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Archive backends
+const (
+	archiveFile = "file"
+	archiveS3   = "s3"
+)
+
+// Options holds the command line options
+type Options struct {
+	Years    int
+	PageSize int
+	DryRun   bool
+	// Archive is the archive backend, archiveFile or archiveS3
+	Archive string
+	// ArchiveDir is the directory of the file archive
+	ArchiveDir string
+	// ArchiveBucket and ArchivePrefix locate the objects of the S3 archive
+	ArchiveBucket string
+	ArchivePrefix string
+}
+
+// ApplyRetentionUseCase interface defines the contract for applying the retention policy
+type ApplyRetentionUseCase interface {
+	Execute(ctx context.Context, dryRun bool) (*usecase.ApplyRetentionResult, error)
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger, err := logging.NewStructuredLogger(logging.LoggerConfig{
+		Level:       service.LogLevelWarn,
+		Format:      logging.FormatJSON,
+		ServiceName: "chargeback-retention",
+	}, os.Stderr)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	config := bootstrap.LoadStorageConfig()
+	stores, err := openStores(ctx, config, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize chargeback store: %v", err)
+	}
+	defer stores.Close()
+
+	chargebackArchive, err := newArchive(ctx, opts, config.DynamoDB.Region)
+	if err != nil {
+		log.Fatalf("Failed to initialize archive: %v", err)
+	}
+
+	retentionUC := usecase.NewApplyRetentionUseCase(stores.Chargebacks, chargebackArchive, opts.Years, opts.PageSize)
+	if err := run(ctx, retentionUC, opts.DryRun, os.Stdout); err != nil {
+		log.Fatalf("Retention failed: %v", err)
+	}
+}
+
+// openStores opens the chargeback store configured like the API's, wrapped in the same
+// cache and observers
+// The memory backend is refused: it holds no chargebacks outside the API's process.
+func openStores(ctx context.Context, config bootstrap.StorageConfig, logger service.Logger) (*bootstrap.Stores, error) {
+	if config.StorageBackend == "memory" {
+		return nil, fmt.Errorf("storage backend must be 'dynamodb', 'postgres' or 'sqlite', got '%s'", config.StorageBackend)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return bootstrap.OpenStores(ctx, config, logger)
+}
+
+// parseFlags reads the options from the command line arguments
+func parseFlags(args []string) (Options, error) {
+	flags := flag.NewFlagSet("apply-retention", flag.ContinueOnError)
+
+	var opts Options
+	flags.IntVar(&opts.Years, "years", 7, "number of years chargebacks are kept after they are created")
+	flags.IntVar(&opts.PageSize, "page-size", 500, "number of chargebacks archived and purged at a time")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "count the chargebacks to purge without archiving or purging them")
+	flags.StringVar(&opts.Archive, "archive", archiveFile, "archive backend: file or s3")
+	flags.StringVar(&opts.ArchiveDir, "archive-dir", "archive", "directory of the file archive")
+	flags.StringVar(&opts.ArchiveBucket, "archive-bucket", "", "bucket of the s3 archive")
+	flags.StringVar(&opts.ArchivePrefix, "archive-prefix", "chargebacks", "key prefix of the s3 archive")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if opts.Years <= 0 {
+		return opts, fmt.Errorf("years must be positive, got %d", opts.Years)
+	}
+	if opts.PageSize <= 0 {
+		return opts, fmt.Errorf("page size must be positive, got %d", opts.PageSize)
+	}
+	switch opts.Archive {
+	case archiveFile:
+	case archiveS3:
+		if opts.ArchiveBucket == "" {
+			return opts, fmt.Errorf("archive bucket is required for the s3 archive")
+		}
+	default:
+		return opts, fmt.Errorf("unknown archive backend %q", opts.Archive)
+	}
+	return opts, nil
+}
+
+// newArchive creates the archive backend of the options
+// ARCHIVE_S3_ENDPOINT points the s3 archive at an S3-compatible store, addressing the
+// bucket in the path.
+func newArchive(ctx context.Context, opts Options, region string) (service.ChargebackArchive, error) {
+	if opts.Archive == archiveS3 {
+		endpoint := os.Getenv("ARCHIVE_S3_ENDPOINT")
+		return archive.NewS3Archive(ctx, archive.S3Config{
+			Bucket:       opts.ArchiveBucket,
+			Prefix:       opts.ArchivePrefix,
+			Region:       region,
+			Endpoint:     endpoint,
+			UsePathStyle: endpoint != "",
+		})
+	}
+	return archive.NewFileArchive(opts.ArchiveDir)
+}
+
+// run applies the retention policy and prints a summary
+func run(ctx context.Context, retentionUC ApplyRetentionUseCase, dryRun bool, stdout io.Writer) error {
+	result, err := retentionUC.Execute(ctx, dryRun)
+	if err != nil {
+		return err
+	}
+
+	cutoff := result.Cutoff.Format("2006-01-02")
+	if result.DryRun {
+		fmt.Fprintf(stdout, "Would purge %d chargebacks created before %s (dry run)\n", result.Purged, cutoff)
+		return nil
+	}
+	fmt.Fprintf(stdout, "Purged %d chargebacks created before %s\n", result.Purged, cutoff)
+	for _, location := range result.Archives {
+		fmt.Fprintf(stdout, "Archived to %s\n", location)
+	}
+	return nil
+}
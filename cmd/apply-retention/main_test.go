@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/archive"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockApplyRetentionUseCase is a mock implementation of ApplyRetentionUseCase
+type MockApplyRetentionUseCase struct {
+	ExecuteFunc func(ctx context.Context, dryRun bool) (*usecase.ApplyRetentionResult, error)
+}
+
+func (m *MockApplyRetentionUseCase) Execute(ctx context.Context, dryRun bool) (*usecase.ApplyRetentionResult, error) {
+	return m.ExecuteFunc(ctx, dryRun)
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedYears int
+		shouldErr     bool
+	}{
+		{name: "defaults", args: nil, expectedYears: 7},
+		{name: "years", args: []string{"-years", "10", "-dry-run"}, expectedYears: 10},
+		{name: "s3 archive", args: []string{"-archive", "s3", "-archive-bucket", "records"}, expectedYears: 7},
+		{name: "non-positive years", args: []string{"-years", "0"}, shouldErr: true},
+		{name: "non-positive page size", args: []string{"-page-size", "-1"}, shouldErr: true},
+		{name: "s3 archive without bucket", args: []string{"-archive", "s3"}, shouldErr: true},
+		{name: "unknown archive", args: []string{"-archive", "tape"}, shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			opts, err := parseFlags(tt.args)
+
+			// Assert
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.Years != tt.expectedYears || opts.PageSize != 500 {
+				t.Errorf("Expected %d years and page size 500, got %+v", tt.expectedYears, opts)
+			}
+		})
+	}
+}
+
+func TestNewArchive(t *testing.T) {
+	opts, err := parseFlags([]string{"-archive-dir", filepath.Join(t.TempDir(), "archive")})
+	if err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	chargebackArchive, err := newArchive(context.Background(), opts, "us-east-1")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := chargebackArchive.(*archive.FileArchive); !ok {
+		t.Errorf("Expected file archive, got %T", chargebackArchive)
+	}
+}
+
+func TestRun(t *testing.T) {
+	cutoff := time.Date(2019, 10, 18, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dryRun   bool
+		result   *usecase.ApplyRetentionResult
+		err      error
+		expected string
+	}{
+		{
+			name:     "prints a summary",
+			result:   &usecase.ApplyRetentionResult{Cutoff: cutoff, Purged: 3, Archives: []string{"s3://records/a", "s3://records/b"}},
+			expected: "Purged 3 chargebacks created before 2019-10-18\nArchived to s3://records/a\nArchived to s3://records/b\n",
+		},
+		{
+			name:     "prints a dry run summary",
+			dryRun:   true,
+			result:   &usecase.ApplyRetentionResult{Cutoff: cutoff, Purged: 3, DryRun: true},
+			expected: "Would purge 3 chargebacks created before 2019-10-18 (dry run)\n",
+		},
+		{
+			name: "returns use case errors",
+			err:  errors.New("failed to archive chargebacks: bucket unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var stdout bytes.Buffer
+			retentionUC := &MockApplyRetentionUseCase{
+				ExecuteFunc: func(ctx context.Context, dryRun bool) (*usecase.ApplyRetentionResult, error) {
+					if dryRun != tt.dryRun {
+						t.Errorf("Expected dry run %v, got %v", tt.dryRun, dryRun)
+					}
+					return tt.result, tt.err
+				},
+			}
+
+			// Act
+			err := run(context.Background(), retentionUC, tt.dryRun, &stdout)
+
+			// Assert
+			if tt.err != nil {
+				if err == nil || stdout.Len() != 0 {
+					t.Fatalf("Expected error and no output, got %v and %q", err, stdout.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("Expected output %q, got %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRun_SQLite(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "chargebacks.db"))
+	t.Setenv("MERCHANT_STATS_ENABLED", "true")
+	t.Setenv("REPORTS_ENABLED", "true")
+	ctx := context.Background()
+	stores, err := openStores(ctx, bootstrap.LoadStorageConfig(), &testLogger{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stores.Close()
+
+	created := time.Now().UTC().AddDate(-10, 0, 0).Truncate(time.Second)
+	if err := stores.Chargebacks.Save(ctx, &entity.Chargeback{
+		ID:             "cb_1",
+		TransactionID:  "txn_1",
+		MerchantID:     "merchant_1",
+		Amount:         120.5,
+		Currency:       "USD",
+		Reason:         entity.ReasonFraud,
+		Status:         entity.StatusPending,
+		ChargebackDate: created,
+		CreatedAt:      created,
+		UpdatedAt:      created,
+	}); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	chargebackArchive, err := archive.NewFileArchive(filepath.Join(t.TempDir(), "archive"))
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	var stdout bytes.Buffer
+
+	// Act
+	err = run(ctx, usecase.NewApplyRetentionUseCase(stores.Chargebacks, chargebackArchive, 7, 10), false, &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found, err := stores.Chargebacks.FindByID(ctx, "cb_1"); err != nil || found != nil {
+		t.Errorf("Expected the chargeback to be purged, got %+v, %v", found, err)
+	}
+	// Purges reach the observers like the API's deletes
+	stats, err := stores.MerchantStats.Find(ctx, "merchant_1", entity.StatsMonth(created))
+	if err != nil || stats == nil || stats.ChargebackCount != 0 {
+		t.Errorf("Expected the purged chargeback to leave the merchant statistics, got %+v, %v", stats, err)
+	}
+}
+
+func TestOpenStores_UnsupportedBackend(t *testing.T) {
+	for _, backend := range []string{"cassandra", "memory"} {
+		t.Run(backend, func(t *testing.T) {
+			config := bootstrap.StorageConfig{
+				StorageBackend: backend,
+				DynamoDB:       db.DynamoDBConfig{Region: "us-east-1", TableName: "chargebacks"},
+			}
+			if _, err := openStores(context.Background(), config, &testLogger{}); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+// testLogger discards log entries
+type testLogger struct{}
+
+func (l *testLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *testLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) WithContext(ctx context.Context) service.Logger { return l }
@@ -0,0 +1,234 @@
+// Command importer creates chargebacks from a CSV or fixed-width dispute file
+//
+// CSV files are read with the column mapping and formats given as flags; fixed-width
+// files are described by a JSON layout file given with -layout.
+//
+// Rows are validated and created through the same use case as the API. Rows that can't
+// be imported are written, with their line number and the reason, to a rejects file.
+// Progress is saved after every row, so running the same command again after a crash
+// resumes after the last processed line.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/idgen"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/logging"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Options holds the command line options
+type Options struct {
+	File       string
+	Checkpoint string
+	Rejects    string
+	DryRun     bool
+	Parser     importer.DisputeFileParser
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Dry runs only validate rows, so they don't need the chargeback store
+	var createChargebackUC importer.CreateChargebackUseCase
+	if !opts.DryRun {
+		logger, err := logging.NewStructuredLogger(logging.LoggerConfig{
+			Level:       service.LogLevelWarn,
+			Format:      logging.FormatJSON,
+			ServiceName: "chargeback-importer",
+		}, os.Stderr)
+		if err != nil {
+			log.Fatalf("Failed to initialize logger: %v", err)
+		}
+		stores, err := openStores(ctx, bootstrap.LoadStorageConfig(), logger)
+		if err != nil {
+			log.Fatalf("Failed to initialize chargeback store: %v", err)
+		}
+		defer stores.Close()
+		createChargebackUC = usecase.NewCreateChargebackUseCase(stores.Chargebacks, idgen.NewULIDGenerator())
+	}
+
+	if _, err := run(ctx, opts, createChargebackUC, os.Stdout); err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
+
+// openStores opens the chargeback store configured like the API's, wrapped in the same
+// cache and observers, so imported rows are counted in the merchant statistics and report
+// counters
+// The memory backend is refused: the chargebacks would be lost when the import ends.
+func openStores(ctx context.Context, config bootstrap.StorageConfig, logger service.Logger) (*bootstrap.Stores, error) {
+	if config.StorageBackend == "memory" {
+		return nil, fmt.Errorf("storage backend must be 'dynamodb', 'postgres' or 'sqlite', got '%s'", config.StorageBackend)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return bootstrap.OpenStores(ctx, config, logger)
+}
+
+// parseFlags reads the options from the command line arguments
+func parseFlags(args []string) (Options, error) {
+	flags := flag.NewFlagSet("importer", flag.ContinueOnError)
+
+	var (
+		opts      Options
+		csv       = importer.CSVConfig{Format: importer.DefaultFormat()}
+		mapping   string
+		delimiter string
+		layout    string
+	)
+
+	flags.StringVar(&opts.File, "file", "", "CSV dispute file to import (required)")
+	flags.StringVar(&mapping, "map", "", "column mapping as field=column pairs, e.g. transaction_id=Reference,amount=Value")
+	flags.StringVar(&csv.Format.DateLayout, "date-format", csv.Format.DateLayout, "Go time layout of transaction dates")
+	flags.StringVar(&csv.Format.DecimalSeparator, "decimal-separator", csv.Format.DecimalSeparator, "decimal separator of amounts")
+	flags.StringVar(&csv.Format.ThousandsSeparator, "thousands-separator", "", "thousands separator of amounts")
+	flags.BoolVar(&csv.Format.MinorUnits, "minor-units", false, "amounts are in minor units, e.g. cents")
+	flags.StringVar(&delimiter, "delimiter", ",", "field delimiter")
+	flags.StringVar(&layout, "layout", "", "JSON layout of a fixed-width file; CSV options are ignored when set")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "validate rows without creating chargebacks")
+	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "file keeping the last processed line (default <file>.checkpoint)")
+	flags.StringVar(&opts.Rejects, "rejects", "", "file rejected rows are written to (default <file>.rejects.csv)")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if opts.File == "" {
+		return opts, fmt.Errorf("file is required")
+	}
+	if opts.Checkpoint == "" {
+		opts.Checkpoint = opts.File + ".checkpoint"
+	}
+	if opts.Rejects == "" {
+		opts.Rejects = opts.File + ".rejects.csv"
+	}
+
+	if layout != "" {
+		fixedWidthLayout, err := importer.LoadFixedWidthLayout(layout)
+		if err != nil {
+			return opts, err
+		}
+		parser, err := importer.NewFixedWidthParser(fixedWidthLayout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid layout: %w", err)
+		}
+		opts.Parser = parser
+		return opts, nil
+	}
+
+	if mapping != "" {
+		parsed, err := importer.ParseMapping(mapping)
+		if err != nil {
+			return opts, err
+		}
+		csv.Mapping = parsed
+	}
+
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size == 0 || size != len(delimiter) {
+		return opts, fmt.Errorf("delimiter must be a single character")
+	}
+	csv.Delimiter = r
+
+	if err := csv.Validate(); err != nil {
+		return opts, err
+	}
+	opts.Parser = importer.NewCSVParser(csv)
+	return opts, nil
+}
+
+// run imports the file and prints a summary
+// The rejects file is appended to when resuming an interrupted import
+func run(ctx context.Context, opts Options, createChargebackUC importer.CreateChargebackUseCase, stdout io.Writer) (importer.Result, error) {
+	file, err := os.Open(opts.File)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := opts.Parser.Parse(file)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("failed to read %s: %w", opts.File, err)
+	}
+
+	checkpoint := importer.NewFileCheckpoint(opts.Checkpoint)
+	resuming := false
+	if !opts.DryRun {
+		line, err := checkpoint.Load()
+		if err != nil {
+			return importer.Result{}, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		resuming = line > 0
+	}
+
+	flags, header := os.O_CREATE|os.O_WRONLY|os.O_TRUNC, reader.Header()
+	if resuming {
+		flags, header = os.O_CREATE|os.O_WRONLY|os.O_APPEND, nil
+	}
+	rejectsFile, err := os.OpenFile(opts.Rejects, flags, 0o600)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("failed to open rejects file: %w", err)
+	}
+	defer rejectsFile.Close()
+
+	rejects, err := importer.NewCSVRejectWriter(rejectsFile, header)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("failed to write rejects file: %w", err)
+	}
+
+	result, err := importer.NewImporter(createChargebackUC).Run(ctx, reader, importer.Options{
+		DryRun:     opts.DryRun,
+		Checkpoint: checkpoint,
+		Rejects:    rejects,
+	})
+
+	mode := ""
+	if opts.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Fprintf(stdout, "Imported %d, rejected %d, skipped %d%s\n", result.Imported, result.Rejected, result.Skipped, mode)
+	if result.Rejected > 0 {
+		fmt.Fprintf(stdout, "Rejected rows were written to %s\n", opts.Rejects)
+	}
+
+	if err != nil {
+		if !opts.DryRun {
+			fmt.Fprintf(stdout, "Run the command again to resume after line %d\n", lastLine(checkpoint))
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// lastLine returns the checkpoint's line, 0 when it can't be read
+func lastLine(checkpoint importer.Checkpoint) int {
+	line, _ := checkpoint.Load()
+	return line
+}
+
+// getEnvOrDefault returns environment variable value or default if not set
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/idgen"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockCreateChargebackUseCase is a mock implementation of CreateChargebackUseCase
+type MockCreateChargebackUseCase struct {
+	ExecuteFunc func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error)
+}
+
+func (m *MockCreateChargebackUseCase) Execute(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+	if m.ExecuteFunc != nil {
+		return m.ExecuteFunc(ctx, req)
+	}
+	return &usecase.CreateChargebackResponse{TransactionID: req.TransactionID}, nil
+}
+
+const testFile = "Ref;Merchant;Value;Currency;Card;Reason;Date\n" +
+	"tx-1;merchant-1;10,50;usd;4111111111111111;fraud;01/10/2023\n" +
+	"tx-2;merchant-1;abc;usd;4111111111111111;fraud;01/10/2023\n" +
+	"tx-3;merchant-1;7,00;usd;4111111111111111;fraud;01/10/2023\n"
+
+const testMapping = "transaction_id=Ref,merchant_id=Merchant,amount=Value,currency=Currency," +
+	"card_number=Card,reason=Reason,transaction_date=Date"
+
+// writeTestFile writes the dispute file and returns the options to import it
+func writeTestFile(t *testing.T, extraArgs ...string) Options {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "disputes.csv")
+	if err := os.WriteFile(path, []byte(testFile), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	args := append([]string{"-file", path, "-map", testMapping, "-delimiter", ";",
+		"-decimal-separator", ",", "-date-format", "02/01/2006"}, extraArgs...)
+	opts, err := parseFlags(args)
+	if err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	return opts
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name: "defaults",
+			args: []string{"-file", "disputes.csv"},
+		},
+		{
+			name:          "missing file",
+			args:          []string{"-dry-run"},
+			expectedError: "file is required",
+		},
+		{
+			name:          "invalid delimiter",
+			args:          []string{"-file", "disputes.csv", "-delimiter", ";;"},
+			expectedError: "delimiter must be a single character",
+		},
+		{
+			name:          "unknown mapped field",
+			args:          []string{"-file", "disputes.csv", "-map", "acquirer=Acq"},
+			expectedError: "unknown fields in column mapping: acquirer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			opts, err := parseFlags(tt.args)
+
+			// Assert
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Expected error '%s', got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			parser, ok := opts.Parser.(*importer.CSVParser)
+			if !ok {
+				t.Fatalf("Expected CSV parser, got %T", opts.Parser)
+			}
+			if opts.Checkpoint != "disputes.csv.checkpoint" || opts.Rejects != "disputes.csv.rejects.csv" || parser.Config().Delimiter != ',' {
+				t.Errorf("Unexpected defaults %+v", opts)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	// Arrange
+	opts := writeTestFile(t)
+	var created []string
+	createUC := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			created = append(created, req.TransactionID)
+			return &usecase.CreateChargebackResponse{}, nil
+		},
+	}
+	var stdout bytes.Buffer
+
+	// Act
+	_, err := run(context.Background(), opts, createUC, &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(created, ",") != "tx-1,tx-3" {
+		t.Errorf("Expected tx-1 and tx-3 to be created, got %v", created)
+	}
+	if !strings.HasPrefix(stdout.String(), "Imported 2, rejected 1, skipped 0\n") {
+		t.Errorf("Unexpected summary %q", stdout.String())
+	}
+
+	rejects, err := os.ReadFile(opts.Rejects)
+	if err != nil {
+		t.Fatalf("Failed to read rejects file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(rejects)), "\n")
+	if len(lines) != 2 || lines[0] != "line,reason,Ref,Merchant,Value,Currency,Card,Reason,Date" ||
+		!strings.HasPrefix(lines[1], "3,") {
+		t.Errorf("Unexpected rejects file:\n%s", rejects)
+	}
+}
+
+func TestRun_ResumesAndAppendsRejects(t *testing.T) {
+	// Arrange
+	opts := writeTestFile(t)
+	failing := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			if req.TransactionID == "tx-3" {
+				return nil, errors.New("failed to save chargeback: DynamoDB unavailable")
+			}
+			return &usecase.CreateChargebackResponse{}, nil
+		},
+	}
+	var stdout bytes.Buffer
+
+	// Act
+	_, firstErr := run(context.Background(), opts, failing, &stdout)
+	result, err := run(context.Background(), opts, &MockCreateChargebackUseCase{}, &stdout)
+
+	// Assert
+	if firstErr == nil {
+		t.Fatal("Expected first run to fail")
+	}
+	if !strings.Contains(stdout.String(), "Run the command again to resume after line 3") {
+		t.Errorf("Expected resume hint, got %q", stdout.String())
+	}
+	if err != nil {
+		t.Fatalf("Expected resumed run to succeed, got %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 2 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	rejects, err := os.ReadFile(opts.Rejects)
+	if err != nil {
+		t.Fatalf("Failed to read rejects file: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(rejects)), "\n"); len(lines) != 2 {
+		t.Errorf("Expected rejects of the first run to be kept, got:\n%s", rejects)
+	}
+	if _, err := os.Stat(opts.Checkpoint); !os.IsNotExist(err) {
+		t.Errorf("Expected checkpoint to be removed after a complete import, got %v", err)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	// Arrange
+	opts := writeTestFile(t, "-dry-run")
+	var stdout bytes.Buffer
+
+	// Act
+	result, err := run(context.Background(), opts, nil, &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Imported != 2 || result.Rejected != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if !strings.Contains(stdout.String(), "(dry run)") {
+		t.Errorf("Expected dry run summary, got %q", stdout.String())
+	}
+}
+
+func TestRun_FixedWidth(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	layout := `{
+		"type_start": 1, "type_length": 1,
+		"detail": {"type": "D", "fields": [
+			{"name": "transaction_id", "start": 2, "length": 5},
+			{"name": "merchant_id", "start": 7, "length": 10},
+			{"name": "amount", "start": 17, "length": 6, "pad": "0"},
+			{"name": "currency", "start": 23, "length": 3},
+			{"name": "card_number", "start": 26, "length": 16},
+			{"name": "reason", "start": 42, "length": 6},
+			{"name": "transaction_date", "start": 48, "length": 8}
+		]},
+		"trailer": {"type": "T", "fields": [{"name": "record_count", "start": 2, "length": 4, "pad": "0"}]},
+		"format": {"date_layout": "20060102", "minor_units": true}
+	}`
+	file := "Dtx-1 merchant-1001050USD4111111111111111fraud 20231001\n" +
+		"Dtx-2 merchant-1000000USD4111111111111111fraud 20231001\n" +
+		"T0002\n"
+	if err := os.WriteFile(filepath.Join(dir, "layout.json"), []byte(layout), 0o600); err != nil {
+		t.Fatalf("Failed to write layout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "disputes.txt"), []byte(file), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	opts, err := parseFlags([]string{"-file", filepath.Join(dir, "disputes.txt"), "-layout", filepath.Join(dir, "layout.json")})
+	if err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	var stdout bytes.Buffer
+
+	// Act
+	result, err := run(context.Background(), opts, &MockCreateChargebackUseCase{}, &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Imported != 1 || result.Rejected != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	rejects, err := os.ReadFile(opts.Rejects)
+	if err != nil {
+		t.Fatalf("Failed to read rejects file: %v", err)
+	}
+	if !strings.HasPrefix(string(rejects), "line,reason,transaction_id,merchant_id,amount") ||
+		!strings.Contains(string(rejects), "2,validation errors: amount must be greater than zero,tx-2") {
+		t.Errorf("Unexpected rejects file:\n%s", rejects)
+	}
+}
+
+func TestRun_SQLite(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "chargebacks.db"))
+	t.Setenv("MERCHANT_STATS_ENABLED", "true")
+	t.Setenv("REPORTS_ENABLED", "true")
+	ctx := context.Background()
+	stores, err := openStores(ctx, bootstrap.LoadStorageConfig(), &testLogger{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stores.Close()
+	opts := writeTestFile(t)
+	var stdout bytes.Buffer
+
+	// Act
+	result, err := run(ctx, opts, usecase.NewCreateChargebackUseCase(stores.Chargebacks, idgen.NewULIDGenerator()), &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	chargebacks, err := stores.Chargebacks.List(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Imported != 2 || len(chargebacks) != 2 {
+		t.Errorf("Expected 2 chargebacks imported and stored, got %+v and %d", result, len(chargebacks))
+	}
+
+	// Imported rows are counted like the API's writes
+	day := chargebacks[0].ChargebackDate.UTC().Format(entity.ReportDayLayout)
+	counters, err := stores.ReportCounters.List(ctx, day, day)
+	if err != nil || len(counters) == 0 {
+		t.Errorf("Expected imported chargebacks to be counted in the reports, got %+v, %v", counters, err)
+	}
+	stats, err := stores.MerchantStats.Find(ctx, "merchant-1", entity.StatsMonth(chargebacks[0].ChargebackDate))
+	if err != nil || stats == nil || stats.ChargebackCount != 2 {
+		t.Errorf("Expected imported chargebacks to be counted in the merchant statistics, got %+v, %v", stats, err)
+	}
+}
+
+func TestOpenStores_UnsupportedBackend(t *testing.T) {
+	for _, backend := range []string{"cassandra", "memory"} {
+		t.Run(backend, func(t *testing.T) {
+			config := bootstrap.StorageConfig{
+				StorageBackend: backend,
+				DynamoDB:       db.DynamoDBConfig{Region: "us-east-1", TableName: "chargebacks"},
+			}
+			if _, err := openStores(context.Background(), config, &testLogger{}); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+// testLogger discards log entries
+type testLogger struct{}
+
+func (l *testLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *testLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) WithContext(ctx context.Context) service.Logger { return l }
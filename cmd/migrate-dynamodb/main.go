@@ -0,0 +1,237 @@
+// Command migrate-dynamodb rewrites the chargebacks of a flat DynamoDB table into a table
+// in the single-table layout
+//
+// Usage:
+//
+//	migrate-dynamodb -target chargebacks-v2 [-source chargebacks] [-ensure-table]
+//
+// DynamoDB can't change the key schema of a table, so the chargebacks are copied to the
+// target table, keyed by PK and SK, and the source table is left untouched. The source
+// defaults to DYNAMODB_TABLE. Progress is saved after every page, so running the same
+// command again after a crash resumes after the last copied page.
+//
+// Chargebacks changed through the flat table after their page was copied are not copied
+// again: stop writes, run the migration, then start the API with
+// DYNAMODB_TABLE=<target> and DYNAMODB_TABLE_LAYOUT=single. The command refuses to run
+// once the target holds chargebacks updated after the newest of the source, since the
+// API has then been switched to it and copying would overwrite its writes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
+)
+
+// Options holds the command line options
+type Options struct {
+	SourceTable string
+	TargetTable string
+	Checkpoint  string
+	PageSize    int
+	// EnsureTable creates the target table and its indexes when they are missing
+	EnsureTable bool
+}
+
+// LayoutMigrator interface defines the contract for rewriting chargebacks page by page
+type LayoutMigrator interface {
+	MigratePage(ctx context.Context, cursor string, limit int) (int, string, error)
+	// NewestUpdates returns when the newest chargebacks of the source and the target were updated
+	NewestUpdates(ctx context.Context) (source, target time.Time, err error)
+}
+
+// Checkpoint stores the cursor of the last copied page
+type Checkpoint interface {
+	// Load returns the saved cursor, "" when the migration hasn't started
+	Load() (string, error)
+	Save(cursor string) error
+	// Clear forgets the progress once the migration is complete
+	Clear() error
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	config := db.DynamoDBConfig{
+		Endpoint:  os.Getenv("DYNAMODB_ENDPOINT"),
+		Region:    getEnvOrDefault("AWS_REGION", "us-east-1"),
+		TableName: opts.SourceTable,
+	}
+	client, err := db.NewDynamoDBClient(ctx, config)
+	if err != nil {
+		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
+	}
+
+	if err := db.VerifyTable(ctx, client, db.ChargebackTableSchema(opts.SourceTable)); err != nil {
+		log.Fatalf("Invalid source table: %v", err)
+	}
+	target := db.SingleTableSchema(opts.TargetTable)
+	if opts.EnsureTable {
+		err = db.EnsureTable(ctx, client, target)
+	} else {
+		err = db.VerifyTable(ctx, client, target)
+	}
+	if err != nil {
+		log.Fatalf("Invalid target table: %v", err)
+	}
+
+	migrator := dynamoRepo.NewDynamoDBLayoutMigrator(client, opts.SourceTable, opts.TargetTable)
+	if err := run(ctx, migrator, newFileCheckpoint(opts.Checkpoint), opts, os.Stdout); err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+// parseFlags reads the options from the command line arguments
+func parseFlags(args []string) (Options, error) {
+	flags := flag.NewFlagSet("migrate-dynamodb", flag.ContinueOnError)
+
+	var opts Options
+	flags.StringVar(&opts.SourceTable, "source", getEnvOrDefault("DYNAMODB_TABLE", "chargebacks"), "flat table to read chargebacks from")
+	flags.StringVar(&opts.TargetTable, "target", "", "single-table layout table to write chargebacks to (required)")
+	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "file keeping the cursor of the last copied page (default <target>.checkpoint)")
+	flags.IntVar(&opts.PageSize, "page-size", 100, "chargebacks read per page")
+	flags.BoolVar(&opts.EnsureTable, "ensure-table", false, "create the target table and its indexes when they are missing")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+	if flags.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	if opts.TargetTable == "" {
+		return opts, fmt.Errorf("target table is required")
+	}
+	if opts.TargetTable == opts.SourceTable {
+		return opts, fmt.Errorf("target table must differ from the source table")
+	}
+	if opts.PageSize <= 0 {
+		return opts, fmt.Errorf("page size must be positive")
+	}
+	if opts.Checkpoint == "" {
+		opts.Checkpoint = opts.TargetTable + ".checkpoint"
+	}
+	return opts, nil
+}
+
+// run copies the chargebacks page by page from the checkpoint and prints a summary
+func run(ctx context.Context, migrator LayoutMigrator, checkpoint Checkpoint, opts Options, stdout io.Writer) error {
+	source, target, err := migrator.NewestUpdates(ctx)
+	if err != nil {
+		return err
+	}
+	if target.After(source) {
+		return fmt.Errorf("table '%s' holds chargebacks updated at %s, after the newest of '%s' (%s); it is already written to, so copying would overwrite those changes",
+			opts.TargetTable, target.Format(time.RFC3339), opts.SourceTable, source.Format(time.RFC3339))
+	}
+
+	cursor, err := checkpoint.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if cursor != "" {
+		fmt.Fprintf(stdout, "Resuming from %s\n", opts.Checkpoint)
+	}
+
+	copied := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			fmt.Fprintf(stdout, "Copied %d chargebacks; run the command again to resume\n", copied)
+			return err
+		}
+
+		n, next, err := migrator.MigratePage(ctx, cursor, opts.PageSize)
+		copied += n
+		if err != nil {
+			fmt.Fprintf(stdout, "Copied %d chargebacks; run the command again to resume\n", copied)
+			return err
+		}
+		if next == "" {
+			break
+		}
+		if err := checkpoint.Save(next); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		cursor = next
+	}
+
+	if err := checkpoint.Clear(); err != nil {
+		return fmt.Errorf("failed to clear checkpoint: %w", err)
+	}
+	fmt.Fprintf(stdout, "Copied %d chargebacks from %s to %s\n", copied, opts.SourceTable, opts.TargetTable)
+	return nil
+}
+
+// fileCheckpoint keeps the cursor of the last copied page in a file
+type fileCheckpoint struct {
+	path string
+}
+
+// newFileCheckpoint creates a checkpoint stored at path
+func newFileCheckpoint(path string) *fileCheckpoint {
+	return &fileCheckpoint{path: path}
+}
+
+// Load returns the saved cursor, "" when the file doesn't exist
+func (c *fileCheckpoint) Load() (string, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save replaces the checkpoint through a rename, so a crash never leaves it half written
+func (c *fileCheckpoint) Save(cursor string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(cursor + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Clear removes the checkpoint file
+func (c *fileCheckpoint) Clear() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// getEnvOrDefault returns environment variable value or default if not set
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
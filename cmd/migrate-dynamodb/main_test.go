@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// MockLayoutMigrator is a mock implementation of LayoutMigrator
+type MockLayoutMigrator struct {
+	MigratePageFunc   func(ctx context.Context, cursor string, limit int) (int, string, error)
+	NewestUpdatesFunc func(ctx context.Context) (time.Time, time.Time, error)
+}
+
+func (m *MockLayoutMigrator) MigratePage(ctx context.Context, cursor string, limit int) (int, string, error) {
+	return m.MigratePageFunc(ctx, cursor, limit)
+}
+
+func (m *MockLayoutMigrator) NewestUpdates(ctx context.Context) (time.Time, time.Time, error) {
+	return m.NewestUpdatesFunc(ctx)
+}
+
+// pagedMigrator copies pages of 10 chargebacks, cursors "1" to "3", failing at failAt
+func pagedMigrator(cursors *[]string, failAt string) *MockLayoutMigrator {
+	return &MockLayoutMigrator{
+		MigratePageFunc: func(ctx context.Context, cursor string, limit int) (int, string, error) {
+			*cursors = append(*cursors, cursor)
+			if cursor == failAt {
+				return 4, "", errors.New("ProvisionedThroughputExceededException")
+			}
+			switch cursor {
+			case "":
+				return 10, "1", nil
+			case "1":
+				return 10, "2", nil
+			case "2":
+				return 10, "3", nil
+			default:
+				return 5, "", nil
+			}
+		},
+		NewestUpdatesFunc: func(ctx context.Context) (time.Time, time.Time, error) {
+			source := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+			return source, source, nil
+		},
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("DYNAMODB_TABLE", "chargebacks")
+
+	tests := []struct {
+		name      string
+		args      []string
+		expected  Options
+		shouldErr bool
+	}{
+		{
+			name:     "defaults",
+			args:     []string{"-target", "chargebacks-v2"},
+			expected: Options{SourceTable: "chargebacks", TargetTable: "chargebacks-v2", Checkpoint: "chargebacks-v2.checkpoint", PageSize: 100},
+		},
+		{
+			name: "all flags",
+			args: []string{"-source", "old", "-target", "new", "-checkpoint", "/tmp/cp", "-page-size", "25", "-ensure-table"},
+			expected: Options{
+				SourceTable: "old", TargetTable: "new", Checkpoint: "/tmp/cp", PageSize: 25, EnsureTable: true,
+			},
+		},
+		{name: "missing target", args: nil, shouldErr: true},
+		{name: "target is the source", args: []string{"-target", "chargebacks"}, shouldErr: true},
+		{name: "non-positive page size", args: []string{"-target", "new", "-page-size", "0"}, shouldErr: true},
+		{name: "extra arguments", args: []string{"-target", "new", "now"}, shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			opts, err := parseFlags(tt.args)
+
+			// Assert
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, opts)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts := Options{SourceTable: "chargebacks", TargetTable: "chargebacks-v2", PageSize: 10}
+
+	t.Run("copies every page and clears the checkpoint", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		var cursors []string
+		checkpoint := newFileCheckpoint(filepath.Join(t.TempDir(), "migration.checkpoint"))
+
+		// Act
+		err := run(context.Background(), pagedMigrator(&cursors, "none"), checkpoint, opts, &stdout)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(cursors, ",") != ",1,2,3" {
+			t.Errorf("Expected pages after each cursor, got %q", cursors)
+		}
+		if stdout.String() != "Copied 35 chargebacks from chargebacks to chargebacks-v2\n" {
+			t.Errorf("Unexpected output %q", stdout.String())
+		}
+		if _, err := os.Stat(checkpoint.path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected the checkpoint to be cleared, got %v", err)
+		}
+	})
+
+	t.Run("resumes after the last copied page", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		var cursors []string
+		checkpoint := newFileCheckpoint(filepath.Join(t.TempDir(), "migration.checkpoint"))
+
+		// Act
+		errFirst := run(context.Background(), pagedMigrator(&cursors, "2"), checkpoint, opts, &stdout)
+		saved, _ := checkpoint.Load()
+		cursors = nil
+		errSecond := run(context.Background(), pagedMigrator(&cursors, "none"), checkpoint, opts, &stdout)
+
+		// Assert
+		if errFirst == nil {
+			t.Fatal("Expected the first run to fail")
+		}
+		if errSecond != nil {
+			t.Fatalf("Unexpected error: %v", errSecond)
+		}
+		if saved != "2" {
+			t.Errorf("Expected checkpoint 2 after the failure, got %q", saved)
+		}
+		if strings.Join(cursors, ",") != "2,3" {
+			t.Errorf("Expected the second run to resume at cursor 2, got %q", cursors)
+		}
+		if !strings.Contains(stdout.String(), "Copied 24 chargebacks; run the command again to resume") {
+			t.Errorf("Unexpected output %q", stdout.String())
+		}
+	})
+
+	t.Run("refuses a target updated after the source", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		var cursors []string
+		migrator := pagedMigrator(&cursors, "none")
+		migrator.NewestUpdatesFunc = func(ctx context.Context) (time.Time, time.Time, error) {
+			source := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+			return source, source.Add(time.Minute), nil
+		}
+		checkpoint := newFileCheckpoint(filepath.Join(t.TempDir(), "migration.checkpoint"))
+
+		// Act
+		err := run(context.Background(), migrator, checkpoint, opts, &stdout)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "already written to") {
+			t.Fatalf("Expected the target to be refused, got %v", err)
+		}
+		if len(cursors) != 0 {
+			t.Errorf("Expected no page to be copied, got %q", cursors)
+		}
+	})
+}
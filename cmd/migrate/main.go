@@ -0,0 +1,190 @@
+// Command migrate manages the schema of the postgres and sqlite storage backends
+//
+// Usage:
+//
+//	migrate [flags] up               apply pending migrations
+//	migrate [flags] down [-steps N]  revert the newest N applied migrations (default 1)
+//	migrate [flags] status           list migrations and when they were applied
+//	migrate [flags] unlock           release a lock left behind by a crashed run
+//
+// The backend is postgres unless STORAGE_BACKEND=sqlite or -backend says otherwise. The
+// DSN defaults to POSTGRES_DSN and the SQLite path to SQLITE_PATH. The API applies
+// pending migrations at startup unless POSTGRES_AUTO_MIGRATE=false, in which case this
+// command is run on deploy.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+)
+
+// Options holds the command line options
+type Options struct {
+	// Backend is "postgres" or "sqlite"
+	Backend string
+	DSN     string
+	// Path is the SQLite database file
+	Path    string
+	Command string
+	// Steps is how many migrations down reverts
+	Steps int
+}
+
+// SchemaMigrator interface defines the contract for applying and reverting migrations
+type SchemaMigrator interface {
+	Up(ctx context.Context) ([]db.Migration, error)
+	Down(ctx context.Context, steps int) ([]db.Migration, error)
+	Status(ctx context.Context) ([]db.MigrationStatus, error)
+	Unlock(ctx context.Context) error
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var sqlDB *sql.DB
+	loadMigrations := db.PostgresMigrations
+	if opts.Backend == "sqlite" {
+		sqlDB, err = db.NewSQLiteDB(ctx, db.SQLiteConfig{Path: opts.Path})
+		loadMigrations = db.SQLiteMigrations
+	} else {
+		sqlDB, err = db.NewPostgresDB(ctx, db.PostgresConfig{DSN: opts.DSN})
+	}
+	if err != nil {
+		log.Fatalf("Failed to connect: %v", err)
+	}
+	defer sqlDB.Close()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if err := run(ctx, db.NewMigrator(sqlDB, migrations), opts, os.Stdout); err != nil {
+		sqlDB.Close()
+		log.Fatalf("Migration failed: %v", err)
+	}
+}
+
+// parseFlags reads the options from the command line arguments
+func parseFlags(args []string) (Options, error) {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+
+	defaultBackend := "postgres"
+	if os.Getenv("STORAGE_BACKEND") == "sqlite" {
+		defaultBackend = "sqlite"
+	}
+
+	var opts Options
+	flags.StringVar(&opts.Backend, "backend", defaultBackend, "postgres or sqlite")
+	flags.StringVar(&opts.DSN, "dsn", os.Getenv("POSTGRES_DSN"), "PostgreSQL connection URL")
+	flags.StringVar(&opts.Path, "path", getEnvOrDefault("SQLITE_PATH", "chargebacks.db"), "SQLite database file")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+	if flags.NArg() == 0 {
+		return opts, fmt.Errorf("command is required: up, down, status or unlock")
+	}
+	opts.Command = flags.Arg(0)
+
+	commandFlags := flag.NewFlagSet("migrate "+opts.Command, flag.ContinueOnError)
+	switch opts.Command {
+	case "down":
+		commandFlags.IntVar(&opts.Steps, "steps", 1, "number of migrations to revert")
+	case "up", "status", "unlock":
+	default:
+		return opts, fmt.Errorf("unknown command %q, expected up, down, status or unlock", opts.Command)
+	}
+	if err := commandFlags.Parse(flags.Args()[1:]); err != nil {
+		return opts, err
+	}
+	if commandFlags.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected arguments: %v", commandFlags.Args())
+	}
+
+	switch opts.Backend {
+	case "postgres":
+		if opts.DSN == "" {
+			return opts, fmt.Errorf("DSN is required, set -dsn or POSTGRES_DSN")
+		}
+	case "sqlite":
+		if opts.Path == "" {
+			return opts, fmt.Errorf("SQLite path is required")
+		}
+	default:
+		return opts, fmt.Errorf("backend must be 'postgres' or 'sqlite', got '%s'", opts.Backend)
+	}
+	if opts.Command == "down" && opts.Steps <= 0 {
+		return opts, fmt.Errorf("steps must be positive, got %d", opts.Steps)
+	}
+	return opts, nil
+}
+
+// run executes the command and prints what it did
+func run(ctx context.Context, migrator SchemaMigrator, opts Options, stdout io.Writer) error {
+	switch opts.Command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(stdout, "Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(stdout, "Schema is up to date")
+		}
+		return err
+	case "down":
+		reverted, err := migrator.Down(ctx, opts.Steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(stdout, "Reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(stdout, "No migrations to revert")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = "applied " + status.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(stdout, "%04d_%s  %s\n", status.Version, status.Name, appliedAt)
+		}
+		return nil
+	case "unlock":
+		if err := migrator.Unlock(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "Migration lock released")
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", opts.Command)
+	}
+}
+
+// getEnvOrDefault returns environment variable value or default if not set
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
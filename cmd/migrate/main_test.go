@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+)
+
+// MockSchemaMigrator is a mock implementation of SchemaMigrator
+type MockSchemaMigrator struct {
+	UpFunc     func(ctx context.Context) ([]db.Migration, error)
+	DownFunc   func(ctx context.Context, steps int) ([]db.Migration, error)
+	StatusFunc func(ctx context.Context) ([]db.MigrationStatus, error)
+	UnlockFunc func(ctx context.Context) error
+}
+
+func (m *MockSchemaMigrator) Up(ctx context.Context) ([]db.Migration, error) {
+	return m.UpFunc(ctx)
+}
+
+func (m *MockSchemaMigrator) Down(ctx context.Context, steps int) ([]db.Migration, error) {
+	return m.DownFunc(ctx, steps)
+}
+
+func (m *MockSchemaMigrator) Status(ctx context.Context) ([]db.MigrationStatus, error) {
+	return m.StatusFunc(ctx)
+}
+
+func (m *MockSchemaMigrator) Unlock(ctx context.Context) error {
+	return m.UnlockFunc(ctx)
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("POSTGRES_DSN", "postgres://localhost/chargebacks")
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectedCommand string
+		expectedSteps   int
+		expectedDSN     string
+		shouldErr       bool
+	}{
+		{name: "up", args: []string{"up"}, expectedCommand: "up", expectedDSN: "postgres://localhost/chargebacks"},
+		{name: "down defaults to one step", args: []string{"down"}, expectedCommand: "down", expectedSteps: 1, expectedDSN: "postgres://localhost/chargebacks"},
+		{name: "down with steps", args: []string{"down", "-steps", "3"}, expectedCommand: "down", expectedSteps: 3, expectedDSN: "postgres://localhost/chargebacks"},
+		{name: "dsn flag", args: []string{"-dsn", "postgres://db/other", "status"}, expectedCommand: "status", expectedDSN: "postgres://db/other"},
+		{name: "missing command", args: nil, shouldErr: true},
+		{name: "unknown command", args: []string{"redo"}, shouldErr: true},
+		{name: "steps on up", args: []string{"up", "-steps", "2"}, shouldErr: true},
+		{name: "non-positive steps", args: []string{"down", "-steps", "0"}, shouldErr: true},
+		{name: "extra arguments", args: []string{"unlock", "now"}, shouldErr: true},
+		{name: "unknown backend", args: []string{"-backend", "mysql", "up"}, shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			opts, err := parseFlags(tt.args)
+
+			// Assert
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.Command != tt.expectedCommand {
+				t.Errorf("Expected command %q, got %q", tt.expectedCommand, opts.Command)
+			}
+			if opts.Steps != tt.expectedSteps {
+				t.Errorf("Expected steps %d, got %d", tt.expectedSteps, opts.Steps)
+			}
+			if opts.DSN != tt.expectedDSN {
+				t.Errorf("Expected DSN %q, got %q", tt.expectedDSN, opts.DSN)
+			}
+		})
+	}
+
+	t.Run("sqlite backend", func(t *testing.T) {
+		t.Setenv("STORAGE_BACKEND", "sqlite")
+		t.Setenv("SQLITE_PATH", "/var/lib/chargebacks/chargebacks.db")
+
+		opts, err := parseFlags([]string{"status"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if opts.Backend != "sqlite" || opts.Path != "/var/lib/chargebacks/chargebacks.db" {
+			t.Errorf("Expected sqlite backend at SQLITE_PATH, got %+v", opts)
+		}
+	})
+
+	t.Run("missing DSN", func(t *testing.T) {
+		t.Setenv("POSTGRES_DSN", "")
+		if _, err := parseFlags([]string{"up"}); err == nil {
+			t.Error("Expected error but got none")
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	migrations := []db.Migration{
+		{Version: 1, Name: "create_chargebacks"},
+		{Version: 2, Name: "add_index"},
+	}
+
+	t.Run("up prints applied migrations", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		migrator := &MockSchemaMigrator{
+			UpFunc: func(ctx context.Context) ([]db.Migration, error) { return migrations, nil },
+		}
+
+		// Act
+		err := run(context.Background(), migrator, Options{Command: "up"}, &stdout)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := "Applied 0001_create_chargebacks\nApplied 0002_add_index\n"; stdout.String() != want {
+			t.Errorf("Expected output %q, got %q", want, stdout.String())
+		}
+	})
+
+	t.Run("up reports an up to date schema", func(t *testing.T) {
+		var stdout bytes.Buffer
+		migrator := &MockSchemaMigrator{
+			UpFunc: func(ctx context.Context) ([]db.Migration, error) { return nil, nil },
+		}
+
+		if err := run(context.Background(), migrator, Options{Command: "up"}, &stdout); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stdout.String() != "Schema is up to date\n" {
+			t.Errorf("Unexpected output %q", stdout.String())
+		}
+	})
+
+	t.Run("down passes the steps and prints what was applied before an error", func(t *testing.T) {
+		var stdout bytes.Buffer
+		migrator := &MockSchemaMigrator{
+			DownFunc: func(ctx context.Context, steps int) ([]db.Migration, error) {
+				if steps != 2 {
+					t.Errorf("Expected 2 steps, got %d", steps)
+				}
+				return migrations[1:], errors.New("migration 1_create_chargebacks can't be reverted")
+			},
+		}
+
+		err := run(context.Background(), migrator, Options{Command: "down", Steps: 2}, &stdout)
+
+		if err == nil {
+			t.Fatal("Expected error but got none")
+		}
+		if stdout.String() != "Reverted 0002_add_index\n" {
+			t.Errorf("Unexpected output %q", stdout.String())
+		}
+	})
+
+	t.Run("status lists pending and applied migrations", func(t *testing.T) {
+		var stdout bytes.Buffer
+		appliedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		migrator := &MockSchemaMigrator{
+			StatusFunc: func(ctx context.Context) ([]db.MigrationStatus, error) {
+				return []db.MigrationStatus{
+					{Migration: migrations[0], AppliedAt: appliedAt},
+					{Migration: migrations[1]},
+				}, nil
+			},
+		}
+
+		if err := run(context.Background(), migrator, Options{Command: "status"}, &stdout); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		output := stdout.String()
+		if !strings.Contains(output, "0001_create_chargebacks  applied 2024-03-01T12:00:00Z") ||
+			!strings.Contains(output, "0002_add_index  pending") {
+			t.Errorf("Unexpected output %q", output)
+		}
+	})
+
+	t.Run("unlock releases the lock", func(t *testing.T) {
+		var stdout bytes.Buffer
+		unlocked := false
+		migrator := &MockSchemaMigrator{
+			UnlockFunc: func(ctx context.Context) error { unlocked = true; return nil },
+		}
+
+		if err := run(context.Background(), migrator, Options{Command: "unlock"}, &stdout); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !unlocked {
+			t.Error("Expected the lock to be released")
+		}
+	})
+}
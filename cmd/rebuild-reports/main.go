@@ -0,0 +1,105 @@
+// Command rebuild-reports recomputes the report counters from the chargebacks table
+//
+// The API keeps the counters behind GET /reports/summary up to date on every write.
+// Running this command replaces them with counts computed from every chargeback, which
+// fills them in for chargebacks written before reports were enabled and repairs
+// counters that missed writes. Chargebacks should not be written while it runs.
+//
+// The chargebacks and the counters are opened from the same settings as the API's.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/logging"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Options holds the command line options
+type Options struct {
+	PageSize int
+}
+
+// RebuildReportCountersUseCase interface defines the contract for rebuilding the report counters
+type RebuildReportCountersUseCase interface {
+	Execute(ctx context.Context) (*usecase.RebuildReportCountersResult, error)
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid options: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger, err := logging.NewStructuredLogger(logging.LoggerConfig{
+		Level:       service.LogLevelWarn,
+		Format:      logging.FormatJSON,
+		ServiceName: "chargeback-rebuild-reports",
+	}, os.Stderr)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	stores, err := openStores(ctx, bootstrap.LoadStorageConfig(), logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize stores: %v", err)
+	}
+	defer stores.Close()
+
+	if err := run(ctx, usecase.NewRebuildReportCountersUseCase(stores.Chargebacks, stores.ReportCounters, opts.PageSize), os.Stdout); err != nil {
+		log.Fatalf("Rebuild failed: %v", err)
+	}
+}
+
+// openStores opens the chargebacks and the report counters configured like the API's
+// Counters kept in memory are refused: they live in each API instance, out of the
+// command's reach.
+func openStores(ctx context.Context, config bootstrap.StorageConfig, logger service.Logger) (*bootstrap.Stores, error) {
+	config.Reports.Enabled = true
+	if config.StorageBackend == "memory" || config.Reports.Store == "memory" {
+		return nil, fmt.Errorf("storage backend and reports store must not be 'memory', got '%s' and '%s'", config.StorageBackend, config.Reports.Store)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return bootstrap.OpenStores(ctx, config, logger)
+}
+
+// parseFlags reads the options from the command line arguments
+func parseFlags(args []string) (Options, error) {
+	flags := flag.NewFlagSet("rebuild-reports", flag.ContinueOnError)
+
+	var opts Options
+	flags.IntVar(&opts.PageSize, "page-size", 500, "number of chargebacks read at a time")
+
+	if err := flags.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if opts.PageSize <= 0 {
+		return opts, fmt.Errorf("page size must be positive, got %d", opts.PageSize)
+	}
+	return opts, nil
+}
+
+// run rebuilds the counters and prints a summary
+func run(ctx context.Context, rebuildUC RebuildReportCountersUseCase, stdout io.Writer) error {
+	result, err := rebuildUC.Execute(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Rebuilt %d report counters from %d chargebacks\n", result.Counters, result.Chargebacks)
+	return nil
+}
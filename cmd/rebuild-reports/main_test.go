@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/bootstrap"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockRebuildReportCountersUseCase is a mock implementation of RebuildReportCountersUseCase
+type MockRebuildReportCountersUseCase struct {
+	ExecuteFunc func(ctx context.Context) (*usecase.RebuildReportCountersResult, error)
+}
+
+func (m *MockRebuildReportCountersUseCase) Execute(ctx context.Context) (*usecase.RebuildReportCountersResult, error) {
+	return m.ExecuteFunc(ctx)
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		expectedPageSize int
+		shouldErr        bool
+	}{
+		{name: "defaults", args: nil, expectedPageSize: 500},
+		{name: "page size", args: []string{"-page-size", "100"}, expectedPageSize: 100},
+		{name: "non-positive page size", args: []string{"-page-size", "0"}, shouldErr: true},
+		{name: "unknown flag", args: []string{"-table", "x"}, shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			opts, err := parseFlags(tt.args)
+
+			// Assert
+			if tt.shouldErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if opts.PageSize != tt.expectedPageSize {
+				t.Errorf("Expected page size %d, got %d", tt.expectedPageSize, opts.PageSize)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("prints a summary", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		rebuildUC := &MockRebuildReportCountersUseCase{
+			ExecuteFunc: func(ctx context.Context) (*usecase.RebuildReportCountersResult, error) {
+				return &usecase.RebuildReportCountersResult{Chargebacks: 1200, Counters: 85}, nil
+			},
+		}
+
+		// Act
+		err := run(context.Background(), rebuildUC, &stdout)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := "Rebuilt 85 report counters from 1200 chargebacks\n"; stdout.String() != expected {
+			t.Errorf("Expected output %q, got %q", expected, stdout.String())
+		}
+	})
+
+	t.Run("returns use case errors", func(t *testing.T) {
+		// Arrange
+		var stdout bytes.Buffer
+		rebuildUC := &MockRebuildReportCountersUseCase{
+			ExecuteFunc: func(ctx context.Context) (*usecase.RebuildReportCountersResult, error) {
+				return nil, errors.New("failed to list chargebacks: DynamoDB unavailable")
+			},
+		}
+
+		// Act
+		err := run(context.Background(), rebuildUC, &stdout)
+
+		// Assert
+		if err == nil || stdout.Len() != 0 {
+			t.Fatalf("Expected error and no output, got %v and %q", err, stdout.String())
+		}
+	})
+}
+
+func TestRun_SQLite(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "chargebacks.db"))
+	t.Setenv("REPORTS_ENABLED", "false")
+	ctx := context.Background()
+	stores, err := openStores(ctx, bootstrap.LoadStorageConfig(), &testLogger{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer stores.Close()
+
+	chargebackDate := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	if err := stores.Chargebacks.Save(ctx, &entity.Chargeback{
+		ID:             "cb_1",
+		TransactionID:  "txn_1",
+		MerchantID:     "merchant_1",
+		Amount:         120.5,
+		Currency:       "USD",
+		Reason:         entity.ReasonFraud,
+		Status:         entity.StatusPending,
+		ChargebackDate: chargebackDate,
+		CreatedAt:      chargebackDate,
+		UpdatedAt:      chargebackDate,
+	}); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	if err := stores.ReportCounters.ReplaceAll(ctx, nil); err != nil {
+		t.Fatalf("Failed to clear counters: %v", err)
+	}
+	var stdout bytes.Buffer
+
+	// Act
+	err = run(ctx, usecase.NewRebuildReportCountersUseCase(stores.Chargebacks, stores.ReportCounters, 10), &stdout)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	day := chargebackDate.Format(entity.ReportDayLayout)
+	counters, err := stores.ReportCounters.List(ctx, day, day)
+	if err != nil || len(counters) != 1 || counters[0].Count != 1 {
+		t.Errorf("Expected the counters to be rebuilt in the sqlite database, got %+v, %v", counters, err)
+	}
+}
+
+func TestOpenStores_MemoryStores(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		store   string
+	}{
+		{name: "memory backend", backend: "memory", store: "dynamodb"},
+		{name: "memory reports store", backend: "dynamodb", store: "memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := bootstrap.StorageConfig{
+				StorageBackend: tt.backend,
+				DynamoDB:       db.DynamoDBConfig{Region: "us-east-1", TableName: "chargebacks"},
+				Reports:        bootstrap.ReportsConfig{Store: tt.store, Table: "report_counters"},
+			}
+			if _, err := openStores(context.Background(), config, &testLogger{}); err == nil {
+				t.Error("Expected error but got none")
+			}
+		})
+	}
+}
+
+// testLogger discards log entries
+type testLogger struct{}
+
+func (l *testLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *testLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) WithContext(ctx context.Context) service.Logger { return l }
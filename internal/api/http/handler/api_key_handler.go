@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// IssueAPIKeyUseCase interface defines the contract for issuing API keys
+type IssueAPIKeyUseCase interface {
+	Execute(ctx context.Context, req usecase.IssueAPIKeyRequest) (*usecase.APIKeyResponse, error)
+}
+
+// ManageAPIKeyUseCase interface defines the contract for operations on an existing API key
+type ManageAPIKeyUseCase interface {
+	Execute(ctx context.Context, id string) (*usecase.APIKeyResponse, error)
+}
+
+// APIKeyHandler handles HTTP requests for API key administration
+type APIKeyHandler struct {
+	issueAPIKeyUC  IssueAPIKeyUseCase
+	rotateAPIKeyUC ManageAPIKeyUseCase
+	revokeAPIKeyUC ManageAPIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(issueAPIKeyUC IssueAPIKeyUseCase, rotateAPIKeyUC, revokeAPIKeyUC ManageAPIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{
+		issueAPIKeyUC:  issueAPIKeyUC,
+		rotateAPIKeyUC: rotateAPIKeyUC,
+		revokeAPIKeyUC: revokeAPIKeyUC,
+	}
+}
+
+// IssueAPIKeyRequest represents the HTTP request body for issuing an API key
+type IssueAPIKeyRequest struct {
+	Name        string   `json:"name"`
+	MerchantIDs []string `json:"merchant_ids,omitempty"`
+	Scopes      []string `json:"scopes"`
+}
+
+// IssueAPIKey handles POST /admin/api-keys
+func (h *APIKeyHandler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{Error: "Content-Type must be application/json"})
+		return
+	}
+
+	var req IssueAPIKeyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	scopes := make([]auth.Scope, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+
+	response, err := h.issueAPIKeyUC.Execute(r.Context(), usecase.IssueAPIKeyRequest{
+		Name:        req.Name,
+		MerchantIDs: req.MerchantIDs,
+		Scopes:      scopes,
+	})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// RotateAPIKey handles POST /admin/api-keys/{id}/rotate
+func (h *APIKeyHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	response, err := h.rotateAPIKeyUC.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/{id}
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	response, err := h.revokeAPIKeyUC.Execute(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleUseCaseError maps API key use case errors to HTTP status codes
+func (h *APIKeyHandler) handleUseCaseError(w http.ResponseWriter, err error) {
+	errorMessage := err.Error()
+
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: errorMessage})
+	case errors.Is(err, usecase.ErrAPIKeyNotFound):
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: errorMessage})
+	case strings.Contains(errorMessage, "validation errors"):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: errorMessage})
+	case strings.Contains(errorMessage, "already revoked"), strings.Contains(errorMessage, "only active"):
+		writeJSON(w, http.StatusConflict, ErrorResponse{Error: errorMessage})
+	default:
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: errorMessage})
+	}
+}
@@ -0,0 +1,166 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockIssueAPIKeyUseCase is a mock implementation of IssueAPIKeyUseCase
+type MockIssueAPIKeyUseCase struct {
+	ExecuteFunc func(ctx context.Context, req usecase.IssueAPIKeyRequest) (*usecase.APIKeyResponse, error)
+}
+
+func (m *MockIssueAPIKeyUseCase) Execute(ctx context.Context, req usecase.IssueAPIKeyRequest) (*usecase.APIKeyResponse, error) {
+	return m.ExecuteFunc(ctx, req)
+}
+
+// MockManageAPIKeyUseCase is a mock implementation of ManageAPIKeyUseCase
+type MockManageAPIKeyUseCase struct {
+	ExecuteFunc func(ctx context.Context, id string) (*usecase.APIKeyResponse, error)
+}
+
+func (m *MockManageAPIKeyUseCase) Execute(ctx context.Context, id string) (*usecase.APIKeyResponse, error) {
+	return m.ExecuteFunc(ctx, id)
+}
+
+func TestAPIKeyHandler_IssueAPIKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		contentType  string
+		body         string
+		useCaseErr   error
+		expectedCode int
+	}{
+		{
+			name:         "issues key",
+			method:       http.MethodPost,
+			contentType:  "application/json",
+			body:         `{"name":"acme","merchant_ids":["merchant-789"],"scopes":["chargebacks:write"]}`,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "wrong method",
+			method:       http.MethodGet,
+			contentType:  "application/json",
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "wrong content type",
+			method:       http.MethodPost,
+			contentType:  "text/plain",
+			body:         `{}`,
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "invalid JSON",
+			method:       http.MethodPost,
+			contentType:  "application/json",
+			body:         `{`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "validation error",
+			method:       http.MethodPost,
+			contentType:  "application/json",
+			body:         `{"scopes":["chargebacks:write"]}`,
+			useCaseErr:   fmt.Errorf("failed to create API key entity: validation errors: name is required"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "forbidden",
+			method:       http.MethodPost,
+			contentType:  "application/json",
+			body:         `{"name":"acme","scopes":["admin"]}`,
+			useCaseErr:   fmt.Errorf("%w: missing scope admin", auth.ErrForbidden),
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issueUC := &MockIssueAPIKeyUseCase{
+				ExecuteFunc: func(ctx context.Context, req usecase.IssueAPIKeyRequest) (*usecase.APIKeyResponse, error) {
+					if tt.useCaseErr != nil {
+						return nil, tt.useCaseErr
+					}
+					if len(req.Scopes) != 1 || req.Scopes[0] != auth.ScopeChargebacksWrite {
+						t.Errorf("Expected scope chargebacks:write, got %v", req.Scopes)
+					}
+					return &usecase.APIKeyResponse{ID: "key-1", Key: "cbk_key-1_secret", Status: entity.APIKeyStatusActive}, nil
+				},
+			}
+			h := handler.NewAPIKeyHandler(issueUC, nil, nil)
+
+			req := httptest.NewRequest(tt.method, "/admin/api-keys", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+
+			h.IssueAPIKey(recorder, req)
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+
+			if tt.expectedCode == http.StatusCreated {
+				var response usecase.APIKeyResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Key == "" {
+					t.Error("Expected raw key in response")
+				}
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_RotateAndRevoke(t *testing.T) {
+	manageUC := &MockManageAPIKeyUseCase{
+		ExecuteFunc: func(ctx context.Context, id string) (*usecase.APIKeyResponse, error) {
+			if id == "missing" {
+				return nil, fmt.Errorf("%w: %s", usecase.ErrAPIKeyNotFound, id)
+			}
+			return &usecase.APIKeyResponse{ID: id}, nil
+		},
+	}
+	h := handler.NewAPIKeyHandler(nil, manageUC, manageUC)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/api-keys/{id}", h.RevokeAPIKey)
+	mux.HandleFunc("/admin/api-keys/{id}/rotate", h.RotateAPIKey)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"rotate", http.MethodPost, "/admin/api-keys/key-1/rotate", http.StatusOK},
+		{"rotate wrong method", http.MethodGet, "/admin/api-keys/key-1/rotate", http.StatusMethodNotAllowed},
+		{"revoke", http.MethodDelete, "/admin/api-keys/key-1", http.StatusOK},
+		{"revoke missing", http.MethodDelete, "/admin/api-keys/missing", http.StatusNotFound},
+		{"revoke wrong method", http.MethodPost, "/admin/api-keys/key-1", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+		})
+	}
+}
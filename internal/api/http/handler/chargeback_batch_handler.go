@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MaxBatchRequestBodyBytes is the largest batch request body the API accepts
+const MaxBatchRequestBodyBytes = 10 << 20
+
+// BatchCreateChargebacksUseCase interface defines the contract for creating chargebacks in bulk
+type BatchCreateChargebacksUseCase interface {
+	Execute(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error)
+}
+
+// ChargebackBatchHandler handles HTTP requests that create many chargebacks at once
+type ChargebackBatchHandler struct {
+	batchCreateUC BatchCreateChargebacksUseCase
+	maxItems      int
+}
+
+// NewChargebackBatchHandler creates a new chargeback batch handler
+// Batches with more than maxItems items, valid or not, are rejected as a whole
+func NewChargebackBatchHandler(batchCreateUC BatchCreateChargebacksUseCase, maxItems int) *ChargebackBatchHandler {
+	return &ChargebackBatchHandler{
+		batchCreateUC: batchCreateUC,
+		maxItems:      maxItems,
+	}
+}
+
+// BatchChargebackResponse is the multi-status response of a batch create
+type BatchChargebackResponse struct {
+	Summary BatchSummary            `json:"summary"`
+	Results []BatchChargebackResult `json:"results"`
+}
+
+// BatchSummary counts the batch items by outcome
+type BatchSummary struct {
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Duplicate int `json:"duplicate"`
+	Invalid   int `json:"invalid"`
+	Failed    int `json:"failed"`
+}
+
+// BatchChargebackResult is the outcome of the batch item at Index
+type BatchChargebackResult struct {
+	Index         int                               `json:"index"`
+	Status        usecase.BatchItemStatus           `json:"status"`
+	TransactionID string                            `json:"transaction_id,omitempty"`
+	Chargeback    *usecase.CreateChargebackResponse `json:"chargeback,omitempty"`
+	Error         string                            `json:"error,omitempty"`
+	Fields        []FieldError                      `json:"fields,omitempty"`
+}
+
+// CreateChargebacks handles POST /chargebacks/batch
+// The body is a JSON array of chargebacks, or one chargeback per line with
+// Content-Type application/x-ndjson; every item gets a result, so the response is
+// 207 Multi-Status whenever the batch itself is accepted
+func (h *ChargebackBatchHandler) CreateChargebacks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	ndjson := strings.Contains(contentType, "application/x-ndjson")
+	if !ndjson && !strings.Contains(contentType, "application/json") {
+		writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{
+			Error: "Content-Type must be application/json or application/x-ndjson",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBatchRequestBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error: fmt.Sprintf("Request body must not exceed %d bytes", MaxBatchRequestBodyBytes),
+			})
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "Failed to read request body"})
+		return
+	}
+
+	// Split the body into items; each item is decoded on its own so one bad item
+	// doesn't reject the others
+	var items []json.RawMessage
+	if ndjson {
+		items = splitLines(body)
+	} else if err := json.Unmarshal(body, &items); err != nil {
+		writeJSON(w, http.StatusBadRequest, decodeError(err))
+		return
+	}
+
+	if len(items) == 0 {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "Batch must contain at least one chargeback"})
+		return
+	}
+	if len(items) > h.maxItems {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: fmt.Sprintf("Batch must not contain more than %d chargebacks", h.maxItems),
+		})
+		return
+	}
+
+	results := make([]BatchChargebackResult, len(items))
+	useCaseReqs := make([]usecase.CreateChargebackRequest, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		results[i] = BatchChargebackResult{Index: i}
+
+		var req CreateChargebackRequest
+		if errResp := decodeStrict(item, &req); errResp != nil {
+			results[i].Status = usecase.BatchItemInvalid
+			results[i].Error = errResp.Error
+			results[i].Fields = errResp.Fields
+			continue
+		}
+		results[i].TransactionID = req.TransactionID
+
+		useCaseReq, err := req.toUseCaseRequest()
+		if err != nil {
+			results[i].Status = usecase.BatchItemInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+
+		useCaseReqs = append(useCaseReqs, useCaseReq)
+		positions = append(positions, i)
+	}
+
+	if len(useCaseReqs) > 0 {
+		itemResults, err := h.batchCreateUC.Execute(r.Context(), useCaseReqs)
+		if err != nil {
+			switch {
+			case errors.Is(err, auth.ErrForbidden):
+				writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+			case errors.Is(err, usecase.ErrBatchTooLarge):
+				writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{Error: err.Error()})
+			default:
+				writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			}
+			return
+		}
+
+		for j, itemResult := range itemResults {
+			result := &results[positions[j]]
+			result.Status = itemResult.Status
+			result.Chargeback = itemResult.Chargeback
+			result.Error = itemResult.Error
+		}
+	}
+
+	writeJSON(w, http.StatusMultiStatus, BatchChargebackResponse{
+		Summary: summarize(results),
+		Results: results,
+	})
+}
+
+// splitLines returns the non-blank lines of an NDJSON body
+func splitLines(body []byte) []json.RawMessage {
+	var lines []json.RawMessage
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			lines = append(lines, json.RawMessage(line))
+		}
+	}
+	return lines
+}
+
+// summarize counts the results by status
+func summarize(results []BatchChargebackResult) BatchSummary {
+	summary := BatchSummary{Total: len(results)}
+	for _, result := range results {
+		switch result.Status {
+		case usecase.BatchItemCreated:
+			summary.Created++
+		case usecase.BatchItemDuplicate:
+			summary.Duplicate++
+		case usecase.BatchItemInvalid:
+			summary.Invalid++
+		case usecase.BatchItemFailed:
+			summary.Failed++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,194 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockBatchCreateChargebacksUseCase is a mock implementation of BatchCreateChargebacksUseCase
+type MockBatchCreateChargebacksUseCase struct {
+	ExecuteFunc func(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error)
+}
+
+func (m *MockBatchCreateChargebacksUseCase) Execute(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error) {
+	if m.ExecuteFunc != nil {
+		return m.ExecuteFunc(ctx, reqs)
+	}
+	results := make([]usecase.BatchItemResult, len(reqs))
+	for i, req := range reqs {
+		results[i] = usecase.BatchItemResult{
+			Status:     usecase.BatchItemCreated,
+			Chargeback: &usecase.CreateChargebackResponse{ID: "cb_" + req.TransactionID, TransactionID: req.TransactionID},
+		}
+	}
+	return results, nil
+}
+
+func batchItem(transactionID string) string {
+	return fmt.Sprintf(`{"transaction_id": "%s", "merchant_id": "merchant-789", "amount": 150.75, "currency": "USD",`+
+		` "card_number": "4111111111111111", "reason": "fraud", "transaction_date": "2023-10-01T10:00:00Z"}`, transactionID)
+}
+
+func TestChargebackBatchHandler_CreateChargebacks(t *testing.T) {
+	tests := []struct {
+		name             string
+		contentType      string
+		body             string
+		executeFunc      func(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error)
+		expectedStatus   int
+		expectedError    string
+		expectedSummary  handler.BatchSummary
+		expectedStatuses []usecase.BatchItemStatus
+	}{
+		{
+			name:             "JSON array with invalid items",
+			contentType:      "application/json",
+			body:             `[` + batchItem("tx-1") + `, {"transaction_id": "tx-2", "extra": 1}, ` + strings.Replace(batchItem("tx-3"), "fraud", "unknown", 1) + `]`,
+			expectedStatus:   http.StatusMultiStatus,
+			expectedSummary:  handler.BatchSummary{Total: 3, Created: 1, Invalid: 2},
+			expectedStatuses: []usecase.BatchItemStatus{usecase.BatchItemCreated, usecase.BatchItemInvalid, usecase.BatchItemInvalid},
+		},
+		{
+			name:             "NDJSON stream",
+			contentType:      "application/x-ndjson",
+			body:             batchItem("tx-1") + "\n\n" + batchItem("tx-2") + "\r\n",
+			expectedStatus:   http.StatusMultiStatus,
+			expectedSummary:  handler.BatchSummary{Total: 2, Created: 2},
+			expectedStatuses: []usecase.BatchItemStatus{usecase.BatchItemCreated, usecase.BatchItemCreated},
+		},
+		{
+			name:        "use case outcomes are merged by position",
+			contentType: "application/json",
+			body:        `[` + batchItem("tx-1") + `, {}, ` + batchItem("tx-1") + `]`,
+			executeFunc: func(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error) {
+				return []usecase.BatchItemResult{
+					{Status: usecase.BatchItemCreated, Chargeback: &usecase.CreateChargebackResponse{ID: "cb_1"}},
+					{Status: usecase.BatchItemDuplicate, Error: "transaction tx-1 appears more than once in the batch"},
+				}, nil
+			},
+			expectedStatus:   http.StatusMultiStatus,
+			expectedSummary:  handler.BatchSummary{Total: 3, Created: 1, Duplicate: 1, Invalid: 1},
+			expectedStatuses: []usecase.BatchItemStatus{usecase.BatchItemCreated, usecase.BatchItemInvalid, usecase.BatchItemDuplicate},
+		},
+		{
+			name:           "too many items",
+			contentType:    "application/json",
+			body:           `[{}, {}, {}, {}]`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "Batch must not contain more than 3 chargebacks",
+		},
+		{
+			name:           "empty batch",
+			contentType:    "application/json",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Batch must contain at least one chargeback",
+		},
+		{
+			name:           "not an array",
+			contentType:    "application/json",
+			body:           batchItem("tx-1"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+		},
+		{
+			name:        "forbidden",
+			contentType: "application/json",
+			body:        `[` + batchItem("tx-1") + `]`,
+			executeFunc: func(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error) {
+				return nil, fmt.Errorf("%w: missing scope chargebacks:write", auth.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "forbidden: missing scope chargebacks:write",
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "text/csv",
+			body:           "transaction_id\ntx-1",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedError:  "Content-Type must be application/json or application/x-ndjson",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := handler.NewChargebackBatchHandler(&MockBatchCreateChargebacksUseCase{ExecuteFunc: tt.executeFunc}, 3)
+			req := httptest.NewRequest(http.MethodPost, "/chargebacks/batch", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			h.CreateChargebacks(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			if tt.expectedStatus != http.StatusMultiStatus {
+				var response handler.ErrorResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error != tt.expectedError {
+					t.Errorf("Expected error '%s', got '%s'", tt.expectedError, response.Error)
+				}
+				return
+			}
+
+			var response handler.BatchChargebackResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Summary != tt.expectedSummary {
+				t.Errorf("Expected summary %+v, got %+v", tt.expectedSummary, response.Summary)
+			}
+			if len(response.Results) != len(tt.expectedStatuses) {
+				t.Fatalf("Expected %d results, got %d", len(tt.expectedStatuses), len(response.Results))
+			}
+			for i, status := range tt.expectedStatuses {
+				result := response.Results[i]
+				if result.Index != i || result.Status != status {
+					t.Errorf("Expected result %d to be %s, got index %d status %s", i, status, result.Index, result.Status)
+				}
+				if status == usecase.BatchItemInvalid && result.Error == "" {
+					t.Errorf("Expected result %d to explain why it is invalid", i)
+				}
+				if status == usecase.BatchItemCreated && result.Chargeback == nil {
+					t.Errorf("Expected result %d to include the created chargeback", i)
+				}
+			}
+		})
+	}
+}
+
+func TestChargebackBatchHandler_CreateChargebacks_FieldErrors(t *testing.T) {
+	// Arrange
+	h := handler.NewChargebackBatchHandler(&MockBatchCreateChargebacksUseCase{}, 10)
+	req := httptest.NewRequest(http.MethodPost, "/chargebacks/batch", strings.NewReader(`[{"transaction_id": "tx-1", "amount": "1"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	h.CreateChargebacks(recorder, req)
+
+	// Assert
+	var response handler.BatchChargebackResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	fields := response.Results[0].Fields
+	if len(fields) != 1 || fields[0] != (handler.FieldError{Field: "amount", Message: "must be a number"}) {
+		t.Errorf("Expected amount field error, got %+v", fields)
+	}
+}
@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Media types GET /chargebacks/export can respond with
+const (
+	ContentTypeCSV     = "text/csv"
+	ContentTypeNDJSON  = "application/x-ndjson"
+	ContentTypeParquet = "application/vnd.apache.parquet"
+)
+
+const (
+	// exportFlushRows is how many rows are buffered before they are sent to the client
+	exportFlushRows = 1000
+
+	// exportRowGroupSize bounds the rows a Parquet export holds in memory
+	exportRowGroupSize = 10000
+
+	// exportWriteTimeout is how long sending a batch of rows may take; it is renewed
+	// after every batch, so large exports aren't cut off by the server's write timeout
+	exportWriteTimeout = 30 * time.Second
+)
+
+// ExportChargebacksUseCase interface defines the contract for streaming chargebacks
+type ExportChargebacksUseCase interface {
+	Execute(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error
+}
+
+// ChargebackExportHandler handles HTTP requests that export chargebacks
+type ChargebackExportHandler struct {
+	exportUC ExportChargebacksUseCase
+}
+
+// NewChargebackExportHandler creates a new chargeback export handler
+func NewChargebackExportHandler(exportUC ExportChargebacksUseCase) *ChargebackExportHandler {
+	return &ChargebackExportHandler{
+		exportUC: exportUC,
+	}
+}
+
+// ExportChargebacks handles GET /chargebacks/export
+// The format is negotiated from the Accept header: CSV, newline-delimited JSON or Parquet,
+// CSV being the default. Chargebacks are filtered with the merchant_id, status, reason,
+// created_from and created_to query parameters.
+func (h *ChargebackExportHandler) ExportChargebacks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	contentType, ok := negotiateExportFormat(r.Header.Get("Accept"))
+	if !ok {
+		writeJSON(w, http.StatusNotAcceptable, ErrorResponse{
+			Error: fmt.Sprintf("Accept must allow one of %s, %s or %s", ContentTypeCSV, ContentTypeNDJSON, ContentTypeParquet),
+		})
+		return
+	}
+
+	filter, err := parseExportFilter(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// The response starts with the first chargeback, so errors raised before any
+	// chargeback is exported, such as a forbidden merchant, are still reported as such
+	controller := http.NewResponseController(w)
+	var writer exportWriter
+	rows := 0
+	start := func() {
+		extension := map[string]string{ContentTypeCSV: "csv", ContentTypeNDJSON: "ndjson", ContentTypeParquet: "parquet"}[contentType]
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chargebacks.%s"`, extension))
+		w.WriteHeader(http.StatusOK)
+		writer = newExportWriter(contentType, w)
+		controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+	}
+
+	err = h.exportUC.Execute(r.Context(), filter, func(chargeback *usecase.CreateChargebackResponse) error {
+		if writer == nil {
+			start()
+		}
+		if err := writer.Write(chargeback); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			controller.Flush()
+			controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		}
+		return nil
+	})
+	if err != nil && writer == nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+	if writer == nil {
+		start()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// The status was sent already; abort the response so the client sees a broken
+		// download rather than an export that looks complete
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// negotiateExportFormat picks the export media type the Accept header prefers
+func negotiateExportFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeCSV, true
+	}
+
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		var candidate string
+		switch mediaType {
+		case ContentTypeCSV, ContentTypeNDJSON, ContentTypeParquet:
+			candidate = mediaType
+		case "*/*", "text/*":
+			candidate = ContentTypeCSV
+		case "application/*":
+			candidate = ContentTypeNDJSON
+		}
+		if candidate != "" && quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best, best != ""
+}
+
+// parseExportFilter reads the chargeback filter from query parameters
+// merchant_id may be repeated or hold a comma-separated list; created_from and
+// created_to are RFC 3339 timestamps or dates
+func parseExportFilter(query url.Values) (repository.ChargebackFilter, error) {
+	var filter repository.ChargebackFilter
+
+	for _, value := range query["merchant_id"] {
+		for _, merchantID := range strings.Split(value, ",") {
+			if merchantID = strings.TrimSpace(merchantID); merchantID != "" {
+				filter.MerchantIDs = append(filter.MerchantIDs, merchantID)
+			}
+		}
+	}
+
+	if value := query.Get("status"); value != "" {
+		filter.Status = entity.ChargebackStatus(value)
+		if !slices.Contains([]entity.ChargebackStatus{entity.StatusPending, entity.StatusApproved, entity.StatusRejected}, filter.Status) {
+			return filter, fmt.Errorf("invalid status %q", value)
+		}
+	}
+	if value := query.Get("reason"); value != "" {
+		filter.Reason = entity.ChargebackReason(value)
+		validReasons := []entity.ChargebackReason{
+			entity.ReasonFraud, entity.ReasonAuthorizationError, entity.ReasonProcessingError, entity.ReasonConsumerDispute,
+		}
+		if !slices.Contains(validReasons, filter.Reason) {
+			return filter, fmt.Errorf("invalid reason %q", value)
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseExportTime(query.Get("created_from")); err != nil {
+		return filter, fmt.Errorf("invalid created_from: %w", err)
+	}
+	if filter.CreatedTo, err = parseExportTime(query.Get("created_to")); err != nil {
+		return filter, fmt.Errorf("invalid created_to: %w", err)
+	}
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && !filter.CreatedFrom.Before(filter.CreatedTo) {
+		return filter, fmt.Errorf("created_from must be before created_to")
+	}
+
+	return filter, nil
+}
+
+// parseExportTime parses an RFC 3339 timestamp or a date, which starts at midnight UTC
+func parseExportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a date", value)
+	}
+	return t, nil
+}
+
+// exportWriter encodes exported chargebacks in one of the export formats
+type exportWriter interface {
+	Write(chargeback *usecase.CreateChargebackResponse) error
+	// Flush sends buffered rows to the underlying writer
+	Flush() error
+	// Close flushes the remaining rows and completes the file
+	Close() error
+}
+
+// newExportWriter returns the writer of the media type
+func newExportWriter(contentType string, w io.Writer) exportWriter {
+	switch contentType {
+	case ContentTypeNDJSON:
+		return &ndjsonExportWriter{encoder: json.NewEncoder(w)}
+	case ContentTypeParquet:
+		return &parquetExportWriter{writer: parquet.NewGenericWriter[exportRow](w, parquet.MaxRowsPerRowGroup(exportRowGroupSize))}
+	default:
+		return &csvExportWriter{writer: csv.NewWriter(w)}
+	}
+}
+
+// exportColumns are the columns of CSV exports, in order
+var exportColumns = []string{
+	"id", "transaction_id", "merchant_id", "amount", "currency", "card_number", "reason", "status",
+	"description", "transaction_date", "chargeback_date", "created_at", "updated_at",
+}
+
+// csvExportWriter writes chargebacks as CSV rows under a header row
+type csvExportWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func (c *csvExportWriter) Write(chargeback *usecase.CreateChargebackResponse) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.writer.Write([]string{
+		csvText(chargeback.ID),
+		csvText(chargeback.TransactionID),
+		csvText(chargeback.MerchantID),
+		strconv.FormatFloat(chargeback.Amount, 'f', -1, 64),
+		csvText(chargeback.Currency),
+		csvText(chargeback.CardNumber),
+		string(chargeback.Reason),
+		string(chargeback.Status),
+		csvText(chargeback.Description),
+		chargeback.TransactionDate.Format(time.RFC3339),
+		chargeback.ChargebackDate.Format(time.RFC3339),
+		chargeback.CreatedAt.Format(time.RFC3339),
+		chargeback.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// Close writes the header of empty exports too
+func (c *csvExportWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+func (c *csvExportWriter) writeHeader() error {
+	if c.wroteHeader {
+		return nil
+	}
+	c.wroteHeader = true
+	return c.writer.Write(exportColumns)
+}
+
+// csvText escapes values spreadsheets would otherwise evaluate as formulas
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ndjsonExportWriter writes chargebacks as one JSON object per line
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonExportWriter) Write(chargeback *usecase.CreateChargebackResponse) error {
+	return n.encoder.Encode(chargeback)
+}
+
+func (n *ndjsonExportWriter) Flush() error { return nil }
+
+func (n *ndjsonExportWriter) Close() error { return nil }
+
+// exportRow is the schema of Parquet exports
+type exportRow struct {
+	ID              string    `parquet:"id"`
+	TransactionID   string    `parquet:"transaction_id"`
+	MerchantID      string    `parquet:"merchant_id,dict"`
+	Amount          float64   `parquet:"amount"`
+	Currency        string    `parquet:"currency,dict"`
+	CardNumber      string    `parquet:"card_number"`
+	Reason          string    `parquet:"reason,dict"`
+	Status          string    `parquet:"status,dict"`
+	Description     string    `parquet:"description"`
+	TransactionDate time.Time `parquet:"transaction_date,timestamp(millisecond)"`
+	ChargebackDate  time.Time `parquet:"chargeback_date,timestamp(millisecond)"`
+	CreatedAt       time.Time `parquet:"created_at,timestamp(millisecond)"`
+	UpdatedAt       time.Time `parquet:"updated_at,timestamp(millisecond)"`
+}
+
+// parquetExportWriter writes chargebacks as a Parquet file
+// Rows are held until a row group is full, which is then written out
+type parquetExportWriter struct {
+	writer *parquet.GenericWriter[exportRow]
+}
+
+func (p *parquetExportWriter) Write(chargeback *usecase.CreateChargebackResponse) error {
+	_, err := p.writer.Write([]exportRow{{
+		ID:              chargeback.ID,
+		TransactionID:   chargeback.TransactionID,
+		MerchantID:      chargeback.MerchantID,
+		Amount:          chargeback.Amount,
+		Currency:        chargeback.Currency,
+		CardNumber:      chargeback.CardNumber,
+		Reason:          string(chargeback.Reason),
+		Status:          string(chargeback.Status),
+		Description:     chargeback.Description,
+		TransactionDate: chargeback.TransactionDate,
+		ChargebackDate:  chargeback.ChargebackDate,
+		CreatedAt:       chargeback.CreatedAt,
+		UpdatedAt:       chargeback.UpdatedAt,
+	}})
+	return err
+}
+
+// Flush is a no-op; flushing would end the row group early
+func (p *parquetExportWriter) Flush() error { return nil }
+
+func (p *parquetExportWriter) Close() error {
+	return p.writer.Close()
+}
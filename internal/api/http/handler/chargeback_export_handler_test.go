@@ -0,0 +1,248 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockExportChargebacksUseCase is a mock implementation of ExportChargebacksUseCase
+type MockExportChargebacksUseCase struct {
+	ExecuteFunc func(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error
+}
+
+func (m *MockExportChargebacksUseCase) Execute(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+	return m.ExecuteFunc(ctx, filter, emit)
+}
+
+// exportedChargebacks emits two chargebacks, one with a description spreadsheets would evaluate
+func exportedChargebacks(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+	created := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	for _, chargeback := range []*usecase.CreateChargebackResponse{
+		{ID: "cb_1", MerchantID: "merchant-789", Amount: 10.5, Currency: "USD", CardNumber: "************1111",
+			Reason: entity.ReasonFraud, Status: entity.StatusPending, Description: "=HYPERLINK()", CreatedAt: created},
+		{ID: "cb_2", MerchantID: "merchant-789", Amount: 7, Currency: "USD", CardNumber: "************2222",
+			Reason: entity.ReasonFraud, Status: entity.StatusApproved, CreatedAt: created},
+	} {
+		if err := emit(chargeback); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportedParquetRow mirrors the schema of Parquet exports
+type exportedParquetRow struct {
+	ID         string    `parquet:"id"`
+	Amount     float64   `parquet:"amount"`
+	CardNumber string    `parquet:"card_number"`
+	CreatedAt  time.Time `parquet:"created_at,timestamp(millisecond)"`
+}
+
+func TestChargebackExportHandler_ExportChargebacks_Formats(t *testing.T) {
+	exportHandler := handler.NewChargebackExportHandler(&MockExportChargebacksUseCase{ExecuteFunc: exportedChargebacks})
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		assertBody          func(t *testing.T, body []byte)
+	}{
+		{
+			name:                "csv by default",
+			expectedContentType: handler.ContentTypeCSV,
+			assertBody: func(t *testing.T, body []byte) {
+				lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+				if len(lines) != 3 || !strings.HasPrefix(lines[0], "id,transaction_id,merchant_id,amount,") {
+					t.Fatalf("Unexpected CSV:\n%s", body)
+				}
+				if !strings.Contains(lines[1], "cb_1,,merchant-789,10.5,USD,************1111,fraud,pending,'=HYPERLINK(),") {
+					t.Errorf("Expected masked card and escaped formula, got %s", lines[1])
+				}
+			},
+		},
+		{
+			name:                "ndjson",
+			accept:              "application/x-ndjson",
+			expectedContentType: handler.ContentTypeNDJSON,
+			assertBody: func(t *testing.T, body []byte) {
+				decoder := json.NewDecoder(bytes.NewReader(body))
+				var ids []string
+				for decoder.More() {
+					var chargeback usecase.CreateChargebackResponse
+					if err := decoder.Decode(&chargeback); err != nil {
+						t.Fatalf("Failed to decode line: %v", err)
+					}
+					ids = append(ids, chargeback.ID)
+				}
+				if strings.Join(ids, ",") != "cb_1,cb_2" {
+					t.Errorf("Expected both chargebacks, got %v", ids)
+				}
+			},
+		},
+		{
+			name:                "parquet preferred by quality",
+			accept:              "text/csv;q=0.5, application/vnd.apache.parquet",
+			expectedContentType: handler.ContentTypeParquet,
+			assertBody: func(t *testing.T, body []byte) {
+				rows, err := parquet.Read[exportedParquetRow](bytes.NewReader(body), int64(len(body)))
+				if err != nil {
+					t.Fatalf("Failed to read Parquet file: %v", err)
+				}
+				if len(rows) != 2 || rows[0].ID != "cb_1" || rows[0].Amount != 10.5 || rows[1].CardNumber != "************2222" ||
+					!rows[0].CreatedAt.Equal(time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)) {
+					t.Errorf("Unexpected rows %+v", rows)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/chargebacks/export", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			exportHandler.ExportChargebacks(recorder, req)
+
+			// Assert
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != tt.expectedContentType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.expectedContentType, contentType)
+			}
+			if !strings.HasPrefix(recorder.Header().Get("Content-Disposition"), "attachment;") {
+				t.Errorf("Expected an attachment, got %q", recorder.Header().Get("Content-Disposition"))
+			}
+			tt.assertBody(t, recorder.Body.Bytes())
+		})
+	}
+}
+
+func TestChargebackExportHandler_ExportChargebacks_Errors(t *testing.T) {
+	mockUseCase := &MockExportChargebacksUseCase{
+		ExecuteFunc: func(ctx context.Context, f repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+			switch {
+			case len(f.MerchantIDs) > 0 && f.MerchantIDs[0] == "merchant-000":
+				return fmt.Errorf("%w: no access to merchant merchant-000", auth.ErrForbidden)
+			case f.Status == entity.StatusRejected:
+				return errors.New("failed to list chargebacks: DynamoDB unavailable")
+			}
+			return nil
+		},
+	}
+	exportHandler := handler.NewChargebackExportHandler(mockUseCase)
+
+	tests := []struct {
+		name         string
+		method       string
+		query        string
+		accept       string
+		expectedCode int
+	}{
+		{"unsupported format", http.MethodGet, "", "application/pdf", http.StatusNotAcceptable},
+		{"invalid status", http.MethodGet, "status=closed", "", http.StatusBadRequest},
+		{"invalid date", http.MethodGet, "created_to=yesterday", "", http.StatusBadRequest},
+		{"empty date range", http.MethodGet, "created_from=2023-10-02&created_to=2023-10-01", "", http.StatusBadRequest},
+		{"forbidden merchant", http.MethodGet, "merchant_id=merchant-000", "", http.StatusForbidden},
+		{"repository error", http.MethodGet, "status=rejected", "", http.StatusInternalServerError},
+		{"wrong method", http.MethodPost, "", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(tt.method, "/chargebacks/export?"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			exportHandler.ExportChargebacks(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestChargebackExportHandler_ExportChargebacks_Filter(t *testing.T) {
+	// Arrange
+	var filter repository.ChargebackFilter
+	mockUseCase := &MockExportChargebacksUseCase{
+		ExecuteFunc: func(ctx context.Context, f repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+			filter = f
+			return nil
+		},
+	}
+	req := httptest.NewRequest(http.MethodGet,
+		"/chargebacks/export?merchant_id=merchant-789,merchant-790&merchant_id=merchant-791&reason=fraud&created_from=2023-10-01&created_to=2023-10-02T12:00:00Z", nil)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	handler.NewChargebackExportHandler(mockUseCase).ExportChargebacks(recorder, req)
+
+	// Assert
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if strings.TrimSpace(recorder.Body.String()) != "id,transaction_id,merchant_id,amount,currency,card_number,reason,status,"+
+		"description,transaction_date,chargeback_date,created_at,updated_at" {
+		t.Errorf("Expected only the header row, got %q", recorder.Body.String())
+	}
+	if strings.Join(filter.MerchantIDs, ",") != "merchant-789,merchant-790,merchant-791" || filter.Reason != entity.ReasonFraud {
+		t.Errorf("Unexpected filter %+v", filter)
+	}
+	if !filter.CreatedFrom.Equal(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)) ||
+		!filter.CreatedTo.Equal(time.Date(2023, 10, 2, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected creation range %v to %v", filter.CreatedFrom, filter.CreatedTo)
+	}
+}
+
+func TestChargebackExportHandler_ExportChargebacks_AbortsOnErrorMidStream(t *testing.T) {
+	// Arrange
+	mockUseCase := &MockExportChargebacksUseCase{
+		ExecuteFunc: func(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+			if err := emit(&usecase.CreateChargebackResponse{ID: "cb_1"}); err != nil {
+				return err
+			}
+			return errors.New("failed to list chargebacks: DynamoDB unavailable")
+		},
+	}
+	recorder := httptest.NewRecorder()
+
+	// Act
+	recovered := func() (recovered any) {
+		defer func() { recovered = recover() }()
+		handler.NewChargebackExportHandler(mockUseCase).ExportChargebacks(recorder, httptest.NewRequest(http.MethodGet, "/chargebacks/export", nil))
+		return nil
+	}()
+
+	// Assert
+	if recovered != http.ErrAbortHandler {
+		t.Errorf("Expected the response to be aborted, got %v", recovered)
+	}
+}
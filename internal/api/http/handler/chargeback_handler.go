@@ -3,11 +3,13 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
 	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
 )
@@ -42,8 +44,10 @@ type CreateChargebackRequest struct {
 }
 
 // ErrorResponse represents an error response
+// Fields lists the offending fields when a request body fails validation
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
 // CreateChargeback handles POST /chargebacks
@@ -67,33 +71,47 @@ func (h *ChargebackHandler) CreateChargeback(w http.ResponseWriter, r *http.Requ
 
 	// Parse JSON request body
 	var req CreateChargebackRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid JSON format"})
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
-	// Parse transaction date
-	transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
+	// Parse transaction date and reason
+	useCaseReq, err := req.toUseCaseRequest()
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid transaction_date format. Use RFC3339 format"})
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	// Execute use case
+	response, err := h.createChargebackUC.Execute(r.Context(), useCaseReq)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// toUseCaseRequest converts the HTTP request body into the use case request,
+// parsing the transaction date and reason
+func (req CreateChargebackRequest) toUseCaseRequest() (usecase.CreateChargebackRequest, error) {
+	transactionDate, err := time.Parse(time.RFC3339, req.TransactionDate)
+	if err != nil {
+		return usecase.CreateChargebackRequest{}, errors.New("Invalid transaction_date format. Use RFC3339 format")
+	}
+
 	// Convert reason string to enum
 	reason, err := parseChargebackReason(req.Reason)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
-		return
+		return usecase.CreateChargebackRequest{}, err
 	}
 
-	// Create use case request
-	useCaseReq := usecase.CreateChargebackRequest{
+	return usecase.CreateChargebackRequest{
 		TransactionID:   req.TransactionID,
 		MerchantID:      req.MerchantID,
 		Amount:          req.Amount,
@@ -102,19 +120,7 @@ func (h *ChargebackHandler) CreateChargeback(w http.ResponseWriter, r *http.Requ
 		Reason:          reason,
 		Description:     req.Description,
 		TransactionDate: transactionDate,
-	}
-
-	// Execute use case
-	response, err := h.createChargebackUC.Execute(r.Context(), useCaseReq)
-	if err != nil {
-		h.handleUseCaseError(w, err)
-		return
-	}
-
-	// Return success response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	}, nil
 }
 
 // handleUseCaseError handles different types of use case errors and returns appropriate HTTP status codes
@@ -125,6 +131,8 @@ func (h *ChargebackHandler) handleUseCaseError(w http.ResponseWriter, err error)
 
 	// Determine status code based on error type
 	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		w.WriteHeader(http.StatusForbidden)
 	case strings.Contains(errorMessage, "validation errors"):
 		w.WriteHeader(http.StatusBadRequest)
 	case strings.Contains(errorMessage, "already exists"):
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// GetChargebackUseCase interface defines the contract for retrieving chargebacks
+type GetChargebackUseCase interface {
+	Execute(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error)
+}
+
+// ChargebackQueryHandler handles HTTP requests that read chargebacks
+type ChargebackQueryHandler struct {
+	getChargebackUC GetChargebackUseCase
+}
+
+// NewChargebackQueryHandler creates a new chargeback query handler
+func NewChargebackQueryHandler(getChargebackUC GetChargebackUseCase) *ChargebackQueryHandler {
+	return &ChargebackQueryHandler{
+		getChargebackUC: getChargebackUC,
+	}
+}
+
+// GetChargeback handles GET /chargebacks/{id}
+func (h *ChargebackQueryHandler) GetChargeback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	id, ok := chargebackIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.getChargebackUC.Execute(r.Context(), id.String())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, usecase.ErrChargebackNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// chargebackIDParam parses the {id} path parameter, writing a 400 response when it is
+// not a valid chargeback ID
+func chargebackIDParam(w http.ResponseWriter, r *http.Request) (entity.ChargebackID, bool) {
+	id, err := entity.ParseChargebackID(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return "", false
+	}
+	return id, true
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
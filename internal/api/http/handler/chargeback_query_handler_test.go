@@ -0,0 +1,80 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockGetChargebackUseCase is a mock implementation of GetChargebackUseCase
+type MockGetChargebackUseCase struct {
+	ExecuteFunc func(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error)
+}
+
+func (m *MockGetChargebackUseCase) Execute(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error) {
+	return m.ExecuteFunc(ctx, id)
+}
+
+func TestChargebackQueryHandler_GetChargeback(t *testing.T) {
+	mockUseCase := &MockGetChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error) {
+			switch id {
+			case "cb_12345":
+				return &usecase.CreateChargebackResponse{ID: id, MerchantID: "merchant-789"}, nil
+			case "cb_403":
+				return nil, fmt.Errorf("%w: missing scope chargebacks:read", auth.ErrForbidden)
+			case "cb_500":
+				return nil, errors.New("failed to find chargeback: database connection failed")
+			default:
+				return nil, fmt.Errorf("%w: %s", usecase.ErrChargebackNotFound, id)
+			}
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chargebacks/{id}", handler.NewChargebackQueryHandler(mockUseCase).GetChargeback)
+
+	tests := []struct {
+		name         string
+		method       string
+		id           string
+		expectedCode int
+	}{
+		{"found", http.MethodGet, "cb_12345", http.StatusOK},
+		{"not found", http.MethodGet, "cb_404", http.StatusNotFound},
+		{"forbidden", http.MethodGet, "cb_403", http.StatusForbidden},
+		{"repository error", http.MethodGet, "cb_500", http.StatusInternalServerError},
+		{"wrong method", http.MethodPut, "cb_12345", http.StatusMethodNotAllowed},
+		{"invalid ID", http.MethodGet, "cb_not-an-id", http.StatusBadRequest},
+		{"ID without prefix", http.MethodGet, "01ARZ3NDEKTSV4RRFFQ69G5FAV", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(tt.method, "/chargebacks/"+tt.id, nil))
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+
+			if tt.expectedCode == http.StatusOK {
+				var response usecase.CreateChargebackResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.ID != tt.id {
+					t.Errorf("Expected ID %s, got %s", tt.id, response.ID)
+				}
+			}
+		})
+	}
+}
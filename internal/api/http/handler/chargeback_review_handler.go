@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// ReviewChargebackUseCase interface defines the contract for approving or rejecting chargebacks
+type ReviewChargebackUseCase interface {
+	Execute(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error)
+}
+
+// ChargebackReviewHandler handles HTTP requests that decide pending chargebacks
+type ChargebackReviewHandler struct {
+	approveChargebackUC ReviewChargebackUseCase
+	rejectChargebackUC  ReviewChargebackUseCase
+}
+
+// NewChargebackReviewHandler creates a new chargeback review handler
+func NewChargebackReviewHandler(approveChargebackUC, rejectChargebackUC ReviewChargebackUseCase) *ChargebackReviewHandler {
+	return &ChargebackReviewHandler{
+		approveChargebackUC: approveChargebackUC,
+		rejectChargebackUC:  rejectChargebackUC,
+	}
+}
+
+// ApproveChargeback handles POST /chargebacks/{id}/approve
+func (h *ChargebackReviewHandler) ApproveChargeback(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.approveChargebackUC)
+}
+
+// RejectChargeback handles POST /chargebacks/{id}/reject
+func (h *ChargebackReviewHandler) RejectChargeback(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.rejectChargebackUC)
+}
+
+// review executes a review use case and writes the updated chargeback
+func (h *ChargebackReviewHandler) review(w http.ResponseWriter, r *http.Request, reviewUC ReviewChargebackUseCase) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	id, ok := chargebackIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := reviewUC.Execute(r.Context(), id.String())
+	if err != nil {
+		errorMessage := err.Error()
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: errorMessage})
+		case errors.Is(err, usecase.ErrChargebackNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: errorMessage})
+		case strings.Contains(errorMessage, "only pending chargebacks"):
+			writeJSON(w, http.StatusConflict, ErrorResponse{Error: errorMessage})
+		default:
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: errorMessage})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
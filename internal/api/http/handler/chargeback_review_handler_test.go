@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockReviewChargebackUseCase is a mock implementation of ReviewChargebackUseCase
+type MockReviewChargebackUseCase struct {
+	ExecuteFunc func(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error)
+}
+
+func (m *MockReviewChargebackUseCase) Execute(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error) {
+	return m.ExecuteFunc(ctx, id)
+}
+
+// newMockReviewUseCase returns a review use case that decides chargebacks with the given status
+func newMockReviewUseCase(status entity.ChargebackStatus) *MockReviewChargebackUseCase {
+	return &MockReviewChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error) {
+			switch id {
+			case "cb_12345":
+				return &usecase.CreateChargebackResponse{ID: id, Status: status}, nil
+			case "cb_403":
+				return nil, fmt.Errorf("%w: approving chargebacks above 10000.00 requires supervisor review", auth.ErrForbidden)
+			case "cb_409":
+				return nil, fmt.Errorf("failed to approve chargeback: %w", errors.New("only pending chargebacks can be approved"))
+			case "cb_500":
+				return nil, errors.New("failed to update chargeback: database connection failed")
+			default:
+				return nil, fmt.Errorf("%w: %s", usecase.ErrChargebackNotFound, id)
+			}
+		},
+	}
+}
+
+func TestChargebackReviewHandler(t *testing.T) {
+	reviewHandler := handler.NewChargebackReviewHandler(
+		newMockReviewUseCase(entity.StatusApproved),
+		newMockReviewUseCase(entity.StatusRejected),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chargebacks/{id}/approve", reviewHandler.ApproveChargeback)
+	mux.HandleFunc("/chargebacks/{id}/reject", reviewHandler.RejectChargeback)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedCode   int
+		expectedStatus entity.ChargebackStatus
+	}{
+		{"approve", http.MethodPost, "/chargebacks/cb_12345/approve", http.StatusOK, entity.StatusApproved},
+		{"reject", http.MethodPost, "/chargebacks/cb_12345/reject", http.StatusOK, entity.StatusRejected},
+		{"high value requires supervisor", http.MethodPost, "/chargebacks/cb_403/approve", http.StatusForbidden, ""},
+		{"already decided", http.MethodPost, "/chargebacks/cb_409/approve", http.StatusConflict, ""},
+		{"not found", http.MethodPost, "/chargebacks/cb_404/reject", http.StatusNotFound, ""},
+		{"repository error", http.MethodPost, "/chargebacks/cb_500/approve", http.StatusInternalServerError, ""},
+		{"wrong method", http.MethodGet, "/chargebacks/cb_12345/approve", http.StatusMethodNotAllowed, ""},
+		{"invalid ID", http.MethodPost, "/chargebacks/cb_12345x/approve", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+
+			if tt.expectedCode == http.StatusOK {
+				var response usecase.CreateChargebackResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Status != tt.expectedStatus {
+					t.Errorf("Expected status %s, got %s", tt.expectedStatus, response.Status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MaxRequestBodyBytes is the largest request body the API accepts
+const MaxRequestBodyBytes = 1 << 20
+
+// FieldError describes a request body field that failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// decodeJSON strictly decodes the request body into dst, a pointer to a struct
+// Bodies over MaxRequestBodyBytes, trailing data, unknown fields, values of the wrong
+// JSON type and missing fields without omitempty are rejected
+// It writes the error response and returns false when the body is invalid
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error: fmt.Sprintf("Request body must not exceed %d bytes", MaxRequestBodyBytes),
+			})
+			return false
+		}
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "Failed to read request body"})
+		return false
+	}
+
+	if errResp := decodeStrict(body, dst); errResp != nil {
+		writeJSON(w, http.StatusBadRequest, errResp)
+		return false
+	}
+
+	return true
+}
+
+// decodeStrict decodes a JSON document into dst with the same rules as decodeJSON
+// It returns the error response to send when the document is invalid
+func decodeStrict(body []byte, dst interface{}) *ErrorResponse {
+	// Decode the keys first, so missing required fields can be told apart from zero values
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(body, &present); err != nil {
+		return decodeError(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	if missing := missingFields(dst, present); len(missing) > 0 {
+		return &ErrorResponse{Error: "Invalid request body", Fields: missing}
+	}
+
+	return nil
+}
+
+// decodeError maps JSON decoding errors to field-level errors where possible
+func decodeError(err error) *ErrorResponse {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := FieldError{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type)}
+		if typeErr.Field == "" {
+			field = FieldError{Field: "", Message: "request body must be " + jsonTypeName(typeErr.Type)}
+		}
+		return &ErrorResponse{Error: "Invalid request body", Fields: []FieldError{field}}
+	}
+
+	// encoding/json reports unknown fields only through the error message
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return &ErrorResponse{
+			Error:  "Invalid request body",
+			Fields: []FieldError{{Field: strings.Trim(name, `"`), Message: "is not allowed"}},
+		}
+	}
+
+	return &ErrorResponse{Error: "Invalid JSON format"}
+}
+
+// missingFields returns the struct's required fields, those without omitempty,
+// that the request body didn't include
+func missingFields(dst interface{}, present map[string]json.RawMessage) []FieldError {
+	t := reflect.TypeOf(dst)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var missing []FieldError
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" || strings.Contains(options, "omitempty") {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := present[name]; !ok {
+			missing = append(missing, FieldError{Field: name, Message: "is required"})
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Field < missing[j].Field
+	})
+	return missing
+}
+
+// jsonTypeName describes the JSON type a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+func TestChargebackHandler_CreateChargeback_StrictDecoding(t *testing.T) {
+	validBody := `{
+		"transaction_id": "tx-12345",
+		"merchant_id": "merchant-789",
+		"amount": 150.75,
+		"currency": "USD",
+		"card_number": "4111111111111111",
+		"reason": "fraud",
+		"transaction_date": "2023-10-01T10:00:00Z"`
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedError  string
+		expectedFields []handler.FieldError
+	}{
+		{
+			name:           "unknown field",
+			body:           validBody + `, "merchant": "typo"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+			expectedFields: []handler.FieldError{{Field: "merchant", Message: "is not allowed"}},
+		},
+		{
+			name:           "wrong JSON type",
+			body:           strings.Replace(validBody, `150.75`, `"150.75"`, 1) + `}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+			expectedFields: []handler.FieldError{{Field: "amount", Message: "must be a number"}},
+		},
+		{
+			name:           "missing required fields",
+			body:           `{"transaction_id": "tx-12345", "merchant_id": "merchant-789", "amount": 1, "currency": "USD", "reason": "fraud"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+			expectedFields: []handler.FieldError{
+				{Field: "card_number", Message: "is required"},
+				{Field: "transaction_date", Message: "is required"},
+			},
+		},
+		{
+			name:           "not an object",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request body",
+			expectedFields: []handler.FieldError{{Field: "", Message: "request body must be an object"}},
+		},
+		{
+			name:           "trailing data",
+			body:           validBody + `} {}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid JSON format",
+		},
+		{
+			name:           "oversized body",
+			body:           validBody + `, "description": "` + strings.Repeat("a", handler.MaxRequestBodyBytes) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedError:  "Request body must not exceed 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			h := handler.NewChargebackHandler(&MockCreateChargebackUseCase{
+				ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+					called = true
+					return &usecase.CreateChargebackResponse{}, nil
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/chargebacks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			// Act
+			h.CreateChargeback(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if called {
+				t.Error("Expected use case not to run for an invalid body")
+			}
+
+			var response handler.ErrorResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error != tt.expectedError {
+				t.Errorf("Expected error '%s', got '%s'", tt.expectedError, response.Error)
+			}
+			if !reflect.DeepEqual(response.Fields, tt.expectedFields) {
+				t.Errorf("Expected fields %+v, got %+v", tt.expectedFields, response.Fields)
+			}
+		})
+	}
+}
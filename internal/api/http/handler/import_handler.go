@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+)
+
+// MaxImportRequestBodyBytes is the largest dispute file accepted by POST /imports
+const MaxImportRequestBodyBytes = 50 << 20
+
+// ImportJobService interface defines the contract for running imports in the background
+type ImportJobService interface {
+	Start(ctx context.Context, data []byte, parser importer.DisputeFileParser, dryRun bool) (importer.Job, error)
+	Get(ctx context.Context, id string) (importer.Job, error)
+}
+
+// ImportHandler handles HTTP requests that import dispute files
+type ImportHandler struct {
+	jobs ImportJobService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(jobs ImportJobService) *ImportHandler {
+	return &ImportHandler{
+		jobs: jobs,
+	}
+}
+
+// ImportJobResponse represents an import job
+type ImportJobResponse struct {
+	ID          string            `json:"id"`
+	Status      string            `json:"status"`
+	DryRun      bool              `json:"dry_run"`
+	Summary     ImportSummary     `json:"summary"`
+	Rejects     []ImportRejectRow `json:"rejects,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	CompletedAt *time.Time        `json:"completed_at,omitempty"`
+}
+
+// ImportSummary counts the rows of an import job
+type ImportSummary struct {
+	Imported int `json:"imported"`
+	Rejected int `json:"rejected"`
+}
+
+// ImportRejectRow describes a row that was not imported; row values are left out as
+// they hold card numbers
+type ImportRejectRow struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// StartImport handles POST /imports
+// The body is a CSV file; column mappings and formats are given as query parameters:
+// map (field=column pairs), date_format (a Go time layout), decimal_separator,
+// thousands_separator, minor_units, delimiter and dry_run
+func (h *ImportHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), "text/csv") {
+		writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{Error: "Content-Type must be text/csv"})
+		return
+	}
+
+	config, dryRun, err := parseImportQuery(r.URL.Query())
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxImportRequestBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error: fmt.Sprintf("Request body must not exceed %d bytes", MaxImportRequestBodyBytes),
+			})
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "Failed to read request body"})
+		return
+	}
+
+	job, err := h.jobs.Start(r.Context(), data, importer.NewCSVParser(config), dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, importer.ErrInvalidFile):
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+job.ID)
+	writeJSON(w, http.StatusAccepted, toImportJobResponse(job))
+}
+
+// GetImport handles GET /imports/{id}
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	job, err := h.jobs.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrForbidden):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, importer.ErrJobNotFound):
+			writeJSON(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		default:
+			writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toImportJobResponse(job))
+}
+
+// parseImportQuery reads the CSV configuration and dry-run flag from query parameters
+func parseImportQuery(query url.Values) (importer.CSVConfig, bool, error) {
+	config := importer.CSVConfig{Format: importer.DefaultFormat()}
+
+	if value := query.Get("map"); value != "" {
+		mapping, err := importer.ParseMapping(value)
+		if err != nil {
+			return config, false, err
+		}
+		config.Mapping = mapping
+	}
+	if value := query.Get("date_format"); value != "" {
+		config.Format.DateLayout = value
+	}
+	if value := query.Get("decimal_separator"); value != "" {
+		config.Format.DecimalSeparator = value
+	}
+	config.Format.ThousandsSeparator = query.Get("thousands_separator")
+
+	if value := query.Get("delimiter"); value != "" {
+		delimiter, size := utf8.DecodeRuneInString(value)
+		if size != len(value) {
+			return config, false, fmt.Errorf("delimiter must be a single character")
+		}
+		config.Delimiter = delimiter
+	}
+
+	var err error
+	if config.Format.MinorUnits, err = parseBoolQuery(query, "minor_units"); err != nil {
+		return config, false, err
+	}
+	dryRun, err := parseBoolQuery(query, "dry_run")
+	if err != nil {
+		return config, false, err
+	}
+
+	return config, dryRun, config.Validate()
+}
+
+// parseBoolQuery parses an optional boolean query parameter
+func parseBoolQuery(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", name)
+	}
+	return parsed, nil
+}
+
+// toImportJobResponse converts an import job into its response representation
+func toImportJobResponse(job importer.Job) ImportJobResponse {
+	response := ImportJobResponse{
+		ID:     job.ID,
+		Status: string(job.Status),
+		DryRun: job.DryRun,
+		Summary: ImportSummary{
+			Imported: job.Result.Imported,
+			Rejected: job.Result.Rejected,
+		},
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+	}
+
+	for _, reject := range job.Rejects {
+		response.Rejects = append(response.Rejects, ImportRejectRow{Line: reject.Line, Reason: reject.Reason})
+	}
+	if !job.CompletedAt.IsZero() {
+		completedAt := job.CompletedAt
+		response.CompletedAt = &completedAt
+	}
+
+	return response
+}
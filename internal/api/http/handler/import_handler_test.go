@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+)
+
+// MockImportJobService is a mock implementation of ImportJobService
+type MockImportJobService struct {
+	StartFunc func(ctx context.Context, data []byte, parser importer.DisputeFileParser, dryRun bool) (importer.Job, error)
+	GetFunc   func(ctx context.Context, id string) (importer.Job, error)
+}
+
+func (m *MockImportJobService) Start(ctx context.Context, data []byte, parser importer.DisputeFileParser, dryRun bool) (importer.Job, error) {
+	if m.StartFunc != nil {
+		return m.StartFunc(ctx, data, parser, dryRun)
+	}
+	return importer.Job{ID: "imp_1", Status: importer.JobRunning, DryRun: dryRun}, nil
+}
+
+func (m *MockImportJobService) Get(ctx context.Context, id string) (importer.Job, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, id)
+	}
+	return importer.Job{ID: id}, nil
+}
+
+func TestImportHandler_StartImport(t *testing.T) {
+	tests := []struct {
+		name             string
+		query            string
+		contentType      string
+		startErr         error
+		expectedStatus   int
+		expectedError    string
+		expectedConfig   func(t *testing.T, config importer.CSVConfig, dryRun bool)
+		expectedLocation string
+	}{
+		{
+			name:             "accepted with configuration from the query",
+			query:            "?map=transaction_id%3DRef&date_format=02/01/2006&decimal_separator=,&delimiter=%3B&minor_units=false&dry_run=true",
+			contentType:      "text/csv",
+			expectedStatus:   http.StatusAccepted,
+			expectedLocation: "/v1/imports/imp_1",
+			expectedConfig: func(t *testing.T, config importer.CSVConfig, dryRun bool) {
+				if config.Mapping.Column(importer.FieldTransactionID) != "Ref" || config.Format.DateLayout != "02/01/2006" ||
+					config.Format.DecimalSeparator != "," || config.Delimiter != ';' || !dryRun {
+					t.Errorf("Unexpected configuration %+v (dry run %t)", config, dryRun)
+				}
+			},
+		},
+		{
+			name:           "invalid mapping",
+			query:          "?map=acquirer%3DAcq",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "unknown fields in column mapping: acquirer",
+		},
+		{
+			name:           "invalid boolean",
+			query:          "?dry_run=maybe",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "dry_run must be a boolean",
+		},
+		{
+			name:           "invalid file",
+			contentType:    "text/csv",
+			startErr:       fmt.Errorf("%w: missing columns: amount", importer.ErrInvalidFile),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid dispute file: missing columns: amount",
+		},
+		{
+			name:           "forbidden",
+			contentType:    "text/csv",
+			startErr:       fmt.Errorf("%w: missing scope chargebacks:write", auth.ErrForbidden),
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "forbidden: missing scope chargebacks:write",
+		},
+		{
+			name:           "unsupported content type",
+			contentType:    "application/json",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			expectedError:  "Content-Type must be text/csv",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			service := &MockImportJobService{
+				StartFunc: func(ctx context.Context, data []byte, parser importer.DisputeFileParser, dryRun bool) (importer.Job, error) {
+					if tt.startErr != nil {
+						return importer.Job{}, tt.startErr
+					}
+					csvParser, ok := parser.(*importer.CSVParser)
+					if !ok {
+						t.Fatalf("Expected CSV parser, got %T", parser)
+					}
+					if tt.expectedConfig != nil {
+						tt.expectedConfig(t, csvParser.Config(), dryRun)
+					}
+					return importer.Job{ID: "imp_1", Status: importer.JobRunning, DryRun: dryRun, CreatedAt: time.Now()}, nil
+				},
+			}
+			h := handler.NewImportHandler(service)
+			req := httptest.NewRequest(http.MethodPost, "/v1/imports"+tt.query, strings.NewReader("transaction_id\n"))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			h.StartImport(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			if tt.expectedError != "" {
+				var response handler.ErrorResponse
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Error != tt.expectedError {
+					t.Errorf("Expected error '%s', got '%s'", tt.expectedError, response.Error)
+				}
+				return
+			}
+
+			var response handler.ImportJobResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.ID != "imp_1" || response.Status != "running" {
+				t.Errorf("Unexpected job %+v", response)
+			}
+			if location := recorder.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected Location '%s', got '%s'", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestImportHandler_GetImport(t *testing.T) {
+	completedAt := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		job            importer.Job
+		err            error
+		expectedStatus int
+	}{
+		{
+			name: "completed job",
+			job: importer.Job{
+				ID:          "imp_1",
+				Status:      importer.JobCompleted,
+				Result:      importer.Result{Imported: 1, Rejected: 1},
+				Rejects:     []importer.Reject{{Line: 3, Reason: "invalid amount", Fields: []string{"4111111111111111"}}},
+				CompletedAt: completedAt,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown job",
+			err:            importer.ErrJobNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := handler.NewImportHandler(&MockImportJobService{
+				GetFunc: func(ctx context.Context, id string) (importer.Job, error) {
+					return tt.job, tt.err
+				},
+			})
+			req := httptest.NewRequest(http.MethodGet, "/v1/imports/imp_1", nil)
+			req.SetPathValue("id", "imp_1")
+			recorder := httptest.NewRecorder()
+
+			// Act
+			h.GetImport(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if strings.Contains(recorder.Body.String(), "4111111111111111") {
+				t.Error("Expected rejected row values to be left out of the response")
+			}
+
+			var response handler.ImportJobResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Summary != (handler.ImportSummary{Imported: 1, Rejected: 1}) ||
+				len(response.Rejects) != 1 || response.Rejects[0].Line != 3 ||
+				response.CompletedAt == nil || !response.CompletedAt.Equal(completedAt) {
+				t.Errorf("Unexpected response %+v", response)
+			}
+		})
+	}
+}
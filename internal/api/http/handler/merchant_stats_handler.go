@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// RecordTransactionVolumeUseCase interface defines the contract for reporting merchants' sales
+type RecordTransactionVolumeUseCase interface {
+	Execute(ctx context.Context, merchantID, month string, req usecase.RecordTransactionVolumeRequest) (*usecase.MerchantStatsResponse, error)
+}
+
+// GetMerchantStatsUseCase interface defines the contract for retrieving merchants' statistics
+type GetMerchantStatsUseCase interface {
+	Execute(ctx context.Context, merchantID, fromMonth, toMonth string) (*usecase.MerchantStatsHistoryResponse, error)
+}
+
+// MerchantStatsHandler handles HTTP requests for merchants' chargeback ratio monitoring
+type MerchantStatsHandler struct {
+	recordVolumeUC     RecordTransactionVolumeUseCase
+	getMerchantStatsUC GetMerchantStatsUseCase
+}
+
+// NewMerchantStatsHandler creates a new merchant statistics handler
+func NewMerchantStatsHandler(recordVolumeUC RecordTransactionVolumeUseCase, getMerchantStatsUC GetMerchantStatsUseCase) *MerchantStatsHandler {
+	return &MerchantStatsHandler{
+		recordVolumeUC:     recordVolumeUC,
+		getMerchantStatsUC: getMerchantStatsUC,
+	}
+}
+
+// TransactionVolumeRequest represents the HTTP request body for reporting a month's sales
+type TransactionVolumeRequest struct {
+	TransactionCount  int     `json:"transaction_count"`
+	TransactionAmount float64 `json:"transaction_amount"`
+}
+
+// RecordTransactionVolume handles PUT /merchants/{merchant_id}/volumes/{month}
+func (h *MerchantStatsHandler) RecordTransactionVolume(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		writeJSON(w, http.StatusUnsupportedMediaType, ErrorResponse{Error: "Content-Type must be application/json"})
+		return
+	}
+
+	var req TransactionVolumeRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	response, err := h.recordVolumeUC.Execute(r.Context(), r.PathValue("merchant_id"), r.PathValue("month"), usecase.RecordTransactionVolumeRequest{
+		TransactionCount:  req.TransactionCount,
+		TransactionAmount: req.TransactionAmount,
+	})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GetMerchantStats handles GET /merchants/{merchant_id}/stats
+func (h *MerchantStatsHandler) GetMerchantStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	response, err := h.getMerchantStatsUC.Execute(r.Context(), r.PathValue("merchant_id"), query.Get("from"), query.Get("to"))
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleUseCaseError maps merchant statistics use case errors to HTTP status codes
+func (h *MerchantStatsHandler) handleUseCaseError(w http.ResponseWriter, err error) {
+	errorMessage := err.Error()
+
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: errorMessage})
+	case strings.Contains(errorMessage, "validation errors"):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: errorMessage})
+	default:
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: errorMessage})
+	}
+}
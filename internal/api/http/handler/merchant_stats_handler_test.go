@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockRecordTransactionVolumeUseCase is a mock implementation of RecordTransactionVolumeUseCase
+type MockRecordTransactionVolumeUseCase struct {
+	ExecuteFunc func(ctx context.Context, merchantID, month string, req usecase.RecordTransactionVolumeRequest) (*usecase.MerchantStatsResponse, error)
+}
+
+func (m *MockRecordTransactionVolumeUseCase) Execute(ctx context.Context, merchantID, month string, req usecase.RecordTransactionVolumeRequest) (*usecase.MerchantStatsResponse, error) {
+	return m.ExecuteFunc(ctx, merchantID, month, req)
+}
+
+// MockGetMerchantStatsUseCase is a mock implementation of GetMerchantStatsUseCase
+type MockGetMerchantStatsUseCase struct {
+	ExecuteFunc func(ctx context.Context, merchantID, fromMonth, toMonth string) (*usecase.MerchantStatsHistoryResponse, error)
+}
+
+func (m *MockGetMerchantStatsUseCase) Execute(ctx context.Context, merchantID, fromMonth, toMonth string) (*usecase.MerchantStatsHistoryResponse, error) {
+	return m.ExecuteFunc(ctx, merchantID, fromMonth, toMonth)
+}
+
+// newMerchantStatsMux routes requests to a merchant statistics handler whose use cases fail
+// for the merchant IDs merchant-forbidden and merchant-error
+func newMerchantStatsMux() *http.ServeMux {
+	failure := func(merchantID string) error {
+		switch merchantID {
+		case "merchant-forbidden":
+			return fmt.Errorf("%w: no access to merchant %s", auth.ErrForbidden, merchantID)
+		case "merchant-error":
+			return errors.New("failed to list merchant statistics: DynamoDB unavailable")
+		}
+		return nil
+	}
+
+	statsHandler := handler.NewMerchantStatsHandler(
+		&MockRecordTransactionVolumeUseCase{
+			ExecuteFunc: func(ctx context.Context, merchantID, month string, req usecase.RecordTransactionVolumeRequest) (*usecase.MerchantStatsResponse, error) {
+				if err := failure(merchantID); err != nil {
+					return nil, err
+				}
+				if month == "2023-13" {
+					return nil, errors.New("validation errors: invalid month \"2023-13\", expected YYYY-MM")
+				}
+				return &usecase.MerchantStatsResponse{MerchantMonthlyStats: entity.MerchantMonthlyStats{
+					MerchantID:        merchantID,
+					Month:             month,
+					TransactionCount:  req.TransactionCount,
+					TransactionAmount: req.TransactionAmount,
+				}}, nil
+			},
+		},
+		&MockGetMerchantStatsUseCase{
+			ExecuteFunc: func(ctx context.Context, merchantID, fromMonth, toMonth string) (*usecase.MerchantStatsHistoryResponse, error) {
+				if err := failure(merchantID); err != nil {
+					return nil, err
+				}
+				return &usecase.MerchantStatsHistoryResponse{MerchantID: merchantID, From: fromMonth, To: toMonth}, nil
+			},
+		},
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/merchants/{merchant_id}/volumes/{month}", statsHandler.RecordTransactionVolume)
+	mux.HandleFunc("/merchants/{merchant_id}/stats", statsHandler.GetMerchantStats)
+	return mux
+}
+
+func TestMerchantStatsHandler_RecordTransactionVolume(t *testing.T) {
+	mux := newMerchantStatsMux()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		contentType  string
+		body         string
+		expectedCode int
+	}{
+		{"records volume", http.MethodPut, "/merchants/merchant-789/volumes/2023-01", "application/json", `{"transaction_count":1000,"transaction_amount":50000}`, http.StatusOK},
+		{"invalid month", http.MethodPut, "/merchants/merchant-789/volumes/2023-13", "application/json", `{"transaction_count":1000,"transaction_amount":50000}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPut, "/merchants/merchant-789/volumes/2023-01", "application/json", `{"transactions":1000}`, http.StatusBadRequest},
+		{"forbidden merchant", http.MethodPut, "/merchants/merchant-forbidden/volumes/2023-01", "application/json", `{"transaction_count":1000,"transaction_amount":50000}`, http.StatusForbidden},
+		{"wrong content type", http.MethodPut, "/merchants/merchant-789/volumes/2023-01", "text/plain", `{}`, http.StatusUnsupportedMediaType},
+		{"wrong method", http.MethodPost, "/merchants/merchant-789/volumes/2023-01", "application/json", `{}`, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK {
+				var response usecase.MerchantStatsResponse
+				json.NewDecoder(recorder.Body).Decode(&response)
+				if response.MerchantID != "merchant-789" || response.Month != "2023-01" || response.TransactionCount != 1000 {
+					t.Errorf("Unexpected response %+v", response)
+				}
+			}
+		})
+	}
+}
+
+func TestMerchantStatsHandler_GetMerchantStats(t *testing.T) {
+	mux := newMerchantStatsMux()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{"returns statistics", http.MethodGet, "/merchants/merchant-789/stats?from=2023-01&to=2023-06", http.StatusOK},
+		{"forbidden merchant", http.MethodGet, "/merchants/merchant-forbidden/stats", http.StatusForbidden},
+		{"repository error", http.MethodGet, "/merchants/merchant-error/stats", http.StatusInternalServerError},
+		{"wrong method", http.MethodDelete, "/merchants/merchant-789/stats", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			recorder := httptest.NewRecorder()
+
+			// Act
+			mux.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK {
+				var response usecase.MerchantStatsHistoryResponse
+				json.NewDecoder(recorder.Body).Decode(&response)
+				if response.MerchantID != "merchant-789" || response.From != "2023-01" || response.To != "2023-06" {
+					t.Errorf("Unexpected response %+v", response)
+				}
+			}
+		})
+	}
+}
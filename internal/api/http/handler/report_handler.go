@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// GetReportSummaryUseCase interface defines the contract for summarizing chargebacks
+type GetReportSummaryUseCase interface {
+	Execute(ctx context.Context, req usecase.ReportSummaryRequest) (*usecase.ReportSummaryResponse, error)
+}
+
+// ReportHandler handles HTTP requests for chargeback reports
+type ReportHandler struct {
+	getReportSummaryUC GetReportSummaryUseCase
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(getReportSummaryUC GetReportSummaryUseCase) *ReportHandler {
+	return &ReportHandler{
+		getReportSummaryUC: getReportSummaryUC,
+	}
+}
+
+// GetReportSummary handles GET /reports/summary
+// group_by is a comma-separated list of merchant, reason, status and currency; period
+// is day, week or month; from and to are dates. merchant_id, status, reason and
+// currency filter the chargebacks the same way as the export does.
+func (h *ReportHandler) GetReportSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: "Method not allowed"})
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := parseExportFilter(query)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	req := usecase.ReportSummaryRequest{
+		Period:      entity.ReportPeriod(query.Get("period")),
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+		MerchantIDs: filter.MerchantIDs,
+		Status:      filter.Status,
+		Reason:      filter.Reason,
+		Currency:    strings.ToUpper(query.Get("currency")),
+	}
+	for _, value := range query["group_by"] {
+		for _, dimension := range strings.Split(value, ",") {
+			if dimension = strings.TrimSpace(dimension); dimension != "" {
+				req.GroupBy = append(req.GroupBy, dimension)
+			}
+		}
+	}
+
+	response, err := h.getReportSummaryUC.Execute(r.Context(), req)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleUseCaseError maps report use case errors to HTTP status codes
+func (h *ReportHandler) handleUseCaseError(w http.ResponseWriter, err error) {
+	errorMessage := err.Error()
+
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		writeJSON(w, http.StatusForbidden, ErrorResponse{Error: errorMessage})
+	case strings.Contains(errorMessage, "validation errors"):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: errorMessage})
+	default:
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: errorMessage})
+	}
+}
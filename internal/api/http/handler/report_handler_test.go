@@ -0,0 +1,105 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockGetReportSummaryUseCase is a mock implementation of GetReportSummaryUseCase
+type MockGetReportSummaryUseCase struct {
+	ExecuteFunc func(ctx context.Context, req usecase.ReportSummaryRequest) (*usecase.ReportSummaryResponse, error)
+}
+
+func (m *MockGetReportSummaryUseCase) Execute(ctx context.Context, req usecase.ReportSummaryRequest) (*usecase.ReportSummaryResponse, error) {
+	return m.ExecuteFunc(ctx, req)
+}
+
+func TestReportHandler_GetReportSummary(t *testing.T) {
+	var received usecase.ReportSummaryRequest
+	reportHandler := handler.NewReportHandler(&MockGetReportSummaryUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.ReportSummaryRequest) (*usecase.ReportSummaryResponse, error) {
+			received = req
+			switch {
+			case len(req.MerchantIDs) > 0 && req.MerchantIDs[0] == "merchant-forbidden":
+				return nil, fmt.Errorf("%w: no access to merchant merchant-forbidden", auth.ErrForbidden)
+			case req.Period == "year":
+				return nil, errors.New("validation errors: invalid period \"year\", expected day, week or month")
+			case req.Currency == "XXX":
+				return nil, errors.New("failed to list report counters: DynamoDB unavailable")
+			}
+			return &usecase.ReportSummaryResponse{
+				From:    req.From,
+				To:      req.To,
+				GroupBy: req.GroupBy,
+				Groups:  []*usecase.ReportGroup{{MerchantID: "merchant-789", Count: 2, TotalAmount: 300, AverageAmount: 150}},
+			}, nil
+		},
+	})
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedCode    int
+		expectedRequest *usecase.ReportSummaryRequest
+	}{
+		{
+			name:         "summarizes with groups and filters",
+			method:       http.MethodGet,
+			path:         "/reports/summary?group_by=merchant,status&group_by=currency&period=week&from=2023-10-01&to=2023-10-31&merchant_id=merchant-789&status=pending&reason=fraud&currency=usd",
+			expectedCode: http.StatusOK,
+			expectedRequest: &usecase.ReportSummaryRequest{
+				GroupBy:     []string{"merchant", "status", "currency"},
+				Period:      entity.ReportPeriodWeek,
+				From:        "2023-10-01",
+				To:          "2023-10-31",
+				MerchantIDs: []string{"merchant-789"},
+				Status:      entity.StatusPending,
+				Reason:      entity.ReasonFraud,
+				Currency:    "USD",
+			},
+		},
+		{name: "invalid status", method: http.MethodGet, path: "/reports/summary?status=open", expectedCode: http.StatusBadRequest},
+		{name: "invalid period", method: http.MethodGet, path: "/reports/summary?period=year", expectedCode: http.StatusBadRequest},
+		{name: "forbidden merchant", method: http.MethodGet, path: "/reports/summary?merchant_id=merchant-forbidden", expectedCode: http.StatusForbidden},
+		{name: "repository error", method: http.MethodGet, path: "/reports/summary?currency=XXX", expectedCode: http.StatusInternalServerError},
+		{name: "wrong method", method: http.MethodPost, path: "/reports/summary", expectedCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			received = usecase.ReportSummaryRequest{}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			reportHandler.GetReportSummary(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.expectedRequest != nil && !reflect.DeepEqual(received, *tt.expectedRequest) {
+				t.Errorf("Expected request %+v, got %+v", *tt.expectedRequest, received)
+			}
+			if tt.expectedCode == http.StatusOK {
+				var response usecase.ReportSummaryResponse
+				json.NewDecoder(recorder.Body).Decode(&response)
+				if len(response.Groups) != 1 || response.Groups[0].AverageAmount != 150 {
+					t.Errorf("Unexpected response %+v", response)
+				}
+			}
+		})
+	}
+}
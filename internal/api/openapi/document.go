@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI specification version of generated documents
+const Version = "3.1.0"
+
+// Document represents an OpenAPI document
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+
+	// componentTypes tracks the Go type behind each schema component
+	componentTypes map[string]reflect.Type
+}
+
+// Info holds the API metadata
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path keyed by lowercase HTTP method
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []SecurityRequirement `json:"security,omitempty"`
+}
+
+// Parameter describes a path, query or header parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes an operation's request body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes an operation's response for a status code
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how clients authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement lists the schemes, and their scopes, an operation accepts
+type SecurityRequirement map[string][]string
+
+// Endpoint describes an API operation in terms of the Go types of its bodies
+type Endpoint struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Tags        []string
+	Deprecated  bool
+
+	// Request is a value of the request body type, nil for operations without a body
+	Request interface{}
+
+	// AlternateRequests maps further accepted request content types to a value of
+	// their body type, e.g. "application/x-ndjson" to the type of a single line
+	AlternateRequests map[string]interface{}
+
+	// Parameters are further parameters of the operation, e.g. query parameters; path
+	// parameters are taken from Path
+	Parameters []Parameter
+
+	// Responses maps status codes to a value of the response body type; nil values
+	// describe responses without a body
+	Responses map[int]interface{}
+
+	// ContentTypes overrides the response content type per status code; defaults to
+	// application/json
+	ContentTypes map[int]string
+
+	// AlternateResponses maps status codes to further content types of the response,
+	// mapped to a value of their body type, e.g. for responses negotiated with Accept
+	AlternateResponses map[int]map[string]interface{}
+
+	// Public operations don't require authentication
+	Public bool
+}
+
+// pathParameterPattern matches the wildcards of a route pattern, e.g. "{id}"
+var pathParameterPattern = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// NewDocument creates an empty document
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]SecurityScheme),
+		},
+		componentTypes: make(map[string]reflect.Type),
+	}
+}
+
+// AddSecurityScheme registers a security scheme that every non-public operation accepts
+func (d *Document) AddSecurityScheme(name string, scheme SecurityScheme) {
+	d.Components.SecuritySchemes[name] = scheme
+	d.Security = append(d.Security, SecurityRequirement{name: []string{}})
+	sort.Slice(d.Security, func(i, j int) bool {
+		return firstKey(d.Security[i]) < firstKey(d.Security[j])
+	})
+}
+
+// AddEndpoint adds the operation, generating schemas for its body types
+func (d *Document) AddEndpoint(endpoint Endpoint) {
+	operation := &Operation{
+		OperationID: endpoint.OperationID,
+		Summary:     endpoint.Summary,
+		Tags:        endpoint.Tags,
+		Deprecated:  endpoint.Deprecated,
+		Responses:   make(map[string]Response),
+	}
+
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(endpoint.Path, -1) {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	operation.Parameters = append(operation.Parameters, endpoint.Parameters...)
+
+	if endpoint.Request != nil || len(endpoint.AlternateRequests) > 0 {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  make(map[string]MediaType),
+		}
+		if endpoint.Request != nil {
+			operation.RequestBody.Content["application/json"] = MediaType{Schema: d.SchemaFor(endpoint.Request)}
+		}
+		for contentType, body := range endpoint.AlternateRequests {
+			operation.RequestBody.Content[contentType] = MediaType{Schema: d.SchemaFor(body)}
+		}
+	}
+
+	for status, body := range endpoint.Responses {
+		response := Response{Description: http.StatusText(status)}
+		if body != nil {
+			contentType := endpoint.ContentTypes[status]
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			response.Content = map[string]MediaType{contentType: {Schema: d.SchemaFor(body)}}
+		}
+		for contentType, body := range endpoint.AlternateResponses[status] {
+			if response.Content == nil {
+				response.Content = make(map[string]MediaType)
+			}
+			response.Content[contentType] = MediaType{Schema: d.SchemaFor(body)}
+		}
+		operation.Responses[strconv.Itoa(status)] = response
+	}
+
+	if endpoint.Public {
+		// An empty requirement allows anonymous requests
+		operation.Security = []SecurityRequirement{{}}
+	}
+
+	path := pathParameterPattern.ReplaceAllString(endpoint.Path, "{$1}")
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(PathItem)
+	}
+	d.Paths[path][strings.ToLower(endpoint.Method)] = operation
+}
+
+// firstKey returns the scheme name of a single-scheme requirement
+func firstKey(requirement SecurityRequirement) string {
+	for name := range requirement {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testEmbedded struct {
+	Source string `json:"source"`
+}
+
+type testRequest struct {
+	testEmbedded
+	Name      string            `json:"name"`
+	Amount    float64           `json:"amount"`
+	Count     int               `json:"count,omitempty"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+	Address   testAddress       `json:"address"`
+	Secret    string            `json:"-"`
+	internal  string
+}
+
+func TestDocument_AddEndpoint(t *testing.T) {
+	// Arrange
+	document := NewDocument(Info{Title: "Test", Version: "1.0.0"})
+
+	// Act
+	document.AddEndpoint(Endpoint{
+		Method:      "POST",
+		Path:        "/v1/things/{id}",
+		OperationID: "createThing",
+		Request:     testRequest{},
+		AlternateRequests: map[string]interface{}{
+			"application/x-ndjson": testAddress{},
+		},
+		Responses: map[int]interface{}{
+			201: testAddress{},
+			204: nil,
+		},
+		ContentTypes: map[int]string{201: "application/problem+json"},
+	})
+
+	// Assert
+	operation := document.Paths["/v1/things/{id}"]["post"]
+	if operation == nil {
+		t.Fatalf("Expected post operation, got paths %v", document.Paths)
+	}
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "id" || operation.Parameters[0].In != "path" {
+		t.Errorf("Expected path parameter 'id', got %+v", operation.Parameters)
+	}
+	if ref := operation.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/testRequest" {
+		t.Errorf("Expected request body to reference its component, got '%s'", ref)
+	}
+	if ref := operation.RequestBody.Content["application/x-ndjson"].Schema.Ref; ref != "#/components/schemas/testAddress" {
+		t.Errorf("Expected alternate request body to reference its component, got '%s'", ref)
+	}
+	if _, ok := operation.Responses["201"].Content["application/problem+json"]; !ok {
+		t.Errorf("Expected overridden content type, got %+v", operation.Responses["201"])
+	}
+	if response := operation.Responses["204"]; response.Description != "No Content" || response.Content != nil {
+		t.Errorf("Expected bodiless 204 response, got %+v", response)
+	}
+
+	schema := document.Components.Schemas["testRequest"]
+	expectedProperties := map[string]Schema{
+		"source":     {Type: "string"},
+		"name":       {Type: "string"},
+		"amount":     {Type: "number"},
+		"count":      {Type: "integer"},
+		"tags":       {Type: "array", Items: &Schema{Type: "string"}},
+		"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+		"created_at": {Type: "string", Format: "date-time"},
+		"deleted_at": {Type: "string", Format: "date-time"},
+		"address":    {Ref: "#/components/schemas/testAddress"},
+	}
+	if len(schema.Properties) != len(expectedProperties) {
+		t.Errorf("Expected %d properties, got %d: %v", len(expectedProperties), len(schema.Properties), schema.Properties)
+	}
+	for name, expected := range expectedProperties {
+		if actual, ok := schema.Properties[name]; !ok || !reflect.DeepEqual(*actual, expected) {
+			t.Errorf("Expected property %s to be %+v, got %+v", name, expected, actual)
+		}
+	}
+
+	expectedRequired := []string{"source", "name", "amount", "tags", "created_at", "address"}
+	if !reflect.DeepEqual(schema.Required, expectedRequired) {
+		t.Errorf("Expected required %v, got %v", expectedRequired, schema.Required)
+	}
+}
+
+func TestDocument_AddEndpoint_NonJSONBody(t *testing.T) {
+	// Arrange
+	document := NewDocument(Info{Title: "Test", Version: "1.0.0"})
+
+	// Act
+	document.AddEndpoint(Endpoint{
+		Method:            "POST",
+		Path:              "/v1/uploads",
+		OperationID:       "upload",
+		AlternateRequests: map[string]interface{}{"text/csv": ""},
+		Parameters: []Parameter{
+			{Name: "dry_run", In: "query", Schema: &Schema{Type: "boolean"}},
+		},
+		Responses:          map[int]interface{}{200: "", 202: nil},
+		ContentTypes:       map[int]string{200: "text/csv"},
+		AlternateResponses: map[int]map[string]interface{}{200: {"application/vnd.apache.parquet": []byte{}}},
+	})
+
+	// Assert
+	operation := document.Paths["/v1/uploads"]["post"]
+	if content := operation.Responses["200"].Content; len(content) != 2 || content["text/csv"].Schema.Type != "string" ||
+		content["application/vnd.apache.parquet"].Schema.Format != "byte" {
+		t.Errorf("Expected text/csv and Parquet responses, got %+v", content)
+	}
+	if operation.Responses["202"].Content != nil {
+		t.Errorf("Expected 202 response without a body, got %+v", operation.Responses["202"].Content)
+	}
+	if operation.RequestBody == nil || len(operation.RequestBody.Content) != 1 ||
+		operation.RequestBody.Content["text/csv"].Schema.Type != "string" {
+		t.Errorf("Expected text/csv request body only, got %+v", operation.RequestBody)
+	}
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "dry_run" || operation.Parameters[0].In != "query" {
+		t.Errorf("Expected query parameter 'dry_run', got %+v", operation.Parameters)
+	}
+}
+
+func TestDocument_PublicEndpoint(t *testing.T) {
+	// Arrange
+	document := NewDocument(Info{Title: "Test", Version: "1.0.0"})
+	document.AddSecurityScheme("ApiKey", SecurityScheme{Type: "apiKey", In: "header", Name: "X-API-Key"})
+
+	// Act
+	document.AddEndpoint(Endpoint{Method: "GET", Path: "/status", OperationID: "status", Public: true})
+	document.AddEndpoint(Endpoint{Method: "GET", Path: "/private", OperationID: "private"})
+
+	// Assert
+	if len(document.Security) != 1 {
+		t.Fatalf("Expected document-wide security requirement, got %v", document.Security)
+	}
+	if security := document.Paths["/status"]["get"].Security; len(security) != 1 || len(security[0]) != 0 {
+		t.Errorf("Expected public operation to allow anonymous requests, got %v", security)
+	}
+	if security := document.Paths["/private"]["get"].Security; security != nil {
+		t.Errorf("Expected private operation to inherit document security, got %v", security)
+	}
+}
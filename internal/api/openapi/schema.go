@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema represents a JSON Schema (draft 2020-12), as used by OpenAPI 3.1
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+// timeType is formatted as an RFC 3339 string by encoding/json
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaFor returns the schema of the value's type; named struct types are added
+// to the components and referenced
+func (d *Document) SchemaFor(value interface{}) *Schema {
+	return d.schemaForType(reflect.TypeOf(value))
+}
+
+// schemaForType builds the schema encoding/json would produce for the type
+func (d *Document) schemaForType(t reflect.Type) *Schema {
+	// Pointer fields are expected to be omitted rather than encoded as null
+	if t.Kind() == reflect.Pointer {
+		return d.schemaForType(t.Elem())
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaForType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		name := d.componentName(t)
+		if _, ok := d.Components.Schemas[name]; !ok {
+			// Reserve the name before descending so recursive types terminate
+			d.Components.Schemas[name] = &Schema{}
+			*d.Components.Schemas[name] = *d.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// Interfaces may hold any value
+		return &Schema{}
+	}
+}
+
+// structSchema builds an object schema from the struct's JSON fields
+// Fields without omitempty are required, since they are always encoded, and fields
+// the struct doesn't declare are not allowed
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	d.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the struct's fields to the schema, flattening embedded structs
+func (d *Document) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				d.addFields(schema, embedded)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = d.schemaForType(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// componentName names the component of a struct type after the type, prefixing the
+// package name when types from different packages share a name
+func (d *Document) componentName(t reflect.Type) string {
+	name := t.Name()
+	existing, ok := d.componentTypes[name]
+	if !ok {
+		d.componentTypes[name] = t
+		return name
+	}
+	if existing == t {
+		return name
+	}
+
+	pkg := []rune(path.Base(t.PkgPath()))
+	pkg[0] = unicode.ToUpper(pkg[0])
+	qualified := string(pkg) + name
+	d.componentTypes[qualified] = t
+	return qualified
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldError describes a value that doesn't match its schema
+// Field is the dotted path of the value, e.g. "amount" or "items[0].name"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Validate checks a JSON value, decoded with json.Decoder.UseNumber, against the schema
+// Component references are resolved against the document
+func (d *Document) Validate(schema *Schema, value interface{}) []FieldError {
+	var errs []FieldError
+	d.validate(schema, value, "", &errs)
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
+
+// validate appends the errors of the value at path to errs
+func (d *Document) validate(schema *Schema, value interface{}, path string, errs *[]FieldError) {
+	if schema.Ref != "" {
+		resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("has unresolvable schema %s", schema.Ref)})
+			return
+		}
+		schema = resolved
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be an object"})
+			return
+		}
+		d.validateObject(schema, object, path, errs)
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be an array"})
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				d.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be a string"})
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				*errs = append(*errs, FieldError{Field: path, Message: "must be an RFC 3339 date-time"})
+			}
+		}
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be a number"})
+		}
+
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be an integer"})
+			return
+		}
+		if _, err := number.Int64(); err != nil {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be an integer"})
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*errs = append(*errs, FieldError{Field: path, Message: "must be a boolean"})
+		}
+	}
+}
+
+// validateObject checks required, declared and additional properties
+func (d *Document) validateObject(schema *Schema, object map[string]interface{}, path string, errs *[]FieldError) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+		}
+	}
+
+	for name, value := range object {
+		if property, ok := schema.Properties[name]; ok {
+			d.validate(property, value, joinPath(path, name), errs)
+			continue
+		}
+
+		switch additional := schema.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is not allowed"})
+			}
+		case *Schema:
+			d.validate(additional, value, joinPath(path, name), errs)
+		}
+	}
+}
+
+// joinPath appends a property name to a dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Validate(t *testing.T) {
+	document := NewDocument(Info{Title: "Test", Version: "1.0.0"})
+	schema := document.SchemaFor(testRequest{})
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []FieldError
+	}{
+		{
+			name: "valid body",
+			body: `{"source": "api", "name": "n", "amount": 1.5, "count": 2, "tags": ["a"],
+				"labels": {"k": "v"}, "created_at": "2023-10-01T10:00:00Z", "address": {"city": "Lisbon"}}`,
+		},
+		{
+			name: "missing required fields",
+			body: `{"source": "api", "name": "n", "amount": 1, "tags": [], "created_at": "2023-10-01T10:00:00Z"}`,
+			expected: []FieldError{
+				{Field: "address", Message: "is required"},
+			},
+		},
+		{
+			name: "wrong types and unknown fields",
+			body: `{"source": 1, "name": "n", "amount": "1", "count": 1.5, "tags": ["a", 2],
+				"labels": {"k": true}, "created_at": "yesterday", "address": {"city": "Lisbon", "zip": "1000"},
+				"extra": null}`,
+			expected: []FieldError{
+				{Field: "address.zip", Message: "is not allowed"},
+				{Field: "amount", Message: "must be a number"},
+				{Field: "count", Message: "must be an integer"},
+				{Field: "created_at", Message: "must be an RFC 3339 date-time"},
+				{Field: "extra", Message: "is not allowed"},
+				{Field: "labels.k", Message: "must be a string"},
+				{Field: "source", Message: "must be a string"},
+				{Field: "tags[1]", Message: "must be a string"},
+			},
+		},
+		{
+			name:     "not an object",
+			body:     `[]`,
+			expected: []FieldError{{Field: "", Message: "must be an object"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			decoder := json.NewDecoder(strings.NewReader(tt.body))
+			decoder.UseNumber()
+			var value interface{}
+			if err := decoder.Decode(&value); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+
+			// Act
+			errs := document.Validate(schema, value)
+
+			// Assert
+			if !reflect.DeepEqual(errs, tt.expected) {
+				t.Errorf("Expected errors %+v, got %+v", tt.expected, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// Package bootstrap builds the stores shared by the commands from their configuration
+//
+// The API, the importer and the maintenance commands open the chargeback store through
+// it, so every write goes through the same cache and observers whichever command makes it.
+package bootstrap
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
+)
+
+// StorageConfig selects the chargeback store and the stores of the features following
+// chargeback writes
+type StorageConfig struct {
+	// StorageBackend selects where chargebacks are kept: "dynamodb", "postgres", "sqlite"
+	// (a local file, for single-node deployments) or "memory" (per instance, lost on
+	// restart; for local development)
+	StorageBackend string
+	DynamoDB       db.DynamoDBConfig
+	// DynamoDBResilience wraps the DynamoDB calls of every store in retries and a circuit
+	// breaker
+	DynamoDBResilience ResilienceConfig
+	Postgres           PostgresConfig
+	SQLite             db.SQLiteConfig
+	Cache              CacheConfig
+	MerchantStats      MerchantStatsConfig
+	Reports            ReportsConfig
+}
+
+// PostgresConfig holds the PostgreSQL storage backend configuration
+type PostgresConfig struct {
+	DSN          string
+	MaxOpenConns int
+	// AutoMigrate applies pending schema migrations at startup; when disabled, run cmd/migrate
+	AutoMigrate bool
+}
+
+// ResilienceConfig holds the retry and circuit breaker policies of DynamoDB calls
+type ResilienceConfig struct {
+	Retry   dynamoRepo.RetryPolicy
+	Breaker dynamoRepo.CircuitBreakerPolicy
+}
+
+// CacheConfig holds the chargeback lookup cache configuration
+type CacheConfig struct {
+	Enabled bool
+	// Backend selects where cached chargebacks are kept: "memory" (per instance, so an
+	// instance may serve a chargeback changed through another until its TTL runs out) or
+	// "redis" (shared)
+	Backend string
+	// Size is the most entries the memory backend holds
+	Size int
+	TTL  time.Duration
+	// NegativeTTL is how long lookups that found no chargeback are cached; 0 disables it
+	NegativeTTL time.Duration
+	Redis       cache.RedisConfig
+}
+
+// ReportsConfig holds the report summary configuration
+type ReportsConfig struct {
+	Enabled bool
+	// Store selects where report counters are kept: "dynamodb" or "sql" (the postgres or
+	// sqlite storage backend's database), rebuilt with cmd/rebuild-reports, or "memory"
+	// (per instance, counting writes since startup)
+	Store string
+	Table string
+}
+
+// MerchantStatsConfig holds the merchant chargeback ratio monitoring configuration
+type MerchantStatsConfig struct {
+	Enabled bool
+	// Store selects where monthly statistics are kept: "dynamodb", "sql" (the postgres or
+	// sqlite storage backend's database) or "memory" (per instance)
+	Store string
+	Table string
+	// Thresholds are the chargeback ratios alerts are raised at
+	Thresholds []entity.RatioThreshold
+	// AlertWebhookURL receives alerts as JSON when set
+	AlertWebhookURL string
+}
+
+// LoadStorageConfig reads the storage configuration from the environment
+func LoadStorageConfig() StorageConfig {
+	backend := strings.ToLower(GetEnvOrDefault("STORAGE_BACKEND", "dynamodb"))
+	return StorageConfig{
+		StorageBackend: backend,
+		DynamoDB: db.DynamoDBConfig{
+			Endpoint:    GetEnvOrDefault("DYNAMODB_ENDPOINT", ""),
+			Region:      GetEnvOrDefault("AWS_REGION", "us-east-1"),
+			TableName:   GetEnvOrDefault("DYNAMODB_TABLE", "chargebacks"),
+			EnsureTable: GetBoolOrDefault("DYNAMODB_ENSURE_TABLE", false),
+			Layout:      strings.ToLower(GetEnvOrDefault("DYNAMODB_TABLE_LAYOUT", db.LayoutFlat)),
+		},
+		DynamoDBResilience: ResilienceConfig{
+			Retry: dynamoRepo.RetryPolicy{
+				MaxAttempts: GetIntOrDefault("DYNAMODB_RETRY_MAX_ATTEMPTS", dynamoRepo.DefaultRetryPolicy().MaxAttempts),
+				BaseDelay:   GetDurationOrDefault("DYNAMODB_RETRY_BASE_DELAY", dynamoRepo.DefaultRetryPolicy().BaseDelay),
+				MaxDelay:    GetDurationOrDefault("DYNAMODB_RETRY_MAX_DELAY", dynamoRepo.DefaultRetryPolicy().MaxDelay),
+			},
+			Breaker: dynamoRepo.CircuitBreakerPolicy{
+				FailureThreshold: GetIntOrDefault("DYNAMODB_BREAKER_FAILURE_THRESHOLD", dynamoRepo.DefaultCircuitBreakerPolicy().FailureThreshold),
+				OpenTimeout:      GetDurationOrDefault("DYNAMODB_BREAKER_OPEN_TIMEOUT", dynamoRepo.DefaultCircuitBreakerPolicy().OpenTimeout),
+			},
+		},
+		Postgres: PostgresConfig{
+			DSN:          GetEnvOrDefault("POSTGRES_DSN", ""),
+			MaxOpenConns: GetIntOrDefault("POSTGRES_MAX_OPEN_CONNS", 10),
+			AutoMigrate:  GetBoolOrDefault("POSTGRES_AUTO_MIGRATE", true),
+		},
+		SQLite: db.SQLiteConfig{
+			Path: GetEnvOrDefault("SQLITE_PATH", "chargebacks.db"),
+		},
+		Cache: CacheConfig{
+			Enabled:     GetBoolOrDefault("CHARGEBACK_CACHE_ENABLED", false),
+			Backend:     strings.ToLower(GetEnvOrDefault("CHARGEBACK_CACHE_BACKEND", "memory")),
+			Size:        GetIntOrDefault("CHARGEBACK_CACHE_SIZE", 10000),
+			TTL:         GetDurationOrDefault("CHARGEBACK_CACHE_TTL", 30*time.Second),
+			NegativeTTL: GetDurationOrDefault("CHARGEBACK_CACHE_NEGATIVE_TTL", 5*time.Second),
+			Redis: cache.RedisConfig{
+				Addr:      GetEnvOrDefault("REDIS_ADDR", "localhost:6379"),
+				Password:  GetEnvOrDefault("REDIS_PASSWORD", ""),
+				DB:        GetIntOrDefault("REDIS_DB", 0),
+				KeyPrefix: GetEnvOrDefault("REDIS_KEY_PREFIX", "chargeback-api:"),
+			},
+		},
+		MerchantStats: MerchantStatsConfig{
+			Enabled:         GetBoolOrDefault("MERCHANT_STATS_ENABLED", true),
+			Store:           strings.ToLower(GetEnvOrDefault("MERCHANT_STATS_STORE", DefaultStore(backend))),
+			Table:           GetEnvOrDefault("MERCHANT_STATS_TABLE", "merchant_stats"),
+			Thresholds:      parseRatioThresholds(GetEnvOrDefault("RATIO_THRESHOLDS", "early_warning=0.0065:75,excessive=0.009:100")),
+			AlertWebhookURL: GetEnvOrDefault("ALERT_WEBHOOK_URL", ""),
+		},
+		Reports: ReportsConfig{
+			Enabled: GetBoolOrDefault("REPORTS_ENABLED", true),
+			Store:   strings.ToLower(GetEnvOrDefault("REPORTS_STORE", DefaultStore(backend))),
+			Table:   GetEnvOrDefault("REPORTS_TABLE", "report_counters"),
+		},
+	}
+}
+
+// DefaultStore returns the store of a feature that isn't configured: the storage
+// backend's own database, so every instance and command shares it, or memory for the
+// memory backend
+func DefaultStore(backend string) string {
+	switch backend {
+	case "postgres", "sqlite":
+		return "sql"
+	case "memory":
+		return "memory"
+	default:
+		return "dynamodb"
+	}
+}
+
+// ValidateStore checks that a feature's store is one of memory, dynamodb and, on the
+// postgres and sqlite storage backends, sql
+func (c StorageConfig) ValidateStore(feature, store string) error {
+	switch store {
+	case "memory", "dynamodb":
+		return nil
+	case "sql":
+		if c.StorageBackend == "postgres" || c.StorageBackend == "sqlite" {
+			return nil
+		}
+		return fmt.Errorf("%s store 'sql' requires the postgres or sqlite storage backend, got '%s'", feature, c.StorageBackend)
+	default:
+		return fmt.Errorf("%s store must be 'memory', 'dynamodb' or 'sql', got '%s'", feature, store)
+	}
+}
+
+// Validate checks the storage configuration
+func (c StorageConfig) Validate() error {
+	if c.DynamoDB.Region == "" {
+		return fmt.Errorf("AWS region is required")
+	}
+	if c.DynamoDB.TableName == "" {
+		return fmt.Errorf("DynamoDB table name is required")
+	}
+	switch c.StorageBackend {
+	case "dynamodb":
+		switch c.DynamoDB.Layout {
+		case "", db.LayoutFlat, db.LayoutSingleTable:
+		default:
+			return fmt.Errorf("DynamoDB table layout must be 'flat' or 'single', got '%s'", c.DynamoDB.Layout)
+		}
+		// Zero-valued policies, as in configurations built in tests, disable retries and the breaker
+		retry, breaker := c.DynamoDBResilience.Retry, c.DynamoDBResilience.Breaker
+		if retry.MaxAttempts < 0 || retry.BaseDelay < 0 || retry.MaxDelay < retry.BaseDelay {
+			return fmt.Errorf("DynamoDB retry policy must have non-negative attempts and 0 <= base delay <= max delay")
+		}
+		if breaker.FailureThreshold < 0 || (breaker.FailureThreshold > 0 && breaker.OpenTimeout <= 0) {
+			return fmt.Errorf("DynamoDB circuit breaker must have a non-negative failure threshold and a positive open timeout")
+		}
+	case "memory":
+	case "postgres":
+		if c.Postgres.DSN == "" {
+			return fmt.Errorf("PostgreSQL DSN is required for the postgres storage backend")
+		}
+		if c.Postgres.MaxOpenConns <= 0 {
+			return fmt.Errorf("PostgreSQL max open connections must be positive")
+		}
+	case "sqlite":
+		if c.SQLite.Path == "" {
+			return fmt.Errorf("SQLite path is required for the sqlite storage backend")
+		}
+	default:
+		return fmt.Errorf("storage backend must be 'dynamodb', 'postgres', 'sqlite' or 'memory', got '%s'", c.StorageBackend)
+	}
+	if c.Cache.Enabled {
+		switch c.Cache.Backend {
+		case "memory":
+			if c.Cache.Size <= 0 {
+				return fmt.Errorf("chargeback cache size must be positive")
+			}
+		case "redis":
+			if c.Cache.Redis.Addr == "" {
+				return fmt.Errorf("Redis address is required for the redis cache backend")
+			}
+		default:
+			return fmt.Errorf("chargeback cache backend must be 'memory' or 'redis', got '%s'", c.Cache.Backend)
+		}
+		if c.Cache.TTL <= 0 || c.Cache.NegativeTTL < 0 {
+			return fmt.Errorf("chargeback cache TTL must be positive and negative TTL non-negative")
+		}
+	}
+	if c.MerchantStats.Enabled {
+		if err := c.ValidateStore("merchant stats", c.MerchantStats.Store); err != nil {
+			return err
+		}
+		if c.MerchantStats.Store == "dynamodb" && c.MerchantStats.Table == "" {
+			return fmt.Errorf("merchant stats table name is required")
+		}
+		for _, threshold := range c.MerchantStats.Thresholds {
+			if threshold.Ratio <= 0 || threshold.Ratio > 1 {
+				return fmt.Errorf("ratio threshold '%s' must be between 0 and 1, got %v", threshold.Name, threshold.Ratio)
+			}
+		}
+		if webhookURL := c.MerchantStats.AlertWebhookURL; webhookURL != "" {
+			if parsed, err := url.Parse(webhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("alert webhook URL must be an http(s) URL, got '%s'", webhookURL)
+			}
+		}
+	}
+	if c.Reports.Enabled {
+		if err := c.ValidateStore("reports", c.Reports.Store); err != nil {
+			return err
+		}
+		if c.Reports.Store == "dynamodb" && c.Reports.Table == "" {
+			return fmt.Errorf("reports table name is required")
+		}
+	}
+	return nil
+}
+
+// parseRatioThresholds parses "NAME=RATIO:MIN_CHARGEBACKS" pairs separated by commas, e.g.
+// "early_warning=0.0065:75,excessive=0.009:100"; invalid entries are skipped
+func parseRatioThresholds(value string) []entity.RatioThreshold {
+	var thresholds []entity.RatioThreshold
+	for _, pair := range strings.Split(value, ",") {
+		name, rawThreshold, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			continue
+		}
+
+		rawRatio, rawMinimum, _ := strings.Cut(rawThreshold, ":")
+		ratio, err := strconv.ParseFloat(strings.TrimSpace(rawRatio), 64)
+		if err != nil {
+			log.Printf("⚠️  Warning: invalid ratio for threshold %q: %q, ignoring", name, rawRatio)
+			continue
+		}
+
+		minimum := 0
+		if rawMinimum != "" {
+			if minimum, err = strconv.Atoi(strings.TrimSpace(rawMinimum)); err != nil || minimum < 0 {
+				log.Printf("⚠️  Warning: invalid minimum chargebacks for threshold %q: %q, ignoring", name, rawMinimum)
+				continue
+			}
+		}
+
+		thresholds = append(thresholds, entity.RatioThreshold{
+			Name:           strings.TrimSpace(name),
+			Ratio:          ratio,
+			MinChargebacks: minimum,
+		})
+	}
+	return thresholds
+}
@@ -0,0 +1,117 @@
+package bootstrap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestParseRatioThresholds(t *testing.T) {
+	// Act
+	thresholds := parseRatioThresholds("early_warning=0.0065:75, excessive = 0.009,bad=high:1,negative=0.01:-1,malformed")
+
+	// Assert
+	expected := []entity.RatioThreshold{
+		{Name: "early_warning", Ratio: 0.0065, MinChargebacks: 75},
+		{Name: "excessive", Ratio: 0.009},
+	}
+	if !reflect.DeepEqual(thresholds, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, thresholds)
+	}
+}
+
+func TestLoadStorageConfig(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "SQLite")
+	t.Setenv("SQLITE_PATH", "/var/lib/chargebacks.db")
+	t.Setenv("REPORTS_ENABLED", "false")
+
+	// Act
+	config := LoadStorageConfig()
+
+	// Assert
+	if config.StorageBackend != "sqlite" || config.SQLite.Path != "/var/lib/chargebacks.db" {
+		t.Errorf("Expected the sqlite backend at the configured path, got %q at %q", config.StorageBackend, config.SQLite.Path)
+	}
+	if config.Reports.Enabled || !config.MerchantStats.Enabled {
+		t.Errorf("Expected reports disabled and merchant stats enabled by default, got %+v and %+v", config.Reports, config.MerchantStats)
+	}
+	if config.MerchantStats.Store != "sql" || config.Reports.Store != "sql" {
+		t.Errorf("Expected the stores to default to the sqlite database, got %q and %q", config.MerchantStats.Store, config.Reports.Store)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected the loaded configuration to be valid, got %v", err)
+	}
+}
+
+func TestDefaultStore(t *testing.T) {
+	tests := []struct {
+		backend  string
+		expected string
+	}{
+		{backend: "dynamodb", expected: "dynamodb"},
+		{backend: "postgres", expected: "sql"},
+		{backend: "sqlite", expected: "sql"},
+		{backend: "memory", expected: "memory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			// Act
+			store := DefaultStore(tt.backend)
+
+			// Assert
+			if store != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, store)
+			}
+		})
+	}
+}
+
+func TestStorageConfig_ValidateStore(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     string
+		store       string
+		expectError bool
+	}{
+		{name: "memory", backend: "dynamodb", store: "memory"},
+		{name: "dynamodb on a SQL backend", backend: "postgres", store: "dynamodb"},
+		{name: "sql on postgres", backend: "postgres", store: "sql"},
+		{name: "sql on sqlite", backend: "sqlite", store: "sql"},
+		{name: "sql on dynamodb", backend: "dynamodb", store: "sql", expectError: true},
+		{name: "sql on memory", backend: "memory", store: "sql", expectError: true},
+		{name: "unknown store", backend: "sqlite", store: "redis", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := StorageConfig{StorageBackend: tt.backend}.ValidateStore("reports", tt.store)
+
+			// Assert
+			if tt.expectError && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadStorageConfig_SharesStoresOnDynamoDB(t *testing.T) {
+	// Arrange
+	t.Setenv("STORAGE_BACKEND", "")
+	t.Setenv("MERCHANT_STATS_STORE", "")
+	t.Setenv("REPORTS_STORE", "")
+
+	// Act
+	config := LoadStorageConfig()
+
+	// Assert
+	if config.MerchantStats.Store != "dynamodb" || config.Reports.Store != "dynamodb" {
+		t.Errorf("Expected the stores to default to DynamoDB, got %q and %q", config.MerchantStats.Store, config.Reports.Store)
+	}
+}
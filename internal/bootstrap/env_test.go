@@ -0,0 +1,134 @@
+package bootstrap
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGetEnvOrDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		key          string
+		defaultValue string
+		envValue     string
+		expected     string
+	}{
+		{
+			name:         "returns environment variable when set",
+			key:          "TEST_KEY",
+			defaultValue: "default",
+			envValue:     "environment_value",
+			expected:     "environment_value",
+		},
+		{
+			name:         "returns default when environment variable not set",
+			key:          "UNSET_KEY",
+			defaultValue: "default_value",
+			envValue:     "",
+			expected:     "default_value",
+		},
+		{
+			name:         "returns default when environment variable is empty",
+			key:          "EMPTY_KEY",
+			defaultValue: "default",
+			envValue:     "",
+			expected:     "default",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv(tt.key, tt.envValue)
+				defer os.Unsetenv(tt.key)
+			}
+
+			// Act
+			result := GetEnvOrDefault(tt.key, tt.defaultValue)
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("GetEnvOrDefault(%s, %s) = %s, want %s", tt.key, tt.defaultValue, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetDurationOrDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue time.Duration
+		expected     time.Duration
+	}{
+		{
+			name:         "returns parsed duration when set",
+			envValue:     "750ms",
+			defaultValue: 2 * time.Second,
+			expected:     750 * time.Millisecond,
+		},
+		{
+			name:         "returns default when not set",
+			envValue:     "",
+			defaultValue: 2 * time.Second,
+			expected:     2 * time.Second,
+		},
+		{
+			name:         "returns default when invalid",
+			envValue:     "soon",
+			defaultValue: 5 * time.Second,
+			expected:     5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv("TEST_DURATION", tt.envValue)
+				defer os.Unsetenv("TEST_DURATION")
+			}
+
+			// Act
+			result := GetDurationOrDefault("TEST_DURATION", tt.defaultValue)
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("GetDurationOrDefault() = %s, want %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetBoolOrDefault(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue bool
+		expected     bool
+	}{
+		{"returns parsed value when set", "false", true, false},
+		{"returns default when not set", "", true, true},
+		{"returns default when invalid", "maybe", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setup
+			if tt.envValue != "" {
+				os.Setenv("TEST_BOOL", tt.envValue)
+				defer os.Unsetenv("TEST_BOOL")
+			}
+
+			// Act
+			result := GetBoolOrDefault("TEST_BOOL", tt.defaultValue)
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("GetBoolOrDefault() = %t, want %t", result, tt.expected)
+			}
+		})
+	}
+}
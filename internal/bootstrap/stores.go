@@ -0,0 +1,257 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/alerting"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Stores holds the chargeback store and the stores of the features following its writes
+type Stores struct {
+	// Chargebacks is the selected chargeback store, wrapped in the cache and the observers
+	// of the enabled features
+	Chargebacks  repository.ChargebackRepository
+	DynamoClient *dynamodb.Client
+	// DynamoDB wraps DynamoClient in the retry and circuit breaker policies; every DynamoDB
+	// store goes through it, so they share its breaker and its call statistics
+	DynamoDB *dynamoRepo.ResilientDynamoDB
+	// SQLDB is nil unless the postgres or sqlite storage backend is selected
+	SQLDB *sql.DB
+	// ChargebackCache is nil unless the chargeback cache is enabled
+	ChargebackCache *dynamoRepo.CachedChargebackRepository
+	// Redis is nil unless the chargeback cache uses the redis backend
+	Redis *cache.RedisCache
+	// MerchantStats and AlertSink are nil unless merchant ratio monitoring is enabled;
+	// AlertSink is also nil without an alert webhook
+	MerchantStats repository.MerchantStatsRepository
+	AlertSink     service.AlertSink
+	// ReportCounters is nil unless report summaries are enabled
+	ReportCounters repository.ReportCounterRepository
+}
+
+// OpenStores opens the stores of the configuration
+// Features that follow chargeback writes register observers; the chargeback store is
+// wrapped once so every write notifies all of them.
+func OpenStores(ctx context.Context, config StorageConfig, logger service.Logger) (*Stores, error) {
+	dynamoClient, err := db.NewDynamoDBClient(ctx, config.DynamoDB)
+	if err != nil {
+		logger.Error(ctx, "Failed to initialize DynamoDB client", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, fmt.Errorf("failed to initialize DynamoDB client: %w", err)
+	}
+	stores := &Stores{
+		DynamoClient: dynamoClient,
+		DynamoDB:     dynamoRepo.NewResilientDynamoDB(dynamoClient, config.DynamoDBResilience.Retry, config.DynamoDBResilience.Breaker, logger),
+	}
+
+	switch config.StorageBackend {
+	case "memory":
+		logger.Warn(ctx, "Chargebacks are kept in memory and lost on restart", nil)
+		stores.Chargebacks = dynamoRepo.NewMemoryChargebackRepository()
+	case "postgres":
+		stores.SQLDB, err = openPostgres(ctx, config.Postgres, logger)
+		if err != nil {
+			logger.Error(ctx, "Failed to connect to PostgreSQL", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return nil, err
+		}
+		stores.Chargebacks = dynamoRepo.NewPostgresChargebackRepository(stores.SQLDB)
+	case "sqlite":
+		stores.SQLDB, err = openSQLite(ctx, config.SQLite, logger)
+		if err != nil {
+			logger.Error(ctx, "Failed to open SQLite database", map[string]interface{}{
+				"error": err.Error(),
+				"path":  config.SQLite.Path,
+			})
+			return nil, err
+		}
+		stores.Chargebacks = dynamoRepo.NewSQLiteChargebackRepository(stores.SQLDB)
+	default:
+		// Test DynamoDB connection
+		if err := testDynamoDBConnection(ctx, dynamoClient, config.DynamoDB, logger); err != nil {
+			logger.Error(ctx, "Failed to connect to DynamoDB", map[string]interface{}{
+				"error":      err.Error(),
+				"table_name": config.DynamoDB.TableName,
+			})
+			return nil, fmt.Errorf("failed to connect to DynamoDB: %w", err)
+		}
+		stores.Chargebacks = dynamoRepo.NewDynamoDBChargebackRepositoryForLayout(stores.DynamoDB, config.DynamoDB.TableName, config.DynamoDB.Layout)
+	}
+
+	if config.Cache.Enabled {
+		var store cache.Cache
+		if config.Cache.Backend == "redis" {
+			stores.Redis, err = cache.NewRedisCache(ctx, config.Cache.Redis)
+			if err != nil {
+				logger.Error(ctx, "Failed to connect to Redis", map[string]interface{}{
+					"error": err.Error(),
+					"addr":  config.Cache.Redis.Addr,
+				})
+				stores.Close()
+				return nil, err
+			}
+			store = stores.Redis
+		} else {
+			store = cache.NewLRUCache(config.Cache.Size)
+		}
+		stores.ChargebackCache = dynamoRepo.NewCachedChargebackRepository(stores.Chargebacks, store, config.Cache.TTL, config.Cache.NegativeTTL, logger)
+		stores.Chargebacks = stores.ChargebackCache
+
+		logger.Info(ctx, "Chargeback cache enabled", map[string]interface{}{
+			"backend":      config.Cache.Backend,
+			"ttl":          config.Cache.TTL.String(),
+			"negative_ttl": config.Cache.NegativeTTL.String(),
+		})
+	}
+
+	var observers []repository.ChargebackObserver
+	if config.MerchantStats.Enabled {
+		switch config.MerchantStats.Store {
+		case "dynamodb":
+			stores.MerchantStats = dynamoRepo.NewDynamoDBMerchantStatsRepository(stores.DynamoDB, config.MerchantStats.Table)
+		case "sql":
+			stores.MerchantStats = dynamoRepo.NewSQLMerchantStatsRepository(stores.SQLDB)
+		default:
+			stores.MerchantStats = dynamoRepo.NewMemoryMerchantStatsRepository()
+		}
+		if config.MerchantStats.Store == "memory" && config.StorageBackend != "memory" {
+			logger.Warn(ctx, "Merchant statistics are kept in memory: ratios and alerts only count this process's writes", nil)
+		}
+		if config.MerchantStats.AlertWebhookURL != "" {
+			stores.AlertSink = alerting.NewWebhookSink(config.MerchantStats.AlertWebhookURL)
+		}
+		observers = append(observers, usecase.NewMerchantStatsRecorder(stores.MerchantStats, config.MerchantStats.Thresholds, logger, stores.AlertSink))
+
+		logger.Info(ctx, "Merchant ratio monitoring enabled", map[string]interface{}{
+			"store":         config.MerchantStats.Store,
+			"thresholds":    len(config.MerchantStats.Thresholds),
+			"alert_webhook": config.MerchantStats.AlertWebhookURL != "",
+		})
+	}
+	if config.Reports.Enabled {
+		switch config.Reports.Store {
+		case "dynamodb":
+			stores.ReportCounters = dynamoRepo.NewDynamoDBReportCounterRepository(stores.DynamoDB, config.Reports.Table)
+		case "sql":
+			stores.ReportCounters = dynamoRepo.NewSQLReportCounterRepository(stores.SQLDB)
+		default:
+			stores.ReportCounters = dynamoRepo.NewMemoryReportCounterRepository()
+		}
+		if config.Reports.Store == "memory" && config.StorageBackend != "memory" {
+			logger.Warn(ctx, "Report counters are kept in memory: summaries only count this process's writes since it started", nil)
+		}
+		observers = append(observers, usecase.NewReportCounterRecorder(stores.ReportCounters))
+
+		logger.Info(ctx, "Report summaries enabled", map[string]interface{}{
+			"store": config.Reports.Store,
+		})
+	}
+	if len(observers) > 0 {
+		stores.Chargebacks = dynamoRepo.NewObservedChargebackRepository(stores.Chargebacks, logger, observers...)
+	}
+
+	return stores, nil
+}
+
+// Close releases the database and cache connections
+func (s *Stores) Close() {
+	if s.SQLDB != nil {
+		s.SQLDB.Close()
+	}
+	if s.Redis != nil {
+		s.Redis.Close()
+	}
+}
+
+// openPostgres connects to PostgreSQL and, when enabled, applies pending migrations
+func openPostgres(ctx context.Context, config PostgresConfig, logger service.Logger) (*sql.DB, error) {
+	sqlDB, err := db.NewPostgresDB(ctx, db.PostgresConfig{DSN: config.DSN, MaxOpenConns: config.MaxOpenConns})
+	if err != nil {
+		return nil, err
+	}
+	if !config.AutoMigrate {
+		return sqlDB, nil
+	}
+
+	if err := migrate(ctx, sqlDB, db.PostgresMigrations, logger); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to migrate PostgreSQL schema: %w", err)
+	}
+	return sqlDB, nil
+}
+
+// openSQLite opens the SQLite database and applies pending migrations
+func openSQLite(ctx context.Context, config db.SQLiteConfig, logger service.Logger) (*sql.DB, error) {
+	sqlDB, err := db.NewSQLiteDB(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(ctx, sqlDB, db.SQLiteMigrations, logger); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to migrate SQLite schema: %w", err)
+	}
+	return sqlDB, nil
+}
+
+// migrate applies the pending migrations and logs them
+func migrate(ctx context.Context, sqlDB *sql.DB, load func() ([]db.Migration, error), logger service.Logger) error {
+	migrations, err := load()
+	if err != nil {
+		return err
+	}
+	applied, err := db.NewMigrator(sqlDB, migrations).Up(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range applied {
+		logger.Info(ctx, "Applied schema migration", map[string]interface{}{
+			"version": migration.Version,
+			"name":    migration.Name,
+		})
+	}
+	return nil
+}
+
+func testDynamoDBConnection(ctx context.Context, client *dynamodb.Client, config db.DynamoDBConfig, logger service.Logger) error {
+	logger.Info(ctx, "Testing DynamoDB connection", map[string]interface{}{
+		"table_name": config.TableName,
+		"layout":     config.Layout,
+	})
+
+	schema := db.ChargebackTableSchemaForLayout(config.Layout, config.TableName)
+	if config.EnsureTable {
+		logger.Info(ctx, "Ensuring DynamoDB table and indexes exist", map[string]interface{}{
+			"table_name": config.TableName,
+		})
+		if err := db.EnsureTable(ctx, client, schema); err != nil {
+			return err
+		}
+	}
+
+	// The repository queries the indexes, so a table without them fails at startup
+	// rather than on the first lookup
+	if err := db.VerifyTable(ctx, client, schema); err != nil {
+		logger.Error(ctx, "DynamoDB connection test failed", map[string]interface{}{
+			"error":      err.Error(),
+			"table_name": config.TableName,
+		})
+		return fmt.Errorf("%w (set DYNAMODB_ENSURE_TABLE=true to create the table and its indexes)", err)
+	}
+
+	logger.Info(ctx, "DynamoDB connection test successful", map[string]interface{}{
+		"table_name": config.TableName,
+	})
+	return nil
+}
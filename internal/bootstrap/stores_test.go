@@ -0,0 +1,129 @@
+package bootstrap
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/logging"
+	dynamoRepo "github.com/DiegoSantos90/chargeback-api/internal/infra/repository"
+)
+
+// newTestLogger creates a logger discarding its output
+func newTestLogger(t *testing.T) service.Logger {
+	t.Helper()
+	logger, err := logging.NewStructuredLogger(logging.LoggerConfig{
+		Level:       service.LogLevelError,
+		Format:      logging.FormatJSON,
+		ServiceName: "chargeback-api",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return logger
+}
+
+func TestOpenStores_ObservesChargebackWrites(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	config := StorageConfig{
+		StorageBackend: "sqlite",
+		DynamoDB:       db.DynamoDBConfig{Region: "us-east-1", TableName: "chargebacks"},
+		SQLite:         db.SQLiteConfig{Path: filepath.Join(t.TempDir(), "chargebacks.db")},
+		Cache:          CacheConfig{Enabled: true, Backend: "memory", Size: 10, TTL: time.Minute},
+		MerchantStats:  MerchantStatsConfig{Enabled: true, Store: "sql"},
+		Reports:        ReportsConfig{Enabled: true, Store: "sql"},
+	}
+	stores, err := OpenStores(ctx, config, newTestLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to open stores: %v", err)
+	}
+	defer stores.Close()
+
+	chargebackDate := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	chargeback := &entity.Chargeback{
+		ID:             "cb_1",
+		TransactionID:  "txn_1",
+		MerchantID:     "merchant_1",
+		Amount:         120.5,
+		Currency:       "USD",
+		Reason:         entity.ReasonFraud,
+		Status:         entity.StatusPending,
+		ChargebackDate: chargebackDate,
+		CreatedAt:      chargebackDate,
+		UpdatedAt:      chargebackDate,
+	}
+
+	// Act
+	err = stores.Chargebacks.Save(ctx, chargeback)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	stats, err := stores.MerchantStats.Find(ctx, "merchant_1", entity.StatsMonth(chargebackDate))
+	if err != nil || stats == nil || stats.ChargebackCount != 1 {
+		t.Errorf("Expected the merchant statistics to count the chargeback, got %+v, %v", stats, err)
+	}
+	day := chargebackDate.Format(entity.ReportDayLayout)
+	counters, err := stores.ReportCounters.List(ctx, day, day)
+	if err != nil || len(counters) != 1 || counters[0].Count != 1 {
+		t.Errorf("Expected the report counters to count the chargeback, got %+v, %v", counters, err)
+	}
+	if stores.ChargebackCache == nil {
+		t.Error("Expected the chargeback cache to be enabled")
+	}
+
+	// Another command opening the same database sees the counts
+	reopened, err := OpenStores(ctx, config, newTestLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to reopen stores: %v", err)
+	}
+	defer reopened.Close()
+	stats, err = reopened.MerchantStats.Find(ctx, "merchant_1", entity.StatsMonth(chargebackDate))
+	if err != nil || stats == nil || stats.ChargebackCount != 1 {
+		t.Errorf("Expected the merchant statistics to be kept in the database, got %+v, %v", stats, err)
+	}
+}
+
+func TestOpenStores_SharesTheResilientDynamoDBClient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	config := StorageConfig{
+		StorageBackend: "memory",
+		// Nothing listens there, so every call fails
+		DynamoDB: db.DynamoDBConfig{Endpoint: "http://127.0.0.1:1", Region: "us-east-1", TableName: "chargebacks"},
+		DynamoDBResilience: ResilienceConfig{
+			Retry:   dynamoRepo.RetryPolicy{MaxAttempts: 1},
+			Breaker: dynamoRepo.DefaultCircuitBreakerPolicy(),
+		},
+		MerchantStats: MerchantStatsConfig{Enabled: true, Store: "dynamodb", Table: "merchant_stats"},
+		Reports:       ReportsConfig{Enabled: true, Store: "dynamodb", Table: "report_counters"},
+	}
+	stores, err := OpenStores(ctx, config, newTestLogger(t))
+	if err != nil {
+		t.Fatalf("Failed to open stores: %v", err)
+	}
+	defer stores.Close()
+	statsCtx, cancelStats := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancelStats()
+	countersCtx, cancelCounters := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancelCounters()
+
+	// Act
+	_, statsErr := stores.MerchantStats.Find(statsCtx, "merchant_1", "2026-03")
+	_, countersErr := stores.ReportCounters.List(countersCtx, "2026-03-01", "2026-03-31")
+
+	// Assert
+	if statsErr == nil || countersErr == nil {
+		t.Fatalf("Expected the calls to fail, got %v and %v", statsErr, countersErr)
+	}
+	if calls := stores.DynamoDB.Stats().Calls; calls < 2 {
+		t.Errorf("Expected both stores to call DynamoDB through the resilient client, got %d calls", calls)
+	}
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnauthenticated is returned when a request carries no valid credentials
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden is returned when the authenticated principal may not perform an operation
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Scope represents a permission granted to a credential
+type Scope string
+
+const (
+	ScopeChargebacksRead   Scope = "chargebacks:read"
+	ScopeChargebacksWrite  Scope = "chargebacks:write"
+	ScopeChargebacksReview Scope = "chargebacks:review"
+	// ScopeChargebacksReviewHighValue allows approving chargebacks above the high-value threshold
+	ScopeChargebacksReviewHighValue Scope = "chargebacks:review_high_value"
+	// ScopeMerchantsWrite allows reporting merchants' monthly transaction volumes
+	ScopeMerchantsWrite Scope = "merchants:write"
+	ScopeAdmin          Scope = "admin"
+)
+
+// IsValid checks if the scope is one of the known scopes
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeChargebacksRead, ScopeChargebacksWrite, ScopeChargebacksReview,
+		ScopeChargebacksReviewHighValue, ScopeMerchantsWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Principal represents the authenticated caller of a request
+type Principal struct {
+	// Subject identifies the credential, e.g. the API key ID
+	Subject string
+
+	// MerchantIDs lists the merchants the principal may act on behalf of
+	MerchantIDs []string
+
+	// AllMerchants grants access to every merchant, e.g. for internal staff
+	AllMerchants bool
+
+	// Roles lists the roles the scopes were derived from, if any
+	Roles []Role
+
+	// Scopes lists the permissions granted to the principal
+	Scopes []Scope
+}
+
+// HasScope checks if the principal was granted the given scope
+// Admin principals implicitly hold every scope
+func (p *Principal) HasScope(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessMerchant checks if the principal may act on behalf of the merchant
+// Admin principals may access every merchant
+func (p *Principal) CanAccessMerchant(merchantID string) bool {
+	if p.AllMerchants || p.HasScope(ScopeAdmin) {
+		return true
+	}
+	for _, id := range p.MerchantIDs {
+		if id == merchantID {
+			return true
+		}
+	}
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// RequireScope checks that the principal in ctx holds the scope
+// When no principal is present authentication is disabled and the check passes
+func RequireScope(ctx context.Context, scope Scope) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !principal.HasScope(scope) {
+		return fmt.Errorf("%w: missing scope %s", ErrForbidden, scope)
+	}
+
+	return nil
+}
+
+// RequireMerchantAccess checks that the principal in ctx holds the scope for the merchant
+// When no principal is present authentication is disabled and the check passes
+func RequireMerchantAccess(ctx context.Context, scope Scope, merchantID string) error {
+	if err := RequireScope(ctx, scope); err != nil {
+		return err
+	}
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !principal.CanAccessMerchant(merchantID) {
+		return fmt.Errorf("%w: no access to merchant %s", ErrForbidden, merchantID)
+	}
+
+	return nil
+}
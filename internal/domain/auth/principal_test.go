@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScope_IsValid(t *testing.T) {
+	tests := []struct {
+		scope    Scope
+		expected bool
+	}{
+		{ScopeChargebacksRead, true},
+		{ScopeChargebacksWrite, true},
+		{ScopeMerchantsWrite, true},
+		{ScopeAdmin, true},
+		{Scope("chargebacks:delete"), false},
+		{Scope(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scope), func(t *testing.T) {
+			if got := tt.scope.IsValid(); got != tt.expected {
+				t.Errorf("Scope(%q).IsValid() = %v, want %v", tt.scope, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	t.Run("returns true for granted scope", func(t *testing.T) {
+		p := &Principal{Scopes: []Scope{ScopeChargebacksRead}}
+		if !p.HasScope(ScopeChargebacksRead) {
+			t.Error("Expected principal to hold chargebacks:read")
+		}
+		if p.HasScope(ScopeChargebacksWrite) {
+			t.Error("Expected principal not to hold chargebacks:write")
+		}
+	})
+
+	t.Run("admin holds every scope", func(t *testing.T) {
+		p := &Principal{Scopes: []Scope{ScopeAdmin}}
+		if !p.HasScope(ScopeChargebacksWrite) {
+			t.Error("Expected admin to hold chargebacks:write")
+		}
+	})
+}
+
+func TestPrincipal_CanAccessMerchant(t *testing.T) {
+	p := &Principal{MerchantIDs: []string{"merchant-1", "merchant-2"}, Scopes: []Scope{ScopeChargebacksRead}}
+
+	if !p.CanAccessMerchant("merchant-2") {
+		t.Error("Expected access to merchant-2")
+	}
+	if p.CanAccessMerchant("merchant-3") {
+		t.Error("Expected no access to merchant-3")
+	}
+
+	admin := &Principal{Scopes: []Scope{ScopeAdmin}}
+	if !admin.CanAccessMerchant("merchant-3") {
+		t.Error("Expected admin to access any merchant")
+	}
+}
+
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Error("Expected no principal in empty context")
+	}
+
+	p := &Principal{Subject: "key-1"}
+	ctx := ContextWithPrincipal(context.Background(), p)
+
+	got, ok := PrincipalFromContext(ctx)
+	if !ok || got.Subject != "key-1" {
+		t.Errorf("Expected principal 'key-1', got %v", got)
+	}
+}
+
+func TestRequireMerchantAccess(t *testing.T) {
+	merchantKey := &Principal{MerchantIDs: []string{"merchant-1"}, Scopes: []Scope{ScopeChargebacksWrite}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		scope     Scope
+		merchant  string
+		forbidden bool
+	}{
+		{
+			name:     "allows when authentication is disabled",
+			ctx:      context.Background(),
+			scope:    ScopeChargebacksWrite,
+			merchant: "merchant-9",
+		},
+		{
+			name:     "allows own merchant with scope",
+			ctx:      ContextWithPrincipal(context.Background(), merchantKey),
+			scope:    ScopeChargebacksWrite,
+			merchant: "merchant-1",
+		},
+		{
+			name:      "rejects other merchant",
+			ctx:       ContextWithPrincipal(context.Background(), merchantKey),
+			scope:     ScopeChargebacksWrite,
+			merchant:  "merchant-2",
+			forbidden: true,
+		},
+		{
+			name:      "rejects missing scope",
+			ctx:       ContextWithPrincipal(context.Background(), merchantKey),
+			scope:     ScopeChargebacksRead,
+			merchant:  "merchant-1",
+			forbidden: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RequireMerchantAccess(tt.ctx, tt.scope, tt.merchant)
+			if tt.forbidden && !errors.Is(err, ErrForbidden) {
+				t.Errorf("Expected ErrForbidden, got %v", err)
+			}
+			if !tt.forbidden && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
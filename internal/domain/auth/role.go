@@ -0,0 +1,60 @@
+package auth
+
+// Role represents a staff role granted by the identity provider
+type Role string
+
+const (
+	RoleViewer     Role = "viewer"
+	RoleAnalyst    Role = "analyst"
+	RoleSupervisor Role = "supervisor"
+	RoleAdmin      Role = "admin"
+)
+
+// roleScopes maps each role to the scopes it grants
+// Roles are cumulative: each role includes the permissions of the roles below it
+var roleScopes = map[Role][]Scope{
+	RoleViewer: {
+		ScopeChargebacksRead,
+	},
+	RoleAnalyst: {
+		ScopeChargebacksRead,
+		ScopeChargebacksWrite,
+		ScopeChargebacksReview,
+	},
+	RoleSupervisor: {
+		ScopeChargebacksRead,
+		ScopeChargebacksWrite,
+		ScopeChargebacksReview,
+		ScopeChargebacksReviewHighValue,
+		ScopeMerchantsWrite,
+	},
+	RoleAdmin: {
+		ScopeAdmin,
+	},
+}
+
+// IsValid checks if the role is one of the known roles
+func (r Role) IsValid() bool {
+	_, ok := roleScopes[r]
+	return ok
+}
+
+// Scopes returns the scopes granted by the role
+func (r Role) Scopes() []Scope {
+	return append([]Scope(nil), roleScopes[r]...)
+}
+
+// ScopesForRoles returns the de-duplicated union of the scopes granted by the roles
+func ScopesForRoles(roles []Role) []Scope {
+	seen := make(map[Scope]bool)
+	var scopes []Scope
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
@@ -0,0 +1,86 @@
+package auth
+
+import "testing"
+
+func TestRole_IsValid(t *testing.T) {
+	tests := []struct {
+		role     Role
+		expected bool
+	}{
+		{RoleViewer, true},
+		{RoleAnalyst, true},
+		{RoleSupervisor, true},
+		{RoleAdmin, true},
+		{Role("auditor"), false},
+		{Role(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			if got := tt.role.IsValid(); got != tt.expected {
+				t.Errorf("Role(%q).IsValid() = %v, want %v", tt.role, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRole_Permissions(t *testing.T) {
+	tests := []struct {
+		role     Role
+		granted  []Scope
+		withheld []Scope
+	}{
+		{
+			role:     RoleViewer,
+			granted:  []Scope{ScopeChargebacksRead},
+			withheld: []Scope{ScopeChargebacksWrite, ScopeChargebacksReview, ScopeChargebacksReviewHighValue, ScopeAdmin},
+		},
+		{
+			role:     RoleAnalyst,
+			granted:  []Scope{ScopeChargebacksRead, ScopeChargebacksWrite, ScopeChargebacksReview},
+			withheld: []Scope{ScopeChargebacksReviewHighValue, ScopeMerchantsWrite, ScopeAdmin},
+		},
+		{
+			role:     RoleSupervisor,
+			granted:  []Scope{ScopeChargebacksRead, ScopeChargebacksWrite, ScopeChargebacksReview, ScopeChargebacksReviewHighValue, ScopeMerchantsWrite},
+			withheld: []Scope{ScopeAdmin},
+		},
+		{
+			role:    RoleAdmin,
+			granted: []Scope{ScopeChargebacksRead, ScopeChargebacksReviewHighValue, ScopeAdmin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			p := &Principal{Roles: []Role{tt.role}, Scopes: tt.role.Scopes()}
+
+			for _, scope := range tt.granted {
+				if !p.HasScope(scope) {
+					t.Errorf("Expected %s to hold %s", tt.role, scope)
+				}
+			}
+			for _, scope := range tt.withheld {
+				if p.HasScope(scope) {
+					t.Errorf("Expected %s not to hold %s", tt.role, scope)
+				}
+			}
+		})
+	}
+}
+
+func TestScopesForRoles(t *testing.T) {
+	t.Run("merges scopes without duplicates", func(t *testing.T) {
+		scopes := ScopesForRoles([]Role{RoleViewer, RoleAnalyst})
+
+		if len(scopes) != 3 {
+			t.Errorf("Expected 3 scopes, got %v", scopes)
+		}
+	})
+
+	t.Run("ignores unknown roles", func(t *testing.T) {
+		if scopes := ScopesForRoles([]Role{"auditor"}); len(scopes) != 0 {
+			t.Errorf("Expected no scopes, got %v", scopes)
+		}
+	})
+}
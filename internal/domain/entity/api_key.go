@@ -0,0 +1,198 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// apiKeyPrefix identifies raw API keys issued by this service
+const apiKeyPrefix = "cbk_"
+
+// APIKeyStatus represents the possible statuses of an API key
+type APIKeyStatus string
+
+const (
+	APIKeyStatusActive  APIKeyStatus = "active"
+	APIKeyStatusRevoked APIKeyStatus = "revoked"
+)
+
+// APIKey represents a credential issued to an API client
+// Only the SHA-256 hash of the secret is kept; the raw key is shown once on issue or rotation
+type APIKey struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	SecretHash  string       `json:"-"`
+	MerchantIDs []string     `json:"merchant_ids"`
+	Scopes      []auth.Scope `json:"scopes"`
+	Status      APIKeyStatus `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	RotatedAt   time.Time    `json:"rotated_at"`
+	RevokedAt   time.Time    `json:"revoked_at"`
+}
+
+// CreateAPIKeyRequest represents the data needed to issue a new API key
+type CreateAPIKeyRequest struct {
+	Name        string       `json:"name"`
+	MerchantIDs []string     `json:"merchant_ids"`
+	Scopes      []auth.Scope `json:"scopes"`
+}
+
+// Validate validates the create API key request
+func (req *CreateAPIKeyRequest) Validate() error {
+	var errors []string
+
+	if strings.TrimSpace(req.Name) == "" {
+		errors = append(errors, "name is required")
+	}
+
+	if len(req.Scopes) == 0 {
+		errors = append(errors, "at least one scope is required")
+	}
+
+	isAdmin := false
+	for _, scope := range req.Scopes {
+		if !scope.IsValid() {
+			errors = append(errors, fmt.Sprintf("invalid scope '%s'", scope))
+		}
+		if scope == auth.ScopeAdmin {
+			isAdmin = true
+		}
+	}
+
+	if !isAdmin && len(req.MerchantIDs) == 0 {
+		errors = append(errors, "at least one merchant ID is required")
+	}
+
+	for _, merchantID := range req.MerchantIDs {
+		if strings.TrimSpace(merchantID) == "" {
+			errors = append(errors, "merchant IDs cannot be empty")
+			break
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+// NewAPIKey creates a new API key from a request, returning the entity and the raw key
+func NewAPIKey(req CreateAPIKeyRequest) (*APIKey, string, error) {
+	if err := req.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key ID: %w", err)
+	}
+
+	now := time.Now()
+	key := &APIKey{
+		ID:          hex.EncodeToString(idBytes),
+		Name:        req.Name,
+		MerchantIDs: req.MerchantIDs,
+		Scopes:      req.Scopes,
+		Status:      APIKeyStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	rawKey, err := key.newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, rawKey, nil
+}
+
+// ParseAPIKey splits a raw API key into its ID and secret
+func ParseAPIKey(rawKey string) (string, string, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return "", "", errors.New("malformed API key")
+	}
+
+	id, secret, ok := strings.Cut(strings.TrimPrefix(rawKey, apiKeyPrefix), "_")
+	if !ok || id == "" || secret == "" {
+		return "", "", errors.New("malformed API key")
+	}
+
+	return id, secret, nil
+}
+
+// Verify checks the secret against the stored hash in constant time
+func (k *APIKey) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(k.SecretHash)) == 1
+}
+
+// IsActive checks if the API key can be used to authenticate
+func (k *APIKey) IsActive() bool {
+	return k.Status == APIKeyStatusActive
+}
+
+// Rotate replaces the secret, invalidating the previous raw key, and returns the new raw key
+func (k *APIKey) Rotate() (string, error) {
+	if !k.IsActive() {
+		return "", errors.New("only active API keys can be rotated")
+	}
+
+	rawKey, err := k.newSecret()
+	if err != nil {
+		return "", err
+	}
+
+	k.RotatedAt = time.Now()
+	k.UpdatedAt = k.RotatedAt
+	return rawKey, nil
+}
+
+// Revoke permanently disables the API key
+func (k *APIKey) Revoke() error {
+	if !k.IsActive() {
+		return errors.New("API key is already revoked")
+	}
+
+	k.Status = APIKeyStatusRevoked
+	k.RevokedAt = time.Now()
+	k.UpdatedAt = k.RevokedAt
+	return nil
+}
+
+// Principal returns the authenticated identity granted by the API key
+func (k *APIKey) Principal() *auth.Principal {
+	return &auth.Principal{
+		Subject:     k.ID,
+		MerchantIDs: k.MerchantIDs,
+		Scopes:      k.Scopes,
+	}
+}
+
+// newSecret generates a random secret, stores its hash and returns the raw key
+func (k *APIKey) newSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate API key secret: %w", err)
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	k.SecretHash = hashSecret(secret)
+
+	return apiKeyPrefix + k.ID + "_" + secret, nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of the secret
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
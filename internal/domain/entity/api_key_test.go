@@ -0,0 +1,175 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+func TestCreateAPIKeyRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         CreateAPIKeyRequest
+		expectedErr string
+	}{
+		{
+			name: "valid merchant key",
+			req: CreateAPIKeyRequest{
+				Name:        "acme integration",
+				MerchantIDs: []string{"merchant-1"},
+				Scopes:      []auth.Scope{auth.ScopeChargebacksWrite},
+			},
+		},
+		{
+			name: "valid admin key without merchants",
+			req: CreateAPIKeyRequest{
+				Name:   "ops",
+				Scopes: []auth.Scope{auth.ScopeAdmin},
+			},
+		},
+		{
+			name: "missing name",
+			req: CreateAPIKeyRequest{
+				MerchantIDs: []string{"merchant-1"},
+				Scopes:      []auth.Scope{auth.ScopeChargebacksRead},
+			},
+			expectedErr: "name is required",
+		},
+		{
+			name: "merchant key without merchants",
+			req: CreateAPIKeyRequest{
+				Name:   "acme",
+				Scopes: []auth.Scope{auth.ScopeChargebacksRead},
+			},
+			expectedErr: "at least one merchant ID is required",
+		},
+		{
+			name: "invalid scope",
+			req: CreateAPIKeyRequest{
+				Name:        "acme",
+				MerchantIDs: []string{"merchant-1"},
+				Scopes:      []auth.Scope{"chargebacks:delete"},
+			},
+			expectedErr: "invalid scope",
+		},
+		{
+			name: "missing scopes",
+			req: CreateAPIKeyRequest{
+				Name:        "acme",
+				MerchantIDs: []string{"merchant-1"},
+			},
+			expectedErr: "at least one scope is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("Expected error containing '%s', got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, rawKey, err := NewAPIKey(CreateAPIKeyRequest{
+		Name:        "acme integration",
+		MerchantIDs: []string{"merchant-1"},
+		Scopes:      []auth.Scope{auth.ScopeChargebacksWrite},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if key.Status != APIKeyStatusActive {
+		t.Errorf("Expected status active, got %s", key.Status)
+	}
+
+	if strings.Contains(key.SecretHash, rawKey) || key.SecretHash == "" {
+		t.Error("Expected only the secret hash to be stored")
+	}
+
+	id, secret, err := ParseAPIKey(rawKey)
+	if err != nil {
+		t.Fatalf("Expected raw key to parse, got %v", err)
+	}
+	if id != key.ID {
+		t.Errorf("Expected ID %s, got %s", key.ID, id)
+	}
+	if !key.Verify(secret) {
+		t.Error("Expected secret to verify")
+	}
+	if key.Verify(secret + "x") {
+		t.Error("Expected tampered secret not to verify")
+	}
+}
+
+func TestParseAPIKey(t *testing.T) {
+	tests := []struct {
+		rawKey string
+		valid  bool
+	}{
+		{"cbk_0123abcd_s3cr_et", true},
+		{"cbk_0123abcd", false},
+		{"cbk__secret", false},
+		{"other_0123abcd_secret", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawKey, func(t *testing.T) {
+			_, _, err := ParseAPIKey(tt.rawKey)
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid key, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected error for malformed key")
+			}
+		})
+	}
+}
+
+func TestAPIKey_RotateAndRevoke(t *testing.T) {
+	key, rawKey, _ := NewAPIKey(CreateAPIKeyRequest{
+		Name:   "ops",
+		Scopes: []auth.Scope{auth.ScopeAdmin},
+	})
+	_, oldSecret, _ := ParseAPIKey(rawKey)
+
+	newRawKey, err := key.Rotate()
+	if err != nil {
+		t.Fatalf("Expected no error rotating, got %v", err)
+	}
+	_, newSecret, _ := ParseAPIKey(newRawKey)
+
+	if key.Verify(oldSecret) {
+		t.Error("Expected old secret to stop working after rotation")
+	}
+	if !key.Verify(newSecret) {
+		t.Error("Expected new secret to verify")
+	}
+	if key.RotatedAt.IsZero() {
+		t.Error("Expected RotatedAt to be set")
+	}
+
+	if err := key.Revoke(); err != nil {
+		t.Fatalf("Expected no error revoking, got %v", err)
+	}
+	if key.IsActive() {
+		t.Error("Expected key to be inactive after revoke")
+	}
+	if err := key.Revoke(); err == nil {
+		t.Error("Expected error revoking twice")
+	}
+	if _, err := key.Rotate(); err == nil {
+		t.Error("Expected error rotating revoked key")
+	}
+}
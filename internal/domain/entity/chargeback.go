@@ -41,6 +41,9 @@ type Chargeback struct {
 	ChargebackDate  time.Time        `json:"chargeback_date"`
 	CreatedAt       time.Time        `json:"created_at"`
 	UpdatedAt       time.Time        `json:"updated_at"`
+	// DeletedAt is set when the chargeback is soft deleted; repositories keep deleted
+	// chargebacks for record keeping but leave them out of lookups and listings
+	DeletedAt time.Time `json:"deleted_at,omitzero"`
 }
 
 // CreateChargebackRequest represents the data needed to create a new chargeback
@@ -107,7 +110,7 @@ func NewChargeback(req CreateChargebackRequest) (*Chargeback, error) {
 		MerchantID:      req.MerchantID,
 		Amount:          req.Amount,
 		Currency:        req.Currency,
-		CardNumber:      maskCardNumber(req.CardNumber),
+		CardNumber:      MaskCardNumber(req.CardNumber),
 		Reason:          req.Reason,
 		Status:          StatusPending, // Always starts as pending
 		Description:     req.Description,
@@ -140,6 +143,11 @@ func (c *Chargeback) Reject() error {
 	return nil
 }
 
+// IsDeleted reports whether the chargeback is soft deleted
+func (c *Chargeback) IsDeleted() bool {
+	return !c.DeletedAt.IsZero()
+}
+
 // IsValid checks if the chargeback has all required fields
 func (c *Chargeback) IsValid() bool {
 	return c.TransactionID != "" &&
@@ -170,8 +178,9 @@ func isValidReason(reason ChargebackReason) bool {
 	return false
 }
 
-// maskCardNumber masks the card number showing only the last 4 digits
-func maskCardNumber(cardNumber string) string {
+// MaskCardNumber masks the card number showing only the last 4 digits
+// Masking a masked card number leaves it unchanged
+func MaskCardNumber(cardNumber string) string {
 	// Remove any spaces or special characters
 	cleaned := strings.ReplaceAll(cardNumber, " ", "")
 	cleaned = strings.ReplaceAll(cleaned, "-", "")
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChargebackIDPrefix starts every chargeback ID
+const ChargebackIDPrefix = "cb_"
+
+// ErrInvalidChargebackID is returned when parsing a malformed chargeback ID
+var ErrInvalidChargebackID = errors.New("invalid chargeback ID")
+
+// ulidAlphabet is Crockford's base32, the alphabet of ULIDs
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the length of a ULID: 10 characters of millisecond timestamp followed by
+// 16 of randomness
+const ulidLength = 26
+
+// ChargebackID identifies a chargeback: "cb_" followed by a ULID
+// IDs generated before ULIDs were adopted hold a decimal timestamp in nanoseconds instead,
+// e.g. cb_1697040000000000000; they remain valid.
+type ChargebackID string
+
+// ParseChargebackID validates a chargeback ID, e.g. from a path parameter
+// ULIDs are case-insensitive and returned in upper case.
+func ParseChargebackID(s string) (ChargebackID, error) {
+	value, ok := strings.CutPrefix(s, ChargebackIDPrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: %q must start with %s", ErrInvalidChargebackID, s, ChargebackIDPrefix)
+	}
+
+	if isLegacyChargebackID(value) {
+		return ChargebackID(s), nil
+	}
+
+	value = strings.ToUpper(value)
+	if len(value) != ulidLength || value[0] > '7' || strings.Trim(value, ulidAlphabet) != "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidChargebackID, s)
+	}
+	return ChargebackID(ChargebackIDPrefix + value), nil
+}
+
+// String returns the ID as stored
+func (id ChargebackID) String() string {
+	return string(id)
+}
+
+// isLegacyChargebackID reports whether the value, without prefix, is a decimal timestamp
+func isLegacyChargebackID(value string) bool {
+	if value == "" || len(value) > 19 {
+		return false
+	}
+	return strings.Trim(value, "0123456789") == ""
+}
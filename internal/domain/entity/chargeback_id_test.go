@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseChargebackID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ChargebackID
+		wantErr  bool
+	}{
+		{name: "ULID", input: "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV", expected: "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "lower case ULID", input: "cb_01arz3ndektsv4rrffq69g5fav", expected: "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV"},
+		{name: "legacy timestamp", input: "cb_1697040000000000000", expected: "cb_1697040000000000000"},
+		{name: "missing prefix", input: "01ARZ3NDEKTSV4RRFFQ69G5FAV", wantErr: true},
+		{name: "other prefix", input: "cbk_01ARZ3NDEKTSV4RRFFQ69G5FAV", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+		{name: "prefix only", input: "cb_", wantErr: true},
+		{name: "too short", input: "cb_01ARZ3NDEKTSV4RRFFQ69G5FA", wantErr: true},
+		{name: "letter outside the alphabet", input: "cb_01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+		{name: "timestamp overflow", input: "cb_81ARZ3NDEKTSV4RRFFQ69G5FAV", wantErr: true},
+		{name: "path traversal", input: "cb_../../etc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			id, err := ParseChargebackID(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidChargebackID) {
+					t.Errorf("Expected ErrInvalidChargebackID, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, id)
+			}
+		})
+	}
+}
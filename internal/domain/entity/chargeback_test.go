@@ -528,6 +528,35 @@ func TestChargeback_IsValid(t *testing.T) {
 	}
 }
 
+func TestChargeback_IsDeleted(t *testing.T) {
+	tests := []struct {
+		name       string
+		chargeback *Chargeback
+		expected   bool
+	}{
+		{
+			name:       "active chargeback",
+			chargeback: &Chargeback{},
+			expected:   false,
+		},
+		{
+			name:       "soft deleted chargeback",
+			chargeback: &Chargeback{DeletedAt: time.Now()},
+			expected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.chargeback.IsDeleted()
+
+			if result != tt.expected {
+				t.Errorf("Expected IsDeleted() to return %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestIsValidReason(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -623,14 +652,19 @@ func TestMaskCardNumber(t *testing.T) {
 			input:    "",
 			expected: "****",
 		},
+		{
+			name:     "masked card number",
+			input:    "************3456",
+			expected: "************3456",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := maskCardNumber(tt.input)
+			result := MaskCardNumber(tt.input)
 
 			if result != tt.expected {
-				t.Errorf("Expected maskCardNumber(%s) to return %s, got %s", tt.input, tt.expected, result)
+				t.Errorf("Expected MaskCardNumber(%s) to return %s, got %s", tt.input, tt.expected, result)
 			}
 		})
 	}
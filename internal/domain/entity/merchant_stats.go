@@ -0,0 +1,146 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatsMonthLayout is the layout of the calendar months merchant statistics are kept for
+const StatsMonthLayout = "2006-01"
+
+// StatsMonth returns the calendar month, in UTC, the time falls in
+func StatsMonth(t time.Time) string {
+	return t.UTC().Format(StatsMonthLayout)
+}
+
+// ParseStatsMonth validates a calendar month such as "2023-10"
+func ParseStatsMonth(month string) (string, error) {
+	t, err := time.Parse(StatsMonthLayout, month)
+	if err != nil {
+		return "", fmt.Errorf("invalid month %q, expected YYYY-MM", month)
+	}
+	return t.Format(StatsMonthLayout), nil
+}
+
+// MerchantMonthlyStats aggregates a merchant's chargebacks and sales for a calendar month
+// Chargebacks count toward the month they were received in. Rejected chargebacks were
+// found invalid, so they are kept apart and don't count toward the ratios.
+type MerchantMonthlyStats struct {
+	MerchantID string `json:"merchant_id"`
+	Month      string `json:"month"`
+
+	ChargebackCount  int     `json:"chargeback_count"`
+	ChargebackAmount float64 `json:"chargeback_amount"`
+	RejectedCount    int     `json:"rejected_count"`
+	RejectedAmount   float64 `json:"rejected_amount"`
+
+	// TransactionCount and TransactionAmount are the month's sales, as reported by the acquirer
+	TransactionCount  int     `json:"transaction_count"`
+	TransactionAmount float64 `json:"transaction_amount"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CountRatio returns the share of the month's transactions that were disputed
+// It is zero until the month's transaction count is known
+func (s *MerchantMonthlyStats) CountRatio() float64 {
+	if s.TransactionCount <= 0 {
+		return 0
+	}
+	return float64(s.ChargebackCount) / float64(s.TransactionCount)
+}
+
+// AmountRatio returns the share of the month's sales amount that was disputed
+// It is zero until the month's transaction amount is known
+func (s *MerchantMonthlyStats) AmountRatio() float64 {
+	if s.TransactionAmount <= 0 {
+		return 0
+	}
+	return s.ChargebackAmount / s.TransactionAmount
+}
+
+// Apply adds the delta's counters to the statistics
+func (s *MerchantMonthlyStats) Apply(delta MerchantStatsDelta) {
+	s.ChargebackCount += delta.ChargebackCount
+	s.ChargebackAmount += delta.ChargebackAmount
+	s.RejectedCount += delta.RejectedCount
+	s.RejectedAmount += delta.RejectedAmount
+}
+
+// MerchantStatsDelta is a change to a merchant's chargeback counters for a month
+type MerchantStatsDelta struct {
+	ChargebackCount  int
+	ChargebackAmount float64
+	RejectedCount    int
+	RejectedAmount   float64
+}
+
+// IsZero reports whether the delta changes nothing
+func (d MerchantStatsDelta) IsZero() bool {
+	return d == MerchantStatsDelta{}
+}
+
+// Add returns the sum of the two deltas
+func (d MerchantStatsDelta) Add(other MerchantStatsDelta) MerchantStatsDelta {
+	return MerchantStatsDelta{
+		ChargebackCount:  d.ChargebackCount + other.ChargebackCount,
+		ChargebackAmount: d.ChargebackAmount + other.ChargebackAmount,
+		RejectedCount:    d.RejectedCount + other.RejectedCount,
+		RejectedAmount:   d.RejectedAmount + other.RejectedAmount,
+	}
+}
+
+// Negate returns the delta that undoes this one
+func (d MerchantStatsDelta) Negate() MerchantStatsDelta {
+	return MerchantStatsDelta{
+		ChargebackCount:  -d.ChargebackCount,
+		ChargebackAmount: -d.ChargebackAmount,
+		RejectedCount:    -d.RejectedCount,
+		RejectedAmount:   -d.RejectedAmount,
+	}
+}
+
+// StatsContribution returns what the chargeback adds to its merchant's statistics for
+// the month it was received in
+func (c *Chargeback) StatsContribution() (month string, delta MerchantStatsDelta) {
+	if c.Status == StatusRejected {
+		return StatsMonth(c.ChargebackDate), MerchantStatsDelta{RejectedCount: 1, RejectedAmount: c.Amount}
+	}
+	return StatsMonth(c.ChargebackDate), MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: c.Amount}
+}
+
+// RatioThreshold is a chargeback ratio merchants' monthly statistics are monitored against
+type RatioThreshold struct {
+	// Name identifies the threshold in alerts, e.g. "early_warning"
+	Name string
+
+	// Ratio is the share of the month's transactions that may be disputed
+	Ratio float64
+
+	// MinChargebacks is the number of chargebacks below which the threshold is never reached
+	MinChargebacks int
+}
+
+// ExceededBy checks if the statistics reach the threshold
+// Statistics without a transaction count never do, as their ratio is unknown
+func (t RatioThreshold) ExceededBy(stats *MerchantMonthlyStats) bool {
+	return stats.TransactionCount > 0 &&
+		stats.ChargebackCount >= t.MinChargebacks &&
+		stats.CountRatio() >= t.Ratio
+}
+
+// MerchantRatioAlert is raised when a merchant's chargeback ratio for a month reaches
+// a monitoring threshold
+type MerchantRatioAlert struct {
+	MerchantID string `json:"merchant_id"`
+	Month      string `json:"month"`
+
+	// Threshold names the threshold that was reached, e.g. "early_warning"
+	Threshold      string  `json:"threshold"`
+	ThresholdRatio float64 `json:"threshold_ratio"`
+
+	Ratio            float64   `json:"ratio"`
+	ChargebackCount  int       `json:"chargeback_count"`
+	TransactionCount int       `json:"transaction_count"`
+	RaisedAt         time.Time `json:"raised_at"`
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMerchantMonthlyStats_Ratios(t *testing.T) {
+	tests := []struct {
+		name                string
+		stats               MerchantMonthlyStats
+		expectedCountRatio  float64
+		expectedAmountRatio float64
+	}{
+		{
+			name:                "with transaction volume",
+			stats:               MerchantMonthlyStats{ChargebackCount: 9, ChargebackAmount: 450, TransactionCount: 1000, TransactionAmount: 50000},
+			expectedCountRatio:  0.009,
+			expectedAmountRatio: 0.009,
+		},
+		{
+			name:  "without transaction volume",
+			stats: MerchantMonthlyStats{ChargebackCount: 9, ChargebackAmount: 450},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ratio := tt.stats.CountRatio(); ratio != tt.expectedCountRatio {
+				t.Errorf("Expected count ratio %v, got %v", tt.expectedCountRatio, ratio)
+			}
+			if ratio := tt.stats.AmountRatio(); ratio != tt.expectedAmountRatio {
+				t.Errorf("Expected amount ratio %v, got %v", tt.expectedAmountRatio, ratio)
+			}
+		})
+	}
+}
+
+func TestChargeback_StatsContribution(t *testing.T) {
+	chargeback := &Chargeback{
+		Amount:         25,
+		Status:         StatusPending,
+		ChargebackDate: time.Date(2023, 10, 31, 23, 30, 0, 0, time.FixedZone("UTC-3", -3*60*60)),
+	}
+
+	month, pending := chargeback.StatsContribution()
+	chargeback.Status = StatusRejected
+	_, rejected := chargeback.StatsContribution()
+
+	if month != "2023-11" {
+		t.Errorf("Expected the UTC month 2023-11, got %s", month)
+	}
+	if pending != (MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: 25}) {
+		t.Errorf("Unexpected pending contribution %+v", pending)
+	}
+	if rejected != (MerchantStatsDelta{RejectedCount: 1, RejectedAmount: 25}) {
+		t.Errorf("Unexpected rejected contribution %+v", rejected)
+	}
+
+	stats := MerchantMonthlyStats{}
+	stats.Apply(pending)
+	stats.Apply(pending.Negate())
+	stats.Apply(rejected)
+	if stats.ChargebackCount != 0 || stats.RejectedCount != 1 || stats.RejectedAmount != 25 {
+		t.Errorf("Expected a rejected chargeback to move out of the ratio, got %+v", stats)
+	}
+}
+
+func TestParseStatsMonth(t *testing.T) {
+	if month, err := ParseStatsMonth("2023-10"); err != nil || month != "2023-10" {
+		t.Errorf("Expected 2023-10, got %q, %v", month, err)
+	}
+	if _, err := ParseStatsMonth("2023-13"); err == nil {
+		t.Error("Expected invalid month to be rejected")
+	}
+}
+
+func TestRatioThreshold_ExceededBy(t *testing.T) {
+	threshold := RatioThreshold{Name: "excessive", Ratio: 0.009, MinChargebacks: 100}
+
+	tests := []struct {
+		name     string
+		stats    MerchantMonthlyStats
+		expected bool
+	}{
+		{
+			name:     "ratio and count reached",
+			stats:    MerchantMonthlyStats{ChargebackCount: 100, TransactionCount: 10000},
+			expected: true,
+		},
+		{
+			name:  "ratio reached below the minimum count",
+			stats: MerchantMonthlyStats{ChargebackCount: 99, TransactionCount: 1000},
+		},
+		{
+			name:  "count reached below the ratio",
+			stats: MerchantMonthlyStats{ChargebackCount: 100, TransactionCount: 100000},
+		},
+		{
+			name:  "transaction count unknown",
+			stats: MerchantMonthlyStats{ChargebackCount: 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if exceeded := threshold.ExceededBy(&tt.stats); exceeded != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, exceeded)
+			}
+		})
+	}
+}
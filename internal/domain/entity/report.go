@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReportDayLayout is the layout of the days report counters are kept for
+const ReportDayLayout = "2006-01-02"
+
+// ReportCell identifies the chargebacks of a merchant received on a day with the same
+// reason, status and currency
+// Cells are the finest grain reports are kept at; any grouping by merchant, reason,
+// status, currency and period is rolled up from them.
+type ReportCell struct {
+	Day        string
+	MerchantID string
+	Reason     ChargebackReason
+	Status     ChargebackStatus
+	Currency   string
+}
+
+// ReportCounter holds the number and total amount of the chargebacks in a cell
+type ReportCounter struct {
+	ReportCell
+	Count  int
+	Amount float64
+}
+
+// ReportCell returns the report cell the chargeback is counted in
+// Chargebacks count toward the UTC day they were received on
+func (c *Chargeback) ReportCell() ReportCell {
+	return ReportCell{
+		Day:        c.ChargebackDate.UTC().Format(ReportDayLayout),
+		MerchantID: c.MerchantID,
+		Reason:     c.Reason,
+		Status:     c.Status,
+		Currency:   c.Currency,
+	}
+}
+
+// ReportPeriod is the length of the periods reports are grouped by
+type ReportPeriod string
+
+const (
+	ReportPeriodDay   ReportPeriod = "day"
+	ReportPeriodWeek  ReportPeriod = "week"
+	ReportPeriodMonth ReportPeriod = "month"
+)
+
+// IsValid checks if the period is one of the known periods
+func (p ReportPeriod) IsValid() bool {
+	switch p {
+	case ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// Label returns the period a day falls in, e.g. "2023-10-16", "2023-W42" or "2023-10"
+// Weeks are ISO 8601 weeks, which start on Monday
+func (p ReportPeriod) Label(day string) (string, error) {
+	t, err := time.Parse(ReportDayLayout, day)
+	if err != nil {
+		return "", fmt.Errorf("invalid day %q, expected YYYY-MM-DD", day)
+	}
+
+	switch p {
+	case ReportPeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case ReportPeriodMonth:
+		return t.Format(StatsMonthLayout), nil
+	default:
+		return t.Format(ReportDayLayout), nil
+	}
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChargeback_ReportCell(t *testing.T) {
+	chargeback := &Chargeback{
+		MerchantID:     "merchant-789",
+		Reason:         ReasonFraud,
+		Status:         StatusPending,
+		Currency:       "USD",
+		ChargebackDate: time.Date(2023, 10, 15, 23, 30, 0, 0, time.FixedZone("UTC-3", -3*60*60)),
+	}
+
+	cell := chargeback.ReportCell()
+
+	expected := ReportCell{Day: "2023-10-16", MerchantID: "merchant-789", Reason: ReasonFraud, Status: StatusPending, Currency: "USD"}
+	if cell != expected {
+		t.Errorf("Expected %+v, got %+v", expected, cell)
+	}
+}
+
+func TestReportPeriod_Label(t *testing.T) {
+	tests := []struct {
+		period   ReportPeriod
+		day      string
+		expected string
+	}{
+		{ReportPeriodDay, "2023-10-16", "2023-10-16"},
+		{ReportPeriodWeek, "2023-10-16", "2023-W42"},
+		{ReportPeriodWeek, "2023-01-01", "2022-W52"},
+		{ReportPeriodMonth, "2023-10-16", "2023-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period)+" "+tt.day, func(t *testing.T) {
+			label, err := tt.period.Label(tt.day)
+			if err != nil || label != tt.expected {
+				t.Errorf("Expected %s, got %s, %v", tt.expected, label, err)
+			}
+		})
+	}
+
+	if _, err := ReportPeriodDay.Label("2023-10-32"); err == nil {
+		t.Error("Expected invalid day to be rejected")
+	}
+	if ReportPeriod("year").IsValid() {
+		t.Error("Expected unknown period to be invalid")
+	}
+}
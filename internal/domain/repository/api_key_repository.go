@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// APIKeyRepository defines the contract for API key persistence operations
+type APIKeyRepository interface {
+	// Save persists a new API key to the data store
+	Save(ctx context.Context, key *entity.APIKey) error
+
+	// FindByID retrieves an API key by its unique identifier
+	FindByID(ctx context.Context, id string) (*entity.APIKey, error)
+
+	// Update updates an existing API key in the data store
+	Update(ctx context.Context, key *entity.APIKey) error
+
+	// List retrieves all API keys
+	List(ctx context.Context) ([]*entity.APIKey, error)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// ErrInvalidCursor is returned when a listing cursor wasn't issued by the repository
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ChargebackFilter narrows the chargebacks a listing returns; zero fields match every
+// chargeback that isn't deleted
+type ChargebackFilter struct {
+	// MerchantIDs matches chargebacks of any of the merchants
+	MerchantIDs []string
+	Status      entity.ChargebackStatus
+	Reason      entity.ChargebackReason
+
+	// CreatedFrom and CreatedTo bound the creation time, inclusive and exclusive respectively
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	// IncludeDeleted lists soft deleted chargebacks too, which are left out otherwise
+	IncludeDeleted bool
+}
+
+// Matches reports whether the chargeback passes the filter
+func (f ChargebackFilter) Matches(chargeback *entity.Chargeback) bool {
+	if !f.IncludeDeleted && chargeback.IsDeleted() {
+		return false
+	}
+	if len(f.MerchantIDs) > 0 && !slices.Contains(f.MerchantIDs, chargeback.MerchantID) {
+		return false
+	}
+	if f.Status != "" && chargeback.Status != f.Status {
+		return false
+	}
+	if f.Reason != "" && chargeback.Reason != f.Reason {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && chargeback.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !chargeback.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// ChargebackPage is one page of a listing
+type ChargebackPage struct {
+	Chargebacks []*entity.Chargeback
+
+	// NextCursor resumes the listing after this page; it is empty on the last page
+	NextCursor string
+}
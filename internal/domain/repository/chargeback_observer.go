@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// ChargebackObserver is notified of chargebacks written to a repository, e.g. to keep
+// aggregates up to date
+// It is called after the write succeeded; its errors don't undo the write
+type ChargebackObserver interface {
+	// ChargebackSaved is called for each new chargeback
+	ChargebackSaved(ctx context.Context, chargeback *entity.Chargeback) error
+
+	// ChargebackUpdated is called with a chargeback as it was before and after an update
+	ChargebackUpdated(ctx context.Context, previous, current *entity.Chargeback) error
+
+	// ChargebackDeleted is called with a chargeback as it was before it was deleted
+	ChargebackDeleted(ctx context.Context, chargeback *entity.Chargeback) error
+}
@@ -2,33 +2,66 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
 )
 
+var (
+	// ErrChargebackExists is returned when saving a chargeback whose ID is already taken
+	ErrChargebackExists = errors.New("chargeback already exists")
+
+	// ErrChargebackNotFound is returned when updating, deleting or restoring a chargeback
+	// that doesn't exist
+	ErrChargebackNotFound = errors.New("chargeback not found")
+)
+
 // ChargebackRepository defines the contract for chargeback persistence operations
+// Chargebacks are soft deleted: Delete marks them with DeletedAt and they are kept, out of
+// lookups and listings, until the retention policy purges them.
 type ChargebackRepository interface {
-	// Save persists a new chargeback to the data store
+	// Save persists a new chargeback to the data store, assigning an ID if missing
+	// It fails with ErrChargebackExists when the ID is already taken
 	Save(ctx context.Context, chargeback *entity.Chargeback) error
 
-	// FindByID retrieves a chargeback by its unique identifier
+	// SaveBatch persists new chargebacks in bulk, assigning missing IDs
+	// It returns the chargebacks that could not be written, along with the error that
+	// stopped the batch if any; the others were saved
+	SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error)
+
+	// FindByID retrieves a chargeback by its unique identifier, or nil if there is none
 	FindByID(ctx context.Context, id string) (*entity.Chargeback, error)
 
-	// FindByTransactionID retrieves a chargeback by transaction ID
+	// FindByTransactionID retrieves a chargeback by transaction ID, or nil if there is none
 	FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error)
 
 	// FindByMerchantID retrieves all chargebacks for a specific merchant
 	FindByMerchantID(ctx context.Context, merchantID string) ([]*entity.Chargeback, error)
 
-	// Update updates an existing chargeback in the data store
+	// Update updates an existing chargeback in the data store, setting its UpdatedAt
+	// It fails with ErrChargebackNotFound when the chargeback doesn't exist or is deleted
 	Update(ctx context.Context, chargeback *entity.Chargeback) error
 
-	// Delete removes a chargeback from the data store
+	// Delete soft deletes a chargeback, setting its DeletedAt
+	// It fails with ErrChargebackNotFound when the chargeback doesn't exist or is deleted
 	Delete(ctx context.Context, id string) error
 
+	// Restore brings back a soft deleted chargeback
+	// It fails with ErrChargebackNotFound when there is no deleted chargeback with the ID
+	Restore(ctx context.Context, id string) error
+
+	// Purge removes chargebacks from the data store for good, whether deleted or not
+	// Chargebacks that are already gone are skipped. Callers archive them first.
+	Purge(ctx context.Context, chargebacks []*entity.Chargeback) error
+
 	// FindByStatus retrieves chargebacks by their status
 	FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error)
 
 	// List retrieves chargebacks with pagination support
 	List(ctx context.Context, offset, limit int) ([]*entity.Chargeback, error)
+
+	// ListPage retrieves the page of chargebacks matching the filter that starts at the cursor
+	// An empty cursor starts at the beginning. A page holds at most limit chargebacks and
+	// may hold fewer, or none, before the last page
+	ListPage(ctx context.Context, filter ChargebackFilter, cursor string, limit int) (*ChargebackPage, error)
 }
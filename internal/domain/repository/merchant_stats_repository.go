@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// MerchantStatsRepository defines the contract for merchant statistics persistence operations
+// Counters are updated atomically, so concurrent writers don't lose updates
+type MerchantStatsRepository interface {
+	// AddChargebacks adds the delta to a merchant's month and returns the updated statistics
+	AddChargebacks(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) (*entity.MerchantMonthlyStats, error)
+
+	// SetTransactionVolume replaces a merchant's sales for a month and returns the updated statistics
+	SetTransactionVolume(ctx context.Context, merchantID, month string, count int, amount float64) (*entity.MerchantMonthlyStats, error)
+
+	// Find retrieves a merchant's statistics for a month, nil when there are none
+	Find(ctx context.Context, merchantID, month string) (*entity.MerchantMonthlyStats, error)
+
+	// ListByMerchant retrieves a merchant's statistics from one month to another, both
+	// included, oldest first
+	ListByMerchant(ctx context.Context, merchantID, fromMonth, toMonth string) ([]*entity.MerchantMonthlyStats, error)
+}
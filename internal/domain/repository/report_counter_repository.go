@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// ReportCounterRepository defines the contract for the pre-aggregated counters reports
+// are read from
+// Counters are updated atomically, so concurrent writers don't lose updates
+type ReportCounterRepository interface {
+	// Add adds to the number and total amount of the chargebacks in a cell
+	Add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error
+
+	// List retrieves the counters from one day to another, both included
+	List(ctx context.Context, fromDay, toDay string) ([]*entity.ReportCounter, error)
+
+	// ReplaceAll replaces every counter with the given ones, e.g. after recomputing them
+	// from the chargebacks
+	ReplaceAll(ctx context.Context, counters []*entity.ReportCounter) error
+}
@@ -0,0 +1,552 @@
+// Package repositorytest provides contract test suites that every implementation of the
+// domain repositories must pass, so implementations can't drift apart
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// TestChargebackRepository runs the ChargebackRepository contract against the repositories
+// returned by newRepository, which must be empty and independent of each other
+func TestChargebackRepository(t *testing.T, newRepository func(t *testing.T) repository.ChargebackRepository) {
+	ctx := context.Background()
+
+	t.Run("Save assigns an ID and FindByID returns the chargeback", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargeback := newChargeback("tx-1", "merchant-1", entity.StatusPending)
+		chargeback.ID = ""
+
+		// Act
+		err := repo.Save(ctx, chargeback)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if chargeback.ID == "" {
+			t.Fatal("Expected Save to assign an ID")
+		}
+		found, err := repo.FindByID(ctx, chargeback.ID)
+		if err != nil {
+			t.Fatalf("FindByID failed: %v", err)
+		}
+		assertSameChargeback(t, chargeback, found)
+	})
+
+	t.Run("Save rejects a taken ID", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		mustSave(t, repo, newChargeback("tx-1", "merchant-1", entity.StatusPending))
+		duplicate := newChargeback("tx-2", "merchant-1", entity.StatusPending)
+		duplicate.ID = "cb_tx-1"
+
+		// Act
+		err := repo.Save(ctx, duplicate)
+
+		// Assert
+		if !errors.Is(err, repository.ErrChargebackExists) {
+			t.Fatalf("Expected ErrChargebackExists, got %v", err)
+		}
+		found, _ := repo.FindByID(ctx, "cb_tx-1")
+		if found == nil || found.TransactionID != "tx-1" {
+			t.Errorf("Expected the original chargeback to be kept, got %+v", found)
+		}
+	})
+
+	t.Run("Find returns nil when nothing matches", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		mustSave(t, repo, newChargeback("tx-1", "merchant-1", entity.StatusPending))
+
+		// Act
+		byID, errByID := repo.FindByID(ctx, "cb_missing")
+		byTransaction, errByTransaction := repo.FindByTransactionID(ctx, "tx-missing")
+		byMerchant, errByMerchant := repo.FindByMerchantID(ctx, "merchant-missing")
+		byStatus, errByStatus := repo.FindByStatus(ctx, entity.StatusRejected)
+
+		// Assert
+		if err := errors.Join(errByID, errByTransaction, errByMerchant, errByStatus); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if byID != nil || byTransaction != nil {
+			t.Errorf("Expected nil chargebacks, got %+v and %+v", byID, byTransaction)
+		}
+		if len(byMerchant) != 0 || len(byStatus) != 0 {
+			t.Errorf("Expected no chargebacks, got %d and %d", len(byMerchant), len(byStatus))
+		}
+	})
+
+	t.Run("Find by transaction, merchant and status", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		mustSave(t, repo,
+			newChargeback("tx-1", "merchant-1", entity.StatusPending),
+			newChargeback("tx-2", "merchant-1", entity.StatusApproved),
+			newChargeback("tx-3", "merchant-2", entity.StatusPending),
+		)
+
+		// Act
+		byTransaction, errByTransaction := repo.FindByTransactionID(ctx, "tx-2")
+		byMerchant, errByMerchant := repo.FindByMerchantID(ctx, "merchant-1")
+		byStatus, errByStatus := repo.FindByStatus(ctx, entity.StatusPending)
+
+		// Assert
+		if err := errors.Join(errByTransaction, errByMerchant, errByStatus); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if byTransaction == nil || byTransaction.ID != "cb_tx-2" {
+			t.Errorf("Expected cb_tx-2 by transaction ID, got %+v", byTransaction)
+		}
+		assertIDs(t, byMerchant, "cb_tx-1", "cb_tx-2")
+		assertIDs(t, byStatus, "cb_tx-1", "cb_tx-3")
+	})
+
+	t.Run("Update replaces the chargeback and sets UpdatedAt", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargeback := newChargeback("tx-1", "merchant-1", entity.StatusPending)
+		mustSave(t, repo, chargeback)
+		before := time.Now().Add(-time.Second)
+
+		// Act
+		chargeback.Status = entity.StatusApproved
+		chargeback.Description = "Approved after review"
+		err := repo.Update(ctx, chargeback)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if chargeback.UpdatedAt.Before(before) {
+			t.Errorf("Expected UpdatedAt to be set, got %v", chargeback.UpdatedAt)
+		}
+		found, _ := repo.FindByID(ctx, chargeback.ID)
+		assertSameChargeback(t, chargeback, found)
+		pending, _ := repo.FindByStatus(ctx, entity.StatusPending)
+		assertIDs(t, pending)
+	})
+
+	t.Run("Update rejects a missing chargeback", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+
+		// Act
+		err := repo.Update(ctx, newChargeback("tx-1", "merchant-1", entity.StatusApproved))
+
+		// Assert
+		if !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Fatalf("Expected ErrChargebackNotFound, got %v", err)
+		}
+		if found, _ := repo.FindByID(ctx, "cb_tx-1"); found != nil {
+			t.Errorf("Expected Update not to create the chargeback, got %+v", found)
+		}
+	})
+
+	t.Run("Delete hides the chargeback but keeps it", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		mustSave(t, repo,
+			newChargeback("tx-1", "merchant-1", entity.StatusPending),
+			newChargeback("tx-2", "merchant-1", entity.StatusPending),
+		)
+		before := time.Now().Add(-time.Second)
+
+		// Act
+		err := repo.Delete(ctx, "cb_tx-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		byID, _ := repo.FindByID(ctx, "cb_tx-1")
+		byTransaction, _ := repo.FindByTransactionID(ctx, "tx-1")
+		if byID != nil || byTransaction != nil {
+			t.Errorf("Expected deleted chargeback to be hidden, got %+v and %+v", byID, byTransaction)
+		}
+		byMerchant, _ := repo.FindByMerchantID(ctx, "merchant-1")
+		assertIDs(t, byMerchant, "cb_tx-2")
+		byStatus, _ := repo.FindByStatus(ctx, entity.StatusPending)
+		assertIDs(t, byStatus, "cb_tx-2")
+		listed, _ := repo.List(ctx, 0, 10)
+		assertIDs(t, listed, "cb_tx-2")
+		assertIDs(t, listAll(t, repo, repository.ChargebackFilter{}), "cb_tx-2")
+
+		withDeleted := listAll(t, repo, repository.ChargebackFilter{IncludeDeleted: true})
+		assertIDs(t, withDeleted, "cb_tx-1", "cb_tx-2")
+		for _, chargeback := range withDeleted {
+			if chargeback.ID == "cb_tx-1" && chargeback.DeletedAt.Before(before) {
+				t.Errorf("Expected DeletedAt to be set, got %v", chargeback.DeletedAt)
+			}
+		}
+	})
+
+	t.Run("Deleted chargebacks can't be deleted, updated or saved again", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargeback := newChargeback("tx-1", "merchant-1", entity.StatusPending)
+		mustSave(t, repo, chargeback)
+		if err := repo.Delete(ctx, "cb_tx-1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+
+		// Act
+		errDelete := repo.Delete(ctx, "cb_tx-1")
+		errMissing := repo.Delete(ctx, "cb_missing")
+		errUpdate := repo.Update(ctx, chargeback)
+		errSave := repo.Save(ctx, newChargeback("tx-1", "merchant-1", entity.StatusPending))
+
+		// Assert
+		if !errors.Is(errDelete, repository.ErrChargebackNotFound) || !errors.Is(errMissing, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound deleting again or a missing chargeback, got %v and %v", errDelete, errMissing)
+		}
+		if !errors.Is(errUpdate, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound updating, got %v", errUpdate)
+		}
+		if !errors.Is(errSave, repository.ErrChargebackExists) {
+			t.Errorf("Expected ErrChargebackExists saving, got %v", errSave)
+		}
+	})
+
+	t.Run("Restore brings back a deleted chargeback", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargeback := newChargeback("tx-1", "merchant-1", entity.StatusPending)
+		mustSave(t, repo, chargeback, newChargeback("tx-2", "merchant-1", entity.StatusPending))
+		if err := repo.Delete(ctx, "cb_tx-1"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+
+		// Act
+		err := repo.Restore(ctx, "cb_tx-1")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		found, _ := repo.FindByTransactionID(ctx, "tx-1")
+		assertSameChargeback(t, chargeback, found)
+		if err := repo.Restore(ctx, "cb_tx-1"); !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound restoring again, got %v", err)
+		}
+		if err := repo.Restore(ctx, "cb_tx-2"); !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound restoring a chargeback that isn't deleted, got %v", err)
+		}
+		if err := repo.Restore(ctx, "cb_missing"); !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound restoring a missing chargeback, got %v", err)
+		}
+	})
+
+	t.Run("Purge removes chargebacks for good", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		mustSave(t, repo,
+			newChargeback("tx-1", "merchant-1", entity.StatusPending),
+			newChargeback("tx-2", "merchant-1", entity.StatusPending),
+			newChargeback("tx-3", "merchant-1", entity.StatusPending),
+		)
+		if err := repo.Delete(ctx, "cb_tx-2"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		var purged []*entity.Chargeback
+		for _, chargeback := range listAll(t, repo, repository.ChargebackFilter{IncludeDeleted: true}) {
+			if chargeback.ID != "cb_tx-3" {
+				purged = append(purged, chargeback)
+			}
+		}
+		purged = append(purged, newChargeback("tx-missing", "merchant-1", entity.StatusPending))
+
+		// Act
+		err := repo.Purge(ctx, purged)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Purge failed: %v", err)
+		}
+		assertIDs(t, listAll(t, repo, repository.ChargebackFilter{IncludeDeleted: true}), "cb_tx-3")
+		if err := repo.Restore(ctx, "cb_tx-2"); !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound restoring a purged chargeback, got %v", err)
+		}
+		if err := repo.Delete(ctx, "cb_tx-1"); !errors.Is(err, repository.ErrChargebackNotFound) {
+			t.Errorf("Expected ErrChargebackNotFound deleting a purged chargeback, got %v", err)
+		}
+	})
+
+	t.Run("SaveBatch saves every chargeback", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargebacks := make([]*entity.Chargeback, 30)
+		for i := range chargebacks {
+			chargebacks[i] = newChargeback(fmt.Sprintf("tx-%02d", i), "merchant-1", entity.StatusPending)
+			chargebacks[i].ID = ""
+		}
+
+		// Act
+		failed, err := repo.SaveBatch(ctx, chargebacks)
+
+		// Assert
+		if err != nil || len(failed) != 0 {
+			t.Fatalf("SaveBatch failed: %v (%d unsaved)", err, len(failed))
+		}
+		seen := make(map[string]bool)
+		for _, chargeback := range chargebacks {
+			if chargeback.ID == "" || seen[chargeback.ID] {
+				t.Fatalf("Expected unique IDs to be assigned, got %q", chargeback.ID)
+			}
+			seen[chargeback.ID] = true
+		}
+		saved, _ := repo.FindByMerchantID(ctx, "merchant-1")
+		if len(saved) != len(chargebacks) {
+			t.Errorf("Expected %d chargebacks, got %d", len(chargebacks), len(saved))
+		}
+	})
+
+	t.Run("List pages with offset and limit", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		for i := 0; i < 5; i++ {
+			mustSave(t, repo, newChargeback(fmt.Sprintf("tx-%d", i), "merchant-1", entity.StatusPending))
+		}
+
+		// Act
+		all, errAll := repo.List(ctx, 0, 10)
+		firstTwo, errFirst := repo.List(ctx, 0, 2)
+		rest, errRest := repo.List(ctx, 2, 10)
+		beyond, errBeyond := repo.List(ctx, 10, 10)
+
+		// Assert
+		if err := errors.Join(errAll, errFirst, errRest, errBeyond); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(all) != 5 || len(firstTwo) != 2 || len(rest) != 3 || len(beyond) != 0 {
+			t.Fatalf("Expected 5, 2, 3 and 0 chargebacks, got %d, %d, %d and %d", len(all), len(firstTwo), len(rest), len(beyond))
+		}
+		assertIDs(t, append(firstTwo, rest...), ids(all)...)
+	})
+
+	t.Run("ListPage walks every matching chargeback once", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		var expected []string
+		for i := 0; i < 7; i++ {
+			merchantID, status := "merchant-1", entity.StatusPending
+			if i%3 == 0 {
+				merchantID = "merchant-2"
+			}
+			if i%2 == 0 {
+				status = entity.StatusApproved
+			}
+			chargeback := newChargeback(fmt.Sprintf("tx-%d", i), merchantID, status)
+			mustSave(t, repo, chargeback)
+			if merchantID == "merchant-1" && status == entity.StatusPending {
+				expected = append(expected, chargeback.ID)
+			}
+		}
+
+		filters := map[string]repository.ChargebackFilter{
+			"no filter":    {},
+			"one merchant": {MerchantIDs: []string{"merchant-1"}, Status: entity.StatusPending},
+			"status":       {Status: entity.StatusPending, MerchantIDs: []string{"merchant-1", "merchant-3"}},
+		}
+		for name, filter := range filters {
+			t.Run(name, func(t *testing.T) {
+				// Act
+				var listed []*entity.Chargeback
+				cursor := ""
+				for pages := 0; ; pages++ {
+					if pages > 10 {
+						t.Fatal("Expected the listing to end")
+					}
+					page, err := repo.ListPage(ctx, filter, cursor, 2)
+					if err != nil {
+						t.Fatalf("ListPage failed: %v", err)
+					}
+					if len(page.Chargebacks) > 2 {
+						t.Fatalf("Expected at most 2 chargebacks per page, got %d", len(page.Chargebacks))
+					}
+					listed = append(listed, page.Chargebacks...)
+					if page.NextCursor == "" {
+						break
+					}
+					cursor = page.NextCursor
+				}
+
+				// Assert
+				if len(filter.MerchantIDs) == 0 {
+					if len(listed) != 7 {
+						t.Errorf("Expected 7 chargebacks, got %d", len(listed))
+					}
+					return
+				}
+				assertIDs(t, listed, expected...)
+			})
+		}
+	})
+
+	t.Run("ListPage rejects cursors it didn't issue", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+
+		// Act
+		_, err := repo.ListPage(ctx, repository.ChargebackFilter{}, "not a cursor!", 10)
+
+		// Assert
+		if !errors.Is(err, repository.ErrInvalidCursor) {
+			t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("Returned chargebacks don't share state with the repository", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		chargeback := newChargeback("tx-1", "merchant-1", entity.StatusPending)
+		mustSave(t, repo, chargeback)
+
+		// Act
+		chargeback.Status = entity.StatusRejected
+		found, _ := repo.FindByID(ctx, "cb_tx-1")
+		found.Status = entity.StatusApproved
+
+		// Assert
+		again, _ := repo.FindByID(ctx, "cb_tx-1")
+		if again.Status != entity.StatusPending {
+			t.Errorf("Expected stored status to stay pending, got %s", again.Status)
+		}
+	})
+
+	t.Run("Concurrent saves are all kept", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+
+		// Act
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				chargeback := newChargeback(fmt.Sprintf("tx-%d", i), "merchant-1", entity.StatusPending)
+				chargeback.ID = ""
+				errs <- repo.Save(ctx, chargeback)
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		// Assert
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+		}
+		saved, _ := repo.FindByMerchantID(ctx, "merchant-1")
+		if len(saved) != 20 {
+			t.Errorf("Expected 20 chargebacks, got %d", len(saved))
+		}
+	})
+}
+
+// newChargeback returns a chargeback with ID cb_<transactionID>
+func newChargeback(transactionID, merchantID string, status entity.ChargebackStatus) *entity.Chargeback {
+	now := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+	return &entity.Chargeback{
+		ID:              "cb_" + transactionID,
+		TransactionID:   transactionID,
+		MerchantID:      merchantID,
+		Amount:          125.5,
+		Currency:        "USD",
+		CardNumber:      "************1111",
+		Reason:          entity.ReasonFraud,
+		Status:          status,
+		Description:     "Unauthorized transaction",
+		TransactionDate: now.AddDate(0, 0, -10),
+		ChargebackDate:  now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// mustSave saves the chargebacks, failing the test on error
+func mustSave(t *testing.T, repo repository.ChargebackRepository, chargebacks ...*entity.Chargeback) {
+	t.Helper()
+	for _, chargeback := range chargebacks {
+		if err := repo.Save(context.Background(), chargeback); err != nil {
+			t.Fatalf("Failed to save %s: %v", chargeback.TransactionID, err)
+		}
+	}
+}
+
+// assertSameChargeback compares two chargebacks, times by instant
+func assertSameChargeback(t *testing.T, expected, actual *entity.Chargeback) {
+	t.Helper()
+	if actual == nil {
+		t.Fatalf("Expected chargeback %s, got nil", expected.ID)
+	}
+
+	e, a := *expected, *actual
+	for _, times := range [][2]*time.Time{
+		{&e.TransactionDate, &a.TransactionDate},
+		{&e.ChargebackDate, &a.ChargebackDate},
+		{&e.CreatedAt, &a.CreatedAt},
+		{&e.UpdatedAt, &a.UpdatedAt},
+		{&e.DeletedAt, &a.DeletedAt},
+	} {
+		if !times[0].Equal(*times[1]) {
+			t.Errorf("Expected time %v, got %v", *times[0], *times[1])
+		}
+		*times[0], *times[1] = time.Time{}, time.Time{}
+	}
+	if e != a {
+		t.Errorf("Expected %+v, got %+v", e, a)
+	}
+}
+
+// listAll walks every page of a listing
+func listAll(t *testing.T, repo repository.ChargebackRepository, filter repository.ChargebackFilter) []*entity.Chargeback {
+	t.Helper()
+	var chargebacks []*entity.Chargeback
+	cursor := ""
+	for pages := 0; pages <= 100; pages++ {
+		page, err := repo.ListPage(context.Background(), filter, cursor, 10)
+		if err != nil {
+			t.Fatalf("ListPage failed: %v", err)
+		}
+		chargebacks = append(chargebacks, page.Chargebacks...)
+		if page.NextCursor == "" {
+			return chargebacks
+		}
+		cursor = page.NextCursor
+	}
+	t.Fatal("Expected the listing to end")
+	return nil
+}
+
+// assertIDs checks the chargebacks have exactly the expected IDs, in any order
+func assertIDs(t *testing.T, chargebacks []*entity.Chargeback, expected ...string) {
+	t.Helper()
+	actual := ids(chargebacks)
+	sort.Strings(actual)
+	expected = append([]string(nil), expected...)
+	sort.Strings(expected)
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("Expected chargebacks %v, got %v", expected, actual)
+	}
+}
+
+// ids returns the IDs of the chargebacks
+func ids(chargebacks []*entity.Chargeback) []string {
+	result := make([]string, 0, len(chargebacks))
+	for _, chargeback := range chargebacks {
+		result = append(result, chargeback.ID)
+	}
+	return result
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// AlertSink defines the contract for delivering merchant ratio alerts outside the service
+type AlertSink interface {
+	// Send delivers the alert, returning an error if it could not be delivered
+	Send(ctx context.Context, alert entity.MerchantRatioAlert) error
+}
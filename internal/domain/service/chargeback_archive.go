@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// ChargebackArchive defines the contract for keeping chargebacks in cold storage once
+// they are purged from the repository
+type ChargebackArchive interface {
+	// Archive stores the chargebacks durably and returns where they were stored; an
+	// error means none of them can be assumed archived
+	Archive(ctx context.Context, chargebacks []*entity.Chargeback) (string, error)
+}
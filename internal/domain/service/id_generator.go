@@ -0,0 +1,10 @@
+package service
+
+import "github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+
+// IDGenerator defines the contract for generating chargeback IDs
+type IDGenerator interface {
+	// NewChargebackID returns an ID no other chargeback has, sorting after the IDs the
+	// generator returned before
+	NewChargebackID() entity.ChargebackID
+}
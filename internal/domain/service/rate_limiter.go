@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RateLimit describes how many requests a client may make per window
+type RateLimit struct {
+	// Requests is the number of requests allowed per window; zero means unlimited
+	Requests int
+
+	// Window is the period over which Requests are allowed
+	Window time.Duration
+
+	// Burst is the largest number of requests allowed at once; zero defaults to Requests
+	Burst int
+}
+
+// IsUnlimited checks if the limit allows any number of requests
+func (l RateLimit) IsUnlimited() bool {
+	return l.Requests <= 0 || l.Window <= 0
+}
+
+// Capacity returns the largest number of requests allowed at once
+func (l RateLimit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// String returns the limit in the "requests/window" form used in configuration
+func (l RateLimit) String() string {
+	if l.Burst > 0 {
+		return fmt.Sprintf("%d/%s/%d", l.Requests, l.Window, l.Burst)
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// RateLimitResult represents the outcome of a rate limit check
+type RateLimitResult struct {
+	// Allowed reports whether the request may proceed
+	Allowed bool
+
+	// Limit is the number of requests allowed at once
+	Limit int
+
+	// Remaining is the number of requests left before the client is limited
+	Remaining int
+
+	// ResetAfter is the time until the client's full allowance is restored
+	ResetAfter time.Duration
+
+	// RetryAfter is the time until the next request would be allowed; zero when Allowed
+	RetryAfter time.Duration
+}
+
+// RateLimiter defines the contract for counting requests against a limit
+// Implementations must be safe for concurrent use
+type RateLimiter interface {
+	// Allow records a request for the key and reports whether it is within the limit
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Checkpoint stores the last processed line of an import
+type Checkpoint interface {
+	// Load returns the last processed line, 0 when the import hasn't started
+	Load() (int, error)
+	Save(line int) error
+	// Clear forgets the progress once the import is complete
+	Clear() error
+}
+
+// FileCheckpoint keeps the last processed line in a file
+type FileCheckpoint struct {
+	path string
+}
+
+// NewFileCheckpoint creates a checkpoint stored at path
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{path: path}
+}
+
+// Load returns the saved line, 0 when the file doesn't exist
+func (c *FileCheckpoint) Load() (int, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	line, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint file %s: %w", c.path, err)
+	}
+	return line, nil
+}
+
+// Save replaces the checkpoint through a rename, so a crash never leaves it half written
+func (c *FileCheckpoint) Save(line int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(strconv.Itoa(line) + "\n"); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// Clear removes the checkpoint file
+func (c *FileCheckpoint) Clear() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// CSVConfig configures how a CSV dispute file is read
+type CSVConfig struct {
+	Mapping Mapping
+	Format  Format
+
+	// Delimiter separates columns, ',' unless set
+	Delimiter rune
+}
+
+// Validate validates the configuration
+func (c CSVConfig) Validate() error {
+	if err := c.Mapping.Validate(); err != nil {
+		return err
+	}
+	return c.Format.Validate()
+}
+
+// CSVParser parses CSV dispute files
+type CSVParser struct {
+	config CSVConfig
+}
+
+// NewCSVParser creates a parser for CSV files read with the configuration
+func NewCSVParser(config CSVConfig) *CSVParser {
+	return &CSVParser{config: config}
+}
+
+// Config returns the parser's configuration
+func (p *CSVParser) Config() CSVConfig {
+	return p.config
+}
+
+// Parse reads the header row; rows are read as the import goes
+func (p *CSVParser) Parse(r io.Reader) (Reader, error) {
+	reader, err := NewCSVReader(r, p.config)
+	if err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// CSVReader reads chargebacks from a CSV file whose first row names the columns
+type CSVReader struct {
+	reader  *csv.Reader
+	config  CSVConfig
+	header  []string
+	columns map[string]int
+}
+
+// NewCSVReader reads the header row and checks that every required field has a column
+func NewCSVReader(r io.Reader, config CSVConfig) (*CSVReader, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	if config.Delimiter != 0 {
+		reader.Comma = config.Delimiter
+	}
+	// Rows with missing trailing columns are reported per row instead of failing the file
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("file is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int, len(fields))
+	var missing []string
+	for _, field := range fields {
+		column := config.Mapping.Column(field)
+		if i, ok := positions[strings.ToLower(column)]; ok {
+			columns[field] = i
+		} else if field != FieldDescription {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	return &CSVReader{reader: reader, config: config, header: header, columns: columns}, nil
+}
+
+// Header returns the column names of the file
+func (r *CSVReader) Header() []string {
+	return r.header
+}
+
+// Read returns the next row, or io.EOF after the last one
+// Rows that can't be parsed are returned with Err set, so the import can continue
+func (r *CSVReader) Read() (*Record, error) {
+	values, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &Record{Line: parseErr.StartLine, Fields: values, Err: parseErr.Err}, nil
+		}
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+	record := &Record{Line: line, Fields: values}
+	record.Request, record.Err = r.parse(values)
+	return record, nil
+}
+
+// parse converts the values of a row into a create request
+func (r *CSVReader) parse(values []string) (usecase.CreateChargebackRequest, error) {
+	return r.config.Format.newRequest(func(field string) string {
+		i, ok := r.columns[field]
+		if !ok || i >= len(values) {
+			return ""
+		}
+		return strings.TrimSpace(values[i])
+	})
+}
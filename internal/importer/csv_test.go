@@ -0,0 +1,183 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestNewCSVReader_Header(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		mapping       Mapping
+		expectedError string
+	}{
+		{
+			name: "default column names",
+			data: "transaction_id,merchant_id,amount,currency,card_number,reason,transaction_date\n",
+		},
+		{
+			name:    "mapped columns are matched case-insensitively",
+			data:    "Txn Ref,MERCHANT_ID,Amt,currency,card_number,reason,transaction_date,description\n",
+			mapping: Mapping{FieldTransactionID: "txn ref", FieldAmount: "Amt"},
+		},
+		{
+			name:          "missing required columns",
+			data:          "transaction_id,merchant_id,currency\n",
+			expectedError: "missing columns: amount, card_number, reason, transaction_date",
+		},
+		{
+			name:          "unknown mapped field",
+			data:          "transaction_id\n",
+			mapping:       Mapping{"acquirer": "Acquirer"},
+			expectedError: "unknown fields in column mapping: acquirer",
+		},
+		{
+			name:          "empty file",
+			data:          "",
+			expectedError: "file is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, err := NewCSVReader(strings.NewReader(tt.data), CSVConfig{Mapping: tt.mapping, Format: DefaultFormat()})
+
+			// Assert
+			if tt.expectedError == "" && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.expectedError != "" && (err == nil || err.Error() != tt.expectedError) {
+				t.Errorf("Expected error '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCSVReader_Read(t *testing.T) {
+	// Arrange
+	data := "Ref;Merchant;Value;Currency;Card;Reason;Date;Notes\n" +
+		"tx-1;merchant-1;1.234,56;usd;4111111111111111;Consumer Dispute;15/10/2023;\"multi\nline\"\n" +
+		"tx-2;merchant-1;abc;USD;4111111111111111;fraud;yesterday\n" +
+		"tx-3;merchant-1;10,00\n"
+	config := CSVConfig{
+		Mapping: Mapping{
+			FieldTransactionID:   "Ref",
+			FieldMerchantID:      "Merchant",
+			FieldAmount:          "Value",
+			FieldCurrency:        "Currency",
+			FieldCardNumber:      "Card",
+			FieldReason:          "Reason",
+			FieldTransactionDate: "Date",
+			FieldDescription:     "Notes",
+		},
+		Format:    Format{DateLayout: "02/01/2006", DecimalSeparator: ",", ThousandsSeparator: "."},
+		Delimiter: ';',
+	}
+	reader, err := NewCSVReader(strings.NewReader(data), config)
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+
+	// Act
+	var records []*Record
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+		records = append(records, record)
+	}
+
+	// Assert
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.Line != 2 || first.Err != nil {
+		t.Fatalf("Expected valid record on line 2, got line %d: %v", first.Line, first.Err)
+	}
+	req := first.Request
+	if req.TransactionID != "tx-1" || req.Amount != 1234.56 || req.Currency != "USD" ||
+		req.Reason != entity.ReasonConsumerDispute || req.Description != "multi\nline" ||
+		!req.TransactionDate.Equal(time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected request %+v", req)
+	}
+
+	second := records[1]
+	if second.Line != 4 || second.Err == nil {
+		t.Fatalf("Expected invalid record on line 4, got line %d: %v", second.Line, second.Err)
+	}
+	if !strings.Contains(second.Err.Error(), `invalid amount "abc"`) || !strings.Contains(second.Err.Error(), `invalid transaction date "yesterday"`) {
+		t.Errorf("Expected amount and date errors, got %v", second.Err)
+	}
+
+	third := records[2]
+	if third.Line != 5 || third.Err != nil || third.Request.Amount != 10 || third.Request.CardNumber != "" {
+		t.Errorf("Expected short row to leave missing fields empty, got %+v", third)
+	}
+}
+
+func TestFormat_ParseAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		value    string
+		expected float64
+		wantErr  bool
+	}{
+		{name: "default format", format: DefaultFormat(), value: "99.99", expected: 99.99},
+		{name: "thousands separator", format: Format{DecimalSeparator: ".", ThousandsSeparator: ","}, value: "1,234.50", expected: 1234.5},
+		{name: "minor units", format: Format{MinorUnits: true}, value: "12345", expected: 123.45},
+		{name: "minor units with decimals", format: Format{MinorUnits: true}, value: "123.45", wantErr: true},
+		{name: "not a number", format: DefaultFormat(), value: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			amount, err := tt.format.ParseAmount(tt.value)
+
+			// Assert
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got amount %v", amount)
+				}
+				return
+			}
+			if err != nil || amount != tt.expected {
+				t.Errorf("Expected %v, got %v (%v)", tt.expected, amount, err)
+			}
+		})
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	// Act
+	mapping, err := ParseMapping("transaction_id=Txn Ref, amount = Amount (USD),")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mapping.Column(FieldTransactionID) != "Txn Ref" || mapping.Column(FieldAmount) != "Amount (USD)" {
+		t.Errorf("Unexpected mapping %v", mapping)
+	}
+	if mapping.Column(FieldCurrency) != FieldCurrency {
+		t.Errorf("Expected unmapped field to use its own name, got %s", mapping.Column(FieldCurrency))
+	}
+
+	if _, err := ParseMapping("transaction_id"); err == nil {
+		t.Error("Expected error for pair without column")
+	}
+}
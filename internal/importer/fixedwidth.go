@@ -0,0 +1,437 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Trailer fields holding the control totals of a fixed-width file
+const (
+	FieldRecordCount = "record_count"
+	FieldTotalAmount = "total_amount"
+)
+
+// FieldType is how the value of a fixed-width field is read
+type FieldType string
+
+const (
+	FieldTypeText    FieldType = "text"
+	FieldTypeAmount  FieldType = "amount"
+	FieldTypeDate    FieldType = "date"
+	FieldTypeInteger FieldType = "integer"
+)
+
+// Field alignments; padding is on the other side of the value
+const (
+	AlignLeft  = "left"
+	AlignRight = "right"
+)
+
+// maxFixedWidthLine bounds the length of a record
+const maxFixedWidthLine = 64 * 1024
+
+// FieldSpec locates a field in a fixed-width record
+type FieldSpec struct {
+	Name string `json:"name"`
+
+	// Start is the 1-based position of the field's first character
+	Start  int `json:"start"`
+	Length int `json:"length"`
+
+	// Type defaults to the type of the named field: amount for amount and total_amount,
+	// date for transaction_date, integer for record_count and text otherwise
+	Type FieldType `json:"type,omitempty"`
+
+	// Pad is the character filling the field around the value, a space unless set
+	Pad string `json:"pad,omitempty"`
+
+	// Align defaults to right for amounts and integers and left otherwise
+	Align string `json:"align,omitempty"`
+}
+
+// RecordSpec describes the fields of one record type
+type RecordSpec struct {
+	// Type is the record type code, e.g. "H", "D" or "T"
+	Type   string      `json:"type"`
+	Fields []FieldSpec `json:"fields"`
+}
+
+// FixedWidthLayout describes a fixed-width dispute file: an optional header record, the
+// detail records holding the disputes and an optional trailer record
+// Detail fields are named after the chargeback fields; a trailer's record_count and
+// total_amount fields are checked against the detail records
+type FixedWidthLayout struct {
+	// TypeStart and TypeLength locate the record type code, at the same position in
+	// every record
+	TypeStart  int `json:"type_start"`
+	TypeLength int `json:"type_length"`
+
+	Header  *RecordSpec `json:"header,omitempty"`
+	Detail  RecordSpec  `json:"detail"`
+	Trailer *RecordSpec `json:"trailer,omitempty"`
+
+	Format Format `json:"format"`
+}
+
+// LoadFixedWidthLayout reads a layout from a JSON file
+func LoadFixedWidthLayout(path string) (FixedWidthLayout, error) {
+	layout := FixedWidthLayout{Format: DefaultFormat()}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return layout, err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&layout); err != nil {
+		return layout, fmt.Errorf("invalid layout file %s: %w", path, err)
+	}
+	return layout, nil
+}
+
+// Validate validates the layout
+func (l FixedWidthLayout) Validate() error {
+	if l.TypeStart < 1 || l.TypeLength < 1 {
+		return fmt.Errorf("record type position is required")
+	}
+	if err := l.Format.Validate(); err != nil {
+		return err
+	}
+
+	types := make(map[string]bool)
+	for _, spec := range l.records() {
+		if len(spec.Type) != l.TypeLength {
+			return fmt.Errorf("record type %q must be %d characters", spec.Type, l.TypeLength)
+		}
+		if types[spec.Type] {
+			return fmt.Errorf("record type %q is used twice", spec.Type)
+		}
+		types[spec.Type] = true
+
+		for _, field := range spec.Fields {
+			if err := field.validate(); err != nil {
+				return fmt.Errorf("record %q: %w", spec.Type, err)
+			}
+		}
+	}
+
+	var unknown, missing []string
+	for _, field := range l.Detail.Fields {
+		if !isField(field.Name) {
+			unknown = append(unknown, field.Name)
+		}
+	}
+	for _, name := range fields {
+		if _, ok := l.Detail.field(name); !ok && name != FieldDescription {
+			missing = append(missing, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown detail fields: %s", strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing detail fields: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// records returns the specs of every record type in the layout
+func (l FixedWidthLayout) records() []RecordSpec {
+	specs := []RecordSpec{l.Detail}
+	if l.Header != nil {
+		specs = append(specs, *l.Header)
+	}
+	if l.Trailer != nil {
+		specs = append(specs, *l.Trailer)
+	}
+	return specs
+}
+
+// field returns the spec of the named field
+func (s RecordSpec) field(name string) (FieldSpec, bool) {
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FieldSpec{}, false
+}
+
+// validate validates the field's position, type and padding
+func (f FieldSpec) validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("field name is required")
+	}
+	if f.Start < 1 || f.Length < 1 {
+		return fmt.Errorf("field %s must have a positive start and length", f.Name)
+	}
+	switch f.fieldType() {
+	case FieldTypeText, FieldTypeAmount, FieldTypeDate, FieldTypeInteger:
+	default:
+		return fmt.Errorf("field %s has unknown type %q", f.Name, f.Type)
+	}
+	if expected := defaultFieldType(f.Name); expected != FieldTypeText && f.fieldType() != expected {
+		return fmt.Errorf("field %s must be of type %s", f.Name, expected)
+	}
+	if len([]rune(f.Pad)) > 1 {
+		return fmt.Errorf("field %s padding must be a single character", f.Name)
+	}
+	if f.Align != "" && f.Align != AlignLeft && f.Align != AlignRight {
+		return fmt.Errorf("field %s alignment must be %s or %s", f.Name, AlignLeft, AlignRight)
+	}
+	return nil
+}
+
+// fieldType returns the field's type, defaulting to the type of its name
+func (f FieldSpec) fieldType() FieldType {
+	if f.Type != "" {
+		return f.Type
+	}
+	return defaultFieldType(f.Name)
+}
+
+// defaultFieldType returns the type fields of the given name are read as
+func defaultFieldType(name string) FieldType {
+	switch name {
+	case FieldAmount, FieldTotalAmount:
+		return FieldTypeAmount
+	case FieldTransactionDate:
+		return FieldTypeDate
+	case FieldRecordCount:
+		return FieldTypeInteger
+	default:
+		return FieldTypeText
+	}
+}
+
+// value extracts the field from the record and strips its padding
+// Records shorter than the layout, as when trailing spaces were trimmed, read as padding
+func (f FieldSpec) value(record []rune) string {
+	start := min(f.Start-1, len(record))
+	end := min(start+f.Length, len(record))
+	value := string(record[start:end])
+
+	pad := f.Pad
+	if pad == "" {
+		pad = " "
+	}
+	numeric := f.fieldType() == FieldTypeAmount || f.fieldType() == FieldTypeInteger
+
+	align := f.Align
+	if align == "" {
+		align = AlignLeft
+		if numeric {
+			align = AlignRight
+		}
+	}
+	if align == AlignRight {
+		value = strings.TrimLeft(value, pad)
+	} else {
+		value = strings.TrimRight(value, pad)
+	}
+	value = strings.TrimSpace(value)
+
+	// A numeric field made of padding only, such as "0000", is zero
+	if value == "" && numeric && pad == "0" {
+		return "0"
+	}
+	return value
+}
+
+// FixedWidthParser parses fixed-width dispute files
+type FixedWidthParser struct {
+	layout FixedWidthLayout
+}
+
+// NewFixedWidthParser creates a parser for files with the layout
+func NewFixedWidthParser(layout FixedWidthLayout) (*FixedWidthParser, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	return &FixedWidthParser{layout: layout}, nil
+}
+
+// Parse reads the whole file and checks its header, trailer and control totals, so a
+// truncated or altered file is rejected before any dispute is imported
+// Detail records that can't be parsed are returned with Err set, as with CSV rows
+func (p *FixedWidthParser) Parse(r io.Reader) (Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxFixedWidthLine)
+
+	var (
+		records    []*Record
+		header     bool
+		trailer    []rune
+		trailerAt  int
+		totalCents int64
+		totalErr   error
+	)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if trailer != nil {
+			return nil, fmt.Errorf("line %d: record after the trailer", line)
+		}
+
+		record := []rune(text)
+		start := p.layout.TypeStart - 1
+		recordType := ""
+		if start < len(record) {
+			recordType = string(record[start:min(start+p.layout.TypeLength, len(record))])
+		}
+
+		switch {
+		case p.layout.Header != nil && recordType == p.layout.Header.Type:
+			if header || len(records) > 0 {
+				return nil, fmt.Errorf("line %d: header must be the first record", line)
+			}
+			header = true
+		case p.layout.Trailer != nil && recordType == p.layout.Trailer.Type:
+			trailer, trailerAt = record, line
+		case recordType == p.layout.Detail.Type:
+			if p.layout.Header != nil && !header {
+				return nil, fmt.Errorf("line %d: missing header record", line)
+			}
+			detail := p.detail(line, record)
+			records = append(records, detail)
+
+			amount, err := p.layout.Format.ParseAmount(p.layout.Detail.mustField(FieldAmount).value(record))
+			if err != nil && totalErr == nil {
+				totalErr = fmt.Errorf("line %d: %w", line, err)
+			}
+			totalCents += toCents(amount)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, recordType)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if line == 0 {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if p.layout.Header != nil && !header {
+		return nil, fmt.Errorf("missing header record")
+	}
+	if p.layout.Trailer != nil {
+		if trailer == nil {
+			return nil, fmt.Errorf("missing trailer record")
+		}
+		if err := p.verifyTrailer(trailer, trailerAt, len(records), totalCents, totalErr); err != nil {
+			return nil, err
+		}
+	}
+
+	return &recordReader{header: p.detailHeader(), records: records}, nil
+}
+
+// detail converts a detail record into a create request
+func (p *FixedWidthParser) detail(line int, record []rune) *Record {
+	values := make(map[string]string, len(p.layout.Detail.Fields))
+	result := &Record{Line: line}
+	for _, field := range p.layout.Detail.Fields {
+		value := field.value(record)
+		values[field.Name] = value
+		result.Fields = append(result.Fields, value)
+	}
+
+	result.Request, result.Err = p.layout.Format.newRequest(func(field string) string {
+		return values[field]
+	})
+	return result
+}
+
+// verifyTrailer checks the trailer's control totals against the detail records
+func (p *FixedWidthParser) verifyTrailer(trailer []rune, line, count int, totalCents int64, totalErr error) error {
+	if field, ok := p.layout.Trailer.field(FieldRecordCount); ok {
+		value := field.value(trailer)
+		expected, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid record count %q", line, value)
+		}
+		if expected != count {
+			return fmt.Errorf("trailer record count %d doesn't match the %d detail records", expected, count)
+		}
+	}
+
+	if field, ok := p.layout.Trailer.field(FieldTotalAmount); ok {
+		value := field.value(trailer)
+		expected, err := p.layout.Format.ParseAmount(value)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid total amount %q", line, value)
+		}
+		if totalErr != nil {
+			return fmt.Errorf("cannot verify total amount: %w", totalErr)
+		}
+		if toCents(expected) != totalCents {
+			return fmt.Errorf("trailer total amount %s doesn't match the detail records' total %s",
+				formatCents(toCents(expected)), formatCents(totalCents))
+		}
+	}
+
+	return nil
+}
+
+// detailHeader names the values of detail records
+func (p *FixedWidthParser) detailHeader() []string {
+	header := make([]string, len(p.layout.Detail.Fields))
+	for i, field := range p.layout.Detail.Fields {
+		header[i] = field.Name
+	}
+	return header
+}
+
+// mustField returns the spec of a field the layout is validated to have
+func (s RecordSpec) mustField(name string) FieldSpec {
+	field, _ := s.field(name)
+	return field
+}
+
+// toCents converts an amount to cents, so totals are compared without rounding errors
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// formatCents formats cents as an amount
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// recordReader reads records parsed up front
+type recordReader struct {
+	header  []string
+	records []*Record
+}
+
+// Header names the values of the records' fields
+func (r *recordReader) Header() []string {
+	return r.header
+}
+
+// Read returns the next record, or io.EOF after the last one
+func (r *recordReader) Read() (*Record, error) {
+	if len(r.records) == 0 {
+		return nil, io.EOF
+	}
+	record := r.records[0]
+	r.records = r.records[1:]
+	return record, nil
+}
@@ -0,0 +1,304 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLayout describes records such as
+// "H20231001ACQUIRER  ", "D<tx id 10><merchant 10><amount 10><currency><card 16><reason 20><date 8>"
+// and "T<count 6><total 12>", with zero-padded amounts in cents
+func testLayout() FixedWidthLayout {
+	return FixedWidthLayout{
+		TypeStart:  1,
+		TypeLength: 1,
+		Header: &RecordSpec{
+			Type:   "H",
+			Fields: []FieldSpec{{Name: "file_date", Start: 2, Length: 8}},
+		},
+		Detail: RecordSpec{
+			Type: "D",
+			Fields: []FieldSpec{
+				{Name: FieldTransactionID, Start: 2, Length: 10},
+				{Name: FieldMerchantID, Start: 12, Length: 10},
+				{Name: FieldAmount, Start: 22, Length: 10, Pad: "0"},
+				{Name: FieldCurrency, Start: 32, Length: 3},
+				{Name: FieldCardNumber, Start: 35, Length: 16},
+				{Name: FieldReason, Start: 51, Length: 20},
+				{Name: FieldTransactionDate, Start: 71, Length: 8},
+			},
+		},
+		Trailer: &RecordSpec{
+			Type: "T",
+			Fields: []FieldSpec{
+				{Name: FieldRecordCount, Start: 2, Length: 6, Pad: "0"},
+				{Name: FieldTotalAmount, Start: 8, Length: 12, Pad: "0"},
+			},
+		},
+		Format: Format{DateLayout: "20060102", MinorUnits: true},
+	}
+}
+
+// testDetail builds a detail record of the test layout
+func testDetail(transactionID, amount string) string {
+	return "D" + padRight(transactionID, 10) + padRight("merchant-1", 10) + padLeft(amount, 10, "0") +
+		"usd" + "4111111111111111" + padRight("fraud", 20) + "20231001"
+}
+
+// testTrailer builds a trailer record of the test layout
+func testTrailer(count, total string) string {
+	return "T" + padLeft(count, 6, "0") + padLeft(total, 12, "0")
+}
+
+func padRight(value string, length int) string {
+	return value + strings.Repeat(" ", length-len(value))
+}
+
+func padLeft(value string, length int, pad string) string {
+	return strings.Repeat(pad, length-len(value)) + value
+}
+
+func TestFixedWidthParser_Parse(t *testing.T) {
+	// Arrange
+	parser, err := NewFixedWidthParser(testLayout())
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+	file := strings.Join([]string{
+		"H20231001",
+		testDetail("tx-1", "1050"),
+		testDetail("tx-2", "12x"),
+		testTrailer("2", "1050"),
+	}, "\r\n") + "\r\n"
+
+	// Act
+	reader, parseErr := parser.Parse(strings.NewReader(file))
+
+	// Assert
+	if parseErr == nil {
+		t.Fatal("Expected total amount to be unverifiable with an invalid detail amount")
+	}
+	if !strings.Contains(parseErr.Error(), "cannot verify total amount: line 3") {
+		t.Errorf("Unexpected error %v", parseErr)
+	}
+	if reader != nil {
+		t.Errorf("Expected no reader, got %v", reader)
+	}
+
+	// Arrange
+	file = strings.Join([]string{
+		"H20231001",
+		testDetail("tx-1", "1050"),
+		strings.Replace(testDetail("tx-2", "200"), "fraud", "other", 1),
+		"",
+		testTrailer("2", "1250"),
+	}, "\n")
+
+	// Act
+	reader, err = parser.Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first, _ := reader.Read()
+	second, _ := reader.Read()
+	_, eof := reader.Read()
+
+	// Assert
+	if strings.Join(reader.Header(), ",") != "transaction_id,merchant_id,amount,currency,card_number,reason,transaction_date" {
+		t.Errorf("Unexpected header %v", reader.Header())
+	}
+	if first.Line != 2 || first.Err != nil {
+		t.Fatalf("Unexpected first record %+v", first)
+	}
+	expectedDate := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	req := first.Request
+	if req.TransactionID != "tx-1" || req.MerchantID != "merchant-1" || req.Amount != 10.50 || req.Currency != "USD" ||
+		req.CardNumber != "4111111111111111" || req.Reason != "fraud" || !req.TransactionDate.Equal(expectedDate) {
+		t.Errorf("Unexpected request %+v", req)
+	}
+	if second.Line != 3 || second.Request.Amount != 2 || second.Request.Reason != "other" {
+		t.Errorf("Unexpected second record %+v", second)
+	}
+	if !errors.Is(eof, io.EOF) {
+		t.Errorf("Expected io.EOF after the last record, got %v", eof)
+	}
+}
+
+func TestFixedWidthParser_Parse_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		records       []string
+		expectedError string
+	}{
+		{
+			name:          "empty file",
+			expectedError: "file is empty",
+		},
+		{
+			name:          "missing header",
+			records:       []string{testDetail("tx-1", "100"), testTrailer("1", "100")},
+			expectedError: "line 1: missing header record",
+		},
+		{
+			name:          "missing trailer",
+			records:       []string{"H20231001", testDetail("tx-1", "100")},
+			expectedError: "missing trailer record",
+		},
+		{
+			name:          "record after trailer",
+			records:       []string{"H20231001", testTrailer("0", "0"), testDetail("tx-1", "100")},
+			expectedError: "line 3: record after the trailer",
+		},
+		{
+			name:          "unknown record type",
+			records:       []string{"H20231001", "X123", testTrailer("0", "0")},
+			expectedError: `line 2: unknown record type "X"`,
+		},
+		{
+			name:          "record count mismatch",
+			records:       []string{"H20231001", testDetail("tx-1", "100"), testTrailer("2", "100")},
+			expectedError: "trailer record count 2 doesn't match the 1 detail records",
+		},
+		{
+			name:          "total amount mismatch",
+			records:       []string{"H20231001", testDetail("tx-1", "100"), testTrailer("1", "1000")},
+			expectedError: "trailer total amount 10.00 doesn't match the detail records' total 1.00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			parser, err := NewFixedWidthParser(testLayout())
+			if err != nil {
+				t.Fatalf("Failed to create parser: %v", err)
+			}
+
+			// Act
+			_, err = parser.Parse(strings.NewReader(strings.Join(tt.records, "\n")))
+
+			// Assert
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("Expected error '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestFixedWidthLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(layout *FixedWidthLayout)
+		expectedError string
+	}{
+		{
+			name:   "valid layout",
+			modify: func(layout *FixedWidthLayout) {},
+		},
+		{
+			name:          "missing record type position",
+			modify:        func(layout *FixedWidthLayout) { layout.TypeLength = 0 },
+			expectedError: "record type position is required",
+		},
+		{
+			name:          "record type of the wrong length",
+			modify:        func(layout *FixedWidthLayout) { layout.Detail.Type = "DD" },
+			expectedError: `record type "DD" must be 1 characters`,
+		},
+		{
+			name:          "shared record type",
+			modify:        func(layout *FixedWidthLayout) { layout.Trailer.Type = "H" },
+			expectedError: `record type "H" is used twice`,
+		},
+		{
+			name: "missing detail field",
+			modify: func(layout *FixedWidthLayout) {
+				layout.Detail.Fields = layout.Detail.Fields[1:]
+			},
+			expectedError: "missing detail fields: transaction_id",
+		},
+		{
+			name: "unknown detail field",
+			modify: func(layout *FixedWidthLayout) {
+				layout.Detail.Fields = append(layout.Detail.Fields, FieldSpec{Name: "acquirer", Start: 79, Length: 4})
+			},
+			expectedError: "unknown detail fields: acquirer",
+		},
+		{
+			name: "amount read as text",
+			modify: func(layout *FixedWidthLayout) {
+				layout.Detail.Fields[2].Type = FieldTypeText
+			},
+			expectedError: `record "D": field amount must be of type amount`,
+		},
+		{
+			name: "invalid alignment",
+			modify: func(layout *FixedWidthLayout) {
+				layout.Header.Fields[0].Align = "center"
+			},
+			expectedError: `record "H": field file_date alignment must be left or right`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			layout := testLayout()
+			tt.modify(&layout)
+
+			// Act
+			err := layout.Validate()
+
+			// Assert
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("Expected error '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestLoadFixedWidthLayout(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "layout.json")
+	data := `{
+		"type_start": 1, "type_length": 1,
+		"detail": {"type": "D", "fields": [
+			{"name": "transaction_id", "start": 2, "length": 10},
+			{"name": "merchant_id", "start": 12, "length": 10},
+			{"name": "amount", "start": 22, "length": 10, "pad": "0"},
+			{"name": "currency", "start": 32, "length": 3},
+			{"name": "card_number", "start": 35, "length": 16},
+			{"name": "reason", "start": 51, "length": 20},
+			{"name": "transaction_date", "start": 71, "length": 8}
+		]},
+		"format": {"date_layout": "20060102", "minor_units": true}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write layout: %v", err)
+	}
+
+	// Act
+	layout, err := LoadFixedWidthLayout(path)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := layout.Validate(); err != nil {
+		t.Errorf("Expected valid layout, got %v", err)
+	}
+	if layout.Format.DecimalSeparator != "." || !layout.Format.MinorUnits || layout.Trailer != nil {
+		t.Errorf("Unexpected layout %+v", layout)
+	}
+}
@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Chargeback fields a column can be mapped to; the names match the API request body
+const (
+	FieldTransactionID   = "transaction_id"
+	FieldMerchantID      = "merchant_id"
+	FieldAmount          = "amount"
+	FieldCurrency        = "currency"
+	FieldCardNumber      = "card_number"
+	FieldReason          = "reason"
+	FieldDescription     = "description"
+	FieldTransactionDate = "transaction_date"
+)
+
+// fields lists every mappable field; all but description are required
+var fields = []string{
+	FieldTransactionID,
+	FieldMerchantID,
+	FieldAmount,
+	FieldCurrency,
+	FieldCardNumber,
+	FieldReason,
+	FieldDescription,
+	FieldTransactionDate,
+}
+
+// Mapping maps chargeback fields to the column holding them in the file, e.g.
+// "transaction_id" to "Txn Ref"; unmapped fields are read from the column of the same name
+type Mapping map[string]string
+
+// ParseMapping parses "field=column" pairs separated by commas
+func ParseMapping(value string) (Mapping, error) {
+	mapping := make(Mapping)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		field, column, ok := strings.Cut(pair, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected field=column", pair)
+		}
+		mapping[field] = column
+	}
+
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// Validate checks that only known fields are mapped
+func (m Mapping) Validate() error {
+	var unknown []string
+	for field := range m {
+		if !isField(field) {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown fields in column mapping: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Column returns the column name holding the field
+func (m Mapping) Column(field string) string {
+	if column, ok := m[field]; ok {
+		return column
+	}
+	return field
+}
+
+// isField reports whether name is a mappable field
+func isField(name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Format describes how dates and amounts are written in the file
+type Format struct {
+	// DateLayout is the Go time layout of the transaction date; dates without a zone are UTC
+	DateLayout string `json:"date_layout"`
+
+	// DecimalSeparator separates the fractional part of amounts, "." unless set
+	DecimalSeparator string `json:"decimal_separator,omitempty"`
+
+	// ThousandsSeparator groups the integer part of amounts, e.g. "," in "1,234.56"
+	ThousandsSeparator string `json:"thousands_separator,omitempty"`
+
+	// MinorUnits means amounts are written in minor units, e.g. cents
+	MinorUnits bool `json:"minor_units,omitempty"`
+}
+
+// DefaultFormat matches the API: RFC 3339 dates and amounts such as 1234.56
+func DefaultFormat() Format {
+	return Format{
+		DateLayout:       time.RFC3339,
+		DecimalSeparator: ".",
+	}
+}
+
+// Validate validates the format
+func (f Format) Validate() error {
+	if f.DateLayout == "" {
+		return fmt.Errorf("date layout is required")
+	}
+	if f.DecimalSeparator != "" && f.DecimalSeparator == f.ThousandsSeparator {
+		return fmt.Errorf("decimal and thousands separators must differ")
+	}
+	return nil
+}
+
+// ParseAmount parses an amount written in the format
+func (f Format) ParseAmount(value string) (float64, error) {
+	normalized := strings.TrimSpace(value)
+	if f.ThousandsSeparator != "" {
+		normalized = strings.ReplaceAll(normalized, f.ThousandsSeparator, "")
+	}
+	if f.DecimalSeparator != "" && f.DecimalSeparator != "." {
+		normalized = strings.ReplaceAll(normalized, f.DecimalSeparator, ".")
+	}
+
+	if f.MinorUnits {
+		minor, err := strconv.ParseInt(normalized, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q: expected minor units", value)
+		}
+		return float64(minor) / 100, nil
+	}
+
+	amount, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// ParseDate parses a transaction date written in the format
+func (f Format) ParseDate(value string) (time.Time, error) {
+	date, err := time.Parse(f.DateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transaction date %q: expected layout %s", value, f.DateLayout)
+	}
+	return date, nil
+}
+
+// ParseReason normalizes a reason such as "Consumer Dispute" to its entity value
+// Unknown reasons are left for entity validation to reject
+func ParseReason(value string) entity.ChargebackReason {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	return entity.ChargebackReason(strings.Join(strings.Fields(normalized), "_"))
+}
+
+// newRequest builds a create request from the values of a row's fields
+// Amount and date errors are joined, so a rejected row lists every problem
+func (f Format) newRequest(value func(field string) string) (usecase.CreateChargebackRequest, error) {
+	req := usecase.CreateChargebackRequest{
+		TransactionID: value(FieldTransactionID),
+		MerchantID:    value(FieldMerchantID),
+		Currency:      strings.ToUpper(value(FieldCurrency)),
+		CardNumber:    value(FieldCardNumber),
+		Reason:        ParseReason(value(FieldReason)),
+		Description:   value(FieldDescription),
+	}
+
+	var errs []string
+	if amount := value(FieldAmount); amount != "" {
+		parsed, err := f.ParseAmount(amount)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		req.Amount = parsed
+	}
+	if date := value(FieldTransactionDate); date != "" {
+		parsed, err := f.ParseDate(date)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		req.TransactionDate = parsed
+	}
+
+	if len(errs) > 0 {
+		return req, errors.New(strings.Join(errs, "; "))
+	}
+	return req, nil
+}
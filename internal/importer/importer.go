@@ -0,0 +1,188 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// Record is a row of a dispute file
+type Record struct {
+	// Line is the 1-based line the row starts on
+	Line int
+
+	// Fields holds the raw values, written to the rejects file
+	Fields []string
+
+	// Request is the parsed row; only valid when Err is nil
+	Request usecase.CreateChargebackRequest
+
+	// Err describes why the row could not be parsed
+	Err error
+}
+
+// Reader reads the rows of a dispute file
+type Reader interface {
+	// Header names the values of Record.Fields
+	Header() []string
+
+	// Read returns the next record, or io.EOF after the last one
+	Read() (*Record, error)
+}
+
+// DisputeFileParser opens dispute files of one format
+type DisputeFileParser interface {
+	// Parse checks the file's structure and returns a reader over its rows
+	Parse(r io.Reader) (Reader, error)
+}
+
+// Reject is a row that was not imported
+type Reject struct {
+	Line   int
+	Reason string
+	Fields []string
+}
+
+// RejectWriter records rejected rows
+type RejectWriter interface {
+	WriteReject(reject Reject) error
+}
+
+// Result counts the rows of an import
+type Result struct {
+	// Imported rows were created, or would have been in a dry run
+	Imported int
+	Rejected int
+	// Skipped rows were imported by an earlier, interrupted run
+	Skipped int
+}
+
+// Options configures an import run
+type Options struct {
+	// DryRun validates rows without creating chargebacks or saving progress
+	DryRun bool
+
+	// Checkpoint stores the last processed line so an interrupted import can resume;
+	// nil disables resuming
+	Checkpoint Checkpoint
+
+	// Rejects receives the rows that were not imported; nil discards them
+	Rejects RejectWriter
+}
+
+// CreateChargebackUseCase interface defines the contract for creating chargebacks
+type CreateChargebackUseCase interface {
+	Execute(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error)
+}
+
+// Importer creates chargebacks from the rows of dispute files
+type Importer struct {
+	createChargebackUC CreateChargebackUseCase
+}
+
+// NewImporter creates a new importer
+// Rows go through the create use case, so they are validated and authorized like API requests
+func NewImporter(createChargebackUC CreateChargebackUseCase) *Importer {
+	return &Importer{
+		createChargebackUC: createChargebackUC,
+	}
+}
+
+// Run imports every row of the reader, resuming after the checkpoint's line
+// Rejected rows don't stop the import; it fails, leaving the checkpoint on the last
+// processed row, when the file can't be read or a chargeback can't be saved, and the
+// checkpoint is cleared once the whole file is processed
+func (i *Importer) Run(ctx context.Context, reader Reader, opts Options) (Result, error) {
+	var result Result
+
+	checkpoint := opts.Checkpoint
+	if opts.DryRun {
+		checkpoint = nil
+	}
+
+	resumeAfter := 0
+	if checkpoint != nil {
+		line, err := checkpoint.Load()
+		if err != nil {
+			return result, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		resumeAfter = line
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read record: %w", err)
+		}
+
+		if record.Line <= resumeAfter {
+			result.Skipped++
+			continue
+		}
+
+		reason, err := i.importRecord(ctx, record, opts.DryRun)
+		if err != nil {
+			return result, fmt.Errorf("failed to import line %d: %w", record.Line, err)
+		}
+
+		if reason != "" {
+			result.Rejected++
+			if opts.Rejects != nil {
+				reject := Reject{Line: record.Line, Reason: reason, Fields: record.Fields}
+				if err := opts.Rejects.WriteReject(reject); err != nil {
+					return result, fmt.Errorf("failed to write reject: %w", err)
+				}
+			}
+		} else {
+			result.Imported++
+		}
+
+		if checkpoint != nil {
+			if err := checkpoint.Save(record.Line); err != nil {
+				return result, fmt.Errorf("failed to save checkpoint: %w", err)
+			}
+		}
+	}
+
+	if checkpoint != nil {
+		if err := checkpoint.Clear(); err != nil {
+			return result, fmt.Errorf("failed to clear checkpoint: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// importRecord creates the record's chargeback and returns why it was rejected, if it was
+// Errors that aren't the row's fault, such as the repository being unavailable, are returned
+func (i *Importer) importRecord(ctx context.Context, record *Record, dryRun bool) (string, error) {
+	if record.Err != nil {
+		return record.Err.Error(), nil
+	}
+
+	if err := record.Request.Validate(); err != nil {
+		return err.Error(), nil
+	}
+	if dryRun {
+		return "", nil
+	}
+
+	if _, err := i.createChargebackUC.Execute(ctx, record.Request); err != nil {
+		if errors.Is(err, usecase.ErrChargebackAlreadyExists) || errors.Is(err, auth.ErrForbidden) {
+			return err.Error(), nil
+		}
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,154 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockCreateChargebackUseCase is a mock implementation of CreateChargebackUseCase
+type MockCreateChargebackUseCase struct {
+	ExecuteFunc func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error)
+	created     []string
+}
+
+func (m *MockCreateChargebackUseCase) Execute(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+	if m.ExecuteFunc != nil {
+		if _, err := m.ExecuteFunc(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	m.created = append(m.created, req.TransactionID)
+	return &usecase.CreateChargebackResponse{TransactionID: req.TransactionID}, nil
+}
+
+const testHeader = "transaction_id,merchant_id,amount,currency,card_number,reason,transaction_date\n"
+
+func testRow(transactionID string) string {
+	return transactionID + ",merchant-1,10.50,USD,4111111111111111,fraud,2023-10-01T10:00:00Z\n"
+}
+
+func newTestReader(t *testing.T, data string) *CSVReader {
+	t.Helper()
+
+	reader, err := NewCSVReader(strings.NewReader(data), CSVConfig{Format: DefaultFormat()})
+	if err != nil {
+		t.Fatalf("Failed to create reader: %v", err)
+	}
+	return reader
+}
+
+func TestImporter_Run(t *testing.T) {
+	// Arrange
+	data := testHeader + testRow("tx-1") + strings.Replace(testRow("tx-2"), "10.50", "-1", 1) + testRow("tx-3") + testRow("tx-4")
+	createUC := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			if req.TransactionID == "tx-3" {
+				return nil, fmt.Errorf("%w for transaction tx-3", usecase.ErrChargebackAlreadyExists)
+			}
+			return nil, nil
+		},
+	}
+	var rejects bytes.Buffer
+	rejectWriter, err := NewCSVRejectWriter(&rejects, []string{"transaction_id"})
+	if err != nil {
+		t.Fatalf("Failed to create reject writer: %v", err)
+	}
+	checkpoint := NewFileCheckpoint(filepath.Join(t.TempDir(), "import.checkpoint"))
+
+	// Act
+	result, err := NewImporter(createUC).Run(context.Background(), newTestReader(t, data), Options{
+		Checkpoint: checkpoint,
+		Rejects:    rejectWriter,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result != (Result{Imported: 2, Rejected: 2}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if strings.Join(createUC.created, ",") != "tx-1,tx-4" {
+		t.Errorf("Expected tx-1 and tx-4 to be created, got %v", createUC.created)
+	}
+
+	lines := strings.Split(strings.TrimSpace(rejects.String()), "\n")
+	if len(lines) != 3 || lines[0] != "line,reason,transaction_id" ||
+		!strings.HasPrefix(lines[1], "3,validation errors: amount must be greater than zero,tx-2") ||
+		!strings.HasPrefix(lines[2], "4,chargeback already exists for transaction tx-3,tx-3") {
+		t.Errorf("Unexpected rejects file:\n%s", rejects.String())
+	}
+
+	if line, _ := checkpoint.Load(); line != 0 {
+		t.Errorf("Expected checkpoint to be cleared after a complete import, got line %d", line)
+	}
+}
+
+func TestImporter_Run_ResumesAfterFailure(t *testing.T) {
+	// Arrange
+	data := testHeader + testRow("tx-1") + testRow("tx-2") + testRow("tx-3")
+	checkpoint := NewFileCheckpoint(filepath.Join(t.TempDir(), "import.checkpoint"))
+	failing := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			if req.TransactionID == "tx-2" {
+				return nil, errors.New("failed to save chargeback: DynamoDB unavailable")
+			}
+			return nil, nil
+		},
+	}
+
+	// Act
+	_, firstErr := NewImporter(failing).Run(context.Background(), newTestReader(t, data), Options{Checkpoint: checkpoint})
+	line, _ := checkpoint.Load()
+
+	resumed := &MockCreateChargebackUseCase{}
+	result, err := NewImporter(resumed).Run(context.Background(), newTestReader(t, data), Options{Checkpoint: checkpoint})
+
+	// Assert
+	if firstErr == nil || !strings.Contains(firstErr.Error(), "failed to import line 3") {
+		t.Errorf("Expected first run to stop on line 3, got %v", firstErr)
+	}
+	if line != 2 {
+		t.Errorf("Expected checkpoint on line 2, got %d", line)
+	}
+	if err != nil {
+		t.Fatalf("Expected resumed run to succeed, got %v", err)
+	}
+	if result != (Result{Imported: 2, Skipped: 1}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if strings.Join(resumed.created, ",") != "tx-2,tx-3" {
+		t.Errorf("Expected only remaining rows to be created, got %v", resumed.created)
+	}
+}
+
+func TestImporter_Run_DryRun(t *testing.T) {
+	// Arrange
+	data := testHeader + testRow("tx-1") + strings.Replace(testRow("tx-2"), "fraud", "chargeback", 1)
+	createUC := &MockCreateChargebackUseCase{}
+	checkpoint := NewFileCheckpoint(filepath.Join(t.TempDir(), "import.checkpoint"))
+
+	// Act
+	result, err := NewImporter(createUC).Run(context.Background(), newTestReader(t, data), Options{
+		DryRun:     true,
+		Checkpoint: checkpoint,
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result != (Result{Imported: 1, Rejected: 1}) {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if len(createUC.created) != 0 {
+		t.Errorf("Expected no chargebacks to be created in a dry run, got %v", createUC.created)
+	}
+}
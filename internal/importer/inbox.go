@@ -0,0 +1,366 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// Folders of the inbox directory files move through
+const (
+	InboxProcessing = "processing"
+	InboxProcessed  = "processed"
+	InboxFailed     = "failed"
+)
+
+// Suffixes of the files kept next to a file being ingested
+const (
+	checkpointSuffix = ".checkpoint"
+	rejectsSuffix    = ".rejects.csv"
+)
+
+// InboxConfig configures the inbox directory watcher
+type InboxConfig struct {
+	// Dir is polled for dispute files; hidden files are ignored
+	Dir          string
+	PollInterval time.Duration
+
+	// SettleTime skips files modified more recently, so uploads in progress aren't claimed
+	SettleTime time.Duration
+
+	// Parsers maps lowercase file extensions, e.g. ".csv", to the parser of their format
+	Parsers map[string]DisputeFileParser
+}
+
+// Validate validates the configuration
+func (c InboxConfig) Validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("inbox directory is required")
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("inbox poll interval must be positive")
+	}
+	if c.SettleTime < 0 {
+		return fmt.Errorf("inbox settle time must not be negative")
+	}
+	if len(c.Parsers) == 0 {
+		return fmt.Errorf("inbox needs a parser for at least one file extension")
+	}
+	return nil
+}
+
+// Inbox ingests dispute files dropped in a directory
+// A new file is claimed by renaming it into the processing folder, imported, and moved
+// with its rejects to the processed or failed folder. Files are identified by checksum,
+// so a file that was already processed is not imported again under another name.
+// Imports that stop on an error that isn't the file's fault, such as the repository
+// being unavailable, stay in the processing folder and resume from their checkpoint on
+// the next poll. A single watcher must own an inbox directory.
+type Inbox struct {
+	importer *Importer
+	config   InboxConfig
+	ledger   IngestionLedger
+	logger   service.Logger
+}
+
+// NewInbox creates the inbox's folders and returns its watcher
+func NewInbox(importer *Importer, config InboxConfig, ledger IngestionLedger, logger service.Logger) (*Inbox, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, folder := range []string{InboxProcessing, InboxProcessed, InboxFailed} {
+		if err := os.MkdirAll(filepath.Join(config.Dir, folder), 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create inbox folder: %w", err)
+		}
+	}
+
+	return &Inbox{
+		importer: importer,
+		config:   config,
+		ledger:   ledger,
+		logger:   logger,
+	}, nil
+}
+
+// Run polls the inbox until the context is cancelled
+func (i *Inbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(i.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.Poll(ctx); err != nil && ctx.Err() == nil {
+			i.logger.Error(ctx, "Failed to poll inbox", map[string]interface{}{
+				"dir":   i.config.Dir,
+				"error": err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll resumes files left in the processing folder, then claims and ingests new files
+func (i *Inbox) Poll(ctx context.Context) error {
+	claimed, err := i.claimedFiles()
+	if err != nil {
+		return err
+	}
+	for _, name := range claimed {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		i.ingest(ctx, name)
+	}
+
+	entries, err := os.ReadDir(i.config.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to list inbox: %w", err)
+	}
+
+	settled := time.Now().Add(-i.config.SettleTime)
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(settled) {
+			continue
+		}
+
+		// Renaming over an interrupted file of the same name would resume the new file
+		// from the old one's checkpoint, so it waits until the old one is done
+		claimedPath := filepath.Join(i.config.Dir, InboxProcessing, entry.Name())
+		if _, err := os.Lstat(claimedPath); err == nil {
+			i.logger.Warn(ctx, "Dispute file waits for an interrupted file of the same name", map[string]interface{}{
+				"file": entry.Name(),
+			})
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to check processing folder for %s: %w", entry.Name(), err)
+		}
+		if err := os.Rename(filepath.Join(i.config.Dir, entry.Name()), claimedPath); err != nil {
+			// The file was removed, or claimed by someone else, since it was listed
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("failed to claim %s: %w", entry.Name(), err)
+		}
+		i.ingest(ctx, entry.Name())
+	}
+
+	return nil
+}
+
+// claimedFiles lists the files in the processing folder, leaving out checkpoints and rejects
+func (i *Inbox) claimedFiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(i.config.Dir, InboxProcessing))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processing folder: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || strings.HasSuffix(name, checkpointSuffix) || strings.HasSuffix(name, rejectsSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ingest imports a claimed file and moves it to the processed or failed folder
+func (i *Inbox) ingest(ctx context.Context, name string) {
+	path := filepath.Join(i.config.Dir, InboxProcessing, name)
+	ingestion := Ingestion{File: name}
+
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		i.logger.Error(ctx, "Failed to read claimed dispute file", map[string]interface{}{
+			"file":  name,
+			"error": err.Error(),
+		})
+		return
+	}
+	ingestion.Checksum = checksum
+
+	previous, processed, err := i.ledger.Processed(checksum)
+	if err != nil {
+		i.logger.Error(ctx, "Failed to look up dispute file in ledger", map[string]interface{}{
+			"file":  name,
+			"error": err.Error(),
+		})
+		return
+	}
+
+	switch {
+	case processed:
+		ingestion.Outcome = IngestionDuplicate
+		ingestion.Error = fmt.Sprintf("already processed as %s at %s", previous.File, previous.CompletedAt.Format(time.RFC3339))
+	default:
+		result, err := i.importFile(ctx, path)
+		ingestion.Imported, ingestion.Rejected = result.Imported, result.Rejected
+		if err != nil {
+			var fileErr *invalidFileError
+			if !errors.As(err, &fileErr) {
+				// Left in the processing folder to resume on the next poll
+				if ctx.Err() == nil {
+					i.logger.Error(ctx, "Dispute file import interrupted", map[string]interface{}{
+						"file":  name,
+						"error": err.Error(),
+					})
+				}
+				return
+			}
+			ingestion.Outcome = IngestionFailed
+			ingestion.Error = err.Error()
+		} else {
+			ingestion.Outcome = IngestionProcessed
+		}
+	}
+
+	folder := InboxProcessed
+	if ingestion.Outcome != IngestionProcessed {
+		folder = InboxFailed
+	}
+	location, err := i.moveClaimed(name, folder)
+	if err != nil {
+		i.logger.Error(ctx, "Failed to move dispute file", map[string]interface{}{
+			"file":  name,
+			"error": err.Error(),
+		})
+		return
+	}
+	ingestion.Location = location
+	ingestion.CompletedAt = time.Now().UTC()
+
+	if err := i.ledger.Record(ingestion); err != nil {
+		i.logger.Error(ctx, "Failed to record dispute file ingestion", map[string]interface{}{
+			"file":  name,
+			"error": err.Error(),
+		})
+	}
+
+	fields := map[string]interface{}{
+		"file":     name,
+		"checksum": checksum,
+		"outcome":  string(ingestion.Outcome),
+		"imported": ingestion.Imported,
+		"rejected": ingestion.Rejected,
+		"location": location,
+	}
+	if ingestion.Outcome == IngestionProcessed {
+		i.logger.Info(ctx, "Dispute file ingested", fields)
+	} else {
+		fields["error"] = ingestion.Error
+		i.logger.Warn(ctx, "Dispute file not ingested", fields)
+	}
+}
+
+// invalidFileError marks errors that moving the file to the failed folder is the answer to
+type invalidFileError struct {
+	err error
+}
+
+func (e *invalidFileError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidFileError) Unwrap() error {
+	return e.err
+}
+
+// importFile parses the file with the parser of its extension and imports its rows,
+// resuming from the file's checkpoint and appending to its rejects
+func (i *Inbox) importFile(ctx context.Context, path string) (Result, error) {
+	parser, ok := i.config.Parsers[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return Result{}, &invalidFileError{fmt.Errorf("unsupported file type %q", filepath.Ext(path))}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer file.Close()
+
+	reader, err := parser.Parse(file)
+	if err != nil {
+		return Result{}, &invalidFileError{fmt.Errorf("%w: %v", ErrInvalidFile, err)}
+	}
+
+	checkpoint := NewFileCheckpoint(path + checkpointSuffix)
+	line, err := checkpoint.Load()
+	if err != nil {
+		return Result{}, err
+	}
+
+	flags, header := os.O_CREATE|os.O_WRONLY|os.O_TRUNC, reader.Header()
+	if line > 0 {
+		flags, header = os.O_CREATE|os.O_WRONLY|os.O_APPEND, nil
+	}
+	rejectsFile, err := os.OpenFile(path+rejectsSuffix, flags, 0o600)
+	if err != nil {
+		return Result{}, err
+	}
+	defer rejectsFile.Close()
+
+	rejects, err := NewCSVRejectWriter(rejectsFile, header)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return i.importer.Run(ctx, reader, Options{Checkpoint: checkpoint, Rejects: rejects})
+}
+
+// moveClaimed moves a claimed file, and its rejects if any, to the folder under a
+// timestamped name, and drops its checkpoint; it returns the file's new path
+func (i *Inbox) moveClaimed(name, folder string) (string, error) {
+	claimed := filepath.Join(i.config.Dir, InboxProcessing, name)
+	target := filepath.Join(i.config.Dir, folder, time.Now().UTC().Format("20060102T150405.000Z")+"_"+name)
+
+	if err := os.Rename(claimed+rejectsSuffix, target+rejectsSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.Remove(claimed + checkpointSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if err := os.Rename(claimed, target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// fileChecksum returns the hex-encoded SHA-256 of the file's content
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,260 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// testLogger discards log entries
+type testLogger struct{}
+
+func (l *testLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *testLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *testLogger) WithContext(ctx context.Context) service.Logger { return l }
+
+// newTestInbox creates an inbox over a temporary directory that accepts CSV files
+func newTestInbox(t *testing.T, createUC CreateChargebackUseCase) (*Inbox, string, *FileLedger) {
+	t.Helper()
+
+	dir := t.TempDir()
+	ledger, err := NewFileLedger(filepath.Join(dir, ".ingestions.jsonl"))
+	if err != nil {
+		t.Fatalf("Failed to create ledger: %v", err)
+	}
+	inbox, err := NewInbox(NewImporter(createUC), InboxConfig{
+		Dir:          dir,
+		PollInterval: time.Second,
+		Parsers:      map[string]DisputeFileParser{".csv": NewCSVParser(CSVConfig{Format: DefaultFormat()})},
+	}, ledger, &testLogger{})
+	if err != nil {
+		t.Fatalf("Failed to create inbox: %v", err)
+	}
+	return inbox, dir, ledger
+}
+
+// dropFile writes a file into the inbox
+func dropFile(t *testing.T, dir, name, data string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
+
+// folderFiles lists the names of the files in an inbox folder
+func folderFiles(t *testing.T, dir, folder string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, folder))
+	if err != nil {
+		t.Fatalf("Failed to list %s: %v", folder, err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestInbox_Poll(t *testing.T) {
+	// Arrange
+	createUC := &MockCreateChargebackUseCase{}
+	inbox, dir, ledger := newTestInbox(t, createUC)
+	data := testHeader + testRow("tx-1") + strings.Replace(testRow("tx-2"), "USD", "", 1)
+	dropFile(t, dir, "disputes.csv", data)
+	dropFile(t, dir, "broken.csv", "transaction_id\ntx-1\n")
+	dropFile(t, dir, "notes.pdf", "%PDF")
+
+	// Act
+	err := inbox.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(createUC.created, ",") != "tx-1" {
+		t.Errorf("Expected tx-1 to be created, got %v", createUC.created)
+	}
+	if remaining := folderFiles(t, dir, InboxProcessing); len(remaining) != 0 {
+		t.Errorf("Expected processing folder to be empty, got %v", remaining)
+	}
+
+	processed := folderFiles(t, dir, InboxProcessed)
+	if len(processed) != 2 || !strings.HasSuffix(processed[0], "_disputes.csv") || !strings.HasSuffix(processed[1], "_disputes.csv.rejects.csv") {
+		t.Errorf("Expected the file and its rejects in the processed folder, got %v", processed)
+	}
+	failed := folderFiles(t, dir, InboxFailed)
+	if len(failed) != 2 {
+		t.Errorf("Expected the unparseable and unsupported files in the failed folder, got %v", failed)
+	}
+
+	checksum, err := fileChecksum(filepath.Join(dir, InboxProcessed, processed[0]))
+	if err != nil {
+		t.Fatalf("Failed to compute checksum: %v", err)
+	}
+	ingestion, ok, _ := ledger.Processed(checksum)
+	if !ok || ingestion.File != "disputes.csv" || ingestion.Imported != 1 || ingestion.Rejected != 1 {
+		t.Errorf("Expected processed ingestion in the ledger, got %+v", ingestion)
+	}
+}
+
+func TestInbox_Poll_SkipsDuplicatesAndUnsettledFiles(t *testing.T) {
+	// Arrange
+	createUC := &MockCreateChargebackUseCase{}
+	inbox, dir, _ := newTestInbox(t, createUC)
+	data := testHeader + testRow("tx-1")
+	dropFile(t, dir, "monday.csv", data)
+	if err := inbox.Poll(context.Background()); err != nil {
+		t.Fatalf("Failed to poll: %v", err)
+	}
+	dropFile(t, dir, "monday-copy.csv", data)
+	dropFile(t, dir, ".uploading.csv", data)
+
+	// Act
+	err := inbox.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(createUC.created) != 1 {
+		t.Errorf("Expected the copy not to be imported again, got %v", createUC.created)
+	}
+	if failed := folderFiles(t, dir, InboxFailed); len(failed) != 1 || !strings.HasSuffix(failed[0], "_monday-copy.csv") {
+		t.Errorf("Expected the copy in the failed folder, got %v", failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".uploading.csv")); err != nil {
+		t.Errorf("Expected hidden file to be left alone, got %v", err)
+	}
+
+	// Arrange
+	inbox.config.SettleTime = time.Hour
+	dropFile(t, dir, "tuesday.csv", testHeader+testRow("tx-2"))
+
+	// Act
+	err = inbox.Poll(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tuesday.csv")); err != nil {
+		t.Errorf("Expected recently modified file to be left in the inbox, got %v", err)
+	}
+}
+
+func TestInbox_Poll_ResumesInterruptedImport(t *testing.T) {
+	// Arrange
+	unavailable := true
+	createUC := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			if req.TransactionID == "tx-2" && unavailable {
+				return nil, errors.New("failed to save chargeback: DynamoDB unavailable")
+			}
+			return nil, nil
+		},
+	}
+	inbox, dir, _ := newTestInbox(t, createUC)
+	dropFile(t, dir, "disputes.csv", testHeader+testRow("tx-1")+testRow("tx-2"))
+
+	// Act
+	firstErr := inbox.Poll(context.Background())
+	claimed := folderFiles(t, dir, InboxProcessing)
+	unavailable = false
+	err := inbox.Poll(context.Background())
+
+	// Assert
+	if firstErr != nil || err != nil {
+		t.Fatalf("Expected no poll errors, got %v and %v", firstErr, err)
+	}
+	if len(claimed) != 3 {
+		t.Errorf("Expected the file, checkpoint and rejects to stay in the processing folder, got %v", claimed)
+	}
+	if strings.Join(createUC.created, ",") != "tx-1,tx-2" {
+		t.Errorf("Expected each row to be created once, got %v", createUC.created)
+	}
+	if processed := folderFiles(t, dir, InboxProcessed); len(processed) != 2 {
+		t.Errorf("Expected the file and its rejects in the processed folder, got %v", processed)
+	}
+}
+
+func TestInbox_Poll_WaitsForInterruptedFileOfTheSameName(t *testing.T) {
+	// Arrange
+	unavailable := true
+	createUC := &MockCreateChargebackUseCase{
+		ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+			if req.TransactionID == "tx-2" && unavailable {
+				return nil, errors.New("failed to save chargeback: DynamoDB unavailable")
+			}
+			return nil, nil
+		},
+	}
+	inbox, dir, _ := newTestInbox(t, createUC)
+	dropFile(t, dir, "disputes.csv", testHeader+testRow("tx-1")+testRow("tx-2"))
+	firstErr := inbox.Poll(context.Background())
+
+	// Act: the acquirer sends a new file under the same name while the first is interrupted
+	dropFile(t, dir, "disputes.csv", testHeader+testRow("tx-3")+testRow("tx-4"))
+	secondErr := inbox.Poll(context.Background())
+	waiting := folderFiles(t, dir, ".")
+	unavailable = false
+	err := inbox.Poll(context.Background())
+
+	// Assert
+	if firstErr != nil || secondErr != nil || err != nil {
+		t.Fatalf("Expected no poll errors, got %v, %v and %v", firstErr, secondErr, err)
+	}
+	if !strings.Contains(strings.Join(waiting, ","), "disputes.csv") {
+		t.Errorf("Expected the new file to wait in the inbox, got %v", waiting)
+	}
+	if strings.Join(createUC.created, ",") != "tx-1,tx-2,tx-3,tx-4" {
+		t.Errorf("Expected every row of both files to be created once, got %v", createUC.created)
+	}
+	if remaining := folderFiles(t, dir, InboxProcessing); len(remaining) != 0 {
+		t.Errorf("Expected processing folder to be empty, got %v", remaining)
+	}
+}
+
+func TestNewFileLedger_LoadsProcessedFiles(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger, err := NewFileLedger(path)
+	if err != nil {
+		t.Fatalf("Failed to create ledger: %v", err)
+	}
+	ledger.Record(Ingestion{File: "a.csv", Checksum: "aaa", Outcome: IngestionProcessed})
+	ledger.Record(Ingestion{File: "b.csv", Checksum: "bbb", Outcome: IngestionFailed})
+
+	// Act
+	reloaded, err := NewFileLedger(path)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ingestion, ok, _ := reloaded.Processed("aaa"); !ok || ingestion.File != "a.csv" {
+		t.Errorf("Expected processed file to be found, got %+v", ingestion)
+	}
+	if _, ok, _ := reloaded.Processed("bbb"); ok {
+		t.Error("Expected failed file to be ingestible again")
+	}
+}
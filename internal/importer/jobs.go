@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// ErrJobNotFound is returned when an import job doesn't exist or belongs to another caller
+var ErrJobNotFound = errors.New("import job not found")
+
+// ErrInvalidFile is returned when an import can't start because the parser rejects the
+// file's structure
+var ErrInvalidFile = errors.New("invalid dispute file")
+
+// JobStatus is the state of an import job
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+const (
+	// maxJobRejects bounds the rejects kept per job; the count in the result is exact
+	maxJobRejects = 1000
+
+	// jobRetention is how long finished jobs can be looked up
+	jobRetention = 24 * time.Hour
+)
+
+// Job is an import running in the background
+type Job struct {
+	ID          string
+	Status      JobStatus
+	DryRun      bool
+	Result      Result
+	Rejects     []Reject
+	Error       string
+	CreatedAt   time.Time
+	CompletedAt time.Time
+
+	// owner is the subject of the principal that started the job
+	owner string
+}
+
+// JobManager runs imports in the background and keeps their progress in memory
+// Jobs don't survive a restart; use the importer command to resume large files
+type JobManager struct {
+	importer *Importer
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+	wg   sync.WaitGroup
+}
+
+// NewJobManager creates a new job manager
+func NewJobManager(importer *Importer) *JobManager {
+	return &JobManager{
+		importer: importer,
+		jobs:     make(map[string]*Job),
+	}
+}
+
+// Start parses the file and imports its rows in the background
+// The job runs with the caller's principal, so rows are authorized as if created
+// through the API, and keeps running after the request that started it completes
+func (m *JobManager) Start(ctx context.Context, data []byte, parser DisputeFileParser, dryRun bool) (Job, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksWrite); err != nil {
+		return Job{}, err
+	}
+
+	reader, err := parser.Parse(bytes.NewReader(data))
+	if err != nil {
+		return Job{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to generate job ID: %w", err)
+	}
+
+	job := &Job{
+		ID:        id,
+		Status:    JobRunning,
+		DryRun:    dryRun,
+		CreatedAt: time.Now().UTC(),
+		owner:     subject(ctx),
+	}
+
+	m.mu.Lock()
+	m.pruneLocked(job.CreatedAt)
+	m.jobs[id] = job
+	snapshot := job.snapshot()
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		result, err := m.importer.Run(context.WithoutCancel(ctx), reader, Options{
+			DryRun:  dryRun,
+			Rejects: &jobRejects{manager: m, job: job},
+		})
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		job.Result = result
+		job.Status = JobCompleted
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = err.Error()
+		}
+		job.CompletedAt = time.Now().UTC()
+	}()
+
+	return snapshot, nil
+}
+
+// Get returns the job if it was started by the same caller
+func (m *JobManager) Get(ctx context.Context, id string) (Job, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksWrite); err != nil {
+		return Job{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok || job.owner != subject(ctx) {
+		return Job{}, ErrJobNotFound
+	}
+	return job.snapshot(), nil
+}
+
+// Wait blocks until every running job has finished
+func (m *JobManager) Wait() {
+	m.wg.Wait()
+}
+
+// pruneLocked forgets jobs that finished more than jobRetention ago
+func (m *JobManager) pruneLocked(now time.Time) {
+	for id, job := range m.jobs {
+		if job.Status != JobRunning && now.Sub(job.CompletedAt) > jobRetention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// snapshot copies the job so it can be read without holding the lock
+func (j *Job) snapshot() Job {
+	snapshot := *j
+	snapshot.Rejects = append([]Reject(nil), j.Rejects...)
+	return snapshot
+}
+
+// jobRejects records a job's rejects, keeping at most maxJobRejects
+type jobRejects struct {
+	manager *JobManager
+	job     *Job
+}
+
+// WriteReject adds the reject to the job
+func (r *jobRejects) WriteReject(reject Reject) error {
+	r.manager.mu.Lock()
+	defer r.manager.mu.Unlock()
+
+	r.job.Result.Rejected++
+	if len(r.job.Rejects) < maxJobRejects {
+		r.job.Rejects = append(r.job.Rejects, reject)
+	}
+	return nil
+}
+
+// subject identifies the caller; empty when authentication is disabled
+func subject(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return principal.Subject
+	}
+	return ""
+}
+
+// newJobID generates a random job identifier
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "imp_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+func merchantContext(subject string, scopes ...auth.Scope) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{
+		Subject:     subject,
+		MerchantIDs: []string{"merchant-1"},
+		Scopes:      scopes,
+	})
+}
+
+func TestJobManager(t *testing.T) {
+	// Arrange
+	manager := NewJobManager(NewImporter(&MockCreateChargebackUseCase{}))
+	ctx := merchantContext("key-1", auth.ScopeChargebacksWrite)
+	data := testHeader + testRow("tx-1") + strings.Replace(testRow("tx-2"), "USD", "", 1)
+
+	// Act
+	started, err := manager.Start(ctx, []byte(data), NewCSVParser(CSVConfig{Format: DefaultFormat()}), false)
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	manager.Wait()
+	job, err := manager.Get(ctx, started.ID)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected job, got %v", err)
+	}
+	if !strings.HasPrefix(started.ID, "imp_") || started.Status != JobRunning {
+		t.Errorf("Expected running job with an ID, got %+v", started)
+	}
+	if job.Status != JobCompleted || job.CompletedAt.IsZero() {
+		t.Errorf("Expected completed job, got %+v", job)
+	}
+	if job.Result != (Result{Imported: 1, Rejected: 1}) {
+		t.Errorf("Unexpected result %+v", job.Result)
+	}
+	if len(job.Rejects) != 1 || job.Rejects[0].Line != 3 {
+		t.Errorf("Expected reject for line 3, got %+v", job.Rejects)
+	}
+
+	if _, err := manager.Get(merchantContext("key-2", auth.ScopeChargebacksWrite), started.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Expected other callers not to see the job, got %v", err)
+	}
+}
+
+func TestJobManager_Start_Rejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		data          string
+		expectedError error
+	}{
+		{
+			name:          "missing write scope",
+			ctx:           merchantContext("key-1", auth.ScopeChargebacksRead),
+			data:          testHeader,
+			expectedError: auth.ErrForbidden,
+		},
+		{
+			name:          "missing columns",
+			ctx:           context.Background(),
+			data:          "transaction_id\n",
+			expectedError: ErrInvalidFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			manager := NewJobManager(NewImporter(&MockCreateChargebackUseCase{}))
+
+			// Act
+			_, err := manager.Start(tt.ctx, []byte(tt.data), NewCSVParser(CSVConfig{Format: DefaultFormat()}), false)
+
+			// Assert
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
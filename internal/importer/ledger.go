@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// IngestionOutcome is what happened to a file picked up from the inbox
+type IngestionOutcome string
+
+const (
+	// IngestionProcessed files were imported; rejected rows don't fail a file
+	IngestionProcessed IngestionOutcome = "processed"
+	// IngestionFailed files could not be parsed or imported and may be dropped again once fixed
+	IngestionFailed IngestionOutcome = "failed"
+	// IngestionDuplicate files have the checksum of a file that was already processed
+	IngestionDuplicate IngestionOutcome = "duplicate"
+)
+
+// Ingestion records the outcome of a file picked up from the inbox
+type Ingestion struct {
+	File     string           `json:"file"`
+	Checksum string           `json:"checksum"`
+	Outcome  IngestionOutcome `json:"outcome"`
+	Imported int              `json:"imported"`
+	Rejected int              `json:"rejected"`
+	Error    string           `json:"error,omitempty"`
+	// Location is where the file was moved to
+	Location    string    `json:"location"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// IngestionLedger records ingested files so the same file is never imported twice
+type IngestionLedger interface {
+	// Processed returns the ingestion that processed a file with the checksum, if any
+	Processed(checksum string) (Ingestion, bool, error)
+	Record(ingestion Ingestion) error
+}
+
+// FileLedger keeps ingestions as JSON lines in an append-only file
+type FileLedger struct {
+	path string
+
+	mu        sync.Mutex
+	processed map[string]Ingestion
+}
+
+// NewFileLedger loads the ledger at path, which is created on the first ingestion
+func NewFileLedger(path string) (*FileLedger, error) {
+	ledger := &FileLedger{path: path, processed: make(map[string]Ingestion)}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ledger, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var ingestion Ingestion
+		if err := json.Unmarshal(scanner.Bytes(), &ingestion); err != nil {
+			return nil, fmt.Errorf("invalid ledger %s on line %d: %w", path, line, err)
+		}
+		if ingestion.Outcome == IngestionProcessed {
+			ledger.processed[ingestion.Checksum] = ingestion
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger %s: %w", path, err)
+	}
+
+	return ledger, nil
+}
+
+// Processed returns the ingestion that processed a file with the checksum, if any
+func (l *FileLedger) Processed(checksum string) (Ingestion, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ingestion, ok := l.processed[checksum]
+	return ingestion, ok, nil
+}
+
+// Record appends the ingestion to the ledger file
+func (l *FileLedger) Record(ingestion Ingestion) error {
+	data, err := json.Marshal(ingestion)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if ingestion.Outcome == IngestionProcessed {
+		l.processed[ingestion.Checksum] = ingestion
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// CSVRejectWriter writes rejected rows as CSV: the line, the reason and the original values
+type CSVRejectWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVRejectWriter creates a reject writer; the header row is written when header is
+// not nil, and should be left out when appending to the rejects of an interrupted run
+func NewCSVRejectWriter(w io.Writer, header []string) (*CSVRejectWriter, error) {
+	writer := csv.NewWriter(w)
+	if header != nil {
+		if err := writer.Write(append([]string{"line", "reason"}, header...)); err != nil {
+			return nil, err
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, err
+		}
+	}
+	return &CSVRejectWriter{writer: writer}, nil
+}
+
+// WriteReject writes and flushes the row, so rejects survive a crash
+func (w *CSVRejectWriter) WriteReject(reject Reject) error {
+	record := append([]string{strconv.Itoa(reject.Line), reject.Reason}, reject.Fields...)
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
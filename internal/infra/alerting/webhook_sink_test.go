@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestWebhookSink_Send(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		expectErr bool
+	}{
+		{name: "delivers the alert", status: http.StatusAccepted},
+		{name: "reports non-2xx responses", status: http.StatusBadGateway, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var received entity.MerchantRatioAlert
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("Unexpected request %s with content type %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+			sink := NewWebhookSink(server.URL)
+
+			// Act
+			err := sink.Send(context.Background(), entity.MerchantRatioAlert{MerchantID: "merchant-789", Month: "2023-01", Threshold: "excessive"})
+
+			// Assert
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if received.MerchantID != "merchant-789" || received.Threshold != "excessive" {
+				t.Errorf("Unexpected alert received %+v", received)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package archive keeps chargebacks purged under the retention policy in cold storage
+// Each call to Archive writes one object: the chargebacks as gzipped newline-delimited
+// JSON, named after the first and last chargeback IDs so retried runs overwrite their
+// own objects instead of duplicating them.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// ErrNothingToArchive is returned when Archive is called without chargebacks
+var ErrNothingToArchive = errors.New("no chargebacks to archive")
+
+// objectName names the object holding the chargebacks
+func objectName(chargebacks []*entity.Chargeback) string {
+	return fmt.Sprintf("chargebacks_%s_%s.ndjson.gz", chargebacks[0].ID, chargebacks[len(chargebacks)-1].ID)
+}
+
+// encode writes the chargebacks as gzipped JSON, one chargeback per line
+func encode(chargebacks []*entity.Chargeback) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(writer)
+	for _, chargeback := range chargebacks {
+		if err := encoder.Encode(chargeback); err != nil {
+			return nil, fmt.Errorf("failed to encode chargeback %s: %w", chargeback.ID, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress chargebacks: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,153 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func testChargebacks() []*entity.Chargeback {
+	created := time.Date(2019, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*entity.Chargeback{
+		{ID: "cb_1", TransactionID: "tx-1", MerchantID: "merchant-1", Amount: 10.5, Status: entity.StatusApproved, CreatedAt: created},
+		{ID: "cb_2", TransactionID: "tx-2", MerchantID: "merchant-1", Amount: 7, Status: entity.StatusRejected, CreatedAt: created, DeletedAt: created.Add(time.Hour)},
+	}
+}
+
+// decode reads gzipped newline-delimited JSON chargebacks
+func decode(t *testing.T, data []byte) []entity.Chargeback {
+	t.Helper()
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a gzip archive, got %v", err)
+	}
+	var chargebacks []entity.Chargeback
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var chargeback entity.Chargeback
+		if err := json.Unmarshal(scanner.Bytes(), &chargeback); err != nil {
+			t.Fatalf("Expected a chargeback per line, got %v", err)
+		}
+		chargebacks = append(chargebacks, chargeback)
+	}
+	return chargebacks
+}
+
+func assertArchived(t *testing.T, data []byte) {
+	t.Helper()
+
+	archived := decode(t, data)
+	if len(archived) != 2 || archived[0].ID != "cb_1" || archived[1].Amount != 7 || !archived[1].IsDeleted() {
+		t.Errorf("Unexpected archived chargebacks %+v", archived)
+	}
+}
+
+func TestFileArchive_Archive(t *testing.T) {
+	// Arrange
+	dir := filepath.Join(t.TempDir(), "archive")
+	archive, err := NewFileArchive(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Act
+	location, err := archive.Archive(context.Background(), testChargebacks())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if location != filepath.Join(dir, "chargebacks_cb_1_cb_2.ndjson.gz") {
+		t.Errorf("Unexpected location %s", location)
+	}
+	data, err := os.ReadFile(location)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	assertArchived(t, data)
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the archive in the directory, got %d entries", len(entries))
+	}
+}
+
+func TestS3Archive_Archive(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		expectErr bool
+	}{
+		{name: "uploads the chargebacks", status: http.StatusOK},
+		{name: "reports failed uploads", status: http.StatusForbidden, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var path string
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := s3.New(s3.Options{
+				Region:       "us-east-1",
+				BaseEndpoint: aws.String(server.URL),
+				UsePathStyle: true,
+				Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+			})
+			archive := NewS3ArchiveWithClient(client, "records", "chargebacks/archive")
+
+			// Act
+			location, err := archive.Archive(context.Background(), testChargebacks())
+
+			// Assert
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if location != "s3://records/chargebacks/archive/chargebacks_cb_1_cb_2.ndjson.gz" {
+				t.Errorf("Unexpected location %s", location)
+			}
+			if path != "/records/chargebacks/archive/chargebacks_cb_1_cb_2.ndjson.gz" {
+				t.Errorf("Unexpected request path %s", path)
+			}
+			assertArchived(t, body)
+		})
+	}
+}
+
+func TestArchive_NothingToArchive(t *testing.T) {
+	archive, err := NewFileArchive(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := archive.Archive(context.Background(), nil); !errors.Is(err, ErrNothingToArchive) {
+		t.Errorf("Expected ErrNothingToArchive, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// FileArchive writes archived chargebacks to files in a local directory, e.g. a mounted
+// network share
+type FileArchive struct {
+	dir string
+}
+
+// NewFileArchive creates an archive writing to the directory, creating it if needed
+func NewFileArchive(dir string) (service.ChargebackArchive, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory %s: %w", dir, err)
+	}
+	return &FileArchive{dir: dir}, nil
+}
+
+// Archive writes the chargebacks to a temporary file and renames it once it is synced,
+// so the directory never holds a partial archive, and returns the file path
+func (a *FileArchive) Archive(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+	if len(chargebacks) == 0 {
+		return "", ErrNothingToArchive
+	}
+	data, err := encode(chargebacks)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(a.dir, ".archive-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to sync archive file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to close archive file: %w", err)
+	}
+
+	path := filepath.Join(a.dir, objectName(chargebacks))
+	if err := os.Rename(file.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to move archive file into place: %w", err)
+	}
+	return path, nil
+}
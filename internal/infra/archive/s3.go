@@ -0,0 +1,88 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// S3Config holds the settings of an S3-compatible bucket
+type S3Config struct {
+	Bucket string
+	// Prefix is prepended to the object keys, e.g. "chargebacks/archive"
+	Prefix string
+	Region string
+	// Endpoint overrides the AWS endpoint for S3-compatible stores such as MinIO
+	Endpoint string
+	// UsePathStyle addresses the bucket in the path rather than the host name, which
+	// most S3-compatible stores need
+	UsePathStyle bool
+}
+
+// PutObjectAPI defines the subset of the S3 client used to archive chargebacks
+type PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Archive uploads archived chargebacks to an S3-compatible bucket
+type S3Archive struct {
+	client PutObjectAPI
+	bucket string
+	prefix string
+}
+
+// NewS3Archive creates an archive uploading to the bucket with the default AWS
+// credential chain
+func NewS3Archive(ctx context.Context, cfg S3Config) (service.ChargebackArchive, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("archive bucket is required")
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+	return NewS3ArchiveWithClient(client, cfg.Bucket, cfg.Prefix), nil
+}
+
+// NewS3ArchiveWithClient creates an archive uploading to the bucket with the client
+func NewS3ArchiveWithClient(client PutObjectAPI, bucket, prefix string) *S3Archive {
+	return &S3Archive{client: client, bucket: bucket, prefix: prefix}
+}
+
+// Archive uploads the chargebacks as one object and returns its s3:// URL
+func (a *S3Archive) Archive(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+	if len(chargebacks) == 0 {
+		return "", ErrNothingToArchive
+	}
+	data, err := encode(chargebacks)
+	if err != nil {
+		return "", err
+	}
+
+	key := path.Join(a.prefix, objectName(chargebacks))
+	_, err = a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload archive %s to bucket %s: %w", key, a.bucket, err)
+	}
+	return fmt.Sprintf("s3://%s/%s", a.bucket, key), nil
+}
@@ -0,0 +1,18 @@
+// Package cache provides the stores behind read-through caches: a bounded in-process LRU
+// and a Redis-protocol server shared by all instances
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache stores values by key for a limited time
+type Cache interface {
+	// Get returns the value stored under key, and false when there is none or it expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value under key until ttl elapses
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the keys, ignoring those that aren't stored
+	Delete(ctx context.Context, keys ...string) error
+}
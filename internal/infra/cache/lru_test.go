@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCache_GetSet(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c := NewLRUCache(10)
+
+	// Act
+	_, foundBefore, _ := c.Get(ctx, "a")
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	value, found, err := c.Get(ctx, "a")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if foundBefore {
+		t.Error("Expected a miss before the value was set")
+	}
+	if !found || string(value) != "1" {
+		t.Errorf("Expected value 1, got %q (found %v)", value, found)
+	}
+}
+
+func TestLRUCache_Expiry(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRUCache(10)
+	c.now = func() time.Time { return now }
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+
+	// Act
+	now = now.Add(59 * time.Second)
+	_, foundBefore, _ := c.Get(ctx, "a")
+	now = now.Add(time.Second)
+	_, foundAfter, _ := c.Get(ctx, "a")
+
+	// Assert
+	if !foundBefore {
+		t.Error("Expected the value before its TTL ran out")
+	}
+	if foundAfter {
+		t.Error("Expected the value to expire")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Expected the expired entry to be dropped, got %d entries", c.Len())
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c := NewLRUCache(2)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+
+	// Act
+	c.Get(ctx, "a")
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	// Assert
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, found, _ := c.Get(ctx, key); found != expected {
+			t.Errorf("Expected %s found=%v, got %v", key, expected, found)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestLRUCache_Delete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c := NewLRUCache(10)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+
+	// Act
+	err := c.Delete(ctx, "a", "b", "missing")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Expected the entries to be deleted, got %d", c.Len())
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig holds the connection settings of a Redis-protocol server
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	// KeyPrefix namespaces the keys, so several services can share a server
+	KeyPrefix string
+}
+
+// RedisCache stores values in a Redis-protocol server (Redis, Valkey, KeyDB...) shared by
+// all instances, so an invalidation made by one is seen by the others
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache connects to the server and checks it answers
+func NewRedisCache(ctx context.Context, cfg RedisConfig) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", cfg.Addr, err)
+	}
+	return &RedisCache{client: client, prefix: cfg.KeyPrefix}, nil
+}
+
+// Get returns the value stored under key, and false when there is none or it expired
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set stores the value under key until ttl elapses
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+// Delete removes the keys
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
+
+// Close closes the connections to the server
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisCache(t *testing.T) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	c, err := NewRedisCache(context.Background(), RedisConfig{Addr: server.Addr(), KeyPrefix: "chargeback-api:"})
+	if err != nil {
+		t.Fatalf("Failed to connect to the test server: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, server
+}
+
+func TestRedisCache_GetSetDelete(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+
+	// Act
+	_, foundBefore, errBefore := c.Get(ctx, "a")
+	setErr := c.Set(ctx, "a", []byte("1"), time.Minute)
+	value, found, err := c.Get(ctx, "a")
+
+	// Assert
+	if errBefore != nil || setErr != nil || err != nil {
+		t.Fatalf("Expected no errors, got %v, %v, %v", errBefore, setErr, err)
+	}
+	if foundBefore {
+		t.Error("Expected a miss before the value was set")
+	}
+	if !found || string(value) != "1" {
+		t.Errorf("Expected value 1, got %q (found %v)", value, found)
+	}
+	if !server.Exists("chargeback-api:a") {
+		t.Error("Expected the key to be prefixed")
+	}
+
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, found, _ := c.Get(ctx, "a"); found {
+		t.Error("Expected the key to be deleted")
+	}
+}
+
+func TestRedisCache_Expiry(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+
+	// Act
+	server.FastForward(time.Minute)
+	_, found, err := c.Get(ctx, "a")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if found {
+		t.Error("Expected the value to expire")
+	}
+}
+
+func TestNewRedisCache_Unreachable(t *testing.T) {
+	// Arrange
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	// Act
+	_, err := NewRedisCache(context.Background(), RedisConfig{Addr: addr})
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error when the server is unreachable")
+	}
+}
+
+func TestRedisCache_ServerDown(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	c, server := newTestRedisCache(t)
+	server.Close()
+
+	// Act
+	_, _, err := c.Get(ctx, "a")
+
+	// Assert
+	if err == nil {
+		t.Error("Expected an error once the server is down")
+	}
+}
@@ -17,6 +17,10 @@ type DynamoDBConfig struct {
 	Endpoint  string
 	Region    string
 	TableName string
+	// EnsureTable creates the table and its missing indexes at startup
+	EnsureTable bool
+	// Layout is the chargeback table layout, LayoutFlat or LayoutSingleTable
+	Layout string
 }
 
 // NewDynamoDBClient creates a new DynamoDB client with proper credential handling
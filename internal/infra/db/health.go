@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DescribeTableAPI defines the subset of the DynamoDB client used for health checks
+type DescribeTableAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+}
+
+// DynamoDBHealthChecker verifies the chargeback table is reachable using DescribeTable
+// Results are cached for CacheTTL so frequent probes don't hit DynamoDB on every request
+type DynamoDBHealthChecker struct {
+	client    DescribeTableAPI
+	tableName string
+	timeout   time.Duration
+	cacheTTL  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	lastErr   error
+}
+
+// NewDynamoDBHealthChecker creates a new DynamoDB health checker
+func NewDynamoDBHealthChecker(client DescribeTableAPI, tableName string, timeout, cacheTTL time.Duration) *DynamoDBHealthChecker {
+	return &DynamoDBHealthChecker{
+		client:    client,
+		tableName: tableName,
+		timeout:   timeout,
+		cacheTTL:  cacheTTL,
+		now:       time.Now,
+	}
+}
+
+// Name returns the dependency name
+func (c *DynamoDBHealthChecker) Name() string {
+	return "dynamodb"
+}
+
+// Check describes the table and reports an error if it is missing or not ACTIVE
+func (c *DynamoDBHealthChecker) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastCheck.IsZero() && c.now().Sub(c.lastCheck) < c.cacheTTL {
+		return c.lastErr
+	}
+
+	c.lastErr = c.describeTable(ctx)
+	c.lastCheck = c.now()
+	return c.lastErr
+}
+
+// describeTable performs the DescribeTable call bounded by the configured timeout
+func (c *DynamoDBHealthChecker) describeTable(ctx context.Context) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	output, err := c.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(c.tableName),
+	})
+	if err != nil {
+		return fmt.Errorf("table '%s' not accessible: %w", c.tableName, err)
+	}
+
+	if output.Table != nil && output.Table.TableStatus != types.TableStatusActive {
+		return fmt.Errorf("table '%s' is not active: %s", c.tableName, output.Table.TableStatus)
+	}
+
+	return nil
+}
+
+// SQLHealthChecker verifies a database/sql connection pool can reach its database
+type SQLHealthChecker struct {
+	name    string
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewSQLHealthChecker creates a health checker reported under name, e.g. "postgres"
+func NewSQLHealthChecker(name string, db *sql.DB, timeout time.Duration) *SQLHealthChecker {
+	return &SQLHealthChecker{
+		name:    name,
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// Name returns the dependency name
+func (c *SQLHealthChecker) Name() string {
+	return c.name
+}
+
+// Check pings the database, bounded by the configured timeout
+func (c *SQLHealthChecker) Check(ctx context.Context) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	if err := c.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database not reachable: %w", err)
+	}
+	return nil
+}
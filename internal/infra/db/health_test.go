@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// MockDescribeTableAPI implements the DescribeTableAPI interface for testing
+type MockDescribeTableAPI struct {
+	DescribeTableFunc func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	calls             int
+}
+
+func (m *MockDescribeTableAPI) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	m.calls++
+	if m.DescribeTableFunc != nil {
+		return m.DescribeTableFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.DescribeTableOutput{
+		Table: &types.TableDescription{TableStatus: types.TableStatusActive},
+	}, nil
+}
+
+func TestDynamoDBHealthChecker_Check(t *testing.T) {
+	t.Run("healthy when table is active", func(t *testing.T) {
+		mockClient := &MockDescribeTableAPI{
+			DescribeTableFunc: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				if *params.TableName != "chargebacks" {
+					t.Errorf("Expected table name 'chargebacks', got %s", *params.TableName)
+				}
+				return &dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{TableStatus: types.TableStatusActive},
+				}, nil
+			},
+		}
+
+		checker := NewDynamoDBHealthChecker(mockClient, "chargebacks", time.Second, 0)
+
+		if checker.Name() != "dynamodb" {
+			t.Errorf("Expected name 'dynamodb', got %s", checker.Name())
+		}
+		if err := checker.Check(context.Background()); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("unhealthy when describe fails", func(t *testing.T) {
+		mockClient := &MockDescribeTableAPI{
+			DescribeTableFunc: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return nil, errors.New("ResourceNotFoundException")
+			},
+		}
+
+		checker := NewDynamoDBHealthChecker(mockClient, "chargebacks", time.Second, 0)
+
+		err := checker.Check(context.Background())
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "not accessible") {
+			t.Errorf("Expected error to contain 'not accessible', got %s", err.Error())
+		}
+	})
+
+	t.Run("unhealthy when table is not active", func(t *testing.T) {
+		mockClient := &MockDescribeTableAPI{
+			DescribeTableFunc: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				return &dynamodb.DescribeTableOutput{
+					Table: &types.TableDescription{TableStatus: types.TableStatusCreating},
+				}, nil
+			},
+		}
+
+		checker := NewDynamoDBHealthChecker(mockClient, "chargebacks", time.Second, 0)
+
+		err := checker.Check(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "not active") {
+			t.Errorf("Expected 'not active' error, got %v", err)
+		}
+	})
+
+	t.Run("applies timeout to describe call", func(t *testing.T) {
+		mockClient := &MockDescribeTableAPI{
+			DescribeTableFunc: func(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+
+		checker := NewDynamoDBHealthChecker(mockClient, "chargebacks", 10*time.Millisecond, 0)
+
+		err := checker.Check(context.Background())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded error, got %v", err)
+		}
+	})
+
+	t.Run("caches result within TTL", func(t *testing.T) {
+		mockClient := &MockDescribeTableAPI{}
+		checker := NewDynamoDBHealthChecker(mockClient, "chargebacks", time.Second, time.Minute)
+
+		now := time.Date(2023, 1, 15, 10, 0, 0, 0, time.UTC)
+		checker.now = func() time.Time { return now }
+
+		checker.Check(context.Background())
+		checker.Check(context.Background())
+		if mockClient.calls != 1 {
+			t.Errorf("Expected 1 DescribeTable call within TTL, got %d", mockClient.calls)
+		}
+
+		now = now.Add(2 * time.Minute)
+		checker.Check(context.Background())
+		if mockClient.calls != 2 {
+			t.Errorf("Expected 2 DescribeTable calls after TTL expired, got %d", mockClient.calls)
+		}
+	})
+}
@@ -0,0 +1,305 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrMigrationsLocked is returned when another process holds the migration lock for
+// longer than the lock timeout
+var ErrMigrationsLocked = errors.New("migrations are locked by another process")
+
+// Migration is one versioned change to a database schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	// Down reverts Up; it is empty when the migration can't be reverted
+	Down string
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	// AppliedAt is zero for pending migrations
+	AppliedAt time.Time
+}
+
+// migrationFilePattern matches files named <version>_<name>.<up|down>.sql, e.g. 0001_create_chargebacks.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// LoadMigrations reads the migrations in a directory, ordered by version
+// Every version needs an up file; the down file is optional.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected <version>_<name>.<up|down>.sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %q and %q", version, migration.Name, match[2])
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrator applies and reverts migrations, recording the applied versions in the
+// schema_migrations table
+// Each migration runs in its own transaction with its version record. Concurrent
+// runs, e.g. several instances starting at once, are serialized by a row in the
+// schema_migrations_lock table; a lock left behind by a crashed run is released
+// with Unlock.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+
+	// lockTimeout is how long to wait for the lock before giving up
+	lockTimeout time.Duration
+	// pollInterval is how often the lock is retried
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+// NewMigrator creates a new migrator for the given migrations
+func NewMigrator(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{
+		db:           db,
+		migrations:   migrations,
+		lockTimeout:  time.Minute,
+		pollInterval: time.Second,
+		now:          time.Now,
+	}
+}
+
+// Up applies the pending migrations in order and returns them
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(appliedVersions map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := appliedVersions[migration.Version]; ok {
+				continue
+			}
+			err := m.inTransaction(ctx, migration.Up,
+				`INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+				migration.Version, migration.Name, m.now().UTC())
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(appliedVersions map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := appliedVersions[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s can't be reverted", migration.Version, migration.Name)
+			}
+			err := m.inTransaction(ctx, migration.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists the known migrations and when they were applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.createTables(ctx); err != nil {
+		return nil, err
+	}
+	appliedVersions, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		statuses = append(statuses, MigrationStatus{Migration: migration, AppliedAt: appliedVersions[migration.Version]})
+	}
+	return statuses, nil
+}
+
+// Unlock releases the migration lock whoever holds it
+func (m *Migrator) Unlock(ctx context.Context) error {
+	if err := m.createTables(ctx); err != nil {
+		return err
+	}
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM schema_migrations_lock`); err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn with the applied versions while holding the migration lock
+func (m *Migrator) withLock(ctx context.Context, fn func(appliedVersions map[int]time.Time) error) error {
+	if err := m.createTables(ctx); err != nil {
+		return err
+	}
+	if err := m.lock(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		// Released even if ctx was canceled, so the next run doesn't wait for the timeout
+		m.db.ExecContext(context.WithoutCancel(ctx), `DELETE FROM schema_migrations_lock WHERE id = 1`)
+	}()
+
+	appliedVersions, err := m.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(appliedVersions)
+}
+
+// createTables creates the bookkeeping tables if they don't exist
+func (m *Migrator) createTables(ctx context.Context) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+			id        INTEGER PRIMARY KEY,
+			locked_by TEXT NOT NULL,
+			locked_at TIMESTAMP NOT NULL
+		)`,
+	}
+	for _, statement := range statements {
+		if _, err := m.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create migration tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// lock takes the single lock row, waiting up to lockTimeout for the holder to release it
+func (m *Migrator) lock(ctx context.Context) error {
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	deadline := m.now().Add(m.lockTimeout)
+
+	for {
+		result, err := m.db.ExecContext(ctx,
+			`INSERT INTO schema_migrations_lock (id, locked_by, locked_at) VALUES (1, $1, $2) ON CONFLICT (id) DO NOTHING`,
+			owner, m.now().UTC())
+		if err != nil {
+			return fmt.Errorf("failed to take migration lock: %w", err)
+		}
+		if rows, err := result.RowsAffected(); err == nil && rows == 1 {
+			return nil
+		}
+
+		if !m.now().Before(deadline) {
+			var lockedBy string
+			var lockedAt time.Time
+			m.db.QueryRowContext(ctx, `SELECT locked_by, locked_at FROM schema_migrations_lock WHERE id = 1`).Scan(&lockedBy, &lockedAt)
+			return fmt.Errorf("%w: held by %s since %s", ErrMigrationsLocked, lockedBy, lockedAt.Format(time.RFC3339))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to take migration lock: %w", ctx.Err())
+		case <-time.After(m.pollInterval):
+		}
+	}
+}
+
+// appliedVersions returns when each applied version was applied
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// inTransaction runs a migration script and the statement recording it atomically
+func (m *Migrator) inTransaction(ctx context.Context, script, record string, args ...interface{}) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
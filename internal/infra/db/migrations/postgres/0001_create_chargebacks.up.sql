@@ -0,0 +1,21 @@
+CREATE TABLE chargebacks (
+    id               TEXT PRIMARY KEY,
+    transaction_id   TEXT NOT NULL,
+    merchant_id      TEXT NOT NULL,
+    amount           NUMERIC(19, 4) NOT NULL,
+    currency         TEXT NOT NULL,
+    card_number      TEXT NOT NULL,
+    reason           TEXT NOT NULL,
+    status           TEXT NOT NULL,
+    description      TEXT NOT NULL DEFAULT '',
+    transaction_date TIMESTAMPTZ NOT NULL,
+    chargeback_date  TIMESTAMPTZ NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL,
+    updated_at       TIMESTAMPTZ NOT NULL,
+    CONSTRAINT chargebacks_transaction_id_key UNIQUE (transaction_id)
+);
+
+-- Listings are ordered by id, so filtered listings page through (filter, id)
+CREATE INDEX chargebacks_merchant_id_idx ON chargebacks (merchant_id, id);
+CREATE INDEX chargebacks_status_idx ON chargebacks (status, id);
+CREATE INDEX chargebacks_created_at_idx ON chargebacks (created_at);
@@ -0,0 +1,13 @@
+-- Merchant IDs and scopes are JSON arrays
+CREATE TABLE api_keys (
+    id           TEXT PRIMARY KEY,
+    name         TEXT NOT NULL,
+    secret_hash  TEXT NOT NULL,
+    merchant_ids TEXT NOT NULL,
+    scopes       TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    rotated_at   TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ
+);
@@ -0,0 +1,13 @@
+-- Months are formatted as YYYY-MM, so they sort lexically
+CREATE TABLE merchant_stats (
+    merchant_id        TEXT NOT NULL,
+    month              TEXT NOT NULL,
+    chargeback_count   INTEGER NOT NULL DEFAULT 0,
+    chargeback_amount  NUMERIC(19, 4) NOT NULL DEFAULT 0,
+    rejected_count     INTEGER NOT NULL DEFAULT 0,
+    rejected_amount    NUMERIC(19, 4) NOT NULL DEFAULT 0,
+    transaction_count  INTEGER NOT NULL DEFAULT 0,
+    transaction_amount NUMERIC(19, 4) NOT NULL DEFAULT 0,
+    updated_at         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (merchant_id, month)
+);
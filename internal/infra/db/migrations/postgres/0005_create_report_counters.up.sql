@@ -0,0 +1,11 @@
+-- Days are formatted as YYYY-MM-DD, so they sort lexically
+CREATE TABLE report_counters (
+    day         TEXT NOT NULL,
+    merchant_id TEXT NOT NULL,
+    reason      TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    currency    TEXT NOT NULL,
+    count       INTEGER NOT NULL,
+    amount      NUMERIC(19, 4) NOT NULL,
+    PRIMARY KEY (day, merchant_id, reason, status, currency)
+);
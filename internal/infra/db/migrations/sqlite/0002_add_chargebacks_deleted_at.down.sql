@@ -0,0 +1 @@
+ALTER TABLE chargebacks DROP COLUMN deleted_at;
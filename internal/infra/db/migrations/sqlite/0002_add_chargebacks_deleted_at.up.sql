@@ -0,0 +1,2 @@
+-- Chargebacks are soft deleted: deleted_at is set instead of removing the row
+ALTER TABLE chargebacks ADD COLUMN deleted_at TIMESTAMP;
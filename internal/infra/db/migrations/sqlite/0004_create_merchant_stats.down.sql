@@ -0,0 +1 @@
+DROP TABLE merchant_stats;
@@ -0,0 +1,410 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Global secondary indexes of the chargeback table
+const (
+	TransactionIDIndex = "transaction-id-index"
+	MerchantIDIndex    = "merchant-id-index"
+	StatusIndex        = "status-index"
+)
+
+// Overloaded global secondary indexes of the single table
+// Each item type decides what its GSIn keys hold, e.g. chargebacks are found by
+// transaction in GSI1, by merchant in GSI2 and by status in GSI3.
+const (
+	GSI1Index = "GSI1"
+	GSI2Index = "GSI2"
+	GSI3Index = "GSI3"
+)
+
+// Layouts of the chargeback table
+const (
+	// LayoutFlat keys the table by chargeback ID, one chargeback per item
+	LayoutFlat = "flat"
+	// LayoutSingleTable keys the table by the PK and SK composite keys, so other item
+	// types can be stored next to chargebacks
+	LayoutSingleTable = "single"
+)
+
+// ErrTableSchemaMismatch is returned when a table exists but doesn't match its schema
+var ErrTableSchemaMismatch = errors.New("table doesn't match its schema")
+
+// tablePollInterval is how often DescribeTable is polled while waiting for a table or
+// index to become ACTIVE
+const tablePollInterval = 2 * time.Second
+
+// TableSchema describes a DynamoDB table whose keys and indexes are string attributes
+type TableSchema struct {
+	Name         string
+	PartitionKey string
+	// SortKey is empty for tables keyed by the partition key alone
+	SortKey string
+	Indexes []IndexSchema
+	// TTLAttribute is the number attribute DynamoDB expires items by, or empty for
+	// tables whose items never expire
+	TTLAttribute string
+}
+
+// IndexSchema describes a global secondary index projecting all attributes
+type IndexSchema struct {
+	Name         string
+	PartitionKey string
+	SortKey      string
+}
+
+// ExpiresAtAttribute is the time to live attribute of chargeback tables, set on
+// chargebacks purged under the retention policy
+const ExpiresAtAttribute = "expires_at"
+
+// ChargebackTableSchema returns the schema of the chargeback table the repository queries
+func ChargebackTableSchema(tableName string) TableSchema {
+	return TableSchema{
+		Name:         tableName,
+		PartitionKey: "id",
+		TTLAttribute: ExpiresAtAttribute,
+		Indexes: []IndexSchema{
+			{Name: TransactionIDIndex, PartitionKey: "transaction_id"},
+			{Name: MerchantIDIndex, PartitionKey: "merchant_id"},
+			{Name: StatusIndex, PartitionKey: "status"},
+		},
+	}
+}
+
+// SingleTableSchema returns the schema of a table in the single-table layout
+func SingleTableSchema(tableName string) TableSchema {
+	return TableSchema{
+		Name:         tableName,
+		PartitionKey: "PK",
+		SortKey:      "SK",
+		TTLAttribute: ExpiresAtAttribute,
+		Indexes: []IndexSchema{
+			{Name: GSI1Index, PartitionKey: "GSI1PK", SortKey: "GSI1SK"},
+			{Name: GSI2Index, PartitionKey: "GSI2PK", SortKey: "GSI2SK"},
+			{Name: GSI3Index, PartitionKey: "GSI3PK", SortKey: "GSI3SK"},
+		},
+	}
+}
+
+// ChargebackTableSchemaForLayout returns the schema of the chargeback table in the given
+// layout, flat unless it is LayoutSingleTable
+func ChargebackTableSchemaForLayout(layout, tableName string) TableSchema {
+	if layout == LayoutSingleTable {
+		return SingleTableSchema(tableName)
+	}
+	return ChargebackTableSchema(tableName)
+}
+
+// TableAPI defines the subset of the DynamoDB client used to provision tables
+type TableAPI interface {
+	DescribeTableAPI
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+	DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error)
+	UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error)
+}
+
+// EnsureTable creates the table, or the indexes it is missing, waits until they are
+// ACTIVE and enables time to live on the TTL attribute of the schema
+// New tables are billed per request. Existing keys and indexes are never changed: when
+// their key schemas differ from the schema, or time to live is enabled on another
+// attribute, ErrTableSchemaMismatch is returned.
+func EnsureTable(ctx context.Context, client TableAPI, schema TableSchema) error {
+	return ensureTable(ctx, client, schema, tablePollInterval)
+}
+
+// VerifyTable checks the table exists with the key schema and ACTIVE indexes of the schema
+func VerifyTable(ctx context.Context, client DescribeTableAPI, schema TableSchema) error {
+	table, err := describeTable(ctx, client, schema.Name)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		return fmt.Errorf("table '%s' does not exist", schema.Name)
+	}
+	if err := verifyKeySchema(schema, table); err != nil {
+		return err
+	}
+
+	var missing, inactive []string
+	for _, index := range schema.Indexes {
+		existing := findIndex(table, index.Name)
+		switch {
+		case existing == nil:
+			missing = append(missing, index.Name)
+		case existing.IndexStatus != types.IndexStatusActive:
+			inactive = append(inactive, fmt.Sprintf("%s (%s)", index.Name, existing.IndexStatus))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table '%s' is missing global secondary indexes: %s", schema.Name, strings.Join(missing, ", "))
+	}
+	if len(inactive) > 0 {
+		return fmt.Errorf("table '%s' has global secondary indexes that are not active: %s", schema.Name, strings.Join(inactive, ", "))
+	}
+	return nil
+}
+
+// ensureTable is EnsureTable polling at the given interval
+func ensureTable(ctx context.Context, client TableAPI, schema TableSchema, pollInterval time.Duration) error {
+	table, err := describeTable(ctx, client, schema.Name)
+	if err != nil {
+		return err
+	}
+
+	if table == nil {
+		if err := createTable(ctx, client, schema); err != nil {
+			return err
+		}
+		if err := waitForTable(ctx, client, schema.Name, pollInterval); err != nil {
+			return err
+		}
+		return ensureTimeToLive(ctx, client, schema)
+	}
+
+	if err := verifyKeySchema(schema, table); err != nil {
+		return err
+	}
+	// DynamoDB creates one index per UpdateTable call, each once the table is ACTIVE
+	for _, index := range schema.Indexes {
+		if findIndex(table, index.Name) != nil {
+			continue
+		}
+		if err := waitForTable(ctx, client, schema.Name, pollInterval); err != nil {
+			return err
+		}
+		definitions := []types.AttributeDefinition{stringAttribute(index.PartitionKey)}
+		if index.SortKey != "" {
+			definitions = append(definitions, stringAttribute(index.SortKey))
+		}
+		_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            aws.String(schema.Name),
+			AttributeDefinitions: definitions,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+				{Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName: aws.String(index.Name),
+					KeySchema: keySchema(index.PartitionKey, index.SortKey),
+					// Provisioned tables need throughput for new indexes; on-demand tables ignore it
+					ProvisionedThroughput: indexThroughput(table),
+					Projection:            &types.Projection{ProjectionType: types.ProjectionTypeAll},
+				}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index '%s' on table '%s': %w", index.Name, schema.Name, err)
+		}
+	}
+	if err := waitForTable(ctx, client, schema.Name, pollInterval); err != nil {
+		return err
+	}
+	return ensureTimeToLive(ctx, client, schema)
+}
+
+// ensureTimeToLive enables time to live on the TTL attribute of the schema unless it
+// is already enabled or being enabled
+func ensureTimeToLive(ctx context.Context, client TableAPI, schema TableSchema) error {
+	if schema.TTLAttribute == "" {
+		return nil
+	}
+
+	output, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(schema.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe time to live of table '%s': %w", schema.Name, err)
+	}
+	if description := output.TimeToLiveDescription; description != nil {
+		switch description.TimeToLiveStatus {
+		case types.TimeToLiveStatusEnabled, types.TimeToLiveStatusEnabling:
+			if attribute := aws.ToString(description.AttributeName); attribute != schema.TTLAttribute {
+				return fmt.Errorf("%w: table '%s' expires items by %q, expected %q",
+					ErrTableSchemaMismatch, schema.Name, attribute, schema.TTLAttribute)
+			}
+			return nil
+		}
+	}
+
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(schema.Name),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(schema.TTLAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable time to live on table '%s': %w", schema.Name, err)
+	}
+	return nil
+}
+
+// createTable creates the table with all its indexes
+func createTable(ctx context.Context, client TableAPI, schema TableSchema) error {
+	attributes := map[string]bool{}
+	var definitions []types.AttributeDefinition
+	define := func(names ...string) {
+		for _, name := range names {
+			if name != "" && !attributes[name] {
+				attributes[name] = true
+				definitions = append(definitions, stringAttribute(name))
+			}
+		}
+	}
+
+	define(schema.PartitionKey, schema.SortKey)
+	indexes := make([]types.GlobalSecondaryIndex, 0, len(schema.Indexes))
+	for _, index := range schema.Indexes {
+		define(index.PartitionKey, index.SortKey)
+		indexes = append(indexes, types.GlobalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  keySchema(index.PartitionKey, index.SortKey),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
+	input := &dynamodb.CreateTableInput{
+		TableName:            aws.String(schema.Name),
+		AttributeDefinitions: definitions,
+		KeySchema:            keySchema(schema.PartitionKey, schema.SortKey),
+		BillingMode:          types.BillingModePayPerRequest,
+	}
+	if len(indexes) > 0 {
+		input.GlobalSecondaryIndexes = indexes
+	}
+
+	if _, err := client.CreateTable(ctx, input); err != nil {
+		var inUse *types.ResourceInUseException
+		if errors.As(err, &inUse) {
+			// Another instance is creating it
+			return nil
+		}
+		return fmt.Errorf("failed to create table '%s': %w", schema.Name, err)
+	}
+	return nil
+}
+
+// waitForTable polls until the table and all its indexes are ACTIVE
+func waitForTable(ctx context.Context, client DescribeTableAPI, tableName string, pollInterval time.Duration) error {
+	for {
+		table, err := describeTable(ctx, client, tableName)
+		if err != nil {
+			return err
+		}
+		if table != nil && table.TableStatus == types.TableStatusActive && indexesActive(table) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("table '%s' did not become active: %w", tableName, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// describeTable returns the table description, or nil if the table doesn't exist
+func describeTable(ctx context.Context, client DescribeTableAPI, tableName string) (*types.TableDescription, error) {
+	output, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("table '%s' not accessible: %w", tableName, err)
+	}
+	return output.Table, nil
+}
+
+// verifyKeySchema compares the key schemas of the table and its existing indexes with the schema
+func verifyKeySchema(schema TableSchema, table *types.TableDescription) error {
+	want := describeKeySchema(keySchema(schema.PartitionKey, schema.SortKey))
+	if got := describeKeySchema(table.KeySchema); got != want {
+		return fmt.Errorf("%w: table '%s' has key schema %q, expected %q", ErrTableSchemaMismatch, schema.Name, got, want)
+	}
+	for _, index := range schema.Indexes {
+		existing := findIndex(table, index.Name)
+		if existing == nil {
+			continue
+		}
+		want := describeKeySchema(keySchema(index.PartitionKey, index.SortKey))
+		if got := describeKeySchema(existing.KeySchema); got != want {
+			return fmt.Errorf("%w: index '%s' of table '%s' has key schema %q, expected %q",
+				ErrTableSchemaMismatch, index.Name, schema.Name, got, want)
+		}
+	}
+	return nil
+}
+
+// describeKeySchema renders a key schema as "attribute TYPE" pairs, hash key first
+func describeKeySchema(keys []types.KeySchemaElement) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, aws.ToString(key.AttributeName)+" "+string(key.KeyType))
+	}
+	// HASH sorts before RANGE
+	sort.Slice(parts, func(i, j int) bool {
+		return strings.HasSuffix(parts[i], " HASH") && !strings.HasSuffix(parts[j], " HASH")
+	})
+	return strings.Join(parts, ", ")
+}
+
+// findIndex returns the global secondary index with the given name, or nil
+func findIndex(table *types.TableDescription, name string) *types.GlobalSecondaryIndexDescription {
+	for i := range table.GlobalSecondaryIndexes {
+		if aws.ToString(table.GlobalSecondaryIndexes[i].IndexName) == name {
+			return &table.GlobalSecondaryIndexes[i]
+		}
+	}
+	return nil
+}
+
+// indexesActive reports whether every global secondary index is ACTIVE
+func indexesActive(table *types.TableDescription) bool {
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive {
+			return false
+		}
+	}
+	return true
+}
+
+// indexThroughput returns the throughput of a new index on a provisioned table, or nil
+// for an on-demand table
+func indexThroughput(table *types.TableDescription) *types.ProvisionedThroughput {
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode == types.BillingModePayPerRequest {
+		return nil
+	}
+	if table.ProvisionedThroughput == nil || aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits) == 0 {
+		return nil
+	}
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  table.ProvisionedThroughput.ReadCapacityUnits,
+		WriteCapacityUnits: table.ProvisionedThroughput.WriteCapacityUnits,
+	}
+}
+
+// stringAttribute defines a string attribute
+func stringAttribute(name string) types.AttributeDefinition {
+	return types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: types.ScalarAttributeTypeS}
+}
+
+// keySchema returns a key schema with a partition key and, unless it is empty, a sort key
+func keySchema(partitionKey, sortKey string) []types.KeySchemaElement {
+	keys := []types.KeySchemaElement{{AttributeName: aws.String(partitionKey), KeyType: types.KeyTypeHash}}
+	if sortKey != "" {
+		keys = append(keys, types.KeySchemaElement{AttributeName: aws.String(sortKey), KeyType: types.KeyTypeRange})
+	}
+	return keys
+}
@@ -0,0 +1,285 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeTableAPI is a single table that becomes ACTIVE after being described activateAfter times
+type fakeTableAPI struct {
+	table         *types.TableDescription
+	activateAfter int
+	describes     int
+	creates       int
+	updates       []string
+	ttl           *types.TimeToLiveDescription
+	ttlUpdates    int
+}
+
+func (f *fakeTableAPI) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if f.table == nil {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("Requested resource not found")}
+	}
+	f.describes++
+	if f.describes > f.activateAfter {
+		f.table.TableStatus = types.TableStatusActive
+		for i := range f.table.GlobalSecondaryIndexes {
+			f.table.GlobalSecondaryIndexes[i].IndexStatus = types.IndexStatusActive
+		}
+	}
+	table := *f.table
+	table.GlobalSecondaryIndexes = append([]types.GlobalSecondaryIndexDescription(nil), f.table.GlobalSecondaryIndexes...)
+	return &dynamodb.DescribeTableOutput{Table: &table}, nil
+}
+
+func (f *fakeTableAPI) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	f.creates++
+	f.describes = 0
+	f.table = &types.TableDescription{
+		TableName:   params.TableName,
+		TableStatus: types.TableStatusCreating,
+		KeySchema:   params.KeySchema,
+	}
+	for _, index := range params.GlobalSecondaryIndexes {
+		f.table.GlobalSecondaryIndexes = append(f.table.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+			IndexName:   index.IndexName,
+			KeySchema:   index.KeySchema,
+			IndexStatus: types.IndexStatusCreating,
+		})
+	}
+	return &dynamodb.CreateTableOutput{}, nil
+}
+
+func (f *fakeTableAPI) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	if len(params.GlobalSecondaryIndexUpdates) != 1 {
+		return nil, errors.New("only one index can be created per update")
+	}
+	if !indexesActive(f.table) {
+		return nil, errors.New("table is being updated")
+	}
+	create := params.GlobalSecondaryIndexUpdates[0].Create
+	f.updates = append(f.updates, aws.ToString(create.IndexName))
+	f.describes = 0
+	f.table.GlobalSecondaryIndexes = append(f.table.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+		IndexName:   create.IndexName,
+		KeySchema:   create.KeySchema,
+		IndexStatus: types.IndexStatusCreating,
+	})
+	return &dynamodb.UpdateTableOutput{}, nil
+}
+
+func (f *fakeTableAPI) DescribeTimeToLive(ctx context.Context, params *dynamodb.DescribeTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTimeToLiveOutput, error) {
+	description := &types.TimeToLiveDescription{TimeToLiveStatus: types.TimeToLiveStatusDisabled}
+	if f.ttl != nil {
+		description = f.ttl
+	}
+	return &dynamodb.DescribeTimeToLiveOutput{TimeToLiveDescription: description}, nil
+}
+
+func (f *fakeTableAPI) UpdateTimeToLive(ctx context.Context, params *dynamodb.UpdateTimeToLiveInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTimeToLiveOutput, error) {
+	f.ttlUpdates++
+	f.ttl = &types.TimeToLiveDescription{
+		AttributeName:    params.TimeToLiveSpecification.AttributeName,
+		TimeToLiveStatus: types.TimeToLiveStatusEnabling,
+	}
+	return &dynamodb.UpdateTimeToLiveOutput{}, nil
+}
+
+// activeTable returns an ACTIVE table keyed by id with the given indexes
+func activeTable(indexes ...IndexSchema) *types.TableDescription {
+	table := &types.TableDescription{
+		TableName:   aws.String("chargebacks"),
+		TableStatus: types.TableStatusActive,
+		KeySchema:   keySchema("id", ""),
+	}
+	for _, index := range indexes {
+		table.GlobalSecondaryIndexes = append(table.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+			IndexName:   aws.String(index.Name),
+			KeySchema:   keySchema(index.PartitionKey, index.SortKey),
+			IndexStatus: types.IndexStatusActive,
+		})
+	}
+	return table
+}
+
+func TestEnsureTable(t *testing.T) {
+	schema := ChargebackTableSchema("chargebacks")
+	ctx := context.Background()
+
+	t.Run("creates a missing table with its indexes", func(t *testing.T) {
+		// Arrange
+		client := &fakeTableAPI{activateAfter: 2}
+
+		// Act
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if client.creates != 1 {
+			t.Errorf("Expected table to be created once, got %d", client.creates)
+		}
+		if client.ttl == nil || aws.ToString(client.ttl.AttributeName) != ExpiresAtAttribute {
+			t.Errorf("Expected time to live to be enabled on %s, got %+v", ExpiresAtAttribute, client.ttl)
+		}
+		if err := VerifyTable(ctx, client, schema); err != nil {
+			t.Errorf("Expected table to match its schema, got %v", err)
+		}
+	})
+
+	t.Run("adds missing indexes one at a time", func(t *testing.T) {
+		client := &fakeTableAPI{table: activeTable(schema.Indexes[1]), activateAfter: 1}
+
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if strings.Join(client.updates, ",") != TransactionIDIndex+","+StatusIndex {
+			t.Errorf("Expected missing indexes to be created, got %v", client.updates)
+		}
+		if err := VerifyTable(ctx, client, schema); err != nil {
+			t.Errorf("Expected table to match its schema, got %v", err)
+		}
+	})
+
+	t.Run("leaves a complete table alone", func(t *testing.T) {
+		client := &fakeTableAPI{
+			table: activeTable(schema.Indexes...),
+			ttl:   &types.TimeToLiveDescription{AttributeName: aws.String(ExpiresAtAttribute), TimeToLiveStatus: types.TimeToLiveStatusEnabled},
+		}
+
+		if err := ensureTable(ctx, client, schema, time.Millisecond); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if client.creates != 0 || len(client.updates) != 0 || client.ttlUpdates != 0 {
+			t.Errorf("Expected no changes, got %d creates, %v updates and %d time to live updates",
+				client.creates, client.updates, client.ttlUpdates)
+		}
+	})
+
+	t.Run("rejects time to live on another attribute", func(t *testing.T) {
+		client := &fakeTableAPI{
+			table: activeTable(schema.Indexes...),
+			ttl:   &types.TimeToLiveDescription{AttributeName: aws.String("ttl"), TimeToLiveStatus: types.TimeToLiveStatusEnabled},
+		}
+
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		if !errors.Is(err, ErrTableSchemaMismatch) || client.ttlUpdates != 0 {
+			t.Errorf("Expected ErrTableSchemaMismatch without changes, got %v and %d updates", err, client.ttlUpdates)
+		}
+	})
+
+	t.Run("rejects a different key schema", func(t *testing.T) {
+		table := activeTable(schema.Indexes...)
+		table.KeySchema = keySchema("chargeback_id", "")
+		client := &fakeTableAPI{table: table}
+
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		if !errors.Is(err, ErrTableSchemaMismatch) {
+			t.Errorf("Expected ErrTableSchemaMismatch, got %v", err)
+		}
+	})
+
+	t.Run("rejects an index with a different key schema", func(t *testing.T) {
+		client := &fakeTableAPI{table: activeTable(IndexSchema{Name: StatusIndex, PartitionKey: "state"})}
+
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		if !errors.Is(err, ErrTableSchemaMismatch) || !strings.Contains(err.Error(), StatusIndex) {
+			t.Errorf("Expected ErrTableSchemaMismatch naming %s, got %v", StatusIndex, err)
+		}
+		if len(client.updates) != 0 {
+			t.Errorf("Expected no changes, got %v", client.updates)
+		}
+	})
+
+	t.Run("creates a single table with composite keys", func(t *testing.T) {
+		client := &fakeTableAPI{activateAfter: 1}
+		single := SingleTableSchema("chargebacks")
+
+		if err := ensureTable(ctx, client, single, time.Millisecond); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got := describeKeySchema(client.table.KeySchema); got != "PK HASH, SK RANGE" {
+			t.Errorf("Expected PK and SK keys, got %q", got)
+		}
+		if got := describeKeySchema(client.table.GlobalSecondaryIndexes[1].KeySchema); got != "GSI2PK HASH, GSI2SK RANGE" {
+			t.Errorf("Expected GSI2PK and GSI2SK keys, got %q", got)
+		}
+		if err := VerifyTable(ctx, client, single); err != nil {
+			t.Errorf("Expected table to match its schema, got %v", err)
+		}
+	})
+
+	t.Run("rejects a flat table for the single-table layout", func(t *testing.T) {
+		client := &fakeTableAPI{table: activeTable(schema.Indexes...)}
+
+		err := ensureTable(ctx, client, ChargebackTableSchemaForLayout(LayoutSingleTable, "chargebacks"), time.Millisecond)
+
+		if !errors.Is(err, ErrTableSchemaMismatch) {
+			t.Errorf("Expected ErrTableSchemaMismatch, got %v", err)
+		}
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		client := &fakeTableAPI{activateAfter: 1000}
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+
+		err := ensureTable(ctx, client, schema, time.Millisecond)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	})
+}
+
+func TestVerifyTable(t *testing.T) {
+	schema := ChargebackTableSchema("chargebacks")
+	creating := activeTable(schema.Indexes...)
+	creating.GlobalSecondaryIndexes[2].IndexStatus = types.IndexStatusCreating
+
+	tests := []struct {
+		name    string
+		table   *types.TableDescription
+		wantErr string
+	}{
+		{name: "complete table", table: activeTable(schema.Indexes...)},
+		{name: "missing table", table: nil, wantErr: "table 'chargebacks' does not exist"},
+		{
+			name:    "missing indexes",
+			table:   activeTable(schema.Indexes[1]),
+			wantErr: "table 'chargebacks' is missing global secondary indexes: transaction-id-index, status-index",
+		},
+		{name: "index being created", table: creating, wantErr: "not active: status-index (CREATING)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := VerifyTable(context.Background(), &fakeTableAPI{table: tt.table, activateAfter: 1}, schema)
+
+			// Assert
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"net/url"
+
+	// Registers the pure-Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteConfig holds the configuration for SQLite
+type SQLiteConfig struct {
+	// Path is the database file, created if it doesn't exist
+	Path string
+}
+
+//go:embed migrations/sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// SQLiteMigrations returns the migrations of the SQLite schema, oldest first
+func SQLiteMigrations() ([]Migration, error) {
+	return LoadMigrations(sqliteMigrations, "migrations/sqlite")
+}
+
+// NewSQLiteDB opens a SQLite database file in WAL mode
+// WAL lets readers work alongside the single writer; writers wait for each other for up
+// to the busy timeout instead of failing. Transactions take the write lock when they
+// begin, so two transactions never deadlock upgrading their locks.
+func NewSQLiteDB(ctx context.Context, cfg SQLiteConfig) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(ON)")
+	params.Set("_time_format", "sqlite")
+	params.Set("_txlock", "immediate")
+
+	sqlDB, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", cfg.Path, err)
+	}
+	return sqlDB, nil
+}
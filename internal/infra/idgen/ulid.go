@@ -0,0 +1,102 @@
+// Package idgen generates chargeback IDs
+package idgen
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// crockford is the base32 alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULIDGenerator generates chargeback IDs holding a ULID: a 48-bit millisecond timestamp
+// followed by 80 bits of crypto-random entropy, so IDs generated on different instances
+// don't collide and sort by creation time to the millisecond.
+// IDs generated within the same millisecond reuse the entropy plus one, so they sort in
+// generation order too.
+type ULIDGenerator struct {
+	entropy io.Reader
+	now     func() time.Time
+
+	mu       sync.Mutex
+	lastTime uint64
+	lastHigh uint16 // top 16 bits of the last entropy
+	lastLow  uint64 // bottom 64 bits of the last entropy
+}
+
+// NewULIDGenerator creates a generator drawing entropy from crypto/rand
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{entropy: rand.Reader, now: time.Now}
+}
+
+// NewChargebackID returns a new chargeback ID, greater than the ones returned before
+func (g *ULIDGenerator) NewChargebackID() entity.ChargebackID {
+	return entity.ChargebackID(entity.ChargebackIDPrefix + g.NewULID())
+}
+
+// NewULID returns a new ULID, greater than the ones returned before
+// When the clock goes backwards, the last timestamp is kept so the order holds.
+func (g *ULIDGenerator) NewULID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms > g.lastTime {
+		g.lastTime = ms
+		g.lastHigh, g.lastLow = g.random()
+	} else {
+		g.increment()
+	}
+	return encode(g.lastTime, g.lastHigh, g.lastLow)
+}
+
+// random draws 80 bits of entropy
+func (g *ULIDGenerator) random() (uint16, uint64) {
+	var b [10]byte
+	if _, err := io.ReadFull(g.entropy, b[:]); err != nil {
+		// crypto/rand doesn't fail on supported platforms
+		panic("idgen: failed to read entropy: " + err.Error())
+	}
+	high := uint16(b[0])<<8 | uint16(b[1])
+	var low uint64
+	for _, c := range b[2:] {
+		low = low<<8 | uint64(c)
+	}
+	return high, low
+}
+
+// increment adds one to the last entropy; when all 80 bits overflow, which takes 2^80
+// IDs in a millisecond, the timestamp moves to the next millisecond instead
+func (g *ULIDGenerator) increment() {
+	g.lastLow++
+	if g.lastLow != 0 {
+		return
+	}
+	g.lastHigh++
+	if g.lastHigh != 0 {
+		return
+	}
+	g.lastTime++
+	g.lastHigh, g.lastLow = g.random()
+}
+
+// encode writes the timestamp and entropy as 26 characters of Crockford's base32
+func encode(ms uint64, high uint16, low uint64) string {
+	var out [26]byte
+	// 10 characters hold the 48-bit timestamp, with 2 leading zero bits
+	for i := 9; i >= 0; i-- {
+		out[i] = crockford[ms&31]
+		ms >>= 5
+	}
+	// 16 characters hold the 80 bits of entropy
+	for i := 25; i >= 10; i-- {
+		out[i] = crockford[low&31]
+		low = low>>5 | uint64(high&31)<<59
+		high >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,102 @@
+package idgen
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// newTestGenerator returns a generator with a fixed clock and all-zero entropy
+func newTestGenerator(now *time.Time) *ULIDGenerator {
+	g := NewULIDGenerator()
+	g.entropy = bytes.NewReader(make([]byte, 1000))
+	g.now = func() time.Time { return *now }
+	return g
+}
+
+func TestULIDGenerator_Encoding(t *testing.T) {
+	// Arrange
+	now := time.UnixMilli(1469918176385)
+	g := newTestGenerator(&now)
+
+	// Act
+	ulid := g.NewULID()
+
+	// Assert
+	if ulid != "01ARYZ6S41"+strings.Repeat("0", 16) {
+		t.Errorf("Expected the timestamp of the ULID spec and zero entropy, got %s", ulid)
+	}
+}
+
+func TestULIDGenerator_MonotonicWithinMillisecond(t *testing.T) {
+	// Arrange
+	now := time.UnixMilli(1469918176385)
+	g := newTestGenerator(&now)
+
+	// Act
+	first := g.NewULID()
+	second := g.NewULID()
+	now = now.Add(-time.Second)
+	afterClockSkew := g.NewULID()
+
+	// Assert
+	if second != "01ARYZ6S41"+strings.Repeat("0", 15)+"1" {
+		t.Errorf("Expected the entropy to be incremented, got %s", second)
+	}
+	if !(first < second && second < afterClockSkew) {
+		t.Errorf("Expected increasing IDs, got %s, %s, %s", first, second, afterClockSkew)
+	}
+}
+
+func TestULIDGenerator_EntropyOverflow(t *testing.T) {
+	// Arrange
+	now := time.UnixMilli(1469918176385)
+	g := newTestGenerator(&now)
+	first := g.NewULID()
+	g.lastHigh, g.lastLow = 1<<16-1, 1<<64-1
+
+	// Act
+	next := g.NewULID()
+
+	// Assert
+	if next <= first || next[:10] != "01ARYZ6S42" {
+		t.Errorf("Expected the timestamp to move to the next millisecond, got %s after %s", next, first)
+	}
+}
+
+func TestULIDGenerator_ChargebackIDs(t *testing.T) {
+	// Arrange
+	g := NewULIDGenerator()
+	const workers, perWorker = 8, 500
+	ids := make(chan entity.ChargebackID, workers*perWorker)
+
+	// Act
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				ids <- g.NewChargebackID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	// Assert
+	seen := make(map[entity.ChargebackID]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("Expected unique IDs, got %s twice", id)
+		}
+		seen[id] = true
+		if parsed, err := entity.ParseChargebackID(id.String()); err != nil || parsed != id {
+			t.Fatalf("Expected %s to be a valid chargeback ID, got %s, %v", id, parsed, err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrKeyNotFound is returned when no key in the set matches the token key ID
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// jsonWebKey represents a single key of a JSON Web Key Set (RFC 7517)
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet loads signing keys from a JWKS file or URL and caches them
+// Keys are reloaded after RefreshInterval, and on demand when a token references an
+// unknown key ID so that key rotation at the identity provider is picked up quickly.
+// When a reload fails the cached keys keep being served, and reloads are retried with
+// exponential backoff, so an identity provider outage doesn't fail every request.
+type KeySet struct {
+	source             string
+	httpClient         *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	retryBackoff       time.Duration
+	maxRetryBackoff    time.Duration
+	now                func() time.Time
+
+	// loads makes concurrent callers share a single load, done without holding mu
+	loads singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	failures  int
+	retryAt   time.Time
+	loadErr   error
+}
+
+// NewKeySet creates a key set reading from a file path or an http(s) URL
+func NewKeySet(source string, refreshInterval time.Duration) *KeySet {
+	return &KeySet{
+		source:             source,
+		httpClient:         &http.Client{Timeout: 10 * time.Second},
+		refreshInterval:    refreshInterval,
+		minRefreshInterval: 30 * time.Second,
+		retryBackoff:       time.Second,
+		maxRetryBackoff:    5 * time.Minute,
+		now:                time.Now,
+	}
+}
+
+// Key returns the public key for the key ID, refreshing the set when needed
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	keys, fetchedAt := s.cached()
+	if keys == nil || (s.refreshInterval > 0 && s.now().Sub(fetchedAt) >= s.refreshInterval) {
+		// Stale keys are better than none: only fail when nothing was ever loaded
+		if err := s.refresh(ctx); err != nil && keys == nil {
+			return nil, err
+		}
+		keys, fetchedAt = s.cached()
+	}
+
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	// Unknown key ID: the provider may have rotated keys, but don't let
+	// tokens with arbitrary key IDs trigger a fetch on every request
+	if s.now().Sub(fetchedAt) >= s.minRefreshInterval {
+		// Keys are loaded, so a failed refresh leaves the key unknown rather than the
+		// key set unavailable
+		if err := s.refresh(ctx); err != nil {
+			return nil, fmt.Errorf("%w: kid '%s' (refreshing the key set failed: %v)", ErrKeyNotFound, kid, err)
+		}
+		keys, _ = s.cached()
+		if key, ok := keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: kid '%s'", ErrKeyNotFound, kid)
+}
+
+// cached returns the loaded keys and when they were loaded
+func (s *KeySet) cached() (map[string]crypto.PublicKey, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys, s.fetchedAt
+}
+
+// refresh reloads the key set from its source, unless a failed load is still backing
+// off, in which case its error is returned
+func (s *KeySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if s.now().Before(s.retryAt) {
+		err := s.loadErr
+		s.mu.Unlock()
+		return err
+	}
+	s.mu.Unlock()
+
+	// The load outlives a caller that gives up, since other callers may share it
+	loadCtx := context.WithoutCancel(ctx)
+	result := s.loads.DoChan("jwks", func() (interface{}, error) {
+		return nil, s.reload(loadCtx)
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-result:
+		return res.Err
+	}
+}
+
+// reload loads and parses the key set, recording the outcome
+func (s *KeySet) reload(ctx context.Context) error {
+	keys, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failures++
+		backoff := s.retryBackoff << min(s.failures-1, 16)
+		s.retryAt = s.now().Add(min(backoff, s.maxRetryBackoff))
+		s.loadErr = err
+		return err
+	}
+
+	s.keys = keys
+	s.fetchedAt = s.now()
+	s.failures = 0
+	s.retryAt = time.Time{}
+	s.loadErr = nil
+	return nil
+}
+
+// fetch loads and parses the key set from its source
+func (s *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS from %s: %w", s.source, err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS from %s: %w", s.source, err)
+	}
+	return keys, nil
+}
+
+// load reads the raw JWKS document
+func (s *KeySet) load(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parseJWKS decodes the RSA and P-256 EC signing keys of a JWKS document
+// Keys of other types or curves, or intended for encryption, are skipped
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch jwk.Kty {
+		case "RSA":
+			key, err = parseRSAKey(jwk)
+		case "EC":
+			if jwk.Crv != "P-256" {
+				continue
+			}
+			key, err = parseECKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key '%s': %w", jwk.Kid, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// parseRSAKey builds an RSA public key from its modulus and exponent
+func parseRSAKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// parseECKey builds a P-256 public key from its coordinates
+func parseECKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	if jwk.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x coordinate: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid y coordinate: %w", err)
+	}
+
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid coordinate length")
+	}
+
+	// Uncompressed SEC 1 point encoding: 0x04 || X || Y
+	point := append(append([]byte{4}, x...), y...)
+	return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+}
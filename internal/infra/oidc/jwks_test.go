@@ -0,0 +1,348 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// generateRSAKey creates a throwaway RSA key for signing test tokens
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	return key
+}
+
+// generateECKey creates a throwaway P-256 key for signing test tokens
+func generateECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate EC key: %v", err)
+	}
+	return key
+}
+
+// rsaJWK encodes the public half of an RSA key as a JWK
+func rsaJWK(kid string, key *rsa.PrivateKey) jsonWebKey {
+	return jsonWebKey{
+		Kid: kid,
+		Kty: "RSA",
+		Alg: "RS256",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// ecJWK encodes the public half of a P-256 key as a JWK
+func ecJWK(kid string, key *ecdsa.PrivateKey) jsonWebKey {
+	point, _ := key.PublicKey.Bytes()
+	return jsonWebKey{
+		Kid: kid,
+		Kty: "EC",
+		Alg: "ES256",
+		Use: "sig",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+	}
+}
+
+// encodeJWKS builds a JWKS document from the given keys
+func encodeJWKS(t *testing.T, keys ...jsonWebKey) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	if err != nil {
+		t.Fatalf("Failed to marshal JWKS: %v", err)
+	}
+	return data
+}
+
+func TestKeySet_Key_FromFile(t *testing.T) {
+	// Arrange
+	rsaKey := generateRSAKey(t)
+	ecKey := generateECKey(t)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, encodeJWKS(t, rsaJWK("rsa-1", rsaKey), ecJWK("ec-1", ecKey)), 0o600); err != nil {
+		t.Fatalf("Failed to write JWKS: %v", err)
+	}
+
+	keySet := NewKeySet(path, time.Hour)
+
+	tests := []struct {
+		name        string
+		kid         string
+		expectedErr error
+	}{
+		{name: "finds RSA key", kid: "rsa-1"},
+		{name: "finds EC key", kid: "ec-1"},
+		{name: "reports unknown key ID", kid: "missing", expectedErr: ErrKeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			key, err := keySet.Key(context.Background(), tt.kid)
+
+			// Assert
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if key == nil {
+				t.Error("Expected key, got nil")
+			}
+		})
+	}
+}
+
+func TestKeySet_Key_PicksUpRotatedKeys(t *testing.T) {
+	// Arrange
+	oldKey := generateRSAKey(t)
+	newKey := generateRSAKey(t)
+
+	var document atomic.Value
+	document.Store(encodeJWKS(t, rsaJWK("old", oldKey)))
+
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document.Load().([]byte))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keySet := NewKeySet(server.URL, time.Hour)
+	keySet.now = func() time.Time { return now }
+
+	if _, err := keySet.Key(context.Background(), "old"); err != nil {
+		t.Fatalf("Expected old key, got %v", err)
+	}
+
+	// Act: the provider rotates keys
+	document.Store(encodeJWKS(t, rsaJWK("new", newKey)))
+
+	_, errBeforeCooldown := keySet.Key(context.Background(), "new")
+	now = now.Add(time.Minute)
+	key, errAfterCooldown := keySet.Key(context.Background(), "new")
+
+	// Assert
+	if !errors.Is(errBeforeCooldown, ErrKeyNotFound) {
+		t.Errorf("Expected refetch to be rate limited, got %v", errBeforeCooldown)
+	}
+	if errAfterCooldown != nil || key == nil {
+		t.Fatalf("Expected rotated key to be found, got %v", errAfterCooldown)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("Expected 2 fetches, got %d", fetches.Load())
+	}
+}
+
+func TestKeySet_Key_RefreshesAfterInterval(t *testing.T) {
+	// Arrange
+	var fetches atomic.Int32
+	jwks := encodeJWKS(t, rsaJWK("rsa-1", generateRSAKey(t)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keySet := NewKeySet(server.URL, time.Hour)
+	keySet.now = func() time.Time { return now }
+
+	// Act
+	keySet.Key(context.Background(), "rsa-1")
+	keySet.Key(context.Background(), "rsa-1")
+	now = now.Add(2 * time.Hour)
+	keySet.Key(context.Background(), "rsa-1")
+
+	// Assert
+	if fetches.Load() != 2 {
+		t.Errorf("Expected cached keys to be reused until the interval elapses, got %d fetches", fetches.Load())
+	}
+}
+
+func TestKeySet_Key_SourceUnavailable(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	keySet := NewKeySet(server.URL, time.Hour)
+
+	// Act
+	_, err := keySet.Key(context.Background(), "rsa-1")
+
+	// Assert
+	if err == nil || errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Expected load error, got %v", err)
+	}
+}
+
+func TestKeySet_Key_ServesStaleKeysWhileSourceIsDown(t *testing.T) {
+	// Arrange
+	var down atomic.Bool
+	var fetches atomic.Int32
+	jwks := encodeJWKS(t, rsaJWK("rsa-1", generateRSAKey(t)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keySet := NewKeySet(server.URL, time.Hour)
+	keySet.now = func() time.Time { return now }
+	if _, err := keySet.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("Expected key, got %v", err)
+	}
+
+	// Act: the provider goes down once the keys are due for a refresh
+	down.Store(true)
+	now = now.Add(2 * time.Hour)
+	var errs []error
+	for range 5 {
+		_, err := keySet.Key(context.Background(), "rsa-1")
+		errs = append(errs, err)
+	}
+	fetchesWhileDown := fetches.Load()
+	down.Store(false)
+	now = now.Add(keySet.retryBackoff)
+	keySet.Key(context.Background(), "rsa-1")
+
+	// Assert
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Request %d: expected the stale key to be served, got %v", i, err)
+		}
+	}
+	if fetchesWhileDown != 2 {
+		t.Errorf("Expected a single failed fetch before backing off, got %d fetches", fetchesWhileDown)
+	}
+	if fetches.Load() != 3 {
+		t.Errorf("Expected the refresh to be retried after the backoff, got %d fetches", fetches.Load())
+	}
+}
+
+func TestKeySet_Key_SharesConcurrentFetches(t *testing.T) {
+	// Arrange
+	var fetches atomic.Int32
+	jwks := encodeJWKS(t, rsaJWK("rsa-1", generateRSAKey(t)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	keySet := NewKeySet(server.URL, time.Hour)
+	errs := make(chan error, 10)
+
+	// Act
+	for range 10 {
+		go func() {
+			_, err := keySet.Key(context.Background(), "rsa-1")
+			errs <- err
+		}()
+	}
+
+	// Assert
+	for range 10 {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected key, got %v", err)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("Expected concurrent requests to share one fetch, got %d", fetches.Load())
+	}
+}
+
+func TestParseJWKS_SkipsUnsupportedKeys(t *testing.T) {
+	// Arrange
+	rsaKey := generateRSAKey(t)
+	encryptionKey := rsaJWK("enc", rsaKey)
+	encryptionKey.Use = "enc"
+	data := encodeJWKS(t,
+		rsaJWK("sig", rsaKey),
+		encryptionKey,
+		jsonWebKey{Kid: "oct", Kty: "oct"},
+		jsonWebKey{Kid: "p384", Kty: "EC", Use: "sig", Crv: "P-384", X: "AA", Y: "AA"},
+		jsonWebKey{Kid: "p521", Kty: "EC", Crv: "P-521", X: "AA", Y: "AA"},
+	)
+
+	// Act
+	keys, err := parseJWKS(data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 1 || keys["sig"] == nil {
+		t.Errorf("Expected only the RSA signing key, got %v", keys)
+	}
+}
+
+func TestKeySet_Key_UnknownKeyWhileSourceIsDown(t *testing.T) {
+	// Arrange
+	var down atomic.Bool
+	jwks := encodeJWKS(t, rsaJWK("rsa-1", generateRSAKey(t)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(jwks)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	keySet := NewKeySet(server.URL, time.Hour)
+	keySet.now = func() time.Time { return now }
+	if _, err := keySet.Key(context.Background(), "rsa-1"); err != nil {
+		t.Fatalf("Expected key, got %v", err)
+	}
+
+	// Act: the first lookup fails the refresh, the second finds it backing off
+	down.Store(true)
+	now = now.Add(keySet.minRefreshInterval)
+	_, errFetch := keySet.Key(context.Background(), "rsa-2")
+	_, errBackoff := keySet.Key(context.Background(), "rsa-2")
+
+	// Assert
+	for _, err := range []error{errFetch, errBackoff} {
+		if !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Expected ErrKeyNotFound, got %v", err)
+		}
+	}
+}
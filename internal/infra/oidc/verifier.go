@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// KeyProvider resolves token key IDs to public keys
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// VerifierConfig holds the token validation rules
+type VerifierConfig struct {
+	// Issuer is the expected "iss" claim; empty disables the check
+	Issuer string
+
+	// Audience is the expected "aud" claim; empty disables the check
+	Audience string
+
+	// RoleClaim is the dot separated path of the claim holding the user's roles or groups
+	RoleClaim string
+
+	// RoleMapping maps identity provider role or group names to service roles
+	// When empty, claim values matching a service role name are used as-is
+	RoleMapping map[string]auth.Role
+
+	// ClockSkew is the tolerance applied to the exp and nbf claims
+	ClockSkew time.Duration
+}
+
+// Verifier validates RS256 and ES256 signed JWT bearer tokens
+type Verifier struct {
+	keys   KeyProvider
+	config VerifierConfig
+	now    func() time.Time
+}
+
+// NewVerifier creates a new token verifier
+func NewVerifier(keys KeyProvider, config VerifierConfig) *Verifier {
+	if config.RoleClaim == "" {
+		config.RoleClaim = "roles"
+	}
+
+	return &Verifier{
+		keys:   keys,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// tokenHeader represents the JOSE header of a JWT
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify validates the token and returns the principal it identifies
+// Token problems are reported as auth.ErrUnauthenticated; failures to load keys are not
+func (v *Verifier) Verify(ctx context.Context, token string) (*auth.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", auth.ErrUnauthenticated)
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: invalid token header", auth.ErrUnauthenticated)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid token signature encoding", auth.ErrUnauthenticated)
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+		}
+		return nil, err
+	}
+
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: invalid token claims", auth.ErrUnauthenticated)
+	}
+
+	if err := v.validateClaims(claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	roles := v.mapRoles(lookupClaim(claims, v.config.RoleClaim))
+
+	return &auth.Principal{
+		Subject:      subject,
+		AllMerchants: true,
+		Roles:        roles,
+		Scopes:       auth.ScopesForRoles(roles),
+	}, nil
+}
+
+// validateClaims checks the registered time, issuer and audience claims
+func (v *Verifier) validateClaims(claims map[string]interface{}) error {
+	now := v.now()
+
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(exp.Add(v.config.ClockSkew)) {
+		return errors.New("token has expired")
+	}
+
+	if nbf, ok := numericDate(claims["nbf"]); ok && now.Add(v.config.ClockSkew).Before(nbf) {
+		return errors.New("token is not valid yet")
+	}
+
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+			return fmt.Errorf("unexpected issuer '%s'", iss)
+		}
+	}
+
+	if v.config.Audience != "" && !containsAudience(claims["aud"], v.config.Audience) {
+		return errors.New("token is not intended for this audience")
+	}
+
+	return nil
+}
+
+// mapRoles converts role claim values into service roles, dropping unknown values
+func (v *Verifier) mapRoles(values []string) []auth.Role {
+	var roles []auth.Role
+	for _, value := range values {
+		role := auth.Role(value)
+		if len(v.config.RoleMapping) > 0 {
+			mapped, ok := v.config.RoleMapping[value]
+			if !ok {
+				continue
+			}
+			role = mapped
+		}
+
+		if role.IsValid() {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// verifySignature checks the signature over the signing input with the given algorithm
+// Only asymmetric algorithms are accepted, so "none" and HMAC tokens are rejected
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signingInput))
+
+	switch alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm RS256")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.New("invalid token signature")
+		}
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm ES256")
+		}
+		// JWS encodes ECDSA signatures as the fixed-width concatenation R || S
+		if len(signature) != 64 {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return errors.New("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported signing algorithm '%s'", alg)
+	}
+
+	return nil
+}
+
+// decodeSegment decodes a base64url encoded JSON token segment
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericDate converts a JSON numeric date claim to a time
+func numericDate(value interface{}) (time.Time, bool) {
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+// containsAudience checks a string or array "aud" claim for the audience
+func containsAudience(value interface{}, audience string) bool {
+	switch aud := value.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, item := range aud {
+			if s, ok := item.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupClaim resolves a dot separated claim path, e.g. "realm_access.roles",
+// returning its values from a string array or a space or comma separated string
+func lookupClaim(claims map[string]interface{}, path string) []string {
+	var current interface{} = claims
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+
+	switch value := current.(type) {
+	case string:
+		return strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// staticKeys is a KeyProvider backed by a fixed map
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// signToken builds a compact JWT signed with the given key
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = sig
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// unsignedToken builds a token with the given header algorithm and no valid signature
+func unsignedToken(alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": "rsa-1"})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey := generateRSAKey(t)
+	ecKey := generateECKey(t)
+	otherKey := generateRSAKey(t)
+
+	now := time.Now()
+	verifier := NewVerifier(
+		staticKeys{"rsa-1": &rsaKey.PublicKey, "ec-1": &ecKey.PublicKey},
+		VerifierConfig{
+			Issuer:    "https://idp.example.com",
+			Audience:  "chargeback-api",
+			ClockSkew: time.Minute,
+		},
+	)
+	verifier.now = func() time.Time { return now }
+
+	validClaims := func(overrides map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{
+			"sub":   "analyst@example.com",
+			"iss":   "https://idp.example.com",
+			"aud":   "chargeback-api",
+			"exp":   now.Add(time.Hour).Unix(),
+			"roles": []string{"analyst"},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(claims, k)
+				continue
+			}
+			claims[k] = v
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedRoles []auth.Role
+		expectErr     bool
+	}{
+		{
+			name:          "accepts RS256 token",
+			token:         signToken(t, "RS256", "rsa-1", rsaKey, validClaims(nil)),
+			expectedRoles: []auth.Role{auth.RoleAnalyst},
+		},
+		{
+			name:          "accepts ES256 token",
+			token:         signToken(t, "ES256", "ec-1", ecKey, validClaims(nil)),
+			expectedRoles: []auth.Role{auth.RoleAnalyst},
+		},
+		{
+			name:          "accepts audience array",
+			token:         signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"aud": []string{"other", "chargeback-api"}})),
+			expectedRoles: []auth.Role{auth.RoleAnalyst},
+		},
+		{
+			name:          "accepts recently expired token within clock skew",
+			token:         signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})),
+			expectedRoles: []auth.Role{auth.RoleAnalyst},
+		},
+		{
+			name:          "drops unknown roles",
+			token:         signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"roles": "viewer unknown"})),
+			expectedRoles: []auth.Role{auth.RoleViewer},
+		},
+		{
+			name:      "rejects expired token",
+			token:     signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+			expectErr: true,
+		},
+		{
+			name:      "rejects token without expiry",
+			token:     signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"exp": nil})),
+			expectErr: true,
+		},
+		{
+			name:      "rejects token not valid yet",
+			token:     signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+			expectErr: true,
+		},
+		{
+			name:      "rejects wrong issuer",
+			token:     signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			expectErr: true,
+		},
+		{
+			name:      "rejects wrong audience",
+			token:     signToken(t, "RS256", "rsa-1", rsaKey, validClaims(map[string]interface{}{"aud": "other-api"})),
+			expectErr: true,
+		},
+		{
+			name:      "rejects token signed by another key",
+			token:     signToken(t, "RS256", "rsa-1", otherKey, validClaims(nil)),
+			expectErr: true,
+		},
+		{
+			name:      "rejects unknown key ID",
+			token:     signToken(t, "RS256", "rsa-2", rsaKey, validClaims(nil)),
+			expectErr: true,
+		},
+		{
+			name:      "rejects algorithm mismatching the key",
+			token:     signToken(t, "ES256", "rsa-1", ecKey, validClaims(nil)),
+			expectErr: true,
+		},
+		{
+			name:      "rejects alg none",
+			token:     unsignedToken("none", validClaims(nil)),
+			expectErr: true,
+		},
+		{
+			name:      "rejects HS256",
+			token:     unsignedToken("HS256", validClaims(nil)),
+			expectErr: true,
+		},
+		{
+			name:      "rejects malformed token",
+			token:     "not-a-jwt",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			principal, err := verifier.Verify(context.Background(), tt.token)
+
+			// Assert
+			if tt.expectErr {
+				if !errors.Is(err, auth.ErrUnauthenticated) {
+					t.Errorf("Expected ErrUnauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if principal.Subject != "analyst@example.com" {
+				t.Errorf("Expected subject analyst@example.com, got %s", principal.Subject)
+			}
+			if !principal.AllMerchants {
+				t.Error("Expected staff principal to access all merchants")
+			}
+			if len(principal.Roles) != len(tt.expectedRoles) {
+				t.Fatalf("Expected roles %v, got %v", tt.expectedRoles, principal.Roles)
+			}
+			for i, role := range tt.expectedRoles {
+				if principal.Roles[i] != role {
+					t.Errorf("Expected roles %v, got %v", tt.expectedRoles, principal.Roles)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify_RoleMapping(t *testing.T) {
+	// Arrange
+	rsaKey := generateRSAKey(t)
+	verifier := NewVerifier(
+		staticKeys{"rsa-1": &rsaKey.PublicKey},
+		VerifierConfig{
+			RoleClaim: "realm_access.roles",
+			RoleMapping: map[string]auth.Role{
+				"cb-supervisors": auth.RoleSupervisor,
+			},
+		},
+	)
+
+	token := signToken(t, "RS256", "rsa-1", rsaKey, map[string]interface{}{
+		"sub": "supervisor@example.com",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"realm_access": map[string]interface{}{
+			"roles": []string{"cb-supervisors", "analyst"},
+		},
+	})
+
+	// Act
+	principal, err := verifier.Verify(context.Background(), token)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(principal.Roles) != 1 || principal.Roles[0] != auth.RoleSupervisor {
+		t.Errorf("Expected only mapped supervisor role, got %v", principal.Roles)
+	}
+	if !principal.HasScope(auth.ScopeChargebacksReviewHighValue) {
+		t.Error("Expected supervisor to have high-value review scope")
+	}
+}
+
+func TestVerifier_Verify_KeyLoadFailure(t *testing.T) {
+	// Arrange
+	rsaKey := generateRSAKey(t)
+	verifier := NewVerifier(NewKeySet("/nonexistent/jwks.json", time.Hour), VerifierConfig{})
+	token := signToken(t, "RS256", "rsa-1", rsaKey, map[string]interface{}{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	// Act
+	_, err := verifier.Verify(context.Background(), token)
+
+	// Assert
+	if err == nil || errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Expected key load failure not to be reported as unauthenticated, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// UpdateItemAPI is the subset of the DynamoDB client used by the distributed limiter
+type UpdateItemAPI interface {
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+// DynamoDBLimiter implements RateLimiter with fixed-window counters stored in DynamoDB,
+// so limits hold across all instances of the service
+// Each window is a separate item that expires through the table's TTL on "expires_at"
+type DynamoDBLimiter struct {
+	client    UpdateItemAPI
+	tableName string
+	now       func() time.Time
+}
+
+// NewDynamoDBLimiter creates a new DynamoDB-backed rate limiter
+func NewDynamoDBLimiter(client UpdateItemAPI, tableName string) *DynamoDBLimiter {
+	return &DynamoDBLimiter{
+		client:    client,
+		tableName: tableName,
+		now:       time.Now,
+	}
+}
+
+// Allow atomically increments the key's counter for the current window
+// The request is counted only if the window still has room, so denied requests don't
+// extend the time a client stays limited
+func (l *DynamoDBLimiter) Allow(ctx context.Context, key string, limit service.RateLimit) (service.RateLimitResult, error) {
+	if limit.IsUnlimited() {
+		return service.RateLimitResult{Allowed: true}, nil
+	}
+
+	now := l.now()
+	windowStart := now.Truncate(limit.Window)
+	windowEnd := windowStart.Add(limit.Window)
+	result := service.RateLimitResult{
+		Limit:      limit.Requests,
+		ResetAfter: windowEnd.Sub(now),
+	}
+
+	output, err := l.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%d", key, windowStart.Unix())},
+		},
+		UpdateExpression:    aws.String("ADD request_count :one SET expires_at = if_not_exists(expires_at, :expires)"),
+		ConditionExpression: aws.String("attribute_not_exists(request_count) OR request_count < :limit"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":     &types.AttributeValueMemberN{Value: "1"},
+			":limit":   &types.AttributeValueMemberN{Value: strconv.Itoa(limit.Requests)},
+			":expires": &types.AttributeValueMemberN{Value: strconv.FormatInt(windowEnd.Add(time.Minute).Unix(), 10)},
+		},
+		ReturnValues: types.ReturnValueUpdatedNew,
+	})
+
+	if err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			result.RetryAfter = result.ResetAfter
+			return result, nil
+		}
+		return service.RateLimitResult{}, fmt.Errorf("failed to update rate limit counter: %w", err)
+	}
+
+	count, err := requestCount(output.Attributes)
+	if err != nil {
+		return service.RateLimitResult{}, err
+	}
+
+	result.Allowed = true
+	result.Remaining = max(limit.Requests-count, 0)
+	return result, nil
+}
+
+// requestCount reads the updated counter from the UpdateItem output
+func requestCount(attributes map[string]types.AttributeValue) (int, error) {
+	value, ok := attributes["request_count"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, errors.New("failed to read rate limit counter: missing request_count")
+	}
+
+	count, err := strconv.Atoi(value.Value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rate limit counter: %w", err)
+	}
+	return count, nil
+}
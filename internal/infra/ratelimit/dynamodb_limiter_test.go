@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// MockUpdateItemAPI is a mock implementation of UpdateItemAPI
+type MockUpdateItemAPI struct {
+	UpdateItemFunc func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+func (m *MockUpdateItemAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return m.UpdateItemFunc(ctx, params, optFns...)
+}
+
+func TestDynamoDBLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 45, 0, time.UTC)
+	limit := service.RateLimit{Requests: 10, Window: time.Minute}
+
+	tests := []struct {
+		name              string
+		output            *dynamodb.UpdateItemOutput
+		err               error
+		expectedAllowed   bool
+		expectedRemaining int
+		expectErr         bool
+	}{
+		{
+			name: "allows request within the window limit",
+			output: &dynamodb.UpdateItemOutput{Attributes: map[string]types.AttributeValue{
+				"request_count": &types.AttributeValueMemberN{Value: "4"},
+			}},
+			expectedAllowed:   true,
+			expectedRemaining: 6,
+		},
+		{
+			name:            "denies request when the window is full",
+			err:             &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")},
+			expectedAllowed: false,
+		},
+		{
+			name:      "reports store errors",
+			err:       errors.New("ProvisionedThroughputExceededException"),
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var input *dynamodb.UpdateItemInput
+			client := &MockUpdateItemAPI{
+				UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+					input = params
+					return tt.output, tt.err
+				},
+			}
+			limiter := NewDynamoDBLimiter(client, "rate_limits")
+			limiter.now = func() time.Time { return now }
+
+			// Act
+			result, err := limiter.Allow(context.Background(), "POST /chargebacks|client:key-1", limit)
+
+			// Assert
+			if tt.expectErr {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			key := input.Key["key"].(*types.AttributeValueMemberS).Value
+			if !strings.HasSuffix(key, "#1759320000") {
+				t.Errorf("Expected counter key for the window starting at 12:00:00, got %s", key)
+			}
+			if *input.TableName != "rate_limits" {
+				t.Errorf("Expected table rate_limits, got %s", *input.TableName)
+			}
+			if result.Allowed != tt.expectedAllowed || result.Remaining != tt.expectedRemaining {
+				t.Errorf("Expected allowed=%t remaining=%d, got %+v", tt.expectedAllowed, tt.expectedRemaining, result)
+			}
+			if result.ResetAfter != 15*time.Second {
+				t.Errorf("Expected reset after 15s, got %s", result.ResetAfter)
+			}
+			if !tt.expectedAllowed && result.RetryAfter != 15*time.Second {
+				t.Errorf("Expected retry after 15s, got %s", result.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := service.RateLimit{Requests: 60, Window: time.Minute, Burst: 3}
+	ctx := context.Background()
+
+	// Act: the burst is available immediately
+	var results []service.RateLimitResult
+	for i := 0; i < 4; i++ {
+		result, err := limiter.Allow(ctx, "client-1", limit)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		results = append(results, result)
+	}
+
+	// Assert
+	for i, expectedRemaining := range []int{2, 1, 0} {
+		if !results[i].Allowed || results[i].Remaining != expectedRemaining {
+			t.Errorf("Request %d: expected allowed with %d remaining, got %+v", i+1, expectedRemaining, results[i])
+		}
+	}
+	denied := results[3]
+	if denied.Allowed {
+		t.Fatal("Expected request beyond the burst to be denied")
+	}
+	if denied.Limit != 3 {
+		t.Errorf("Expected limit 3, got %d", denied.Limit)
+	}
+	if denied.RetryAfter <= 0 || denied.RetryAfter > time.Second {
+		t.Errorf("Expected retry after at most one token interval, got %s", denied.RetryAfter)
+	}
+
+	// Act: one token refills per second at 60 requests per minute
+	now = now.Add(time.Second)
+	refilled, _ := limiter.Allow(ctx, "client-1", limit)
+	other, _ := limiter.Allow(ctx, "client-2", limit)
+
+	// Assert
+	if !refilled.Allowed {
+		t.Error("Expected request to be allowed after refill")
+	}
+	if !other.Allowed || other.Remaining != 2 {
+		t.Errorf("Expected other clients to have their own bucket, got %+v", other)
+	}
+}
+
+func TestMemoryLimiter_Allow_Unlimited(t *testing.T) {
+	limiter := NewMemoryLimiter()
+
+	for i := 0; i < 100; i++ {
+		result, err := limiter.Allow(context.Background(), "client-1", service.RateLimit{})
+		if err != nil || !result.Allowed {
+			t.Fatalf("Expected unlimited requests to be allowed, got %+v, %v", result, err)
+		}
+	}
+}
+
+func TestMemoryLimiter_EvictsRefilledBuckets(t *testing.T) {
+	// Arrange
+	now := time.Now()
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := service.RateLimit{Requests: 10, Window: time.Minute}
+
+	limiter.Allow(context.Background(), "client-1", limit)
+
+	// Act
+	now = now.Add(2 * time.Minute)
+	limiter.Allow(context.Background(), "client-2", limit)
+
+	// Assert
+	if _, ok := limiter.buckets["client-1"]; ok {
+		t.Error("Expected refilled bucket to be evicted")
+	}
+	if _, ok := limiter.buckets["client-2"]; !ok {
+		t.Error("Expected active bucket to be kept")
+	}
+}
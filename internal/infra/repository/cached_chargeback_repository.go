@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
+)
+
+// negativeEntry marks a lookup that found no chargeback
+var negativeEntry = []byte("null")
+
+// ChargebackCacheStats counts the lookups made through a CachedChargebackRepository
+type ChargebackCacheStats struct {
+	Hits int64 `json:"hits"`
+	// NegativeHits counts the hits, included in Hits, on lookups known to find nothing
+	NegativeHits int64 `json:"negative_hits"`
+	Misses       int64 `json:"misses"`
+	// Errors counts cache calls that failed and fell through to the repository
+	Errors int64 `json:"errors"`
+}
+
+// CachedChargebackRepository serves FindByID and FindByTransactionID from a cache
+// Chargebacks are cached for ttl, and lookups that found nothing for negativeTTL, so
+// the duplicate check of the create path doesn't query the repository every time.
+// Writes invalidate the keys of the chargebacks they touch. Cache errors are logged and
+// the call goes to the repository, so a cache outage only costs latency.
+type CachedChargebackRepository struct {
+	repository.ChargebackRepository
+	cache       cache.Cache
+	ttl         time.Duration
+	negativeTTL time.Duration
+	logger      service.Logger
+
+	hits, negativeHits, misses, errors atomic.Int64
+}
+
+// NewCachedChargebackRepository wraps the repository with the cache
+// A negativeTTL of zero disables negative caching.
+func NewCachedChargebackRepository(repo repository.ChargebackRepository, c cache.Cache, ttl, negativeTTL time.Duration, logger service.Logger) *CachedChargebackRepository {
+	return &CachedChargebackRepository{
+		ChargebackRepository: repo,
+		cache:                c,
+		ttl:                  ttl,
+		negativeTTL:          negativeTTL,
+		logger:               logger,
+	}
+}
+
+// Stats returns the lookup counters
+func (r *CachedChargebackRepository) Stats() ChargebackCacheStats {
+	return ChargebackCacheStats{
+		Hits:         r.hits.Load(),
+		NegativeHits: r.negativeHits.Load(),
+		Misses:       r.misses.Load(),
+		Errors:       r.errors.Load(),
+	}
+}
+
+// FindByID retrieves a chargeback by its ID, from the cache when it's there
+func (r *CachedChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	return r.lookup(ctx, idCacheKey(id), func(chargeback *entity.Chargeback) bool {
+		return chargeback.ID == id
+	}, func() (*entity.Chargeback, error) {
+		return r.ChargebackRepository.FindByID(ctx, id)
+	})
+}
+
+// FindByTransactionID retrieves a chargeback by transaction ID, from the cache when it's there
+func (r *CachedChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+	return r.lookup(ctx, transactionCacheKey(transactionID), func(chargeback *entity.Chargeback) bool {
+		return chargeback.TransactionID == transactionID
+	}, func() (*entity.Chargeback, error) {
+		return r.ChargebackRepository.FindByTransactionID(ctx, transactionID)
+	})
+}
+
+// Save persists a new chargeback and forgets the lookups that found nothing for it
+func (r *CachedChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	if err := r.ChargebackRepository.Save(ctx, chargeback); err != nil {
+		return err
+	}
+	r.invalidate(ctx, chargeback)
+	return nil
+}
+
+// SaveBatch persists new chargebacks and forgets the lookups that found nothing for them
+func (r *CachedChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	failed, err := r.ChargebackRepository.SaveBatch(ctx, chargebacks)
+	r.invalidate(ctx, chargebacks...)
+	return failed, err
+}
+
+// Update updates an existing chargeback and invalidates its cached copies
+// The stored chargeback is read first, in case the update changes its transaction ID.
+func (r *CachedChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	previous, err := r.ChargebackRepository.FindByID(ctx, chargeback.ID)
+	if err != nil {
+		return err
+	}
+
+	err = r.ChargebackRepository.Update(ctx, chargeback)
+	// Invalidate even when the update failed, since it may have been applied anyway
+	r.invalidate(ctx, chargeback)
+	if previous != nil && previous.TransactionID != chargeback.TransactionID {
+		r.invalidate(ctx, previous)
+	}
+	return err
+}
+
+// Delete soft deletes a chargeback and invalidates its cached copies
+func (r *CachedChargebackRepository) Delete(ctx context.Context, id string) error {
+	previous, err := r.ChargebackRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = r.ChargebackRepository.Delete(ctx, id)
+	keys := []string{idCacheKey(id)}
+	if previous != nil {
+		keys = append(keys, transactionCacheKey(previous.TransactionID))
+	}
+	r.deleteKeys(ctx, keys)
+	return err
+}
+
+// Restore brings back a soft deleted chargeback and forgets the lookups that found
+// nothing for it while it was deleted
+func (r *CachedChargebackRepository) Restore(ctx context.Context, id string) error {
+	if err := r.ChargebackRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{idCacheKey(id)}
+	if restored, err := r.ChargebackRepository.FindByID(ctx, id); err == nil && restored != nil {
+		keys = append(keys, transactionCacheKey(restored.TransactionID))
+	}
+	r.deleteKeys(ctx, keys)
+	return nil
+}
+
+// Purge removes chargebacks for good and invalidates their cached copies
+func (r *CachedChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	err := r.ChargebackRepository.Purge(ctx, chargebacks)
+	// Invalidate even when the purge failed, since some chargebacks may be gone
+	r.invalidate(ctx, chargebacks...)
+	return err
+}
+
+// lookup returns the chargeback cached under key when it matches, or else loads it and
+// caches the result
+func (r *CachedChargebackRepository) lookup(ctx context.Context, key string, matches func(*entity.Chargeback) bool, load func() (*entity.Chargeback, error)) (*entity.Chargeback, error) {
+	if value, ok, err := r.cache.Get(ctx, key); err != nil {
+		r.cacheFailed(ctx, "get", key, err)
+	} else if ok {
+		if string(value) == string(negativeEntry) {
+			r.hits.Add(1)
+			r.negativeHits.Add(1)
+			return nil, nil
+		}
+		var chargeback entity.Chargeback
+		if err := json.Unmarshal(value, &chargeback); err == nil && matches(&chargeback) {
+			r.hits.Add(1)
+			return &chargeback, nil
+		}
+	}
+	r.misses.Add(1)
+
+	chargeback, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if chargeback == nil {
+		if r.negativeTTL > 0 {
+			r.set(ctx, key, negativeEntry, r.negativeTTL)
+		}
+		return nil, nil
+	}
+	if value, err := json.Marshal(chargeback); err == nil {
+		r.set(ctx, key, value, r.ttl)
+	}
+	return chargeback, nil
+}
+
+// set caches the value, logging failures
+func (r *CachedChargebackRepository) set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if err := r.cache.Set(ctx, key, value, ttl); err != nil {
+		r.cacheFailed(ctx, "set", key, err)
+	}
+}
+
+// invalidate deletes the keys of the chargebacks
+func (r *CachedChargebackRepository) invalidate(ctx context.Context, chargebacks ...*entity.Chargeback) {
+	keys := make([]string, 0, 2*len(chargebacks))
+	for _, chargeback := range chargebacks {
+		if chargeback.ID != "" {
+			keys = append(keys, idCacheKey(chargeback.ID))
+		}
+		if chargeback.TransactionID != "" {
+			keys = append(keys, transactionCacheKey(chargeback.TransactionID))
+		}
+	}
+	r.deleteKeys(ctx, keys)
+}
+
+// deleteKeys deletes the keys, logging failures
+// A failed invalidation leaves a stale entry until its TTL runs out.
+func (r *CachedChargebackRepository) deleteKeys(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := r.cache.Delete(ctx, keys...); err != nil {
+		r.cacheFailed(ctx, "delete", keys[0], err)
+	}
+}
+
+// cacheFailed counts and logs a failed cache call
+func (r *CachedChargebackRepository) cacheFailed(ctx context.Context, operation, key string, err error) {
+	r.errors.Add(1)
+	r.logger.Warn(ctx, "Chargeback cache call failed", map[string]interface{}{
+		"operation": operation,
+		"key":       key,
+		"error":     err.Error(),
+	})
+}
+
+// idCacheKey is the cache key of the chargeback with the ID
+func idCacheKey(id string) string {
+	return "chargeback:id:" + id
+}
+
+// transactionCacheKey is the cache key of the chargeback with the transaction ID
+func transactionCacheKey(transactionID string) string {
+	return "chargeback:txn:" + transactionID
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository/repositorytest"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/cache"
+)
+
+// countingChargebackRepository counts the lookups that reach the repository
+type countingChargebackRepository struct {
+	repository.ChargebackRepository
+	finds int
+}
+
+func (r *countingChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	r.finds++
+	return r.ChargebackRepository.FindByID(ctx, id)
+}
+
+func (r *countingChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+	r.finds++
+	return r.ChargebackRepository.FindByTransactionID(ctx, transactionID)
+}
+
+// failingCache fails every call
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+func (failingCache) Delete(ctx context.Context, keys ...string) error {
+	return errors.New("connection refused")
+}
+
+func newTestCachedRepository(c cache.Cache) (*CachedChargebackRepository, *countingChargebackRepository, *recordingLogger) {
+	inner := &countingChargebackRepository{ChargebackRepository: NewMemoryChargebackRepository()}
+	logger := &recordingLogger{}
+	return NewCachedChargebackRepository(inner, c, time.Minute, 10*time.Second, logger), inner, logger
+}
+
+func TestCachedChargebackRepository_Contract(t *testing.T) {
+	repositorytest.TestChargebackRepository(t, func(t *testing.T) repository.ChargebackRepository {
+		repo, _, _ := newTestCachedRepository(cache.NewLRUCache(100))
+		return repo
+	})
+}
+
+func TestCachedChargebackRepository_Lookups(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, inner, _ := newTestCachedRepository(cache.NewLRUCache(100))
+	chargeback := createTestChargeback()
+	if err := repo.Save(ctx, chargeback); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+
+	// Act
+	for i := 0; i < 3; i++ {
+		byID, err := repo.FindByID(ctx, chargeback.ID)
+		if err != nil || byID == nil || byID.TransactionID != chargeback.TransactionID {
+			t.Fatalf("Expected the chargeback by ID, got %+v, %v", byID, err)
+		}
+		byTransaction, err := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+		if err != nil || byTransaction == nil || byTransaction.ID != chargeback.ID {
+			t.Fatalf("Expected the chargeback by transaction ID, got %+v, %v", byTransaction, err)
+		}
+	}
+
+	// Assert
+	if inner.finds != 2 {
+		t.Errorf("Expected only the first lookups to reach the repository, got %d", inner.finds)
+	}
+	if stats := repo.Stats(); stats.Hits != 4 || stats.Misses != 2 {
+		t.Errorf("Expected 4 hits and 2 misses, got %+v", stats)
+	}
+}
+
+func TestCachedChargebackRepository_NegativeCaching(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, inner, _ := newTestCachedRepository(cache.NewLRUCache(100))
+	chargeback := createTestChargeback()
+
+	// Act
+	first, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+	second, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+	findsBeforeSave := inner.finds
+	if err := repo.Save(ctx, chargeback); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	afterSave, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+	// Assert
+	if first != nil || second != nil {
+		t.Fatal("Expected no chargeback before it was saved")
+	}
+	if findsBeforeSave != 1 || repo.Stats().NegativeHits != 1 {
+		t.Errorf("Expected the second lookup to be served from the cache, got %d finds and %+v", findsBeforeSave, repo.Stats())
+	}
+	if afterSave == nil || afterSave.ID != chargeback.ID {
+		t.Errorf("Expected the save to clear the negative entry, got %+v", afterSave)
+	}
+}
+
+func TestCachedChargebackRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("update", func(t *testing.T) {
+		// Arrange
+		repo, _, _ := newTestCachedRepository(cache.NewLRUCache(100))
+		chargeback := createTestChargeback()
+		repo.Save(ctx, chargeback)
+		repo.FindByID(ctx, chargeback.ID)
+		repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Act
+		updated := *chargeback
+		updated.Status = entity.StatusApproved
+		if err := repo.Update(ctx, &updated); err != nil {
+			t.Fatalf("Failed to update chargeback: %v", err)
+		}
+		byID, _ := repo.FindByID(ctx, chargeback.ID)
+		byTransaction, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Assert
+		if byID.Status != entity.StatusApproved || byTransaction.Status != entity.StatusApproved {
+			t.Errorf("Expected the updated status, got %s and %s", byID.Status, byTransaction.Status)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// Arrange
+		repo, _, _ := newTestCachedRepository(cache.NewLRUCache(100))
+		chargeback := createTestChargeback()
+		repo.Save(ctx, chargeback)
+		repo.FindByID(ctx, chargeback.ID)
+		repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Act
+		if err := repo.Delete(ctx, chargeback.ID); err != nil {
+			t.Fatalf("Failed to delete chargeback: %v", err)
+		}
+		byID, _ := repo.FindByID(ctx, chargeback.ID)
+		byTransaction, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Assert
+		if byID != nil || byTransaction != nil {
+			t.Errorf("Expected the deleted chargeback to be gone, got %+v and %+v", byID, byTransaction)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		// Arrange
+		repo, _, _ := newTestCachedRepository(cache.NewLRUCache(100))
+		chargeback := createTestChargeback()
+		repo.Save(ctx, chargeback)
+		repo.Delete(ctx, chargeback.ID)
+		repo.FindByID(ctx, chargeback.ID)
+		repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Act
+		if err := repo.Restore(ctx, chargeback.ID); err != nil {
+			t.Fatalf("Failed to restore chargeback: %v", err)
+		}
+		byID, _ := repo.FindByID(ctx, chargeback.ID)
+		byTransaction, _ := repo.FindByTransactionID(ctx, chargeback.TransactionID)
+
+		// Assert
+		if byID == nil || byTransaction == nil {
+			t.Errorf("Expected the restore to clear the negative entries, got %+v and %+v", byID, byTransaction)
+		}
+	})
+}
+
+func TestCachedChargebackRepository_CacheFailure(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo, inner, logger := newTestCachedRepository(failingCache{})
+	chargeback := createTestChargeback()
+
+	// Act
+	saveErr := repo.Save(ctx, chargeback)
+	found, findErr := repo.FindByID(ctx, chargeback.ID)
+
+	// Assert
+	if saveErr != nil || findErr != nil {
+		t.Fatalf("Expected cache failures to be hidden, got %v and %v", saveErr, findErr)
+	}
+	if found == nil || inner.finds != 1 {
+		t.Errorf("Expected the lookup to reach the repository, got %+v after %d finds", found, inner.finds)
+	}
+	if len(logger.warnings) != 3 || repo.Stats().Errors != 3 {
+		t.Errorf("Expected the 3 failed cache calls to be logged, got %v", logger.warnings)
+	}
+}
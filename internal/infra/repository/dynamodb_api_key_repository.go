@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// DynamoDBAPIKeyRepository implements APIKeyRepository using DynamoDB
+type DynamoDBAPIKeyRepository struct {
+	client    DynamoDBAPI
+	tableName string
+}
+
+// NewDynamoDBAPIKeyRepository creates a new DynamoDB API key repository
+func NewDynamoDBAPIKeyRepository(client DynamoDBAPI, tableName string) repository.APIKeyRepository {
+	return &DynamoDBAPIKeyRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// apiKeyItem represents the DynamoDB item structure for API keys
+type apiKeyItem struct {
+	ID          string    `dynamodbav:"id"`
+	Name        string    `dynamodbav:"name"`
+	SecretHash  string    `dynamodbav:"secret_hash"`
+	MerchantIDs []string  `dynamodbav:"merchant_ids"`
+	Scopes      []string  `dynamodbav:"scopes"`
+	Status      string    `dynamodbav:"status"`
+	CreatedAt   time.Time `dynamodbav:"created_at"`
+	UpdatedAt   time.Time `dynamodbav:"updated_at"`
+	RotatedAt   time.Time `dynamodbav:"rotated_at"`
+	RevokedAt   time.Time `dynamodbav:"revoked_at"`
+}
+
+// Save persists a new API key to DynamoDB
+func (r *DynamoDBAPIKeyRepository) Save(ctx context.Context, key *entity.APIKey) error {
+	av, err := attributevalue.MarshalMap(apiKeyToItem(key))
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+		// Condition to prevent overwriting existing items
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves an API key by its unique identifier
+func (r *DynamoDBAPIKeyRepository) FindByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil // Not found
+	}
+
+	var item apiKeyItem
+	if err := attributevalue.UnmarshalMap(result.Item, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+	}
+
+	return itemToAPIKey(&item), nil
+}
+
+// Update updates an existing API key in DynamoDB
+func (r *DynamoDBAPIKeyRepository) Update(ctx context.Context, key *entity.APIKey) error {
+	av, err := attributevalue.MarshalMap(apiKeyToItem(key))
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      av,
+		// Condition to ensure the item exists
+		ConditionExpression: aws.String("attribute_exists(id)"),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves all API keys, following scan pagination
+func (r *DynamoDBAPIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	keys := []*entity.APIKey{}
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API keys: %w", err)
+		}
+
+		for _, av := range result.Items {
+			var item apiKeyItem
+			if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+			}
+			keys = append(keys, itemToAPIKey(&item))
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	return keys, nil
+}
+
+// apiKeyToItem converts a domain entity to a DynamoDB item
+func apiKeyToItem(key *entity.APIKey) apiKeyItem {
+	scopes := make([]string, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	return apiKeyItem{
+		ID:          key.ID,
+		Name:        key.Name,
+		SecretHash:  key.SecretHash,
+		MerchantIDs: key.MerchantIDs,
+		Scopes:      scopes,
+		Status:      string(key.Status),
+		CreatedAt:   key.CreatedAt,
+		UpdatedAt:   key.UpdatedAt,
+		RotatedAt:   key.RotatedAt,
+		RevokedAt:   key.RevokedAt,
+	}
+}
+
+// itemToAPIKey converts a DynamoDB item to a domain entity
+func itemToAPIKey(item *apiKeyItem) *entity.APIKey {
+	scopes := make([]auth.Scope, 0, len(item.Scopes))
+	for _, scope := range item.Scopes {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+
+	return &entity.APIKey{
+		ID:          item.ID,
+		Name:        item.Name,
+		SecretHash:  item.SecretHash,
+		MerchantIDs: item.MerchantIDs,
+		Scopes:      scopes,
+		Status:      entity.APIKeyStatus(item.Status),
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+		RotatedAt:   item.RotatedAt,
+		RevokedAt:   item.RevokedAt,
+	}
+}
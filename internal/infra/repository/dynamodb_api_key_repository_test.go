@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+func TestDynamoDBAPIKeyRepository_Save(t *testing.T) {
+	t.Run("successful save", func(t *testing.T) {
+		mockClient := &MockDynamoDBAPI{
+			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				if *params.TableName != "test-api-keys" {
+					t.Errorf("Expected table name 'test-api-keys', got %s", *params.TableName)
+				}
+				if params.Item["secret_hash"] == nil {
+					t.Error("Expected 'secret_hash' field in item")
+				}
+				if params.ConditionExpression == nil || *params.ConditionExpression != "attribute_not_exists(id)" {
+					t.Error("Expected condition to prevent overwriting existing items")
+				}
+				return &dynamodb.PutItemOutput{}, nil
+			},
+		}
+
+		repo := NewDynamoDBAPIKeyRepository(mockClient, "test-api-keys")
+		if err := repo.Save(context.Background(), createTestAPIKey("key-1")); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		mockClient := &MockDynamoDBAPI{
+			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				return nil, errors.New("DynamoDB error")
+			},
+		}
+
+		repo := NewDynamoDBAPIKeyRepository(mockClient, "test-api-keys")
+		err := repo.Save(context.Background(), createTestAPIKey("key-1"))
+		if err == nil || !strings.Contains(err.Error(), "failed to save API key") {
+			t.Errorf("Expected save error, got %v", err)
+		}
+	})
+}
+
+func TestDynamoDBAPIKeyRepository_FindByID(t *testing.T) {
+	t.Run("successful find", func(t *testing.T) {
+		item, _ := attributevalue.MarshalMap(apiKeyToItem(createTestAPIKey("key-1")))
+		mockClient := &MockDynamoDBAPI{
+			GetItemFunc: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				if params.Key["id"].(*types.AttributeValueMemberS).Value != "key-1" {
+					t.Error("Expected key lookup by id")
+				}
+				return &dynamodb.GetItemOutput{Item: item}, nil
+			},
+		}
+
+		repo := NewDynamoDBAPIKeyRepository(mockClient, "test-api-keys")
+		key, err := repo.FindByID(context.Background(), "key-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if key.SecretHash != "hash-key-1" {
+			t.Errorf("Expected secret hash 'hash-key-1', got %s", key.SecretHash)
+		}
+		if len(key.Scopes) != 1 || key.Scopes[0] != auth.ScopeChargebacksWrite {
+			t.Errorf("Expected scopes to round-trip, got %v", key.Scopes)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := NewDynamoDBAPIKeyRepository(&MockDynamoDBAPI{}, "test-api-keys")
+		key, err := repo.FindByID(context.Background(), "missing")
+		if err != nil || key != nil {
+			t.Errorf("Expected nil, nil for missing key, got %v, %v", key, err)
+		}
+	})
+}
+
+func TestDynamoDBAPIKeyRepository_Update(t *testing.T) {
+	mockClient := &MockDynamoDBAPI{
+		PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			if params.ConditionExpression == nil || *params.ConditionExpression != "attribute_exists(id)" {
+				t.Error("Expected condition to ensure the item exists")
+			}
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+
+	repo := NewDynamoDBAPIKeyRepository(mockClient, "test-api-keys")
+	if err := repo.Update(context.Background(), createTestAPIKey("key-1")); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestDynamoDBAPIKeyRepository_List(t *testing.T) {
+	first, _ := attributevalue.MarshalMap(apiKeyToItem(createTestAPIKey("key-1")))
+	second, _ := attributevalue.MarshalMap(apiKeyToItem(createTestAPIKey("key-2")))
+
+	calls := 0
+	mockClient := &MockDynamoDBAPI{
+		ScanFunc: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			calls++
+			if calls == 1 {
+				return &dynamodb.ScanOutput{
+					Items:            []map[string]types.AttributeValue{first},
+					LastEvaluatedKey: map[string]types.AttributeValue{"id": &types.AttributeValueMemberS{Value: "key-1"}},
+				}, nil
+			}
+			if params.ExclusiveStartKey == nil {
+				t.Error("Expected ExclusiveStartKey on second page")
+			}
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{second}}, nil
+		},
+	}
+
+	repo := NewDynamoDBAPIKeyRepository(mockClient, "test-api-keys")
+	keys, err := repo.List(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("Expected 2 keys across pages, got %d", len(keys))
+	}
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,19 +16,53 @@ import (
 
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/idgen"
+)
+
+// DynamoDBAPI defines the subset of the DynamoDB client used by the repository
+// This allows the client to be replaced with a mock in tests
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+const (
+	// batchWriteSize is the maximum number of items DynamoDB accepts per BatchWriteItem request
+	batchWriteSize = 25
+
+	// batchWriteAttempts bounds how often unprocessed items are resubmitted
+	batchWriteAttempts = 5
+)
+
+// Conditions on the soft delete attributes of chargeback items
+// A purged item has an expires_at and is left alone until the table's TTL removes it.
+const (
+	activeItemCondition  = "attribute_not_exists(deleted_at) AND attribute_not_exists(expires_at)"
+	deletedItemCondition = "attribute_exists(deleted_at) AND attribute_not_exists(expires_at)"
 )
 
 // DynamoDBChargebackRepository implements ChargebackRepository using DynamoDB
+// Soft deleted items carry a deleted_at. Purged items get an expires_at, which the
+// table's time to live setting removes them after, and are hidden from every read until then.
 type DynamoDBChargebackRepository struct {
 	client    DynamoDBAPI
 	tableName string
+
+	// retryDelay is the initial backoff before resubmitting unprocessed items
+	retryDelay time.Duration
 }
 
 // NewDynamoDBChargebackRepository creates a new DynamoDB chargeback repository
 func NewDynamoDBChargebackRepository(client *dynamodb.Client, tableName string) repository.ChargebackRepository {
 	return &DynamoDBChargebackRepository{
-		client:    client,
-		tableName: tableName,
+		client:     client,
+		tableName:  tableName,
+		retryDelay: 50 * time.Millisecond,
 	}
 }
 
@@ -32,8 +70,9 @@ func NewDynamoDBChargebackRepository(client *dynamodb.Client, tableName string)
 // This is primarily used for testing with mocks
 func NewDynamoDBChargebackRepositoryWithInterface(client DynamoDBAPI, tableName string) *DynamoDBChargebackRepository {
 	return &DynamoDBChargebackRepository{
-		client:    client,
-		tableName: tableName,
+		client:     client,
+		tableName:  tableName,
+		retryDelay: 50 * time.Millisecond,
 	}
 }
 
@@ -52,6 +91,10 @@ type chargebackItem struct {
 	ChargebackDate  time.Time `dynamodbav:"chargeback_date"`
 	CreatedAt       time.Time `dynamodbav:"created_at"`
 	UpdatedAt       time.Time `dynamodbav:"updated_at"`
+	// DeletedAt is set on soft deleted chargebacks
+	DeletedAt *time.Time `dynamodbav:"deleted_at,omitempty"`
+	// ExpiresAt is the Unix time the table's TTL may remove a purged chargeback after
+	ExpiresAt int64 `dynamodbav:"expires_at,omitempty"`
 }
 
 // Save persists a new chargeback to DynamoDB
@@ -61,25 +104,9 @@ func (r *DynamoDBChargebackRepository) Save(ctx context.Context, chargeback *ent
 		chargeback.ID = generateChargebackID()
 	}
 
-	item := chargebackItem{
-		ID:              chargeback.ID,
-		TransactionID:   chargeback.TransactionID,
-		MerchantID:      chargeback.MerchantID,
-		Amount:          chargeback.Amount,
-		Currency:        chargeback.Currency,
-		CardNumber:      chargeback.CardNumber,
-		Reason:          string(chargeback.Reason),
-		Status:          string(chargeback.Status),
-		Description:     chargeback.Description,
-		TransactionDate: chargeback.TransactionDate,
-		ChargebackDate:  chargeback.ChargebackDate,
-		CreatedAt:       chargeback.CreatedAt,
-		UpdatedAt:       chargeback.UpdatedAt,
-	}
-
-	av, err := attributevalue.MarshalMap(item)
+	av, err := r.entityToItem(chargeback)
 	if err != nil {
-		return fmt.Errorf("failed to marshal chargeback: %w", err)
+		return err
 	}
 
 	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
@@ -90,12 +117,100 @@ func (r *DynamoDBChargebackRepository) Save(ctx context.Context, chargeback *ent
 	})
 
 	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to save chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackExists, err)
+		}
 		return fmt.Errorf("failed to save chargeback: %w", err)
 	}
 
 	return nil
 }
 
+// SaveBatch persists new chargebacks with BatchWriteItem, 25 items per request
+// Items DynamoDB leaves unprocessed, typically when throttled, are resubmitted with
+// exponential backoff; BatchWriteItem cannot apply conditions, so callers must only
+// pass chargebacks with new IDs
+func (r *DynamoDBChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	return saveChargebackBatch(ctx, r.client, r.tableName, r.retryDelay, chargebacks, r.entityToItem)
+}
+
+// saveChargebackBatch writes chargebacks converted by toItem in chunks of batchWriteSize
+// It returns the chargebacks that were not written
+func saveChargebackBatch(ctx context.Context, client DynamoDBAPI, tableName string, retryDelay time.Duration, chargebacks []*entity.Chargeback, toItem func(*entity.Chargeback) (map[string]types.AttributeValue, error)) ([]*entity.Chargeback, error) {
+	for start := 0; start < len(chargebacks); start += batchWriteSize {
+		chunk := chargebacks[start:min(start+batchWriteSize, len(chargebacks))]
+
+		unprocessed, err := writeChunk(ctx, client, tableName, retryDelay, chunk, toItem)
+		if err != nil {
+			return append(unprocessed, chargebacks[start+len(chunk):]...), err
+		}
+		if len(unprocessed) > 0 {
+			return append(unprocessed, chargebacks[start+len(chunk):]...),
+				fmt.Errorf("failed to save chargebacks: %d items unprocessed after %d attempts", len(unprocessed), batchWriteAttempts)
+		}
+	}
+
+	return nil, nil
+}
+
+// writeChunk writes up to batchWriteSize chargebacks, retrying unprocessed items
+// It returns the chargebacks that were not written
+func writeChunk(ctx context.Context, client DynamoDBAPI, tableName string, retryDelay time.Duration, chunk []*entity.Chargeback, toItem func(*entity.Chargeback) (map[string]types.AttributeValue, error)) ([]*entity.Chargeback, error) {
+	pending := make(map[string]*entity.Chargeback, len(chunk))
+	requests := make([]types.WriteRequest, 0, len(chunk))
+	for _, chargeback := range chunk {
+		if chargeback.ID == "" {
+			chargeback.ID = generateChargebackID()
+		}
+
+		av, err := toItem(chargeback)
+		if err != nil {
+			return chunk, err
+		}
+		pending[chargeback.ID] = chargeback
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+	}
+
+	unprocessed, err := batchWrite(ctx, client, tableName, requests, retryDelay)
+
+	notWritten := make([]*entity.Chargeback, 0, len(unprocessed))
+	for _, request := range unprocessed {
+		if id, ok := request.PutRequest.Item["id"].(*types.AttributeValueMemberS); ok && pending[id.Value] != nil {
+			notWritten = append(notWritten, pending[id.Value])
+		}
+	}
+	if err != nil {
+		return notWritten, fmt.Errorf("failed to save chargebacks: %w", err)
+	}
+	return notWritten, nil
+}
+
+// batchWrite submits the requests with BatchWriteItem, resubmitting the items DynamoDB
+// leaves unprocessed with exponential backoff, and returns the requests not written
+func batchWrite(ctx context.Context, client DynamoDBAPI, tableName string, requests []types.WriteRequest, retryDelay time.Duration) ([]types.WriteRequest, error) {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		output, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{tableName: requests},
+		})
+		if err != nil {
+			return requests, err
+		}
+
+		requests = output.UnprocessedItems[tableName]
+		if len(requests) == 0 || attempt == batchWriteAttempts {
+			return requests, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return requests, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
 // FindByID retrieves a chargeback by its unique identifier
 func (r *DynamoDBChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
@@ -109,7 +224,7 @@ func (r *DynamoDBChargebackRepository) FindByID(ctx context.Context, id string)
 		return nil, fmt.Errorf("failed to get chargeback: %w", err)
 	}
 
-	if result.Item == nil {
+	if result.Item == nil || !isActiveItem(result.Item) {
 		return nil, nil // Not found
 	}
 
@@ -125,24 +240,25 @@ func (r *DynamoDBChargebackRepository) FindByID(ctx context.Context, id string)
 func (r *DynamoDBChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
 	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("transaction-id-index"), // GSI on transaction_id
+		IndexName:              aws.String(db.TransactionIDIndex),
 		KeyConditionExpression: aws.String("transaction_id = :tid"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":tid": &types.AttributeValueMemberS{Value: transactionID},
 		},
-		Limit: aws.Int32(1), // We expect only one chargeback per transaction
+		// No limit: a deleted chargeback may share the transaction with an active one
 	})
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to query chargeback by transaction ID: %w", err)
 	}
 
-	if len(result.Items) == 0 {
+	items := activeItems(result.Items)
+	if len(items) == 0 {
 		return nil, nil // Not found
 	}
 
 	var item chargebackItem
-	if err := attributevalue.UnmarshalMap(result.Items[0], &item); err != nil {
+	if err := attributevalue.UnmarshalMap(items[0], &item); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
 	}
 
@@ -153,7 +269,7 @@ func (r *DynamoDBChargebackRepository) FindByTransactionID(ctx context.Context,
 func (r *DynamoDBChargebackRepository) FindByMerchantID(ctx context.Context, merchantID string) ([]*entity.Chargeback, error) {
 	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("merchant-id-index"), // GSI on merchant_id
+		IndexName:              aws.String(db.MerchantIDIndex),
 		KeyConditionExpression: aws.String("merchant_id = :mid"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
 			":mid": &types.AttributeValueMemberS{Value: merchantID},
@@ -165,7 +281,7 @@ func (r *DynamoDBChargebackRepository) FindByMerchantID(ctx context.Context, mer
 	}
 
 	chargebacks := make([]*entity.Chargeback, 0, len(result.Items))
-	for _, item := range result.Items {
+	for _, item := range activeItems(result.Items) {
 		var chargebackItem chargebackItem
 		if err := attributevalue.UnmarshalMap(item, &chargebackItem); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
@@ -180,56 +296,104 @@ func (r *DynamoDBChargebackRepository) FindByMerchantID(ctx context.Context, mer
 func (r *DynamoDBChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
 	chargeback.UpdatedAt = time.Now()
 
-	item := chargebackItem{
-		ID:              chargeback.ID,
-		TransactionID:   chargeback.TransactionID,
-		MerchantID:      chargeback.MerchantID,
-		Amount:          chargeback.Amount,
-		Currency:        chargeback.Currency,
-		CardNumber:      chargeback.CardNumber,
-		Reason:          string(chargeback.Reason),
-		Status:          string(chargeback.Status),
-		Description:     chargeback.Description,
-		TransactionDate: chargeback.TransactionDate,
-		ChargebackDate:  chargeback.ChargebackDate,
-		CreatedAt:       chargeback.CreatedAt,
-		UpdatedAt:       chargeback.UpdatedAt,
-	}
-
-	av, err := attributevalue.MarshalMap(item)
+	av, err := r.entityToItem(chargeback)
 	if err != nil {
-		return fmt.Errorf("failed to marshal chargeback: %w", err)
+		return err
 	}
 
 	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(r.tableName),
 		Item:      av,
-		// Condition to ensure the item exists
-		ConditionExpression: aws.String("attribute_exists(id)"),
+		// Condition to ensure the item exists and isn't deleted
+		ConditionExpression: aws.String("attribute_exists(id) AND " + activeItemCondition),
 	})
 
 	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to update chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackNotFound, err)
+		}
 		return fmt.Errorf("failed to update chargeback: %w", err)
 	}
 
 	return nil
 }
 
-// Delete removes a chargeback from DynamoDB
+// Delete soft deletes a chargeback, setting its deleted_at
 func (r *DynamoDBChargebackRepository) Delete(ctx context.Context, id string) error {
-	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(r.tableName),
-		Key: map[string]types.AttributeValue{
-			"id": &types.AttributeValueMemberS{Value: id},
-		},
-		// Condition to ensure the item exists
-		ConditionExpression: aws.String("attribute_exists(id)"),
-	})
+	return deleteChargebackItem(ctx, r.client, r.tableName, id, chargebackIDKey(id), "id")
+}
 
+// Restore brings back a soft deleted chargeback, removing its deleted_at
+func (r *DynamoDBChargebackRepository) Restore(ctx context.Context, id string) error {
+	return restoreChargebackItem(ctx, r.client, r.tableName, id, chargebackIDKey(id))
+}
+
+// Purge sets the expires_at of the chargebacks, so the table's TTL removes them
+func (r *DynamoDBChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	return purgeChargebackItems(ctx, r.client, r.tableName, chargebacks, chargebackIDKey, "id")
+}
+
+// deleteChargebackItem sets the deleted_at of the chargeback item with the key, unless
+// it is missing, already deleted or purged
+// keyAttribute is the partition key of the table, which only existing items have.
+func deleteChargebackItem(ctx context.Context, client DynamoDBAPI, tableName, id string, key map[string]types.AttributeValue, keyAttribute string) error {
+	deletedAt, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete chargeback: %w", err)
+	}
+
+	_, err = client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(tableName),
+		Key:                       key,
+		UpdateExpression:          aws.String("SET deleted_at = :deleted_at"),
+		ConditionExpression:       aws.String("attribute_exists(" + keyAttribute + ") AND " + activeItemCondition),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":deleted_at": deletedAt},
+	})
 	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to delete chargeback %s: %w: %w", id, repository.ErrChargebackNotFound, err)
+		}
 		return fmt.Errorf("failed to delete chargeback: %w", err)
 	}
+	return nil
+}
 
+// restoreChargebackItem removes the deleted_at of the chargeback item with the key,
+// unless it isn't deleted or is purged
+func restoreChargebackItem(ctx context.Context, client DynamoDBAPI, tableName, id string, key map[string]types.AttributeValue) error {
+	_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(tableName),
+		Key:                 key,
+		UpdateExpression:    aws.String("REMOVE deleted_at"),
+		ConditionExpression: aws.String(deletedItemCondition),
+	})
+	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to restore chargeback %s: %w: %w", id, repository.ErrChargebackNotFound, err)
+		}
+		return fmt.Errorf("failed to restore chargeback: %w", err)
+	}
+	return nil
+}
+
+// purgeChargebackItems sets the expires_at of the chargeback items to now, skipping the
+// items that are gone
+// DynamoDB removes expired items in the background, typically within a few days; reads
+// hide them in the meantime.
+func purgeChargebackItems(ctx context.Context, client DynamoDBAPI, tableName string, chargebacks []*entity.Chargeback, keyOf func(id string) map[string]types.AttributeValue, keyAttribute string) error {
+	expiresAt := &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)}
+	for _, chargeback := range chargebacks {
+		_, err := client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(tableName),
+			Key:                       keyOf(chargeback.ID),
+			UpdateExpression:          aws.String("SET expires_at = :expires_at"),
+			ConditionExpression:       aws.String("attribute_exists(" + keyAttribute + ")"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":expires_at": expiresAt},
+		})
+		if err != nil && !isConditionFailed(err) {
+			return fmt.Errorf("failed to purge chargeback %s: %w", chargeback.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -237,7 +401,7 @@ func (r *DynamoDBChargebackRepository) Delete(ctx context.Context, id string) er
 func (r *DynamoDBChargebackRepository) FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error) {
 	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(r.tableName),
-		IndexName:              aws.String("status-index"), // GSI on status
+		IndexName:              aws.String(db.StatusIndex),
 		KeyConditionExpression: aws.String("#status = :status"),
 		ExpressionAttributeNames: map[string]string{
 			"#status": "status", // status is a reserved word
@@ -252,7 +416,7 @@ func (r *DynamoDBChargebackRepository) FindByStatus(ctx context.Context, status
 	}
 
 	chargebacks := make([]*entity.Chargeback, 0, len(result.Items))
-	for _, item := range result.Items {
+	for _, item := range activeItems(result.Items) {
 		var chargebackItem chargebackItem
 		if err := attributevalue.UnmarshalMap(item, &chargebackItem); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
@@ -288,7 +452,7 @@ func (r *DynamoDBChargebackRepository) List(ctx context.Context, offset, limit i
 				return nil, fmt.Errorf("failed to scan chargebacks: %w", err)
 			}
 
-			scannedItems = append(scannedItems, result.Items...)
+			scannedItems = append(scannedItems, activeItems(result.Items)...)
 			lastEvaluatedKey = result.LastEvaluatedKey
 
 			if lastEvaluatedKey == nil {
@@ -327,7 +491,7 @@ func (r *DynamoDBChargebackRepository) List(ctx context.Context, offset, limit i
 	}
 
 	chargebacks := make([]*entity.Chargeback, 0, len(result.Items))
-	for _, item := range result.Items {
+	for _, item := range activeItems(result.Items) {
 		var chargebackItem chargebackItem
 		if err := attributevalue.UnmarshalMap(item, &chargebackItem); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
@@ -338,9 +502,205 @@ func (r *DynamoDBChargebackRepository) List(ctx context.Context, offset, limit i
 	return chargebacks, nil
 }
 
+// ListPage retrieves a page of chargebacks matching the filter
+// A single merchant or a status is queried through its index, anything else is scanned;
+// the remaining criteria are applied to the items read, so a page may hold fewer than
+// limit chargebacks. The cursor is the encoded key the page stopped at.
+func (r *DynamoDBChargebackRepository) ListPage(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]types.AttributeValue
+	var lastKey map[string]types.AttributeValue
+	switch {
+	case len(filter.MerchantIDs) == 1:
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(db.MerchantIDIndex),
+			KeyConditionExpression: aws.String("merchant_id = :mid"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":mid": &types.AttributeValueMemberS{Value: filter.MerchantIDs[0]},
+			},
+			ExclusiveStartKey: startKey,
+			Limit:             aws.Int32(int32(limit)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query chargebacks by merchant ID: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	case filter.Status != "":
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(db.StatusIndex),
+			KeyConditionExpression: aws.String("#status = :status"),
+			ExpressionAttributeNames: map[string]string{
+				"#status": "status", // status is a reserved word
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":status": &types.AttributeValueMemberS{Value: string(filter.Status)},
+			},
+			ExclusiveStartKey: startKey,
+			Limit:             aws.Int32(int32(limit)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query chargebacks by status: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	default:
+		result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(r.tableName),
+			ExclusiveStartKey: startKey,
+			Limit:             aws.Int32(int32(limit)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chargebacks: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	}
+
+	page := &repository.ChargebackPage{Chargebacks: make([]*entity.Chargeback, 0, len(items))}
+	for _, item := range items {
+		if isPurgedItem(item) {
+			continue
+		}
+		var chargebackItem chargebackItem
+		if err := attributevalue.UnmarshalMap(item, &chargebackItem); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
+		}
+		if chargeback := r.itemToEntity(&chargebackItem); filter.Matches(chargeback) {
+			page.Chargebacks = append(page.Chargebacks, chargeback)
+		}
+	}
+
+	if page.NextCursor, err = encodeCursor(lastKey); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// isPurgedItem reports whether a chargeback item was purged and awaits removal by the
+// table's TTL
+func isPurgedItem(item map[string]types.AttributeValue) bool {
+	_, purged := item["expires_at"]
+	return purged
+}
+
+// isActiveItem reports whether a chargeback item is neither soft deleted nor purged
+func isActiveItem(item map[string]types.AttributeValue) bool {
+	_, deleted := item["deleted_at"]
+	return !deleted && !isPurgedItem(item)
+}
+
+// activeItems returns the chargeback items that are neither soft deleted nor purged
+func activeItems(items []map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	active := make([]map[string]types.AttributeValue, 0, len(items))
+	for _, item := range items {
+		if isActiveItem(item) {
+			active = append(active, item)
+		}
+	}
+	return active
+}
+
+// isConditionFailed reports whether a write was rejected by its condition expression
+func isConditionFailed(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionFailed)
+}
+
+// encodeCursor encodes the key a page stopped at, whose attributes are all strings
+func encodeCursor(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	values := make(map[string]string, len(key))
+	for name, value := range key {
+		s, ok := value.(*types.AttributeValueMemberS)
+		if !ok {
+			return "", fmt.Errorf("failed to encode cursor: key attribute %s is not a string", name)
+		}
+		values[name] = s.Value
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes a cursor into the key to resume after
+func decodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, repository.ErrInvalidCursor
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil || len(values) == 0 {
+		return nil, repository.ErrInvalidCursor
+	}
+
+	key := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return key, nil
+}
+
+// chargebackIDKey returns the primary key of a chargeback item in the flat table
+func chargebackIDKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+// entityToItem converts a domain entity to a DynamoDB item
+func (r *DynamoDBChargebackRepository) entityToItem(chargeback *entity.Chargeback) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(newChargebackItem(chargeback))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chargeback: %w", err)
+	}
+	return av, nil
+}
+
 // itemToEntity converts a DynamoDB item to a domain entity
 func (r *DynamoDBChargebackRepository) itemToEntity(item *chargebackItem) *entity.Chargeback {
-	return &entity.Chargeback{
+	return item.toEntity()
+}
+
+// newChargebackItem copies a chargeback into its item attributes
+func newChargebackItem(chargeback *entity.Chargeback) chargebackItem {
+	item := chargebackItem{
+		ID:              chargeback.ID,
+		TransactionID:   chargeback.TransactionID,
+		MerchantID:      chargeback.MerchantID,
+		Amount:          chargeback.Amount,
+		Currency:        chargeback.Currency,
+		CardNumber:      chargeback.CardNumber,
+		Reason:          string(chargeback.Reason),
+		Status:          string(chargeback.Status),
+		Description:     chargeback.Description,
+		TransactionDate: chargeback.TransactionDate,
+		ChargebackDate:  chargeback.ChargebackDate,
+		CreatedAt:       chargeback.CreatedAt,
+		UpdatedAt:       chargeback.UpdatedAt,
+	}
+	if chargeback.IsDeleted() {
+		item.DeletedAt = &chargeback.DeletedAt
+	}
+	return item
+}
+
+// toEntity converts the item attributes to a domain entity
+func (item *chargebackItem) toEntity() *entity.Chargeback {
+	chargeback := &entity.Chargeback{
 		ID:              item.ID,
 		TransactionID:   item.TransactionID,
 		MerchantID:      item.MerchantID,
@@ -355,9 +715,18 @@ func (r *DynamoDBChargebackRepository) itemToEntity(item *chargebackItem) *entit
 		CreatedAt:       item.CreatedAt,
 		UpdatedAt:       item.UpdatedAt,
 	}
+	if item.DeletedAt != nil {
+		chargeback.DeletedAt = *item.DeletedAt
+	}
+	return chargeback
 }
 
+// chargebackIDs generates the IDs of chargebacks saved without one
+var chargebackIDs = idgen.NewULIDGenerator()
+
 // generateChargebackID generates a unique ID for a chargeback
+// Chargebacks created through the use cases get their ID from the injected generator;
+// this covers callers saving chargebacks directly.
 func generateChargebackID() string {
-	return fmt.Sprintf("cb_%d", time.Now().UnixNano())
+	return chargebackIDs.NewChargebackID().String()
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +14,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository/repositorytest"
 )
 
 // Unit tests for DynamoDB Chargeback Repository
@@ -20,11 +23,12 @@ import (
 
 // MockDynamoDBAPI implements the DynamoDBAPI interface for testing
 type MockDynamoDBAPI struct {
-	PutItemFunc    func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
-	GetItemFunc    func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
-	QueryFunc      func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
-	DeleteItemFunc func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
-	ScanFunc       func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	PutItemFunc        func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItemFunc        func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	QueryFunc          func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	UpdateItemFunc     func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	ScanFunc           func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItemFunc func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
 }
 
 func (m *MockDynamoDBAPI) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
@@ -48,11 +52,11 @@ func (m *MockDynamoDBAPI) Query(ctx context.Context, params *dynamodb.QueryInput
 	return &dynamodb.QueryOutput{}, nil
 }
 
-func (m *MockDynamoDBAPI) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
-	if m.DeleteItemFunc != nil {
-		return m.DeleteItemFunc(ctx, params, optFns...)
+func (m *MockDynamoDBAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if m.UpdateItemFunc != nil {
+		return m.UpdateItemFunc(ctx, params, optFns...)
 	}
-	return &dynamodb.DeleteItemOutput{}, nil
+	return &dynamodb.UpdateItemOutput{}, nil
 }
 
 func (m *MockDynamoDBAPI) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
@@ -62,6 +66,13 @@ func (m *MockDynamoDBAPI) Scan(ctx context.Context, params *dynamodb.ScanInput,
 	return &dynamodb.ScanOutput{}, nil
 }
 
+func (m *MockDynamoDBAPI) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if m.BatchWriteItemFunc != nil {
+		return m.BatchWriteItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
 func createTestChargeback() *entity.Chargeback {
 	return &entity.Chargeback{
 		ID:              "chargeback-123",
@@ -275,10 +286,26 @@ func TestGenerateChargebackID(t *testing.T) {
 		t.Error("Expected unique IDs")
 	}
 
+	// Test that IDs generated back to back don't collide
+	ids := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := generateChargebackID()
+		if ids[id] {
+			t.Fatalf("Expected unique IDs, got duplicate %s", id)
+		}
+		ids[id] = true
+	}
+
 	// Test ID format (should start with "cb_")
 	if len(id1) < 3 || id1[:3] != "cb_" {
 		t.Errorf("Expected ID to start with 'cb_', got %s", id1)
 	}
+	if _, err := entity.ParseChargebackID(id1); err != nil {
+		t.Errorf("Expected a valid chargeback ID, got %v", err)
+	}
+	if id1 >= id2 {
+		t.Errorf("Expected IDs to sort in generation order, got %s then %s", id1, id2)
+	}
 }
 
 func TestChargebackItemSerialization(t *testing.T) {
@@ -734,8 +761,8 @@ func TestDynamoDBChargebackRepository_Update(t *testing.T) {
 		mockClient := &MockDynamoDBAPI{
 			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
 				// Verify condition expression for update
-				if params.ConditionExpression == nil || *params.ConditionExpression != "attribute_exists(id)" {
-					t.Error("Expected condition to ensure item exists")
+				if aws.ToString(params.ConditionExpression) != "attribute_exists(id) AND attribute_not_exists(deleted_at) AND attribute_not_exists(expires_at)" {
+					t.Error("Expected condition to ensure item exists and isn't deleted")
 				}
 
 				return &dynamodb.PutItemOutput{}, nil
@@ -776,11 +803,139 @@ func TestDynamoDBChargebackRepository_Update(t *testing.T) {
 	})
 }
 
+// Test SaveBatch method
+func TestDynamoDBChargebackRepository_SaveBatch(t *testing.T) {
+	newChargebacks := func(n int) []*entity.Chargeback {
+		chargebacks := make([]*entity.Chargeback, n)
+		for i := range chargebacks {
+			chargebacks[i] = createTestChargeback()
+			chargebacks[i].ID = ""
+		}
+		return chargebacks
+	}
+
+	t.Run("splits into requests of 25 and assigns IDs", func(t *testing.T) {
+		var sizes []int
+		mockClient := &MockDynamoDBAPI{
+			BatchWriteItemFunc: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				sizes = append(sizes, len(params.RequestItems["test-chargebacks"]))
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+
+		repo := createTestRepository(mockClient)
+		chargebacks := newChargebacks(60)
+
+		unprocessed, err := repo.SaveBatch(context.Background(), chargebacks)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(unprocessed) != 0 {
+			t.Errorf("Expected no unprocessed chargebacks, got %d", len(unprocessed))
+		}
+		if len(sizes) != 3 || sizes[0] != 25 || sizes[1] != 25 || sizes[2] != 10 {
+			t.Errorf("Expected requests of 25, 25 and 10 items, got %v", sizes)
+		}
+
+		ids := make(map[string]bool)
+		for _, chargeback := range chargebacks {
+			if chargeback.ID == "" || ids[chargeback.ID] {
+				t.Fatalf("Expected unique IDs, got '%s'", chargeback.ID)
+			}
+			ids[chargeback.ID] = true
+		}
+	})
+
+	t.Run("retries unprocessed items", func(t *testing.T) {
+		calls := 0
+		mockClient := &MockDynamoDBAPI{
+			BatchWriteItemFunc: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				calls++
+				requests := params.RequestItems["test-chargebacks"]
+				if calls == 1 {
+					return &dynamodb.BatchWriteItemOutput{
+						UnprocessedItems: map[string][]types.WriteRequest{"test-chargebacks": requests[1:]},
+					}, nil
+				}
+				if len(requests) != 2 {
+					t.Errorf("Expected 2 resubmitted items, got %d", len(requests))
+				}
+				return &dynamodb.BatchWriteItemOutput{}, nil
+			},
+		}
+
+		repo := createTestRepository(mockClient)
+		repo.retryDelay = 0
+
+		unprocessed, err := repo.SaveBatch(context.Background(), newChargebacks(3))
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(unprocessed) != 0 {
+			t.Errorf("Expected no unprocessed chargebacks, got %d", len(unprocessed))
+		}
+		if calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("gives up after repeated throttling", func(t *testing.T) {
+		calls := 0
+		mockClient := &MockDynamoDBAPI{
+			BatchWriteItemFunc: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				calls++
+				requests := params.RequestItems["test-chargebacks"]
+				return &dynamodb.BatchWriteItemOutput{
+					UnprocessedItems: map[string][]types.WriteRequest{"test-chargebacks": requests[:1]},
+				}, nil
+			},
+		}
+
+		repo := createTestRepository(mockClient)
+		repo.retryDelay = 0
+		chargebacks := newChargebacks(30)
+
+		unprocessed, err := repo.SaveBatch(context.Background(), chargebacks)
+
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if calls != batchWriteAttempts {
+			t.Errorf("Expected %d calls, got %d", batchWriteAttempts, calls)
+		}
+		// The throttled item plus the 5 items of the chunk that was never sent
+		if len(unprocessed) != 6 || unprocessed[0] != chargebacks[0] {
+			t.Errorf("Expected the throttled and unsent chargebacks, got %d", len(unprocessed))
+		}
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		mockClient := &MockDynamoDBAPI{
+			BatchWriteItemFunc: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+				return nil, errors.New("DynamoDB error")
+			},
+		}
+
+		repo := createTestRepository(mockClient)
+
+		unprocessed, err := repo.SaveBatch(context.Background(), newChargebacks(3))
+
+		if err == nil || !strings.Contains(err.Error(), "failed to save chargebacks") {
+			t.Errorf("Expected error to contain 'failed to save chargebacks', got %v", err)
+		}
+		if len(unprocessed) != 3 {
+			t.Errorf("Expected 3 unprocessed chargebacks, got %d", len(unprocessed))
+		}
+	})
+}
+
 // Test Delete method
 func TestDynamoDBChargebackRepository_Delete(t *testing.T) {
 	t.Run("successful delete", func(t *testing.T) {
 		mockClient := &MockDynamoDBAPI{
-			DeleteItemFunc: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
 				// Verify table name
 				if *params.TableName != "test-chargebacks" {
 					t.Errorf("Expected table name 'test-chargebacks', got %s", *params.TableName)
@@ -791,12 +946,18 @@ func TestDynamoDBChargebackRepository_Delete(t *testing.T) {
 					t.Error("Expected 'id' in key")
 				}
 
+				// Verify the item is soft deleted rather than removed
+				if aws.ToString(params.UpdateExpression) != "SET deleted_at = :deleted_at" {
+					t.Errorf("Expected deleted_at to be set, got %q", aws.ToString(params.UpdateExpression))
+				}
+
 				// Verify condition expression
-				if params.ConditionExpression == nil || *params.ConditionExpression != "attribute_exists(id)" {
-					t.Error("Expected condition to ensure item exists")
+				expected := "attribute_exists(id) AND attribute_not_exists(deleted_at) AND attribute_not_exists(expires_at)"
+				if aws.ToString(params.ConditionExpression) != expected {
+					t.Errorf("Expected condition %q, got %q", expected, aws.ToString(params.ConditionExpression))
 				}
 
-				return &dynamodb.DeleteItemOutput{}, nil
+				return &dynamodb.UpdateItemOutput{}, nil
 			},
 		}
 
@@ -812,7 +973,7 @@ func TestDynamoDBChargebackRepository_Delete(t *testing.T) {
 
 	t.Run("delete error", func(t *testing.T) {
 		mockClient := &MockDynamoDBAPI{
-			DeleteItemFunc: func(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
 				return nil, errors.New("DynamoDB error")
 			},
 		}
@@ -832,6 +993,54 @@ func TestDynamoDBChargebackRepository_Delete(t *testing.T) {
 	})
 }
 
+// Test Purge method
+func TestDynamoDBChargebackRepository_Purge(t *testing.T) {
+	t.Run("sets the TTL attribute of each chargeback", func(t *testing.T) {
+		var purged []string
+		mockClient := &MockDynamoDBAPI{
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				expiresAt, ok := params.ExpressionAttributeValues[":expires_at"].(*types.AttributeValueMemberN)
+				if aws.ToString(params.UpdateExpression) != "SET expires_at = :expires_at" || !ok {
+					t.Errorf("Expected expires_at to be set to a number, got %q", aws.ToString(params.UpdateExpression))
+				} else if seconds, _ := strconv.ParseInt(expiresAt.Value, 10, 64); seconds > time.Now().Unix() {
+					t.Errorf("Expected expires_at not to be in the future, got %s", expiresAt.Value)
+				}
+				id := params.Key["id"].(*types.AttributeValueMemberS).Value
+				purged = append(purged, id)
+				if id == "cb_gone" {
+					return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+				}
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		repo := createTestRepository(mockClient)
+
+		err := repo.Purge(context.Background(), []*entity.Chargeback{{ID: "cb_gone"}, createTestChargeback()})
+
+		if err != nil {
+			t.Errorf("Expected chargebacks that are gone to be skipped, got %v", err)
+		}
+		if strings.Join(purged, ",") != "cb_gone,chargeback-123" {
+			t.Errorf("Expected both chargebacks to be purged, got %v", purged)
+		}
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		mockClient := &MockDynamoDBAPI{
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				return nil, errors.New("DynamoDB error")
+			},
+		}
+		repo := createTestRepository(mockClient)
+
+		err := repo.Purge(context.Background(), []*entity.Chargeback{createTestChargeback()})
+
+		if err == nil || !strings.Contains(err.Error(), "failed to purge chargeback chargeback-123") {
+			t.Errorf("Expected purge error, got %v", err)
+		}
+	})
+}
+
 // Test FindByTransactionID method
 func TestDynamoDBChargebackRepository_FindByTransactionID(t *testing.T) {
 	t.Run("successful find", func(t *testing.T) {
@@ -1242,3 +1451,128 @@ func TestDynamoDBChargebackRepository_List(t *testing.T) {
 		}
 	})
 }
+
+func TestDynamoDBChargebackRepository_ListPage(t *testing.T) {
+	chargeback := createTestChargeback()
+	item, err := NewDynamoDBChargebackRepositoryWithInterface(nil, "test").entityToItem(chargeback)
+	if err != nil {
+		t.Fatalf("Failed to marshal chargeback: %v", err)
+	}
+	disputed := createTestChargeback()
+	disputed.Reason = entity.ReasonConsumerDispute
+	disputedItem, _ := NewDynamoDBChargebackRepositoryWithInterface(nil, "test").entityToItem(disputed)
+
+	t.Run("queries the merchant index and resumes from the cursor", func(t *testing.T) {
+		// Arrange
+		var inputs []*dynamodb.QueryInput
+		mockClient := &MockDynamoDBAPI{
+			QueryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				inputs = append(inputs, params)
+				if params.ExclusiveStartKey == nil {
+					return &dynamodb.QueryOutput{
+						Items: []map[string]types.AttributeValue{item, disputedItem},
+						LastEvaluatedKey: map[string]types.AttributeValue{
+							"id":          &types.AttributeValueMemberS{Value: chargeback.ID},
+							"merchant_id": &types.AttributeValueMemberS{Value: chargeback.MerchantID},
+						},
+					}, nil
+				}
+				return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil
+			},
+		}
+		repo := createTestRepository(mockClient)
+		filter := repository.ChargebackFilter{MerchantIDs: []string{"merchant-789"}, Reason: entity.ReasonFraud}
+
+		// Act
+		first, err := repo.ListPage(context.Background(), filter, "", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		second, err := repo.ListPage(context.Background(), filter, first.NextCursor, 2)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if aws.ToString(inputs[0].IndexName) != "merchant-id-index" || aws.ToInt32(inputs[0].Limit) != 2 {
+			t.Errorf("Unexpected query %+v", inputs[0])
+		}
+		if len(first.Chargebacks) != 1 || first.Chargebacks[0].Reason != entity.ReasonFraud || first.NextCursor == "" {
+			t.Errorf("Expected one matching chargeback and a cursor, got %+v", first)
+		}
+		startKey, ok := inputs[1].ExclusiveStartKey["merchant_id"].(*types.AttributeValueMemberS)
+		if !ok || startKey.Value != "merchant-789" {
+			t.Errorf("Expected the second query to resume after the first page, got %v", inputs[1].ExclusiveStartKey)
+		}
+		if len(second.Chargebacks) != 1 || second.NextCursor != "" {
+			t.Errorf("Expected the last page, got %+v", second)
+		}
+	})
+
+	t.Run("queries the status index", func(t *testing.T) {
+		// Arrange
+		var index string
+		mockClient := &MockDynamoDBAPI{
+			QueryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+				index = aws.ToString(params.IndexName)
+				return &dynamodb.QueryOutput{}, nil
+			},
+		}
+		repo := createTestRepository(mockClient)
+
+		// Act
+		_, err := repo.ListPage(context.Background(), repository.ChargebackFilter{Status: entity.StatusPending}, "", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if index != "status-index" {
+			t.Errorf("Expected status-index, got %s", index)
+		}
+	})
+
+	t.Run("scans without an indexed criterion", func(t *testing.T) {
+		// Arrange
+		mockClient := &MockDynamoDBAPI{
+			ScanFunc: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+				return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{item}}, nil
+			},
+		}
+		repo := createTestRepository(mockClient)
+		filter := repository.ChargebackFilter{
+			MerchantIDs: []string{"merchant-1", "merchant-789"},
+			CreatedFrom: chargeback.CreatedAt.Add(time.Hour),
+		}
+
+		// Act
+		page, err := repo.ListPage(context.Background(), filter, "", 10)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Chargebacks) != 0 {
+			t.Errorf("Expected chargebacks created before the range to be left out, got %d", len(page.Chargebacks))
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		// Arrange
+		repo := createTestRepository(&MockDynamoDBAPI{})
+
+		// Act
+		_, err := repo.ListPage(context.Background(), repository.ChargebackFilter{}, "not a cursor", 10)
+
+		// Assert
+		if !errors.Is(err, repository.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestDynamoDBChargebackRepository_Contract(t *testing.T) {
+	repositorytest.TestChargebackRepository(t, func(t *testing.T) repository.ChargebackRepository {
+		return NewDynamoDBChargebackRepositoryWithInterface(newFakeDynamoDB("id"), "chargebacks")
+	})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBLayoutMigrator rewrites the chargebacks of a flat table as single-table items
+// DynamoDB can't change the key schema of a table, so the items are written to a table in
+// the single-table layout and the flat table is left untouched. Rewritten items are put
+// under the chargeback's key, so copying a page again overwrites the same items; this
+// also overwrites later changes made through the single table, so the migration must
+// complete before the API is switched to it. Soft deleted chargebacks are copied as
+// deleted; purged ones are skipped.
+type DynamoDBLayoutMigrator struct {
+	client      DynamoDBAPI
+	sourceTable string
+	targetTable string
+
+	// retryDelay is the initial backoff before resubmitting unprocessed items
+	retryDelay time.Duration
+}
+
+// NewDynamoDBLayoutMigrator creates a migrator from the flat source table to the
+// single-table target table
+func NewDynamoDBLayoutMigrator(client DynamoDBAPI, sourceTable, targetTable string) *DynamoDBLayoutMigrator {
+	return &DynamoDBLayoutMigrator{
+		client:      client,
+		sourceTable: sourceTable,
+		targetTable: targetTable,
+		retryDelay:  50 * time.Millisecond,
+	}
+}
+
+// MigratePage rewrites up to limit chargebacks after the cursor
+// It returns how many chargebacks were written and the cursor to resume after, which is
+// empty once the source table has been read to the end.
+func (m *DynamoDBLayoutMigrator) MigratePage(ctx context.Context, cursor string, limit int) (int, string, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return 0, "", err
+	}
+
+	result, err := m.client.Scan(ctx, &dynamodb.ScanInput{
+		TableName:         aws.String(m.sourceTable),
+		ExclusiveStartKey: startKey,
+		Limit:             aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to scan table '%s': %w", m.sourceTable, err)
+	}
+
+	requests := make([]types.WriteRequest, 0, len(result.Items))
+	for _, av := range result.Items {
+		// Purged chargebacks are left for the TTL of the source table
+		if isPurgedItem(av) {
+			continue
+		}
+		var item chargebackItem
+		if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+			return 0, "", fmt.Errorf("failed to unmarshal chargeback: %w", err)
+		}
+		rewritten, err := attributevalue.MarshalMap(newSingleTableChargebackItem(item.toEntity()))
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to marshal chargeback %s: %w", item.ID, err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: rewritten}})
+	}
+
+	written := 0
+	for start := 0; start < len(requests); start += batchWriteSize {
+		chunk := requests[start:min(start+batchWriteSize, len(requests))]
+
+		unprocessed, err := batchWrite(ctx, m.client, m.targetTable, chunk, m.retryDelay)
+		written += len(chunk) - len(unprocessed)
+		if err != nil {
+			return written, "", fmt.Errorf("failed to write to table '%s': %w", m.targetTable, err)
+		}
+		if len(unprocessed) > 0 {
+			return written, "", fmt.Errorf("failed to write to table '%s': %d items unprocessed after %d attempts",
+				m.targetTable, len(unprocessed), batchWriteAttempts)
+		}
+	}
+
+	next, err := encodeCursor(result.LastEvaluatedKey)
+	if err != nil {
+		return written, "", err
+	}
+	return written, next, nil
+}
+
+// NewestUpdates returns when the most recently updated chargebacks of the source and the
+// target tables were updated, zero for an empty table
+// The target only holds copies of source items while the migration runs, so a newer
+// target means chargebacks were written to it directly, e.g. by an API switched to it,
+// and copying again would overwrite them.
+func (m *DynamoDBLayoutMigrator) NewestUpdates(ctx context.Context) (source, target time.Time, err error) {
+	if source, err = m.newestUpdate(ctx, m.sourceTable); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if target, err = m.newestUpdate(ctx, m.targetTable); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return source, target, nil
+}
+
+// newestUpdate scans the updated_at attribute of every item of the table
+func (m *DynamoDBLayoutMigrator) newestUpdate(ctx context.Context, table string) (time.Time, error) {
+	var (
+		newest   time.Time
+		startKey map[string]types.AttributeValue
+	)
+	for {
+		result, err := m.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:            aws.String(table),
+			ProjectionExpression: aws.String("updated_at"),
+			ExclusiveStartKey:    startKey,
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to scan table '%s': %w", table, err)
+		}
+		for _, av := range result.Items {
+			var item struct {
+				UpdatedAt time.Time `dynamodbav:"updated_at"`
+			}
+			if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+				return time.Time{}, fmt.Errorf("failed to unmarshal item of table '%s': %w", table, err)
+			}
+			if item.UpdatedAt.After(newest) {
+				newest = item.UpdatedAt
+			}
+		}
+		if len(result.LastEvaluatedKey) == 0 {
+			return newest, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+func TestDynamoDBLayoutMigrator_MigratePage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rewrites every flat chargeback page by page", func(t *testing.T) {
+		// Arrange
+		tables := fakeDynamoDBTables{
+			"chargebacks":       newFakeDynamoDB("id"),
+			"chargebacks-table": newFakeDynamoDB("PK", "SK"),
+		}
+		flat := NewDynamoDBChargebackRepositoryWithInterface(tables, "chargebacks")
+		chargebacks := make([]*entity.Chargeback, 0, 30)
+		for i := 0; i < 30; i++ {
+			chargeback := createTestChargeback()
+			chargeback.ID = fmt.Sprintf("chargeback-%02d", i)
+			chargeback.TransactionID = fmt.Sprintf("txn-%02d", i)
+			chargebacks = append(chargebacks, chargeback)
+		}
+		if _, err := flat.SaveBatch(ctx, chargebacks); err != nil {
+			t.Fatalf("Failed to save chargebacks: %v", err)
+		}
+		migrator := NewDynamoDBLayoutMigrator(tables, "chargebacks", "chargebacks-table")
+
+		// Act
+		written, pages, cursor := 0, 0, ""
+		for {
+			n, next, err := migrator.MigratePage(ctx, cursor, 7)
+			if err != nil {
+				t.Fatalf("MigratePage failed: %v", err)
+			}
+			written, pages, cursor = written+n, pages+1, next
+			if cursor == "" {
+				break
+			}
+		}
+
+		// Assert
+		if written != 30 || pages != 5 {
+			t.Errorf("Expected 30 chargebacks in 5 pages, got %d in %d", written, pages)
+		}
+		single := NewDynamoDBSingleTableChargebackRepositoryWithInterface(tables, "chargebacks-table")
+		byMerchant, err := single.FindByMerchantID(ctx, "merchant-789")
+		if err != nil || len(byMerchant) != 30 {
+			t.Fatalf("Expected 30 chargebacks in the single table, got %d (%v)", len(byMerchant), err)
+		}
+		found, err := single.FindByTransactionID(ctx, "txn-07")
+		if err != nil || found == nil || found.ID != "chargeback-07" || found.Amount != 99.99 || !found.CreatedAt.Equal(chargebacks[7].CreatedAt) {
+			t.Errorf("Expected chargeback-07 with its attributes, got %+v (%v)", found, err)
+		}
+		if len(tables["chargebacks"].items) != 30 {
+			t.Errorf("Expected the flat table to be left untouched, got %d items", len(tables["chargebacks"].items))
+		}
+	})
+
+	t.Run("copying a page again overwrites the same items", func(t *testing.T) {
+		tables := fakeDynamoDBTables{"chargebacks": newFakeDynamoDB("id"), "target": newFakeDynamoDB("PK", "SK")}
+		if err := NewDynamoDBChargebackRepositoryWithInterface(tables, "chargebacks").Save(ctx, createTestChargeback()); err != nil {
+			t.Fatalf("Failed to save chargeback: %v", err)
+		}
+		migrator := NewDynamoDBLayoutMigrator(tables, "chargebacks", "target")
+
+		for i := 0; i < 2; i++ {
+			if _, _, err := migrator.MigratePage(ctx, "", 10); err != nil {
+				t.Fatalf("MigratePage failed: %v", err)
+			}
+		}
+
+		if len(tables["target"].items) != 1 {
+			t.Errorf("Expected a single item, got %d", len(tables["target"].items))
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		migrator := NewDynamoDBLayoutMigrator(fakeDynamoDBTables{}, "chargebacks", "target")
+
+		_, _, err := migrator.MigratePage(ctx, "not a cursor", 10)
+
+		if !errors.Is(err, repository.ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+}
+
+func TestDynamoDBLayoutMigrator_NewestUpdates(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	tables := fakeDynamoDBTables{"chargebacks": newFakeDynamoDB("id"), "target": newFakeDynamoDB("PK", "SK")}
+	chargeback := createTestChargeback()
+	if err := NewDynamoDBChargebackRepositoryWithInterface(tables, "chargebacks").Save(ctx, chargeback); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	migrator := NewDynamoDBLayoutMigrator(tables, "chargebacks", "target")
+
+	// Act
+	emptySource, emptyTarget, errEmpty := migrator.NewestUpdates(ctx)
+	if _, _, err := migrator.MigratePage(ctx, "", 10); err != nil {
+		t.Fatalf("MigratePage failed: %v", err)
+	}
+	copiedSource, copiedTarget, errCopied := migrator.NewestUpdates(ctx)
+	if err := NewDynamoDBSingleTableChargebackRepositoryWithInterface(tables, "target").Update(ctx, chargeback); err != nil {
+		t.Fatalf("Failed to update chargeback: %v", err)
+	}
+	_, writtenTarget, errWritten := migrator.NewestUpdates(ctx)
+
+	// Assert
+	if errEmpty != nil || errCopied != nil || errWritten != nil {
+		t.Fatalf("Unexpected errors: %v, %v, %v", errEmpty, errCopied, errWritten)
+	}
+	if emptySource.IsZero() || !emptyTarget.IsZero() {
+		t.Errorf("Expected the source's update and none for the empty target, got %v and %v", emptySource, emptyTarget)
+	}
+	if !copiedTarget.Equal(copiedSource) {
+		t.Errorf("Expected copies to keep the source's update, got %v and %v", copiedSource, copiedTarget)
+	}
+	if !writtenTarget.After(copiedSource) {
+		t.Errorf("Expected a write to the target to be newer than the source, got %v and %v", copiedSource, writtenTarget)
+	}
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// MerchantStatsDynamoDBAPI is the subset of the DynamoDB client used by the merchant statistics repository
+type MerchantStatsDynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// DynamoDBMerchantStatsRepository implements MerchantStatsRepository using DynamoDB
+// The table is keyed by merchant_id (partition key) and month (sort key); counters are
+// updated with ADD, so concurrent instances don't lose updates
+type DynamoDBMerchantStatsRepository struct {
+	client    MerchantStatsDynamoDBAPI
+	tableName string
+}
+
+// NewDynamoDBMerchantStatsRepository creates a new DynamoDB merchant statistics repository
+func NewDynamoDBMerchantStatsRepository(client MerchantStatsDynamoDBAPI, tableName string) repository.MerchantStatsRepository {
+	return &DynamoDBMerchantStatsRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// merchantStatsItem represents the DynamoDB item structure for merchant statistics
+type merchantStatsItem struct {
+	MerchantID        string    `dynamodbav:"merchant_id"`
+	Month             string    `dynamodbav:"month"`
+	ChargebackCount   int       `dynamodbav:"chargeback_count"`
+	ChargebackAmount  float64   `dynamodbav:"chargeback_amount"`
+	RejectedCount     int       `dynamodbav:"rejected_count"`
+	RejectedAmount    float64   `dynamodbav:"rejected_amount"`
+	TransactionCount  int       `dynamodbav:"transaction_count"`
+	TransactionAmount float64   `dynamodbav:"transaction_amount"`
+	UpdatedAt         time.Time `dynamodbav:"updated_at"`
+}
+
+// AddChargebacks adds the delta to a merchant's month and returns the updated statistics
+func (r *DynamoDBMerchantStatsRepository) AddChargebacks(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) (*entity.MerchantMonthlyStats, error) {
+	output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(r.tableName),
+		Key:              merchantStatsKey(merchantID, month),
+		UpdateExpression: aws.String("ADD chargeback_count :cc, chargeback_amount :ca, rejected_count :rc, rejected_amount :ra SET updated_at = :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":cc":  numberValue(float64(delta.ChargebackCount)),
+			":ca":  numberValue(delta.ChargebackAmount),
+			":rc":  numberValue(float64(delta.RejectedCount)),
+			":ra":  numberValue(delta.RejectedAmount),
+			":now": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339Nano)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merchant statistics: %w", err)
+	}
+
+	return itemToMerchantStats(output.Attributes)
+}
+
+// SetTransactionVolume replaces a merchant's sales for a month and returns the updated statistics
+func (r *DynamoDBMerchantStatsRepository) SetTransactionVolume(ctx context.Context, merchantID, month string, count int, amount float64) (*entity.MerchantMonthlyStats, error) {
+	output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(r.tableName),
+		Key:              merchantStatsKey(merchantID, month),
+		UpdateExpression: aws.String("SET transaction_count = :tc, transaction_amount = :ta, updated_at = :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":tc":  numberValue(float64(count)),
+			":ta":  numberValue(amount),
+			":now": &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339Nano)},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transaction volume: %w", err)
+	}
+
+	return itemToMerchantStats(output.Attributes)
+}
+
+// Find retrieves a merchant's statistics for a month, nil when there are none
+func (r *DynamoDBMerchantStatsRepository) Find(ctx context.Context, merchantID, month string) (*entity.MerchantMonthlyStats, error) {
+	output, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       merchantStatsKey(merchantID, month),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant statistics: %w", err)
+	}
+
+	if output.Item == nil {
+		return nil, nil // Not found
+	}
+	return itemToMerchantStats(output.Item)
+}
+
+// ListByMerchant retrieves a merchant's statistics from one month to another, oldest first
+func (r *DynamoDBMerchantStatsRepository) ListByMerchant(ctx context.Context, merchantID, fromMonth, toMonth string) ([]*entity.MerchantMonthlyStats, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		KeyConditionExpression: aws.String("merchant_id = :mid AND #month BETWEEN :from AND :to"),
+		ExpressionAttributeNames: map[string]string{
+			"#month": "month", // month is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":mid":  &types.AttributeValueMemberS{Value: merchantID},
+			":from": &types.AttributeValueMemberS{Value: fromMonth},
+			":to":   &types.AttributeValueMemberS{Value: toMonth},
+		},
+	}
+
+	var months []*entity.MerchantMonthlyStats
+	for {
+		output, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query merchant statistics: %w", err)
+		}
+
+		for _, item := range output.Items {
+			stats, err := itemToMerchantStats(item)
+			if err != nil {
+				return nil, err
+			}
+			months = append(months, stats)
+		}
+
+		if output.LastEvaluatedKey == nil {
+			return months, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// merchantStatsKey builds the primary key of a merchant's month
+func merchantStatsKey(merchantID, month string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"merchant_id": &types.AttributeValueMemberS{Value: merchantID},
+		"month":       &types.AttributeValueMemberS{Value: month},
+	}
+}
+
+// numberValue builds a number attribute
+func numberValue(value float64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+// itemToMerchantStats converts a DynamoDB item to merchant statistics
+func itemToMerchantStats(item map[string]types.AttributeValue) (*entity.MerchantMonthlyStats, error) {
+	var statsItem merchantStatsItem
+	if err := attributevalue.UnmarshalMap(item, &statsItem); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merchant statistics: %w", err)
+	}
+
+	return &entity.MerchantMonthlyStats{
+		MerchantID:        statsItem.MerchantID,
+		Month:             statsItem.Month,
+		ChargebackCount:   statsItem.ChargebackCount,
+		ChargebackAmount:  statsItem.ChargebackAmount,
+		RejectedCount:     statsItem.RejectedCount,
+		RejectedAmount:    statsItem.RejectedAmount,
+		TransactionCount:  statsItem.TransactionCount,
+		TransactionAmount: statsItem.TransactionAmount,
+		UpdatedAt:         statsItem.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// MockMerchantStatsDynamoDBAPI implements the MerchantStatsDynamoDBAPI interface for testing
+type MockMerchantStatsDynamoDBAPI struct {
+	GetItemFunc    func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	UpdateItemFunc func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	QueryFunc      func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+func (m *MockMerchantStatsDynamoDBAPI) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if m.GetItemFunc != nil {
+		return m.GetItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (m *MockMerchantStatsDynamoDBAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if m.UpdateItemFunc != nil {
+		return m.UpdateItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (m *MockMerchantStatsDynamoDBAPI) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func merchantStatsAttributes(month, chargebackCount, transactionCount string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"merchant_id":       &types.AttributeValueMemberS{Value: "merchant-789"},
+		"month":             &types.AttributeValueMemberS{Value: month},
+		"chargeback_count":  &types.AttributeValueMemberN{Value: chargebackCount},
+		"chargeback_amount": &types.AttributeValueMemberN{Value: "99.99"},
+		"transaction_count": &types.AttributeValueMemberN{Value: transactionCount},
+		"updated_at":        &types.AttributeValueMemberS{Value: "2023-01-16T12:00:00Z"},
+	}
+}
+
+func TestDynamoDBMerchantStatsRepository_AddChargebacks(t *testing.T) {
+	t.Run("adds the delta atomically", func(t *testing.T) {
+		// Arrange
+		var input *dynamodb.UpdateItemInput
+		mock := &MockMerchantStatsDynamoDBAPI{
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				input = params
+				return &dynamodb.UpdateItemOutput{Attributes: merchantStatsAttributes("2023-01", "3", "1000")}, nil
+			},
+		}
+		repo := NewDynamoDBMerchantStatsRepository(mock, "merchant_stats")
+
+		// Act
+		stats, err := repo.AddChargebacks(context.Background(), "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: -1, ChargebackAmount: -99.99, RejectedCount: 1, RejectedAmount: 99.99})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.HasPrefix(*input.UpdateExpression, "ADD ") {
+			t.Errorf("Expected an ADD update expression, got %s", *input.UpdateExpression)
+		}
+		if cc := input.ExpressionAttributeValues[":cc"].(*types.AttributeValueMemberN).Value; cc != "-1" {
+			t.Errorf("Expected chargeback count delta -1, got %s", cc)
+		}
+		if input.ReturnValues != types.ReturnValueAllNew {
+			t.Errorf("Expected ALL_NEW return values, got %s", input.ReturnValues)
+		}
+		if stats.ChargebackCount != 3 || stats.TransactionCount != 1000 || stats.Month != "2023-01" {
+			t.Errorf("Unexpected statistics %+v", stats)
+		}
+	})
+
+	t.Run("reports store errors", func(t *testing.T) {
+		// Arrange
+		mock := &MockMerchantStatsDynamoDBAPI{
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				return nil, errors.New("ProvisionedThroughputExceededException")
+			},
+		}
+		repo := NewDynamoDBMerchantStatsRepository(mock, "merchant_stats")
+
+		// Act
+		_, err := repo.AddChargebacks(context.Background(), "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 1})
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "failed to update merchant statistics") {
+			t.Errorf("Expected wrapped error, got %v", err)
+		}
+	})
+}
+
+func TestDynamoDBMerchantStatsRepository_Find(t *testing.T) {
+	// Arrange
+	mock := &MockMerchantStatsDynamoDBAPI{}
+	repo := NewDynamoDBMerchantStatsRepository(mock, "merchant_stats")
+
+	// Act
+	stats, err := repo.Find(context.Background(), "merchant-789", "2023-01")
+
+	// Assert
+	if err != nil || stats != nil {
+		t.Errorf("Expected nil statistics for a missing month, got %v, %v", stats, err)
+	}
+}
+
+func TestDynamoDBMerchantStatsRepository_ListByMerchant(t *testing.T) {
+	// Arrange
+	calls := 0
+	mock := &MockMerchantStatsDynamoDBAPI{
+		QueryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			calls++
+			if calls == 1 {
+				return &dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{merchantStatsAttributes("2023-01", "1", "100")},
+					LastEvaluatedKey: merchantStatsKey("merchant-789", "2023-01"),
+				}, nil
+			}
+			if params.ExclusiveStartKey == nil {
+				t.Error("Expected the next page to start after the last evaluated key")
+			}
+			return &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{merchantStatsAttributes("2023-02", "2", "100")},
+			}, nil
+		},
+	}
+	repo := NewDynamoDBMerchantStatsRepository(mock, "merchant_stats")
+
+	// Act
+	months, err := repo.ListByMerchant(context.Background(), "merchant-789", "2023-01", "2023-02")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(months) != 2 || months[0].Month != "2023-01" || months[1].ChargebackCount != 2 {
+		t.Errorf("Unexpected months %v", months)
+	}
+}
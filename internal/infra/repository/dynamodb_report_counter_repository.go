@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// ReportCounterDynamoDBAPI is the subset of the DynamoDB client used by the report counter repository
+type ReportCounterDynamoDBAPI interface {
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// DynamoDBReportCounterRepository implements ReportCounterRepository using DynamoDB
+// The table is keyed by month (partition key) and cell (sort key), where the cell is
+// "day#merchant_id#reason#status#currency", so a month of counters is read with a
+// single query. Counters are updated with ADD, so concurrent instances don't lose updates.
+type DynamoDBReportCounterRepository struct {
+	client    ReportCounterDynamoDBAPI
+	tableName string
+
+	// retryDelay is the initial backoff before resubmitting unprocessed items
+	retryDelay time.Duration
+}
+
+// NewDynamoDBReportCounterRepository creates a new DynamoDB report counter repository
+func NewDynamoDBReportCounterRepository(client ReportCounterDynamoDBAPI, tableName string) repository.ReportCounterRepository {
+	return &DynamoDBReportCounterRepository{
+		client:     client,
+		tableName:  tableName,
+		retryDelay: 50 * time.Millisecond,
+	}
+}
+
+// reportCounterItem represents the DynamoDB item structure for report counters
+type reportCounterItem struct {
+	Month      string  `dynamodbav:"month"`
+	Cell       string  `dynamodbav:"cell"`
+	Day        string  `dynamodbav:"day"`
+	MerchantID string  `dynamodbav:"merchant_id"`
+	Reason     string  `dynamodbav:"reason"`
+	Status     string  `dynamodbav:"status"`
+	Currency   string  `dynamodbav:"currency"`
+	Count      int     `dynamodbav:"count"`
+	Amount     float64 `dynamodbav:"amount"`
+}
+
+// Add adds to the number and total amount of the chargebacks in a cell
+func (r *DynamoDBReportCounterRepository) Add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(r.tableName),
+		Key:              reportCounterKey(cell),
+		UpdateExpression: aws.String("ADD #count :count, amount :amount SET #day = :day, merchant_id = :merchant_id, reason = :reason, #status = :status, currency = :currency"),
+		ExpressionAttributeNames: map[string]string{
+			// count, day and status are reserved words
+			"#count":  "count",
+			"#day":    "day",
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":count":       numberValue(float64(count)),
+			":amount":      numberValue(amount),
+			":day":         &types.AttributeValueMemberS{Value: cell.Day},
+			":merchant_id": &types.AttributeValueMemberS{Value: cell.MerchantID},
+			":reason":      &types.AttributeValueMemberS{Value: string(cell.Reason)},
+			":status":      &types.AttributeValueMemberS{Value: string(cell.Status)},
+			":currency":    &types.AttributeValueMemberS{Value: cell.Currency},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update report counter: %w", err)
+	}
+	return nil
+}
+
+// List retrieves the counters from one day to another, both included, querying a month at a time
+func (r *DynamoDBReportCounterRepository) List(ctx context.Context, fromDay, toDay string) ([]*entity.ReportCounter, error) {
+	from, err := time.Parse(entity.ReportDayLayout, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q: %w", fromDay, err)
+	}
+	to, err := time.Parse(entity.ReportDayLayout, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day %q: %w", toDay, err)
+	}
+
+	var counters []*entity.ReportCounter
+	for month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(to); month = month.AddDate(0, 1, 0) {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			KeyConditionExpression: aws.String("#month = :month AND cell BETWEEN :from AND :to"),
+			ExpressionAttributeNames: map[string]string{
+				"#month": "month", // month is a reserved word
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":month": &types.AttributeValueMemberS{Value: month.Format(entity.StatsMonthLayout)},
+				":from":  &types.AttributeValueMemberS{Value: fromDay},
+				// "$" sorts right after "#", so this includes every cell of the last day
+				":to": &types.AttributeValueMemberS{Value: toDay + "$"},
+			},
+		}
+
+		for {
+			output, err := r.client.Query(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query report counters: %w", err)
+			}
+
+			for _, item := range output.Items {
+				var counterItem reportCounterItem
+				if err := attributevalue.UnmarshalMap(item, &counterItem); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal report counter: %w", err)
+				}
+				counters = append(counters, itemToReportCounter(&counterItem))
+			}
+
+			if output.LastEvaluatedKey == nil {
+				break
+			}
+			input.ExclusiveStartKey = output.LastEvaluatedKey
+		}
+	}
+	return counters, nil
+}
+
+// ReplaceAll replaces every counter with the given ones
+// The counters are written over the existing ones, then the cells that no longer have
+// counters are deleted. Writes made meanwhile to cells being replaced are lost, so
+// chargebacks should not be written while counters are replaced.
+func (r *DynamoDBReportCounterRepository) ReplaceAll(ctx context.Context, counters []*entity.ReportCounter) error {
+	stale, err := r.existingKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	requests := make([]types.WriteRequest, 0, len(counters)+len(stale))
+	for _, counter := range counters {
+		item, err := attributevalue.MarshalMap(reportCounterToItem(counter))
+		if err != nil {
+			return fmt.Errorf("failed to marshal report counter: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		delete(stale, reportCounterCell(counter.ReportCell))
+	}
+	for _, key := range stale {
+		requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+	}
+
+	for start := 0; start < len(requests); start += batchWriteSize {
+		if err := r.writeBatch(ctx, requests[start:min(start+batchWriteSize, len(requests))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// existingKeys returns the keys of the stored counters by cell
+func (r *DynamoDBReportCounterRepository) existingKeys(ctx context.Context) (map[string]map[string]types.AttributeValue, error) {
+	keys := make(map[string]map[string]types.AttributeValue)
+	input := &dynamodb.ScanInput{
+		TableName:            aws.String(r.tableName),
+		ProjectionExpression: aws.String("#month, cell"),
+		ExpressionAttributeNames: map[string]string{
+			"#month": "month",
+		},
+	}
+
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report counters: %w", err)
+		}
+
+		for _, item := range output.Items {
+			if cell, ok := item["cell"].(*types.AttributeValueMemberS); ok {
+				keys[cell.Value] = item
+			}
+		}
+
+		if output.LastEvaluatedKey == nil {
+			return keys, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// writeBatch writes up to batchWriteSize requests, resubmitting unprocessed ones with
+// exponential backoff
+func (r *DynamoDBReportCounterRepository) writeBatch(ctx context.Context, requests []types.WriteRequest) error {
+	delay := r.retryDelay
+	for attempt := 1; ; attempt++ {
+		output, err := r.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{r.tableName: requests},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write report counters: %w", err)
+		}
+
+		requests = output.UnprocessedItems[r.tableName]
+		if len(requests) == 0 {
+			return nil
+		}
+		if attempt == batchWriteAttempts {
+			return fmt.Errorf("failed to write report counters: %d items unprocessed after %d attempts", len(requests), batchWriteAttempts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to write report counters: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// reportCounterCell builds the sort key of a cell
+func reportCounterCell(cell entity.ReportCell) string {
+	return strings.Join([]string{cell.Day, cell.MerchantID, string(cell.Reason), string(cell.Status), cell.Currency}, "#")
+}
+
+// reportCounterKey builds the primary key of a cell
+func reportCounterKey(cell entity.ReportCell) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"month": &types.AttributeValueMemberS{Value: reportCounterMonth(cell.Day)},
+		"cell":  &types.AttributeValueMemberS{Value: reportCounterCell(cell)},
+	}
+}
+
+// reportCounterMonth returns the month partition of a day
+func reportCounterMonth(day string) string {
+	if len(day) < len(entity.StatsMonthLayout) {
+		return day
+	}
+	return day[:len(entity.StatsMonthLayout)]
+}
+
+// reportCounterToItem converts a report counter to a DynamoDB item
+func reportCounterToItem(counter *entity.ReportCounter) *reportCounterItem {
+	return &reportCounterItem{
+		Month:      reportCounterMonth(counter.Day),
+		Cell:       reportCounterCell(counter.ReportCell),
+		Day:        counter.Day,
+		MerchantID: counter.MerchantID,
+		Reason:     string(counter.Reason),
+		Status:     string(counter.Status),
+		Currency:   counter.Currency,
+		Count:      counter.Count,
+		Amount:     counter.Amount,
+	}
+}
+
+// itemToReportCounter converts a DynamoDB item to a report counter
+func itemToReportCounter(item *reportCounterItem) *entity.ReportCounter {
+	return &entity.ReportCounter{
+		ReportCell: entity.ReportCell{
+			Day:        item.Day,
+			MerchantID: item.MerchantID,
+			Reason:     entity.ChargebackReason(item.Reason),
+			Status:     entity.ChargebackStatus(item.Status),
+			Currency:   item.Currency,
+		},
+		Count:  item.Count,
+		Amount: item.Amount,
+	}
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+// MockReportCounterDynamoDBAPI adds UpdateItem to MockDynamoDBAPI
+type MockReportCounterDynamoDBAPI struct {
+	MockDynamoDBAPI
+	UpdateItemFunc func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+}
+
+func (m *MockReportCounterDynamoDBAPI) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if m.UpdateItemFunc != nil {
+		return m.UpdateItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func createTestReportCell() entity.ReportCell {
+	return entity.ReportCell{Day: "2023-10-16", MerchantID: "merchant-789", Reason: entity.ReasonFraud, Status: entity.StatusPending, Currency: "USD"}
+}
+
+func TestDynamoDBReportCounterRepository_Add(t *testing.T) {
+	// Arrange
+	var input *dynamodb.UpdateItemInput
+	mock := &MockReportCounterDynamoDBAPI{
+		UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+			input = params
+			return &dynamodb.UpdateItemOutput{}, nil
+		},
+	}
+	repo := NewDynamoDBReportCounterRepository(mock, "report_counters")
+
+	// Act
+	err := repo.Add(context.Background(), createTestReportCell(), -1, -99.99)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if month := input.Key["month"].(*types.AttributeValueMemberS).Value; month != "2023-10" {
+		t.Errorf("Expected month partition 2023-10, got %s", month)
+	}
+	if cell := input.Key["cell"].(*types.AttributeValueMemberS).Value; cell != "2023-10-16#merchant-789#fraud#pending#USD" {
+		t.Errorf("Unexpected cell %s", cell)
+	}
+	if !strings.HasPrefix(*input.UpdateExpression, "ADD ") {
+		t.Errorf("Expected an ADD update expression, got %s", *input.UpdateExpression)
+	}
+	if count := input.ExpressionAttributeValues[":count"].(*types.AttributeValueMemberN).Value; count != "-1" {
+		t.Errorf("Expected count -1, got %s", count)
+	}
+}
+
+func TestDynamoDBReportCounterRepository_List(t *testing.T) {
+	// Arrange
+	var months []string
+	mock := &MockReportCounterDynamoDBAPI{MockDynamoDBAPI: MockDynamoDBAPI{
+		QueryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			month := params.ExpressionAttributeValues[":month"].(*types.AttributeValueMemberS).Value
+			months = append(months, month)
+			if month != "2023-10" {
+				return &dynamodb.QueryOutput{}, nil
+			}
+			return &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{{
+				"day":         &types.AttributeValueMemberS{Value: "2023-10-16"},
+				"merchant_id": &types.AttributeValueMemberS{Value: "merchant-789"},
+				"reason":      &types.AttributeValueMemberS{Value: "fraud"},
+				"status":      &types.AttributeValueMemberS{Value: "pending"},
+				"currency":    &types.AttributeValueMemberS{Value: "USD"},
+				"count":       &types.AttributeValueMemberN{Value: "3"},
+				"amount":      &types.AttributeValueMemberN{Value: "300"},
+			}}}, nil
+		},
+	}}
+	repo := NewDynamoDBReportCounterRepository(mock, "report_counters")
+
+	// Act
+	counters, err := repo.List(context.Background(), "2023-09-20", "2023-11-05")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Join(months, ",") != "2023-09,2023-10,2023-11" {
+		t.Errorf("Expected a query per month, got %v", months)
+	}
+	if len(counters) != 1 || counters[0].ReportCell != createTestReportCell() || counters[0].Count != 3 {
+		t.Errorf("Unexpected counters %v", counters)
+	}
+}
+
+func TestDynamoDBReportCounterRepository_ReplaceAll(t *testing.T) {
+	// Arrange
+	var puts, deletes []string
+	mock := &MockReportCounterDynamoDBAPI{MockDynamoDBAPI: MockDynamoDBAPI{
+		ScanFunc: func(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+			return &dynamodb.ScanOutput{Items: []map[string]types.AttributeValue{
+				reportCounterKey(createTestReportCell()),
+				{
+					"month": &types.AttributeValueMemberS{Value: "2023-09"},
+					"cell":  &types.AttributeValueMemberS{Value: "2023-09-01#merchant-000#fraud#pending#USD"},
+				},
+			}}, nil
+		},
+		BatchWriteItemFunc: func(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+			for _, request := range params.RequestItems["report_counters"] {
+				if request.PutRequest != nil {
+					puts = append(puts, request.PutRequest.Item["cell"].(*types.AttributeValueMemberS).Value)
+				} else {
+					deletes = append(deletes, request.DeleteRequest.Key["cell"].(*types.AttributeValueMemberS).Value)
+				}
+			}
+			return &dynamodb.BatchWriteItemOutput{}, nil
+		},
+	}}
+	repo := NewDynamoDBReportCounterRepository(mock, "report_counters")
+
+	// Act
+	err := repo.ReplaceAll(context.Background(), []*entity.ReportCounter{{ReportCell: createTestReportCell(), Count: 2, Amount: 200}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(puts) != 1 || puts[0] != "2023-10-16#merchant-789#fraud#pending#USD" {
+		t.Errorf("Expected the rebuilt counter to be written, got %v", puts)
+	}
+	if len(deletes) != 1 || !strings.HasPrefix(deletes[0], "2023-09-01#merchant-000") {
+		t.Errorf("Expected the stale counter to be deleted, got %v", deletes)
+	}
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+)
+
+const (
+	// chargebackItemType is the item_type of chargeback items in the single table
+	chargebackItemType = "CHARGEBACK"
+
+	// chargebackItemVersion is the item_version of the chargeback attributes written
+	// It changes with the attributes, so items written by another release are rejected
+	// rather than misread
+	chargebackItemVersion = 1
+)
+
+// Key prefixes of the single table
+const (
+	chargebackKeyPrefix  = "CB#"
+	transactionKeyPrefix = "TXN#"
+	merchantKeyPrefix    = "MERCHANT#"
+	statusKeyPrefix      = "STATUS#"
+)
+
+// DynamoDBSingleTableChargebackRepository implements ChargebackRepository on a table in
+// the single-table layout
+// A chargeback is stored under PK and SK "CB#<id>" and found by transaction through GSI1
+// ("TXN#<id>"), by merchant through GSI2 ("MERCHANT#<id>") and by status through GSI3
+// ("STATUS#<status>"). The index sort keys hold "CB#<id>", so chargebacks are told apart
+// from other item types sharing an index partition by prefix. Soft deletes and purges
+// work as in DynamoDBChargebackRepository.
+type DynamoDBSingleTableChargebackRepository struct {
+	client    DynamoDBAPI
+	tableName string
+
+	// retryDelay is the initial backoff before resubmitting unprocessed items
+	retryDelay time.Duration
+}
+
+// NewDynamoDBSingleTableChargebackRepository creates a chargeback repository on a table in
+// the single-table layout
+func NewDynamoDBSingleTableChargebackRepository(client *dynamodb.Client, tableName string) repository.ChargebackRepository {
+	return NewDynamoDBSingleTableChargebackRepositoryWithInterface(client, tableName)
+}
+
+// NewDynamoDBSingleTableChargebackRepositoryWithInterface creates a single-table chargeback
+// repository with custom interface
+// This is primarily used for testing with mocks
+func NewDynamoDBSingleTableChargebackRepositoryWithInterface(client DynamoDBAPI, tableName string) *DynamoDBSingleTableChargebackRepository {
+	return &DynamoDBSingleTableChargebackRepository{
+		client:     client,
+		tableName:  tableName,
+		retryDelay: 50 * time.Millisecond,
+	}
+}
+
+// NewDynamoDBChargebackRepositoryForLayout creates the chargeback repository of a table in
+// the given layout, db.LayoutFlat or db.LayoutSingleTable
+func NewDynamoDBChargebackRepositoryForLayout(client DynamoDBAPI, tableName, layout string) repository.ChargebackRepository {
+	if layout == db.LayoutSingleTable {
+		return NewDynamoDBSingleTableChargebackRepositoryWithInterface(client, tableName)
+	}
+	return NewDynamoDBChargebackRepositoryWithInterface(client, tableName)
+}
+
+// singleTableChargebackItem represents a chargeback item in the single table
+type singleTableChargebackItem struct {
+	PK          string `dynamodbav:"PK"`
+	SK          string `dynamodbav:"SK"`
+	GSI1PK      string `dynamodbav:"GSI1PK"`
+	GSI1SK      string `dynamodbav:"GSI1SK"`
+	GSI2PK      string `dynamodbav:"GSI2PK"`
+	GSI2SK      string `dynamodbav:"GSI2SK"`
+	GSI3PK      string `dynamodbav:"GSI3PK"`
+	GSI3SK      string `dynamodbav:"GSI3SK"`
+	ItemType    string `dynamodbav:"item_type"`
+	ItemVersion int    `dynamodbav:"item_version"`
+	chargebackItem
+}
+
+// Save persists a new chargeback
+func (r *DynamoDBSingleTableChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	if chargeback.ID == "" {
+		chargeback.ID = generateChargebackID()
+	}
+
+	av, err := r.entityToItem(chargeback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(PK)"),
+	})
+	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to save chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackExists, err)
+		}
+		return fmt.Errorf("failed to save chargeback: %w", err)
+	}
+	return nil
+}
+
+// SaveBatch persists new chargebacks with BatchWriteItem, 25 items per request
+// Like DynamoDBChargebackRepository.SaveBatch, callers must only pass chargebacks with new IDs
+func (r *DynamoDBSingleTableChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	return saveChargebackBatch(ctx, r.client, r.tableName, r.retryDelay, chargebacks, r.entityToItem)
+}
+
+// FindByID retrieves a chargeback by its unique identifier
+func (r *DynamoDBSingleTableChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       chargebackKey(id),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+	if result.Item == nil || !isActiveItem(result.Item) {
+		return nil, nil // Not found
+	}
+	return r.itemToEntity(result.Item)
+}
+
+// FindByTransactionID retrieves a chargeback by transaction ID
+func (r *DynamoDBSingleTableChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+	// No limit: a deleted chargeback may share the transaction with an active one
+	result, err := r.client.Query(ctx, r.indexQuery(db.GSI1Index, transactionKeyPrefix+transactionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargeback by transaction ID: %w", err)
+	}
+	items := activeItems(result.Items)
+	if len(items) == 0 {
+		return nil, nil // Not found
+	}
+	return r.itemToEntity(items[0])
+}
+
+// FindByMerchantID retrieves all chargebacks for a specific merchant
+func (r *DynamoDBSingleTableChargebackRepository) FindByMerchantID(ctx context.Context, merchantID string) ([]*entity.Chargeback, error) {
+	chargebacks, err := r.queryAll(ctx, r.indexQuery(db.GSI2Index, merchantKeyPrefix+merchantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargebacks by merchant ID: %w", err)
+	}
+	return chargebacks, nil
+}
+
+// FindByStatus retrieves chargebacks by their status
+func (r *DynamoDBSingleTableChargebackRepository) FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error) {
+	chargebacks, err := r.queryAll(ctx, r.indexQuery(db.GSI3Index, statusKeyPrefix+string(status)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargebacks by status: %w", err)
+	}
+	return chargebacks, nil
+}
+
+// Update updates an existing chargeback, moving it between index partitions when its
+// merchant or status changed
+func (r *DynamoDBSingleTableChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	chargeback.UpdatedAt = time.Now()
+
+	av, err := r.entityToItem(chargeback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_exists(PK) AND " + activeItemCondition),
+	})
+	if err != nil {
+		if isConditionFailed(err) {
+			return fmt.Errorf("failed to update chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackNotFound, err)
+		}
+		return fmt.Errorf("failed to update chargeback: %w", err)
+	}
+	return nil
+}
+
+// Delete soft deletes a chargeback, setting its deleted_at
+func (r *DynamoDBSingleTableChargebackRepository) Delete(ctx context.Context, id string) error {
+	return deleteChargebackItem(ctx, r.client, r.tableName, id, chargebackKey(id), "PK")
+}
+
+// Restore brings back a soft deleted chargeback, removing its deleted_at
+func (r *DynamoDBSingleTableChargebackRepository) Restore(ctx context.Context, id string) error {
+	return restoreChargebackItem(ctx, r.client, r.tableName, id, chargebackKey(id))
+}
+
+// Purge sets the expires_at of the chargebacks, so the table's TTL removes them
+func (r *DynamoDBSingleTableChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	return purgeChargebackItems(ctx, r.client, r.tableName, chargebacks, chargebackKey, "PK")
+}
+
+// List retrieves chargebacks with pagination support
+// The table is scanned from the start and offset chargebacks are skipped
+func (r *DynamoDBSingleTableChargebackRepository) List(ctx context.Context, offset, limit int) ([]*entity.Chargeback, error) {
+	var chargebacks []*entity.Chargeback
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := r.client.Scan(ctx, r.chargebackScan(startKey, max(offset+limit-len(chargebacks), 1)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chargebacks: %w", err)
+		}
+		for _, item := range activeItems(result.Items) {
+			chargeback, err := r.itemToEntity(item)
+			if err != nil {
+				return nil, err
+			}
+			chargebacks = append(chargebacks, chargeback)
+		}
+
+		startKey = result.LastEvaluatedKey
+		if startKey == nil || len(chargebacks) >= offset+limit {
+			break
+		}
+	}
+
+	if offset >= len(chargebacks) {
+		return []*entity.Chargeback{}, nil
+	}
+	return chargebacks[offset:min(offset+limit, len(chargebacks))], nil
+}
+
+// ListPage retrieves a page of chargebacks matching the filter
+// A single merchant or a status is queried through its index, anything else is scanned;
+// the remaining criteria are applied to the items read, so a page may hold fewer than
+// limit chargebacks. The cursor is the encoded key the page stopped at.
+func (r *DynamoDBSingleTableChargebackRepository) ListPage(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+	startKey, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]types.AttributeValue
+	var lastKey map[string]types.AttributeValue
+	switch {
+	case len(filter.MerchantIDs) == 1:
+		input := r.indexQuery(db.GSI2Index, merchantKeyPrefix+filter.MerchantIDs[0])
+		input.ExclusiveStartKey, input.Limit = startKey, aws.Int32(int32(limit))
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query chargebacks by merchant ID: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	case filter.Status != "":
+		input := r.indexQuery(db.GSI3Index, statusKeyPrefix+string(filter.Status))
+		input.ExclusiveStartKey, input.Limit = startKey, aws.Int32(int32(limit))
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query chargebacks by status: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	default:
+		result, err := r.client.Scan(ctx, r.chargebackScan(startKey, limit))
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan chargebacks: %w", err)
+		}
+		items, lastKey = result.Items, result.LastEvaluatedKey
+	}
+
+	page := &repository.ChargebackPage{Chargebacks: make([]*entity.Chargeback, 0, len(items))}
+	for _, item := range items {
+		if isPurgedItem(item) {
+			continue
+		}
+		chargeback, err := r.itemToEntity(item)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Matches(chargeback) {
+			page.Chargebacks = append(page.Chargebacks, chargeback)
+		}
+	}
+
+	if page.NextCursor, err = encodeCursor(lastKey); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// indexQuery returns the query for the chargebacks under a partition of an overloaded
+// index, whose key attributes are named after it, e.g. GSI1PK and GSI1SK
+func (r *DynamoDBSingleTableChargebackRepository) indexQuery(index, partition string) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(index),
+		KeyConditionExpression: aws.String("#pk = :pk AND begins_with(#sk, :sk)"),
+		ExpressionAttributeNames: map[string]string{
+			"#pk": index + "PK",
+			"#sk": index + "SK",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: partition},
+			":sk": &types.AttributeValueMemberS{Value: chargebackKeyPrefix},
+		},
+	}
+}
+
+// queryAll reads every page of a query
+func (r *DynamoDBSingleTableChargebackRepository) queryAll(ctx context.Context, input *dynamodb.QueryInput) ([]*entity.Chargeback, error) {
+	chargebacks := []*entity.Chargeback{}
+	for {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range activeItems(result.Items) {
+			chargeback, err := r.itemToEntity(item)
+			if err != nil {
+				return nil, err
+			}
+			chargebacks = append(chargebacks, chargeback)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return chargebacks, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// chargebackScan returns a scan of up to limit items after the start key, keeping the
+// chargebacks among them
+func (r *DynamoDBSingleTableChargebackRepository) chargebackScan(startKey map[string]types.AttributeValue, limit int) *dynamodb.ScanInput {
+	return &dynamodb.ScanInput{
+		TableName:        aws.String(r.tableName),
+		FilterExpression: aws.String("item_type = :type"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":type": &types.AttributeValueMemberS{Value: chargebackItemType},
+		},
+		ExclusiveStartKey: startKey,
+		Limit:             aws.Int32(int32(limit)),
+	}
+}
+
+// chargebackKey returns the primary key of a chargeback item
+func chargebackKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"PK": &types.AttributeValueMemberS{Value: chargebackKeyPrefix + id},
+		"SK": &types.AttributeValueMemberS{Value: chargebackKeyPrefix + id},
+	}
+}
+
+// entityToItem converts a domain entity to a single-table item
+func (r *DynamoDBSingleTableChargebackRepository) entityToItem(chargeback *entity.Chargeback) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(newSingleTableChargebackItem(chargeback))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chargeback: %w", err)
+	}
+	return av, nil
+}
+
+// itemToEntity converts a single-table item to a domain entity
+func (r *DynamoDBSingleTableChargebackRepository) itemToEntity(av map[string]types.AttributeValue) (*entity.Chargeback, error) {
+	var item singleTableChargebackItem
+	if err := attributevalue.UnmarshalMap(av, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal chargeback: %w", err)
+	}
+	if item.ItemType != chargebackItemType {
+		return nil, fmt.Errorf("item %s/%s is a '%s', not a chargeback", item.PK, item.SK, item.ItemType)
+	}
+	if item.ItemVersion != chargebackItemVersion {
+		return nil, fmt.Errorf("chargeback %s has unsupported item version %d, expected %d", item.ID, item.ItemVersion, chargebackItemVersion)
+	}
+	return item.toEntity(), nil
+}
+
+// newSingleTableChargebackItem keys a chargeback's attributes for the single table
+func newSingleTableChargebackItem(chargeback *entity.Chargeback) singleTableChargebackItem {
+	key := chargebackKeyPrefix + chargeback.ID
+	return singleTableChargebackItem{
+		PK:             key,
+		SK:             key,
+		GSI1PK:         transactionKeyPrefix + chargeback.TransactionID,
+		GSI1SK:         key,
+		GSI2PK:         merchantKeyPrefix + chargeback.MerchantID,
+		GSI2SK:         key,
+		GSI3PK:         statusKeyPrefix + string(chargeback.Status),
+		GSI3SK:         key,
+		ItemType:       chargebackItemType,
+		ItemVersion:    chargebackItemVersion,
+		chargebackItem: newChargebackItem(chargeback),
+	}
+}
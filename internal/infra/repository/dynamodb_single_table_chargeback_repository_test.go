@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository/repositorytest"
+)
+
+func TestDynamoDBSingleTableChargebackRepository_Contract(t *testing.T) {
+	repositorytest.TestChargebackRepository(t, func(t *testing.T) repository.ChargebackRepository {
+		return NewDynamoDBSingleTableChargebackRepositoryWithInterface(newFakeDynamoDB("PK", "SK"), "chargebacks")
+	})
+}
+
+func TestDynamoDBSingleTableChargebackRepository_ItemLayout(t *testing.T) {
+	// Arrange
+	table := newFakeDynamoDB("PK", "SK")
+	repo := NewDynamoDBSingleTableChargebackRepositoryWithInterface(table, "chargebacks")
+
+	// Act
+	err := repo.Save(context.Background(), createTestChargeback())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	item := table.items["CB#chargeback-123\x00CB#chargeback-123"]
+	expected := map[string]string{
+		"GSI1PK":    "TXN#txn-456",
+		"GSI1SK":    "CB#chargeback-123",
+		"GSI2PK":    "MERCHANT#merchant-789",
+		"GSI3PK":    "STATUS#pending",
+		"item_type": "CHARGEBACK",
+		"id":        "chargeback-123",
+	}
+	for name, value := range expected {
+		if got := stringAttribute(item, name); got != value {
+			t.Errorf("Expected %s %q, got %q", name, value, got)
+		}
+	}
+	if version, ok := item["item_version"].(*types.AttributeValueMemberN); !ok || version.Value != "1" {
+		t.Errorf("Expected item version 1, got %v", item["item_version"])
+	}
+}
+
+func TestDynamoDBSingleTableChargebackRepository_OtherItemTypes(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	table := newFakeDynamoDB("PK", "SK")
+	repo := NewDynamoDBSingleTableChargebackRepositoryWithInterface(table, "chargebacks")
+	if err := repo.Save(ctx, createTestChargeback()); err != nil {
+		t.Fatalf("Failed to save chargeback: %v", err)
+	}
+	// A note on the chargeback, sharing its partition and the merchant's index partition
+	_, _ = table.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("chargebacks"),
+		Item: map[string]types.AttributeValue{
+			"PK":        &types.AttributeValueMemberS{Value: "CB#chargeback-123"},
+			"SK":        &types.AttributeValueMemberS{Value: "NOTE#0001"},
+			"GSI2PK":    &types.AttributeValueMemberS{Value: "MERCHANT#merchant-789"},
+			"GSI2SK":    &types.AttributeValueMemberS{Value: "NOTE#0001"},
+			"item_type": &types.AttributeValueMemberS{Value: "NOTE"},
+		},
+	})
+
+	// Act
+	byMerchant, errMerchant := repo.FindByMerchantID(ctx, "merchant-789")
+	listed, errList := repo.List(ctx, 0, 10)
+	page, errPage := repo.ListPage(ctx, repository.ChargebackFilter{}, "", 10)
+
+	// Assert
+	for _, err := range []error{errMerchant, errList, errPage} {
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(byMerchant) != 1 || len(listed) != 1 || len(page.Chargebacks) != 1 {
+		t.Errorf("Expected only the chargeback, got %d, %d and %d items", len(byMerchant), len(listed), len(page.Chargebacks))
+	}
+}
+
+func TestDynamoDBSingleTableChargebackRepository_UnsupportedItemVersion(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	table := newFakeDynamoDB("PK", "SK")
+	repo := NewDynamoDBSingleTableChargebackRepositoryWithInterface(table, "chargebacks")
+	item, err := repo.entityToItem(createTestChargeback())
+	if err != nil {
+		t.Fatalf("Failed to marshal chargeback: %v", err)
+	}
+	item["item_version"] = &types.AttributeValueMemberN{Value: "2"}
+	_, _ = table.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String("chargebacks"), Item: item})
+
+	// Act
+	_, err = repo.FindByID(ctx, "chargeback-123")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "unsupported item version 2") {
+		t.Errorf("Expected an unsupported item version error, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeDynamoDB is an in-process DynamoDB table keyed by string attributes
+// It understands the expressions the chargeback repositories use: conditions joining
+// attribute_exists and attribute_not_exists with AND, updates that SET or REMOVE one
+// attribute, equality key conditions on any attribute, optionally with begins_with on a
+// second one, which stand in for the global secondary indexes, and equality scan
+// filters. Items are read in key order.
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	keys  []string
+	items map[string]map[string]types.AttributeValue
+}
+
+// newFakeDynamoDB creates an empty table with the given partition key and optional sort key
+func newFakeDynamoDB(keys ...string) *fakeDynamoDB {
+	return &fakeDynamoDB{
+		keys:  keys,
+		items: make(map[string]map[string]types.AttributeValue),
+	}
+}
+
+var (
+	conditionPattern    = regexp.MustCompile(`^attribute_(not_)?exists\((\w+)\)$`)
+	updatePattern       = regexp.MustCompile(`^(?:SET (#?\w+) = (:\w+)|REMOVE (#?\w+))$`)
+	keyConditionPattern = regexp.MustCompile(`^(#?\w+) = (:\w+)(?: AND begins_with\((#?\w+), (:\w+)\))?$`)
+	filterPattern       = regexp.MustCompile(`^(#?\w+) = (:\w+)$`)
+)
+
+func (f *fakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.id(params.Item)
+	if err := f.checkCondition(params.ConditionExpression, id); err != nil {
+		return nil, err
+	}
+	f.items[id] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &dynamodb.GetItemOutput{Item: f.items[f.id(params.Key)]}, nil
+}
+
+func (f *fakeDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	match := updatePattern.FindStringSubmatch(aws.ToString(params.UpdateExpression))
+	if match == nil {
+		return nil, fmt.Errorf("fake DynamoDB: unsupported update %q", aws.ToString(params.UpdateExpression))
+	}
+	id := f.id(params.Key)
+	if err := f.checkCondition(params.ConditionExpression, id); err != nil {
+		return nil, err
+	}
+
+	// Like DynamoDB, updating a missing item creates it
+	item := make(map[string]types.AttributeValue, len(f.items[id])+1)
+	maps.Copy(item, params.Key)
+	maps.Copy(item, f.items[id])
+	if match[1] != "" {
+		item[attributeName(params.ExpressionAttributeNames, match[1])] = params.ExpressionAttributeValues[match[2]]
+	} else {
+		delete(item, attributeName(params.ExpressionAttributeNames, match[3]))
+	}
+	f.items[id] = item
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	match := keyConditionPattern.FindStringSubmatch(aws.ToString(params.KeyConditionExpression))
+	if match == nil {
+		return nil, fmt.Errorf("fake DynamoDB: unsupported key condition %q", aws.ToString(params.KeyConditionExpression))
+	}
+	attribute := attributeName(params.ExpressionAttributeNames, match[1])
+	value := stringAttribute(params.ExpressionAttributeValues, match[2])
+	prefixAttribute := attributeName(params.ExpressionAttributeNames, match[3])
+	prefix := stringAttribute(params.ExpressionAttributeValues, match[4])
+
+	items, lastKey := f.read(params.ExclusiveStartKey, params.Limit, func(item map[string]types.AttributeValue) bool {
+		return stringAttribute(item, attribute) == value &&
+			(prefixAttribute == "" || strings.HasPrefix(stringAttribute(item, prefixAttribute), prefix))
+	})
+	if lastKey != nil {
+		lastKey[attribute] = &types.AttributeValueMemberS{Value: value}
+		if prefixAttribute != "" {
+			lastKey[prefixAttribute] = items[len(items)-1][prefixAttribute]
+		}
+	}
+	return &dynamodb.QueryOutput{Items: items, LastEvaluatedKey: lastKey}, nil
+}
+
+func (f *fakeDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	items, lastKey := f.read(params.ExclusiveStartKey, params.Limit, func(map[string]types.AttributeValue) bool { return true })
+	if params.FilterExpression == nil {
+		return &dynamodb.ScanOutput{Items: items, LastEvaluatedKey: lastKey}, nil
+	}
+
+	// Like DynamoDB, the filter applies to the items read, after the limit
+	match := filterPattern.FindStringSubmatch(*params.FilterExpression)
+	if match == nil {
+		return nil, fmt.Errorf("fake DynamoDB: unsupported filter %q", *params.FilterExpression)
+	}
+	attribute := attributeName(params.ExpressionAttributeNames, match[1])
+	value := stringAttribute(params.ExpressionAttributeValues, match[2])
+	filtered := make([]map[string]types.AttributeValue, 0, len(items))
+	for _, item := range items {
+		if stringAttribute(item, attribute) == value {
+			filtered = append(filtered, item)
+		}
+	}
+	return &dynamodb.ScanOutput{Items: filtered, LastEvaluatedKey: lastKey}, nil
+}
+
+func (f *fakeDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, requests := range params.RequestItems {
+		if len(requests) > batchWriteSize {
+			return nil, fmt.Errorf("fake DynamoDB: %d requests exceed the batch size", len(requests))
+		}
+		for _, request := range requests {
+			switch {
+			case request.PutRequest != nil:
+				f.items[f.id(request.PutRequest.Item)] = request.PutRequest.Item
+			case request.DeleteRequest != nil:
+				delete(f.items, f.id(request.DeleteRequest.Key))
+			}
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+// checkCondition evaluates attribute_exists and attribute_not_exists conditions joined
+// with AND on the item stored under the id
+func (f *fakeDynamoDB) checkCondition(expression *string, id string) error {
+	if expression == nil {
+		return nil
+	}
+	for _, term := range strings.Split(*expression, " AND ") {
+		match := conditionPattern.FindStringSubmatch(term)
+		if match == nil {
+			return fmt.Errorf("fake DynamoDB: unsupported condition %q", *expression)
+		}
+
+		_, exists := f.items[id][match[2]]
+		if exists == (match[1] == "not_") {
+			return &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+		}
+	}
+	return nil
+}
+
+// read returns up to limit matching items after the start key, in key order, and the
+// key to resume after when the limit was reached
+func (f *fakeDynamoDB) read(startKey map[string]types.AttributeValue, limit *int32, match func(map[string]types.AttributeValue) bool) ([]map[string]types.AttributeValue, map[string]types.AttributeValue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := make([]string, 0, len(f.items))
+	for key := range f.items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var after string
+	if startKey != nil {
+		after = f.id(startKey)
+	}
+	var items []map[string]types.AttributeValue
+	for _, key := range keys {
+		if strings.Compare(key, after) <= 0 || !match(f.items[key]) {
+			continue
+		}
+		items = append(items, f.items[key])
+		if limit != nil && len(items) == int(*limit) {
+			lastKey := make(map[string]types.AttributeValue, len(f.keys))
+			for _, name := range f.keys {
+				lastKey[name] = f.items[key][name]
+			}
+			return items, lastKey
+		}
+	}
+	return items, nil
+}
+
+// id joins the key attributes of an item into the string it is stored under
+func (f *fakeDynamoDB) id(item map[string]types.AttributeValue) string {
+	values := make([]string, 0, len(f.keys))
+	for _, name := range f.keys {
+		values = append(values, stringAttribute(item, name))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// attributeName resolves an expression attribute name placeholder
+func attributeName(names map[string]string, attribute string) string {
+	if name, ok := names[attribute]; ok {
+		return name
+	}
+	return attribute
+}
+
+// stringAttribute returns the value of a string attribute, or "" if it is missing
+func stringAttribute(item map[string]types.AttributeValue, name string) string {
+	if value, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+// fakeDynamoDBTables routes requests to fake tables by name
+type fakeDynamoDBTables map[string]*fakeDynamoDB
+
+func (f fakeDynamoDBTables) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return f[aws.ToString(params.TableName)].PutItem(ctx, params, optFns...)
+}
+
+func (f fakeDynamoDBTables) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return f[aws.ToString(params.TableName)].GetItem(ctx, params, optFns...)
+}
+
+func (f fakeDynamoDBTables) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return f[aws.ToString(params.TableName)].UpdateItem(ctx, params, optFns...)
+}
+
+func (f fakeDynamoDBTables) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return f[aws.ToString(params.TableName)].Query(ctx, params, optFns...)
+}
+
+func (f fakeDynamoDBTables) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return f[aws.ToString(params.TableName)].Scan(ctx, params, optFns...)
+}
+
+func (f fakeDynamoDBTables) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	for tableName, requests := range params.RequestItems {
+		input := &dynamodb.BatchWriteItemInput{RequestItems: map[string][]types.WriteRequest{tableName: requests}}
+		if _, err := f[tableName].BatchWriteItem(ctx, input, optFns...); err != nil {
+			return nil, err
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// MemoryAPIKeyRepository implements APIKeyRepository in memory
+// It is intended for local development and single-instance deployments
+type MemoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]*entity.APIKey
+}
+
+// NewMemoryAPIKeyRepository creates a new in-memory API key repository
+func NewMemoryAPIKeyRepository() repository.APIKeyRepository {
+	return &MemoryAPIKeyRepository{
+		keys: make(map[string]*entity.APIKey),
+	}
+}
+
+// Save persists a new API key in memory
+func (r *MemoryAPIKeyRepository) Save(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; exists {
+		return fmt.Errorf("failed to save API key: API key %s already exists", key.ID)
+	}
+
+	r.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+// FindByID retrieves an API key by its unique identifier
+func (r *MemoryAPIKeyRepository) FindByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, exists := r.keys[id]
+	if !exists {
+		return nil, nil // Not found
+	}
+
+	return copyAPIKey(key), nil
+}
+
+// Update updates an existing API key in memory
+func (r *MemoryAPIKeyRepository) Update(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; !exists {
+		return fmt.Errorf("failed to update API key: API key %s does not exist", key.ID)
+	}
+
+	r.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+// List retrieves all API keys ordered by creation time
+func (r *MemoryAPIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*entity.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+
+	return keys, nil
+}
+
+// copyAPIKey returns a deep copy so callers cannot mutate stored state
+func copyAPIKey(key *entity.APIKey) *entity.APIKey {
+	clone := *key
+	clone.MerchantIDs = append([]string(nil), key.MerchantIDs...)
+	clone.Scopes = append([]auth.Scope(nil), key.Scopes...)
+	return &clone
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func createTestAPIKey(id string) *entity.APIKey {
+	return &entity.APIKey{
+		ID:          id,
+		Name:        "acme integration",
+		SecretHash:  "hash-" + id,
+		MerchantIDs: []string{"merchant-789"},
+		Scopes:      []auth.Scope{auth.ScopeChargebacksWrite},
+		Status:      entity.APIKeyStatusActive,
+		CreatedAt:   time.Date(2023, 1, 16, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2023, 1, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMemoryAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save and find", func(t *testing.T) {
+		repo := NewMemoryAPIKeyRepository()
+		key := createTestAPIKey("key-1")
+
+		if err := repo.Save(ctx, key); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found, err := repo.FindByID(ctx, "key-1")
+		if err != nil || found == nil {
+			t.Fatalf("Expected key to be found, got %v, %v", found, err)
+		}
+		if found.SecretHash != "hash-key-1" {
+			t.Errorf("Expected secret hash 'hash-key-1', got %s", found.SecretHash)
+		}
+
+		// Mutating the returned copy must not change stored state
+		found.MerchantIDs[0] = "other"
+		again, _ := repo.FindByID(ctx, "key-1")
+		if again.MerchantIDs[0] != "merchant-789" {
+			t.Error("Expected stored key to be isolated from caller mutations")
+		}
+	})
+
+	t.Run("save duplicate", func(t *testing.T) {
+		repo := NewMemoryAPIKeyRepository()
+		repo.Save(ctx, createTestAPIKey("key-1"))
+
+		if err := repo.Save(ctx, createTestAPIKey("key-1")); err == nil {
+			t.Error("Expected error saving duplicate key")
+		}
+	})
+
+	t.Run("find not found", func(t *testing.T) {
+		repo := NewMemoryAPIKeyRepository()
+
+		found, err := repo.FindByID(ctx, "missing")
+		if err != nil || found != nil {
+			t.Errorf("Expected nil, nil for missing key, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := NewMemoryAPIKeyRepository()
+		key := createTestAPIKey("key-1")
+		repo.Save(ctx, key)
+
+		key.Status = entity.APIKeyStatusRevoked
+		if err := repo.Update(ctx, key); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found, _ := repo.FindByID(ctx, "key-1")
+		if found.Status != entity.APIKeyStatusRevoked {
+			t.Errorf("Expected status revoked, got %s", found.Status)
+		}
+
+		if err := repo.Update(ctx, createTestAPIKey("missing")); err == nil {
+			t.Error("Expected error updating missing key")
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		repo := NewMemoryAPIKeyRepository()
+		first := createTestAPIKey("key-1")
+		second := createTestAPIKey("key-2")
+		second.CreatedAt = first.CreatedAt.Add(time.Hour)
+		repo.Save(ctx, second)
+		repo.Save(ctx, first)
+
+		keys, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(keys) != 2 || keys[0].ID != "key-1" || keys[1].ID != "key-2" {
+			t.Errorf("Expected keys ordered by creation, got %v", keys)
+		}
+	})
+}
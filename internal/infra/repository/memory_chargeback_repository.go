@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// MemoryChargebackRepository implements ChargebackRepository in memory
+// It is intended for tests and local development without DynamoDB; it follows the
+// DynamoDB repository's semantics, e.g. SaveBatch overwrites existing chargebacks and
+// transaction IDs aren't required to be unique. Listings are ordered by ID. Soft deleted
+// chargebacks stay in the map with DeletedAt set.
+type MemoryChargebackRepository struct {
+	mu          sync.RWMutex
+	chargebacks map[string]*entity.Chargeback
+}
+
+// NewMemoryChargebackRepository creates a new in-memory chargeback repository
+func NewMemoryChargebackRepository() repository.ChargebackRepository {
+	return &MemoryChargebackRepository{
+		chargebacks: make(map[string]*entity.Chargeback),
+	}
+}
+
+// Save persists a new chargeback in memory
+func (r *MemoryChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if chargeback.ID == "" {
+		chargeback.ID = generateChargebackID()
+	}
+	if _, exists := r.chargebacks[chargeback.ID]; exists {
+		return fmt.Errorf("failed to save chargeback %s: %w", chargeback.ID, repository.ErrChargebackExists)
+	}
+
+	r.chargebacks[chargeback.ID] = copyChargeback(chargeback)
+	return nil
+}
+
+// SaveBatch persists new chargebacks in memory; like BatchWriteItem, it overwrites
+// chargebacks with the same ID
+func (r *MemoryChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, chargeback := range chargebacks {
+		if chargeback.ID == "" {
+			chargeback.ID = generateChargebackID()
+		}
+		r.chargebacks[chargeback.ID] = copyChargeback(chargeback)
+	}
+	return nil, nil
+}
+
+// FindByID retrieves a chargeback by its unique identifier
+func (r *MemoryChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chargeback, exists := r.chargebacks[id]
+	if !exists || chargeback.IsDeleted() {
+		return nil, nil // Not found
+	}
+	return copyChargeback(chargeback), nil
+}
+
+// FindByTransactionID retrieves a chargeback by transaction ID
+func (r *MemoryChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+	matches := r.findActive(func(chargeback *entity.Chargeback) bool {
+		return chargeback.TransactionID == transactionID
+	})
+	if len(matches) == 0 {
+		return nil, nil // Not found
+	}
+	return matches[0], nil
+}
+
+// FindByMerchantID retrieves all chargebacks for a specific merchant
+func (r *MemoryChargebackRepository) FindByMerchantID(ctx context.Context, merchantID string) ([]*entity.Chargeback, error) {
+	return r.findActive(func(chargeback *entity.Chargeback) bool {
+		return chargeback.MerchantID == merchantID
+	}), nil
+}
+
+// Update updates an existing chargeback in memory
+func (r *MemoryChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, exists := r.chargebacks[chargeback.ID]; !exists || stored.IsDeleted() {
+		return fmt.Errorf("failed to update chargeback %s: %w", chargeback.ID, repository.ErrChargebackNotFound)
+	}
+
+	chargeback.UpdatedAt = time.Now()
+	r.chargebacks[chargeback.ID] = copyChargeback(chargeback)
+	return nil
+}
+
+// Delete soft deletes a chargeback in memory
+func (r *MemoryChargebackRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chargeback, exists := r.chargebacks[id]
+	if !exists || chargeback.IsDeleted() {
+		return fmt.Errorf("failed to delete chargeback %s: %w", id, repository.ErrChargebackNotFound)
+	}
+
+	chargeback.DeletedAt = time.Now()
+	return nil
+}
+
+// Restore brings back a soft deleted chargeback in memory
+func (r *MemoryChargebackRepository) Restore(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chargeback, exists := r.chargebacks[id]
+	if !exists || !chargeback.IsDeleted() {
+		return fmt.Errorf("failed to restore chargeback %s: %w", id, repository.ErrChargebackNotFound)
+	}
+
+	chargeback.DeletedAt = time.Time{}
+	return nil
+}
+
+// Purge removes chargebacks from memory for good
+func (r *MemoryChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, chargeback := range chargebacks {
+		delete(r.chargebacks, chargeback.ID)
+	}
+	return nil
+}
+
+// FindByStatus retrieves chargebacks by their status
+func (r *MemoryChargebackRepository) FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error) {
+	return r.findActive(func(chargeback *entity.Chargeback) bool {
+		return chargeback.Status == status
+	}), nil
+}
+
+// List retrieves chargebacks with pagination support
+func (r *MemoryChargebackRepository) List(ctx context.Context, offset, limit int) ([]*entity.Chargeback, error) {
+	chargebacks := r.findActive(func(*entity.Chargeback) bool { return true })
+	if offset >= len(chargebacks) {
+		return []*entity.Chargeback{}, nil
+	}
+	return chargebacks[offset:min(offset+limit, len(chargebacks))], nil
+}
+
+// ListPage retrieves a page of chargebacks matching the filter
+// The cursor is the encoded ID of the last chargeback of the previous page
+func (r *MemoryChargebackRepository) ListPage(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+	var after string
+	if cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(data) == 0 {
+			return nil, repository.ErrInvalidCursor
+		}
+		after = string(data)
+	}
+
+	matches := r.find(func(chargeback *entity.Chargeback) bool {
+		return chargeback.ID > after && filter.Matches(chargeback)
+	})
+
+	page := &repository.ChargebackPage{Chargebacks: matches}
+	if len(matches) > limit {
+		page.Chargebacks = matches[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(matches[limit-1].ID))
+	}
+	return page, nil
+}
+
+// findActive returns copies of the chargebacks that match and aren't deleted, ordered by ID
+func (r *MemoryChargebackRepository) findActive(match func(*entity.Chargeback) bool) []*entity.Chargeback {
+	return r.find(func(chargeback *entity.Chargeback) bool {
+		return !chargeback.IsDeleted() && match(chargeback)
+	})
+}
+
+// find returns copies of the chargebacks that match, ordered by ID
+func (r *MemoryChargebackRepository) find(match func(*entity.Chargeback) bool) []*entity.Chargeback {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chargebacks := make([]*entity.Chargeback, 0)
+	for _, chargeback := range r.chargebacks {
+		if match(chargeback) {
+			chargebacks = append(chargebacks, copyChargeback(chargeback))
+		}
+	}
+
+	slices.SortFunc(chargebacks, func(a, b *entity.Chargeback) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return chargebacks
+}
+
+// copyChargeback returns a copy so callers cannot mutate stored state
+func copyChargeback(chargeback *entity.Chargeback) *entity.Chargeback {
+	clone := *chargeback
+	return &clone
+}
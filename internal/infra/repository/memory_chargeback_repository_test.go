@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository/repositorytest"
+)
+
+func TestMemoryChargebackRepository_Contract(t *testing.T) {
+	repositorytest.TestChargebackRepository(t, func(t *testing.T) repository.ChargebackRepository {
+		return NewMemoryChargebackRepository()
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// merchantMonth identifies a merchant's statistics for a month
+type merchantMonth struct {
+	merchantID string
+	month      string
+}
+
+// MemoryMerchantStatsRepository implements MerchantStatsRepository in memory
+// It is intended for local development and single-instance deployments
+type MemoryMerchantStatsRepository struct {
+	mu    sync.Mutex
+	stats map[merchantMonth]*entity.MerchantMonthlyStats
+}
+
+// NewMemoryMerchantStatsRepository creates a new in-memory merchant statistics repository
+func NewMemoryMerchantStatsRepository() repository.MerchantStatsRepository {
+	return &MemoryMerchantStatsRepository{
+		stats: make(map[merchantMonth]*entity.MerchantMonthlyStats),
+	}
+}
+
+// AddChargebacks adds the delta to a merchant's month and returns the updated statistics
+func (r *MemoryMerchantStatsRepository) AddChargebacks(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) (*entity.MerchantMonthlyStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.monthLocked(merchantID, month)
+	stats.Apply(delta)
+	stats.UpdatedAt = time.Now()
+
+	clone := *stats
+	return &clone, nil
+}
+
+// SetTransactionVolume replaces a merchant's sales for a month and returns the updated statistics
+func (r *MemoryMerchantStatsRepository) SetTransactionVolume(ctx context.Context, merchantID, month string, count int, amount float64) (*entity.MerchantMonthlyStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.monthLocked(merchantID, month)
+	stats.TransactionCount = count
+	stats.TransactionAmount = amount
+	stats.UpdatedAt = time.Now()
+
+	clone := *stats
+	return &clone, nil
+}
+
+// Find retrieves a merchant's statistics for a month, nil when there are none
+func (r *MemoryMerchantStatsRepository) Find(ctx context.Context, merchantID, month string) (*entity.MerchantMonthlyStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats, ok := r.stats[merchantMonth{merchantID, month}]
+	if !ok {
+		return nil, nil
+	}
+	clone := *stats
+	return &clone, nil
+}
+
+// ListByMerchant retrieves a merchant's statistics from one month to another, oldest first
+func (r *MemoryMerchantStatsRepository) ListByMerchant(ctx context.Context, merchantID, fromMonth, toMonth string) ([]*entity.MerchantMonthlyStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var months []*entity.MerchantMonthlyStats
+	for key, stats := range r.stats {
+		// Months sort lexically, as they are formatted as YYYY-MM
+		if key.merchantID == merchantID && key.month >= fromMonth && key.month <= toMonth {
+			clone := *stats
+			months = append(months, &clone)
+		}
+	}
+
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Month < months[j].Month
+	})
+	return months, nil
+}
+
+// monthLocked returns the stored statistics of a merchant's month, creating them if needed
+// The caller must hold the lock
+func (r *MemoryMerchantStatsRepository) monthLocked(merchantID, month string) *entity.MerchantMonthlyStats {
+	key := merchantMonth{merchantID, month}
+	stats, ok := r.stats[key]
+	if !ok {
+		stats = &entity.MerchantMonthlyStats{MerchantID: merchantID, Month: month}
+		r.stats[key] = stats
+	}
+	return stats
+}
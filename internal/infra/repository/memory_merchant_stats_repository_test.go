@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestMemoryMerchantStatsRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("add chargebacks and set volume", func(t *testing.T) {
+		repo := NewMemoryMerchantStatsRepository()
+
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: 99.99})
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: 0.01})
+		stats, err := repo.SetTransactionVolume(ctx, "merchant-789", "2023-01", 100, 10000)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.ChargebackCount != 2 || stats.ChargebackAmount != 100 || stats.TransactionCount != 100 {
+			t.Errorf("Unexpected statistics %+v", stats)
+		}
+		if stats.CountRatio() != 0.02 {
+			t.Errorf("Expected count ratio 0.02, got %v", stats.CountRatio())
+		}
+	})
+
+	t.Run("find missing month", func(t *testing.T) {
+		repo := NewMemoryMerchantStatsRepository()
+
+		stats, err := repo.Find(ctx, "merchant-789", "2023-01")
+
+		if err != nil || stats != nil {
+			t.Errorf("Expected nil statistics, got %v, %v", stats, err)
+		}
+	})
+
+	t.Run("list by merchant", func(t *testing.T) {
+		repo := NewMemoryMerchantStatsRepository()
+		delta := entity.MerchantStatsDelta{ChargebackCount: 1}
+		repo.AddChargebacks(ctx, "merchant-789", "2023-03", delta)
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", delta)
+		repo.AddChargebacks(ctx, "merchant-789", "2022-12", delta)
+		repo.AddChargebacks(ctx, "merchant-000", "2023-02", delta)
+
+		months, err := repo.ListByMerchant(ctx, "merchant-789", "2023-01", "2023-03")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(months) != 2 || months[0].Month != "2023-01" || months[1].Month != "2023-03" {
+			t.Errorf("Expected 2023-01 and 2023-03, got %v", months)
+		}
+	})
+}
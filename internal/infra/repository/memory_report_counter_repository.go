@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// MemoryReportCounterRepository implements ReportCounterRepository in memory
+// It is intended for local development and single-instance deployments
+type MemoryReportCounterRepository struct {
+	mu       sync.Mutex
+	counters map[entity.ReportCell]*entity.ReportCounter
+}
+
+// NewMemoryReportCounterRepository creates a new in-memory report counter repository
+func NewMemoryReportCounterRepository() repository.ReportCounterRepository {
+	return &MemoryReportCounterRepository{
+		counters: make(map[entity.ReportCell]*entity.ReportCounter),
+	}
+}
+
+// Add adds to the number and total amount of the chargebacks in a cell
+func (r *MemoryReportCounterRepository) Add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[cell]
+	if !ok {
+		counter = &entity.ReportCounter{ReportCell: cell}
+		r.counters[cell] = counter
+	}
+	counter.Count += count
+	counter.Amount += amount
+	return nil
+}
+
+// List retrieves the counters from one day to another, both included
+func (r *MemoryReportCounterRepository) List(ctx context.Context, fromDay, toDay string) ([]*entity.ReportCounter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var counters []*entity.ReportCounter
+	for cell, counter := range r.counters {
+		// Days sort lexically, as they are formatted as YYYY-MM-DD
+		if cell.Day >= fromDay && cell.Day <= toDay {
+			clone := *counter
+			counters = append(counters, &clone)
+		}
+	}
+	return counters, nil
+}
+
+// ReplaceAll replaces every counter with the given ones
+func (r *MemoryReportCounterRepository) ReplaceAll(ctx context.Context, counters []*entity.ReportCounter) error {
+	replaced := make(map[entity.ReportCell]*entity.ReportCounter, len(counters))
+	for _, counter := range counters {
+		clone := *counter
+		replaced[counter.ReportCell] = &clone
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counters = replaced
+	return nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// ObservedChargebackRepository notifies observers of the writes made through a chargeback repository
+// Updates and deletes read the stored chargeback first, so observers can tell what changed.
+// Observer errors are logged rather than returned, since the write already happened.
+type ObservedChargebackRepository struct {
+	repository.ChargebackRepository
+	observers []repository.ChargebackObserver
+	logger    service.Logger
+}
+
+// NewObservedChargebackRepository wraps the repository to notify the observers of its writes
+func NewObservedChargebackRepository(repo repository.ChargebackRepository, logger service.Logger, observers ...repository.ChargebackObserver) *ObservedChargebackRepository {
+	return &ObservedChargebackRepository{
+		ChargebackRepository: repo,
+		observers:            observers,
+		logger:               logger,
+	}
+}
+
+// Save persists a new chargeback and notifies the observers
+func (r *ObservedChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	if err := r.ChargebackRepository.Save(ctx, chargeback); err != nil {
+		return err
+	}
+
+	r.notify(ctx, "saved", chargeback, func(observer repository.ChargebackObserver) error {
+		return observer.ChargebackSaved(ctx, chargeback)
+	})
+	return nil
+}
+
+// SaveBatch persists new chargebacks and notifies the observers of those that were saved
+func (r *ObservedChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	failed, err := r.ChargebackRepository.SaveBatch(ctx, chargebacks)
+
+	unsaved := make(map[*entity.Chargeback]bool, len(failed))
+	for _, chargeback := range failed {
+		unsaved[chargeback] = true
+	}
+	for _, chargeback := range chargebacks {
+		if unsaved[chargeback] {
+			continue
+		}
+		r.notify(ctx, "saved", chargeback, func(observer repository.ChargebackObserver) error {
+			return observer.ChargebackSaved(ctx, chargeback)
+		})
+	}
+
+	return failed, err
+}
+
+// Update updates an existing chargeback and notifies the observers
+func (r *ObservedChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	previous, err := r.ChargebackRepository.FindByID(ctx, chargeback.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ChargebackRepository.Update(ctx, chargeback); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		r.notify(ctx, "updated", chargeback, func(observer repository.ChargebackObserver) error {
+			return observer.ChargebackUpdated(ctx, previous, chargeback)
+		})
+	}
+	return nil
+}
+
+// Delete soft deletes a chargeback and notifies the observers
+func (r *ObservedChargebackRepository) Delete(ctx context.Context, id string) error {
+	previous, err := r.ChargebackRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.ChargebackRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if previous != nil {
+		r.notify(ctx, "deleted", previous, func(observer repository.ChargebackObserver) error {
+			return observer.ChargebackDeleted(ctx, previous)
+		})
+	}
+	return nil
+}
+
+// Restore brings back a soft deleted chargeback and notifies the observers as if it was saved
+func (r *ObservedChargebackRepository) Restore(ctx context.Context, id string) error {
+	if err := r.ChargebackRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+
+	restored, err := r.ChargebackRepository.FindByID(ctx, id)
+	if err != nil {
+		r.logger.Error(ctx, "Failed to read restored chargeback", map[string]interface{}{
+			"chargeback_id": id,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+	if restored != nil {
+		r.notify(ctx, "restored", restored, func(observer repository.ChargebackObserver) error {
+			return observer.ChargebackSaved(ctx, restored)
+		})
+	}
+	return nil
+}
+
+// Purge removes chargebacks for good and notifies the observers of those that weren't
+// deleted, since deleted ones were already reported
+func (r *ObservedChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	if err := r.ChargebackRepository.Purge(ctx, chargebacks); err != nil {
+		return err
+	}
+
+	for _, chargeback := range chargebacks {
+		if chargeback.IsDeleted() {
+			continue
+		}
+		r.notify(ctx, "purged", chargeback, func(observer repository.ChargebackObserver) error {
+			return observer.ChargebackDeleted(ctx, chargeback)
+		})
+	}
+	return nil
+}
+
+// notify calls each observer, logging the errors
+func (r *ObservedChargebackRepository) notify(ctx context.Context, event string, chargeback *entity.Chargeback, call func(repository.ChargebackObserver) error) {
+	for _, observer := range r.observers {
+		if err := call(observer); err != nil {
+			r.logger.Error(ctx, "Failed to notify chargeback observer", map[string]interface{}{
+				"event":         event,
+				"chargeback_id": chargeback.ID,
+				"merchant_id":   chargeback.MerchantID,
+				"error":         err.Error(),
+			})
+		}
+	}
+}
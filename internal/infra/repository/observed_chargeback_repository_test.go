@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// recordingLogger keeps the messages of logged warnings and errors
+type recordingLogger struct {
+	warnings []string
+	errors   []string
+}
+
+func (l *recordingLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *recordingLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *recordingLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *recordingLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	l.warnings = append(l.warnings, message)
+	return nil
+}
+func (l *recordingLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	l.errors = append(l.errors, message)
+	return nil
+}
+func (l *recordingLogger) WithContext(ctx context.Context) service.Logger { return l }
+
+// stubChargebackRepository keeps chargebacks in a map and fails writes of failing IDs
+type stubChargebackRepository struct {
+	repository.ChargebackRepository
+	stored  map[string]entity.Chargeback
+	failing string
+}
+
+func (r *stubChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	if chargeback.ID == r.failing {
+		return errors.New("failed to save chargeback: DynamoDB unavailable")
+	}
+	r.stored[chargeback.ID] = *chargeback
+	return nil
+}
+
+func (r *stubChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	var failed []*entity.Chargeback
+	for _, chargeback := range chargebacks {
+		if err := r.Save(ctx, chargeback); err != nil {
+			failed = append(failed, chargeback)
+		}
+	}
+	return failed, nil
+}
+
+func (r *stubChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	chargeback, ok := r.stored[id]
+	if !ok || chargeback.IsDeleted() {
+		return nil, nil
+	}
+	return &chargeback, nil
+}
+
+func (r *stubChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	r.stored[chargeback.ID] = *chargeback
+	return nil
+}
+
+func (r *stubChargebackRepository) Delete(ctx context.Context, id string) error {
+	chargeback, ok := r.stored[id]
+	if !ok || chargeback.IsDeleted() {
+		return repository.ErrChargebackNotFound
+	}
+	chargeback.DeletedAt = time.Now()
+	r.stored[id] = chargeback
+	return nil
+}
+
+func (r *stubChargebackRepository) Restore(ctx context.Context, id string) error {
+	chargeback, ok := r.stored[id]
+	if !ok || !chargeback.IsDeleted() {
+		return repository.ErrChargebackNotFound
+	}
+	chargeback.DeletedAt = time.Time{}
+	r.stored[id] = chargeback
+	return nil
+}
+
+func (r *stubChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	for _, chargeback := range chargebacks {
+		delete(r.stored, chargeback.ID)
+	}
+	return nil
+}
+
+// recordingObserver records the events it is notified of
+type recordingObserver struct {
+	events []string
+	err    error
+}
+
+func (o *recordingObserver) ChargebackSaved(ctx context.Context, chargeback *entity.Chargeback) error {
+	o.events = append(o.events, "saved:"+chargeback.ID)
+	return o.err
+}
+
+func (o *recordingObserver) ChargebackUpdated(ctx context.Context, previous, current *entity.Chargeback) error {
+	o.events = append(o.events, "updated:"+current.ID+":"+string(previous.Status)+">"+string(current.Status))
+	return o.err
+}
+
+func (o *recordingObserver) ChargebackDeleted(ctx context.Context, chargeback *entity.Chargeback) error {
+	o.events = append(o.events, "deleted:"+chargeback.ID)
+	return o.err
+}
+
+func TestObservedChargebackRepository(t *testing.T) {
+	// Arrange
+	inner := &stubChargebackRepository{stored: make(map[string]entity.Chargeback), failing: "cb_failing"}
+	observer := &recordingObserver{}
+	logger := &recordingLogger{}
+	repo := NewObservedChargebackRepository(inner, logger, observer)
+	ctx := context.Background()
+
+	// Act
+	repo.Save(ctx, &entity.Chargeback{ID: "cb_1", Status: entity.StatusPending})
+	saveErr := repo.Save(ctx, &entity.Chargeback{ID: "cb_failing"})
+	failed, _ := repo.SaveBatch(ctx, []*entity.Chargeback{{ID: "cb_2"}, {ID: "cb_failing"}})
+	repo.Update(ctx, &entity.Chargeback{ID: "cb_1", Status: entity.StatusRejected})
+	repo.Delete(ctx, "cb_2")
+	repo.Delete(ctx, "cb_missing")
+	repo.Restore(ctx, "cb_2")
+	repo.Restore(ctx, "cb_missing")
+	repo.Purge(ctx, []*entity.Chargeback{{ID: "cb_1"}, {ID: "cb_3", DeletedAt: time.Now()}})
+
+	// Assert
+	if saveErr == nil {
+		t.Error("Expected the failed save to be reported")
+	}
+	if len(failed) != 1 || failed[0].ID != "cb_failing" {
+		t.Errorf("Expected the failed batch item to be returned, got %v", failed)
+	}
+	expected := "saved:cb_1,saved:cb_2,updated:cb_1:pending>rejected,deleted:cb_2,saved:cb_2,deleted:cb_1"
+	if strings.Join(observer.events, ",") != expected {
+		t.Errorf("Expected events %s, got %v", expected, observer.events)
+	}
+	if len(logger.errors) != 0 {
+		t.Errorf("Expected no logged errors, got %v", logger.errors)
+	}
+}
+
+func TestObservedChargebackRepository_ObserverErrorsAreLogged(t *testing.T) {
+	// Arrange
+	inner := &stubChargebackRepository{stored: make(map[string]entity.Chargeback)}
+	logger := &recordingLogger{}
+	repo := NewObservedChargebackRepository(inner, logger, &recordingObserver{err: errors.New("stats table unavailable")})
+
+	// Act
+	err := repo.Save(context.Background(), &entity.Chargeback{ID: "cb_1"})
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected the save to succeed, got %v", err)
+	}
+	if _, ok := inner.stored["cb_1"]; !ok {
+		t.Error("Expected the chargeback to be saved")
+	}
+	if len(logger.errors) != 1 {
+		t.Errorf("Expected the observer error to be logged, got %v", logger.errors)
+	}
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// postgresUniqueViolation is the PostgreSQL error code of a unique constraint violation
+const postgresUniqueViolation = "23505"
+
+// NewPostgresChargebackRepository creates a new PostgreSQL chargeback repository
+func NewPostgresChargebackRepository(db *sql.DB) repository.ChargebackRepository {
+	return &SQLChargebackRepository{
+		db:                db,
+		isUniqueViolation: isPostgresUniqueViolation,
+	}
+}
+
+// isPostgresUniqueViolation reports whether an error was caused by a unique constraint
+func isPostgresUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == postgresUniqueViolation
+}
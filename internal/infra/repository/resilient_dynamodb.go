@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// ErrCircuitOpen is returned without calling DynamoDB while the circuit breaker is open
+var ErrCircuitOpen = errors.New("DynamoDB circuit breaker is open")
+
+// RetryPolicy configures how DynamoDB calls failing with retryable errors are retried
+// Delays grow exponentially from BaseDelay up to MaxDelay, with full jitter so instances
+// throttled together don't retry together.
+type RetryPolicy struct {
+	// MaxAttempts counts the first call; 1 disables retries
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Retryable classifies errors, IsRetryableDynamoDBError when nil
+	Retryable func(err error) bool
+}
+
+// CircuitBreakerPolicy configures when DynamoDB is considered degraded
+// After FailureThreshold consecutive calls fail with retryable errors, calls fail fast with
+// ErrCircuitOpen for OpenTimeout; then a single trial call decides whether to close the
+// breaker or keep it open.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold of 0 disables the breaker
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used unless configured otherwise
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second}
+}
+
+// DefaultCircuitBreakerPolicy returns the breaker policy used unless configured otherwise
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{FailureThreshold: 5, OpenTimeout: 30 * time.Second}
+}
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// DynamoDBCallStats counts the calls made through a ResilientDynamoDB
+type DynamoDBCallStats struct {
+	Calls   int64 `json:"calls"`
+	Retries int64 `json:"retries"`
+	// Failures counts calls that failed with a retryable error once retries ran out
+	Failures int64 `json:"failures"`
+	// Rejected counts calls failed fast while the breaker was open
+	Rejected     int64  `json:"rejected"`
+	BreakerOpens int64  `json:"breaker_opens"`
+	BreakerState string `json:"breaker_state"`
+}
+
+// ResilientDynamoDB decorates a DynamoDBAPI with retries and a circuit breaker
+// Errors that aren't retryable, such as failed conditions, are returned at once and
+// don't count against the breaker. Conditional writes are only retried after throttling:
+// after a server error or a timeout the write may have been applied, and its retry would
+// then fail its own condition. The SDK client retries on its own too; each attempt here
+// includes those.
+type ResilientDynamoDB struct {
+	client  DynamoDBAPI
+	retry   RetryPolicy
+	breaker CircuitBreakerPolicy
+	logger  service.Logger
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	calls, retries, failures, rejected, opens atomic.Int64
+
+	mu          sync.Mutex
+	state       string
+	consecutive int
+	openedAt    time.Time
+	trialActive bool
+}
+
+// NewResilientDynamoDB wraps the client with the retry and circuit breaker policies
+func NewResilientDynamoDB(client DynamoDBAPI, retry RetryPolicy, breaker CircuitBreakerPolicy, logger service.Logger) *ResilientDynamoDB {
+	if retry.MaxAttempts < 1 {
+		retry.MaxAttempts = 1
+	}
+	if retry.Retryable == nil {
+		retry.Retryable = IsRetryableDynamoDBError
+	}
+	return &ResilientDynamoDB{
+		client:  client,
+		retry:   retry,
+		breaker: breaker,
+		logger:  logger,
+		now:     time.Now,
+		sleep:   sleepContext,
+		state:   BreakerClosed,
+	}
+}
+
+// IsRetryableDynamoDBError reports whether an error is transient: throttling, server
+// errors and network timeouts
+func IsRetryableDynamoDBError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsThrottledDynamoDBError(err) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "InternalServerError", "ServiceUnavailable":
+			return true
+		}
+	}
+
+	var responseErr *awshttp.ResponseError
+	if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() >= 500 {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsThrottledDynamoDBError reports whether DynamoDB rejected a call for exceeding its
+// throughput, which means the call wasn't applied
+func IsThrottledDynamoDBError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "ProvisionedThroughputExceededException", "RequestLimitExceeded", "ThrottlingException", "Throttling":
+		return true
+	}
+	return false
+}
+
+// Stats returns the call counters and the breaker state
+func (d *ResilientDynamoDB) Stats() DynamoDBCallStats {
+	d.mu.Lock()
+	state := d.state
+	d.mu.Unlock()
+
+	return DynamoDBCallStats{
+		Calls:        d.calls.Load(),
+		Retries:      d.retries.Load(),
+		Failures:     d.failures.Load(),
+		Rejected:     d.rejected.Load(),
+		BreakerOpens: d.opens.Load(),
+		BreakerState: state,
+	}
+}
+
+func (d *ResilientDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return call(ctx, d, "PutItem", params.ConditionExpression != nil, func(ctx context.Context) (*dynamodb.PutItemOutput, error) {
+		return d.client.PutItem(ctx, params, optFns...)
+	})
+}
+
+func (d *ResilientDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return call(ctx, d, "GetItem", false, func(ctx context.Context) (*dynamodb.GetItemOutput, error) {
+		return d.client.GetItem(ctx, params, optFns...)
+	})
+}
+
+func (d *ResilientDynamoDB) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return call(ctx, d, "Query", false, func(ctx context.Context) (*dynamodb.QueryOutput, error) {
+		return d.client.Query(ctx, params, optFns...)
+	})
+}
+
+func (d *ResilientDynamoDB) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return call(ctx, d, "UpdateItem", params.ConditionExpression != nil, func(ctx context.Context) (*dynamodb.UpdateItemOutput, error) {
+		return d.client.UpdateItem(ctx, params, optFns...)
+	})
+}
+
+func (d *ResilientDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return call(ctx, d, "Scan", false, func(ctx context.Context) (*dynamodb.ScanOutput, error) {
+		return d.client.Scan(ctx, params, optFns...)
+	})
+}
+
+func (d *ResilientDynamoDB) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return call(ctx, d, "BatchWriteItem", false, func(ctx context.Context) (*dynamodb.BatchWriteItemOutput, error) {
+		return d.client.BatchWriteItem(ctx, params, optFns...)
+	})
+}
+
+// call runs a DynamoDB operation through the breaker, retrying retryable errors
+// Conditional operations are only retried when they were throttled.
+func call[T any](ctx context.Context, d *ResilientDynamoDB, operation string, conditional bool, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+	d.calls.Add(1)
+	if !d.allow(ctx) {
+		d.rejected.Add(1)
+		return zero, ErrCircuitOpen
+	}
+
+	for attempt := 1; ; attempt++ {
+		output, err := fn(ctx)
+		if err == nil {
+			d.record(ctx, true)
+			return output, nil
+		}
+		if errors.Is(err, context.Canceled) {
+			// The caller gave up, which says nothing about DynamoDB
+			d.abandon()
+			return zero, err
+		}
+		if !d.retry.Retryable(err) {
+			// Anything but a timeout was answered by DynamoDB, so it is healthy
+			d.record(ctx, !errors.Is(err, context.DeadlineExceeded))
+			return zero, err
+		}
+		if attempt >= d.retry.MaxAttempts || (conditional && !IsThrottledDynamoDBError(err)) {
+			d.failures.Add(1)
+			d.record(ctx, false)
+			return zero, err
+		}
+
+		delay := d.backoff(attempt)
+		d.retries.Add(1)
+		d.logger.Debug(ctx, "Retrying DynamoDB call", map[string]interface{}{
+			"operation": operation,
+			"attempt":   attempt,
+			"delay_ms":  delay.Milliseconds(),
+			"error":     err.Error(),
+		})
+		if err := d.sleep(ctx, delay); err != nil {
+			d.abandon()
+			return zero, err
+		}
+	}
+}
+
+// backoff returns the jittered delay before the given retry
+func (d *ResilientDynamoDB) backoff(attempt int) time.Duration {
+	ceiling := d.retry.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > d.retry.MaxDelay {
+		ceiling = d.retry.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// allow reports whether a call may go through, moving an open breaker to half-open once
+// its timeout passed
+func (d *ResilientDynamoDB) allow(ctx context.Context) bool {
+	if d.breaker.FailureThreshold <= 0 {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch d.state {
+	case BreakerOpen:
+		if d.now().Sub(d.openedAt) < d.breaker.OpenTimeout {
+			return false
+		}
+		d.transition(ctx, BreakerHalfOpen)
+		d.trialActive = true
+		return true
+	case BreakerHalfOpen:
+		// Only the trial call goes through until it completes
+		if d.trialActive {
+			return false
+		}
+		d.trialActive = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record feeds the outcome of a call to the breaker
+func (d *ResilientDynamoDB) record(ctx context.Context, healthy bool) {
+	if d.breaker.FailureThreshold <= 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state == BreakerHalfOpen {
+		d.trialActive = false
+		if healthy {
+			d.consecutive = 0
+			d.transition(ctx, BreakerClosed)
+		} else {
+			d.open(ctx)
+		}
+		return
+	}
+
+	if healthy {
+		d.consecutive = 0
+		return
+	}
+	d.consecutive++
+	if d.state == BreakerClosed && d.consecutive >= d.breaker.FailureThreshold {
+		d.open(ctx)
+	}
+}
+
+// abandon releases the trial call of a half-open breaker without deciding its state
+func (d *ResilientDynamoDB) abandon() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.trialActive = false
+}
+
+// open opens the breaker; d.mu must be held
+func (d *ResilientDynamoDB) open(ctx context.Context) {
+	d.openedAt = d.now()
+	d.opens.Add(1)
+	d.transition(ctx, BreakerOpen)
+}
+
+// transition changes the breaker state and logs it; d.mu must be held
+func (d *ResilientDynamoDB) transition(ctx context.Context, state string) {
+	if d.state == state {
+		return
+	}
+	fields := map[string]interface{}{
+		"from":                 d.state,
+		"to":                   state,
+		"consecutive_failures": d.consecutive,
+		"calls":                d.calls.Load(),
+		"retries":              d.retries.Load(),
+		"failures":             d.failures.Load(),
+		"rejected":             d.rejected.Load(),
+	}
+	d.state = state
+
+	if state == BreakerOpen {
+		fields["open_timeout"] = d.breaker.OpenTimeout.String()
+		d.logger.Warn(ctx, "DynamoDB circuit breaker opened", fields)
+		return
+	}
+	d.logger.Info(ctx, "DynamoDB circuit breaker state changed", fields)
+}
+
+// sleepContext waits for the delay or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// newTestResilientDynamoDB returns a decorator that doesn't wait between retries and
+// whose clock is advanced by the returned function
+func newTestResilientDynamoDB(client DynamoDBAPI, retry RetryPolicy, breaker CircuitBreakerPolicy) (*ResilientDynamoDB, *recordingLogger, func(time.Duration)) {
+	logger := &recordingLogger{}
+	d := NewResilientDynamoDB(client, retry, breaker, logger)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+	d.sleep = func(ctx context.Context, delay time.Duration) error { return ctx.Err() }
+	return d, logger, func(elapsed time.Duration) { now = now.Add(elapsed) }
+}
+
+func throttled() error {
+	return fmt.Errorf("operation error DynamoDB: GetItem: %w", &types.ProvisionedThroughputExceededException{Message: aws.String("Rate exceeded")})
+}
+
+func TestIsRetryableDynamoDBError(t *testing.T) {
+	serverError := &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}},
+		Err:      errors.New("service unavailable"),
+	}}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "provisioned throughput exceeded", err: throttled(), expected: true},
+		{name: "request limit exceeded", err: &types.RequestLimitExceeded{Message: aws.String("limit")}, expected: true},
+		{name: "internal server error", err: &types.InternalServerError{Message: aws.String("oops")}, expected: true},
+		{name: "5xx response", err: serverError, expected: true},
+		{name: "failed condition", err: &types.ConditionalCheckFailedException{Message: aws.String("failed")}, expected: false},
+		{name: "missing table", err: &types.ResourceNotFoundException{Message: aws.String("missing")}, expected: false},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableDynamoDBError(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestResilientDynamoDB_Retry(t *testing.T) {
+	retry := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	ctx := context.Background()
+
+	t.Run("retries throttled calls until they succeed", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		mock := &MockDynamoDBAPI{
+			GetItemFunc: func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+				calls++
+				if calls < 3 {
+					return nil, throttled()
+				}
+				return &dynamodb.GetItemOutput{}, nil
+			},
+		}
+		d, _, _ := newTestResilientDynamoDB(mock, retry, CircuitBreakerPolicy{})
+
+		// Act
+		_, err := d.GetItem(ctx, &dynamodb.GetItemInput{})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats := d.Stats(); calls != 3 || stats.Retries != 2 || stats.Failures != 0 {
+			t.Errorf("Expected 3 attempts and 2 retries, got %d attempts and %+v", calls, stats)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		calls := 0
+		mock := &MockDynamoDBAPI{
+			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				calls++
+				return nil, throttled()
+			},
+		}
+		d, _, _ := newTestResilientDynamoDB(mock, retry, CircuitBreakerPolicy{})
+
+		_, err := d.PutItem(ctx, &dynamodb.PutItemInput{})
+
+		var throughput *types.ProvisionedThroughputExceededException
+		if !errors.As(err, &throughput) {
+			t.Errorf("Expected the throttling error, got %v", err)
+		}
+		if calls != 3 || d.Stats().Failures != 1 {
+			t.Errorf("Expected 3 attempts and a failure, got %d attempts and %+v", calls, d.Stats())
+		}
+	})
+
+	t.Run("returns other errors at once", func(t *testing.T) {
+		calls := 0
+		mock := &MockDynamoDBAPI{
+			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				calls++
+				return nil, &types.ConditionalCheckFailedException{Message: aws.String("failed")}
+			},
+		}
+		d, _, _ := newTestResilientDynamoDB(mock, retry, CircuitBreakerPolicy{})
+
+		_, err := d.PutItem(ctx, &dynamodb.PutItemInput{})
+
+		if !isConditionFailed(err) || calls != 1 {
+			t.Errorf("Expected a single failed condition, got %d calls and %v", calls, err)
+		}
+	})
+
+	t.Run("doesn't retry conditional writes that may have been applied", func(t *testing.T) {
+		// Arrange: the first attempt is applied but its response is lost
+		calls := 0
+		mock := &MockDynamoDBAPI{
+			PutItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+				calls++
+				if calls > 1 {
+					return nil, &types.ConditionalCheckFailedException{Message: aws.String("failed")}
+				}
+				return nil, &types.InternalServerError{Message: aws.String("oops")}
+			},
+		}
+		d, _, _ := newTestResilientDynamoDB(mock, retry, CircuitBreakerPolicy{})
+
+		// Act
+		_, err := d.PutItem(ctx, &dynamodb.PutItemInput{ConditionExpression: aws.String("attribute_not_exists(id)")})
+
+		// Assert
+		var serverErr *types.InternalServerError
+		if !errors.As(err, &serverErr) || calls != 1 {
+			t.Errorf("Expected the server error after a single attempt, got %d attempts and %v", calls, err)
+		}
+		if d.Stats().Failures != 1 {
+			t.Errorf("Expected a failure, got %+v", d.Stats())
+		}
+	})
+
+	t.Run("retries throttled conditional writes", func(t *testing.T) {
+		// Arrange
+		calls := 0
+		mock := &MockDynamoDBAPI{
+			UpdateItemFunc: func(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+				calls++
+				if calls == 1 {
+					return nil, throttled()
+				}
+				return &dynamodb.UpdateItemOutput{}, nil
+			},
+		}
+		d, _, _ := newTestResilientDynamoDB(mock, retry, CircuitBreakerPolicy{})
+
+		// Act
+		_, err := d.UpdateItem(ctx, &dynamodb.UpdateItemInput{ConditionExpression: aws.String("attribute_exists(id)")})
+
+		// Assert
+		if err != nil || calls != 2 {
+			t.Errorf("Expected the write to succeed on its retry, got %d attempts and %v", calls, err)
+		}
+	})
+
+	t.Run("jittered delays stay under the cap", func(t *testing.T) {
+		d, _, _ := newTestResilientDynamoDB(&MockDynamoDBAPI{}, retry, CircuitBreakerPolicy{})
+
+		for attempt := 1; attempt < 10; attempt++ {
+			if delay := d.backoff(attempt); delay < 0 || delay > retry.MaxDelay {
+				t.Errorf("Expected a delay within [0, %s] for attempt %d, got %s", retry.MaxDelay, attempt, delay)
+			}
+		}
+	})
+}
+
+func TestResilientDynamoDB_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	breaker := CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: 30 * time.Second}
+	noRetry := RetryPolicy{MaxAttempts: 1}
+
+	// Arrange
+	healthy := false
+	calls := 0
+	mock := &MockDynamoDBAPI{
+		QueryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			calls++
+			if !healthy {
+				return nil, throttled()
+			}
+			return &dynamodb.QueryOutput{}, nil
+		},
+	}
+	d, logger, advance := newTestResilientDynamoDB(mock, noRetry, breaker)
+
+	// Act & Assert
+	_, _ = d.Query(ctx, &dynamodb.QueryInput{})
+	_, _ = d.Query(ctx, &dynamodb.QueryInput{})
+	if d.Stats().BreakerState != BreakerOpen {
+		t.Fatalf("Expected the breaker to open after 2 failures, got %+v", d.Stats())
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("Expected the opening to be logged, got %v", logger.warnings)
+	}
+
+	if _, err := d.Query(ctx, &dynamodb.QueryInput{}); !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Fatalf("Expected to fail fast without calling DynamoDB, got %v after %d calls", err, calls)
+	}
+
+	advance(breaker.OpenTimeout)
+	if _, err := d.Query(ctx, &dynamodb.QueryInput{}); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a trial call after the timeout, got %v", err)
+	}
+	if d.Stats().BreakerState != BreakerOpen || d.Stats().BreakerOpens != 2 {
+		t.Fatalf("Expected a failed trial to reopen the breaker, got %+v", d.Stats())
+	}
+
+	healthy = true
+	advance(breaker.OpenTimeout)
+	if _, err := d.Query(ctx, &dynamodb.QueryInput{}); err != nil {
+		t.Fatalf("Expected the trial call to succeed, got %v", err)
+	}
+	if stats := d.Stats(); stats.BreakerState != BreakerClosed || stats.Rejected != 1 {
+		t.Errorf("Expected the breaker to close after a successful trial, got %+v", stats)
+	}
+}
+
+func TestResilientDynamoDB_Contract(t *testing.T) {
+	// A repository over the decorator behaves like one over the client
+	d := NewResilientDynamoDB(newFakeDynamoDB("id"), DefaultRetryPolicy(), DefaultCircuitBreakerPolicy(), &recordingLogger{})
+	repo := NewDynamoDBChargebackRepositoryWithInterface(d, "chargebacks")
+
+	if err := repo.Save(context.Background(), createTestChargeback()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := repo.Save(context.Background(), createTestChargeback()); err == nil {
+		t.Error("Expected a taken ID to be rejected")
+	}
+	if d.Stats().BreakerState != BreakerClosed {
+		t.Errorf("Expected failed conditions to leave the breaker closed, got %+v", d.Stats())
+	}
+}
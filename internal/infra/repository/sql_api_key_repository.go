@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// apiKeyColumns lists the columns of the api_keys table in scan order
+const apiKeyColumns = `id, name, secret_hash, merchant_ids, scopes, status, created_at, updated_at, rotated_at, revoked_at`
+
+// SQLAPIKeyRepository implements APIKeyRepository on a PostgreSQL or SQLite database
+// Merchant IDs and scopes are stored as JSON arrays, so both databases use the same queries.
+type SQLAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLAPIKeyRepository creates a new API key repository on a PostgreSQL or SQLite database
+func NewSQLAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &SQLAPIKeyRepository{db: db}
+}
+
+// Save persists a new API key to the database
+func (r *SQLAPIKeyRepository) Save(ctx context.Context, key *entity.APIKey) error {
+	values, err := apiKeyValues(key)
+	if err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `INSERT INTO api_keys (`+apiKeyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO NOTHING`, values...)
+	if err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return fmt.Errorf("failed to save API key: API key %s already exists", key.ID)
+	}
+	return nil
+}
+
+// FindByID retrieves an API key by its unique identifier
+func (r *SQLAPIKeyRepository) FindByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+	return key, nil
+}
+
+// Update updates an existing API key in the database
+func (r *SQLAPIKeyRepository) Update(ctx context.Context, key *entity.APIKey) error {
+	values, err := apiKeyValues(key)
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `UPDATE api_keys SET
+			name = $2, secret_hash = $3, merchant_ids = $4, scopes = $5, status = $6,
+			created_at = $7, updated_at = $8, rotated_at = $9, revoked_at = $10
+		WHERE id = $1`, values...)
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return fmt.Errorf("failed to update API key: API key %s does not exist", key.ID)
+	}
+	return nil
+}
+
+// List retrieves all API keys ordered by creation time
+func (r *SQLAPIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list API keys: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// apiKeyValues returns the values of an API key in column order
+func apiKeyValues(key *entity.APIKey) ([]interface{}, error) {
+	merchantIDs, err := json.Marshal(nonNil(key.MerchantIDs))
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := json.Marshal(nonNil(key.Scopes))
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		key.ID,
+		key.Name,
+		key.SecretHash,
+		string(merchantIDs),
+		string(scopes),
+		string(key.Status),
+		sqlTime(key.CreatedAt),
+		sqlTime(key.UpdatedAt),
+		sqlNullTime(key.RotatedAt),
+		sqlNullTime(key.RevokedAt),
+	}, nil
+}
+
+// nonNil returns an empty slice for nil, so it is stored as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// scanAPIKey reads a row in column order
+func scanAPIKey(row rowScanner) (*entity.APIKey, error) {
+	var key entity.APIKey
+	var merchantIDs, scopes, status string
+	var rotatedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.SecretHash,
+		&merchantIDs,
+		&scopes,
+		&status,
+		&key.CreatedAt,
+		&key.UpdatedAt,
+		&rotatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(merchantIDs), &key.MerchantIDs); err != nil {
+		return nil, fmt.Errorf("invalid merchant IDs of API key %s: %w", key.ID, err)
+	}
+	key.Scopes = []auth.Scope{}
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, fmt.Errorf("invalid scopes of API key %s: %w", key.ID, err)
+	}
+	key.Status = entity.APIKeyStatus(status)
+	key.RotatedAt = rotatedAt.Time
+	key.RevokedAt = revokedAt.Time
+	return &key, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestSQLAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save and find", func(t *testing.T) {
+		repo := NewSQLAPIKeyRepository(openTestSQLiteDB(t))
+		key := createTestAPIKey("key-1")
+		key.RotatedAt = time.Date(2023, 2, 1, 8, 30, 0, 0, time.UTC)
+
+		if err := repo.Save(ctx, key); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found, err := repo.FindByID(ctx, "key-1")
+		if err != nil || found == nil {
+			t.Fatalf("Expected key to be found, got %v, %v", found, err)
+		}
+		if found.SecretHash != "hash-key-1" || found.MerchantIDs[0] != "merchant-789" || found.Scopes[0] != key.Scopes[0] {
+			t.Errorf("Unexpected key %+v", found)
+		}
+		if !found.CreatedAt.Equal(key.CreatedAt) || !found.RotatedAt.Equal(key.RotatedAt) || !found.RevokedAt.IsZero() {
+			t.Errorf("Expected timestamps to round trip, got %+v", found)
+		}
+	})
+
+	t.Run("save duplicate", func(t *testing.T) {
+		repo := NewSQLAPIKeyRepository(openTestSQLiteDB(t))
+		repo.Save(ctx, createTestAPIKey("key-1"))
+
+		if err := repo.Save(ctx, createTestAPIKey("key-1")); err == nil {
+			t.Error("Expected error saving duplicate key")
+		}
+	})
+
+	t.Run("find not found", func(t *testing.T) {
+		repo := NewSQLAPIKeyRepository(openTestSQLiteDB(t))
+
+		found, err := repo.FindByID(ctx, "missing")
+		if err != nil || found != nil {
+			t.Errorf("Expected nil, nil for missing key, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := NewSQLAPIKeyRepository(openTestSQLiteDB(t))
+		key := createTestAPIKey("key-1")
+		repo.Save(ctx, key)
+
+		key.Status = entity.APIKeyStatusRevoked
+		key.RevokedAt = time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+		if err := repo.Update(ctx, key); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		found, _ := repo.FindByID(ctx, "key-1")
+		if found.Status != entity.APIKeyStatusRevoked || !found.RevokedAt.Equal(key.RevokedAt) {
+			t.Errorf("Expected key revoked, got %+v", found)
+		}
+
+		if err := repo.Update(ctx, createTestAPIKey("missing")); err == nil {
+			t.Error("Expected error updating missing key")
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		repo := NewSQLAPIKeyRepository(openTestSQLiteDB(t))
+		first := createTestAPIKey("key-1")
+		second := createTestAPIKey("key-2")
+		second.CreatedAt = first.CreatedAt.Add(time.Hour)
+		repo.Save(ctx, second)
+		repo.Save(ctx, first)
+
+		keys, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(keys) != 2 || keys[0].ID != "key-1" || keys[1].ID != "key-2" {
+			t.Errorf("Expected keys ordered by creation, got %v", keys)
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// chargebackColumns lists the columns of the chargebacks table in scan order
+const chargebackColumns = `id, transaction_id, merchant_id, amount, currency, card_number, reason, status,
+	description, transaction_date, chargeback_date, created_at, updated_at, deleted_at`
+
+// SQLChargebackRepository implements ChargebackRepository on a database/sql database
+// It backs both PostgreSQL and SQLite, whose schemas are created by the migrations in
+// internal/infra/db; the queries use only SQL both understand. Unlike DynamoDB, the
+// database enforces one chargeback per transaction ID. Listings are ordered by ID and
+// paginated by keyset, so pages stay fast however deep the listing goes. Timestamps are
+// stored in UTC with microsecond precision. Soft deleted rows have a deleted_at and keep
+// their transaction ID taken.
+type SQLChargebackRepository struct {
+	db *sql.DB
+	// isUniqueViolation reports whether an error of the driver was caused by a
+	// primary key or unique constraint
+	isUniqueViolation func(err error) bool
+}
+
+// Save persists a new chargeback to the database
+func (r *SQLChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
+	if chargeback.ID == "" {
+		chargeback.ID = generateChargebackID()
+	}
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO chargebacks (`+chargebackColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		chargebackValues(chargeback)...)
+	if err != nil {
+		if r.isUniqueViolation(err) {
+			return fmt.Errorf("failed to save chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackExists, err)
+		}
+		return fmt.Errorf("failed to save chargeback: %w", err)
+	}
+	return nil
+}
+
+// SaveBatch persists new chargebacks in a single transaction, so either all or none
+// are saved
+// Like the DynamoDB repository, chargebacks with an existing ID are overwritten.
+func (r *SQLChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	for _, chargeback := range chargebacks {
+		if chargeback.ID == "" {
+			chargeback.ID = generateChargebackID()
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return chargebacks, fmt.Errorf("failed to save chargebacks: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO chargebacks (`+chargebackColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO UPDATE SET
+			transaction_id = EXCLUDED.transaction_id, merchant_id = EXCLUDED.merchant_id,
+			amount = EXCLUDED.amount, currency = EXCLUDED.currency, card_number = EXCLUDED.card_number,
+			reason = EXCLUDED.reason, status = EXCLUDED.status, description = EXCLUDED.description,
+			transaction_date = EXCLUDED.transaction_date, chargeback_date = EXCLUDED.chargeback_date,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at, deleted_at = EXCLUDED.deleted_at`)
+	if err != nil {
+		return chargebacks, fmt.Errorf("failed to save chargebacks: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, chargeback := range chargebacks {
+		if _, err := stmt.ExecContext(ctx, chargebackValues(chargeback)...); err != nil {
+			if r.isUniqueViolation(err) {
+				err = fmt.Errorf("%w: %w", repository.ErrChargebackExists, err)
+			}
+			return chargebacks, fmt.Errorf("failed to save chargeback %s: %w", chargeback.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return chargebacks, fmt.Errorf("failed to save chargebacks: %w", err)
+	}
+	return nil, nil
+}
+
+// FindByID retrieves a chargeback by its unique identifier
+func (r *SQLChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
+	chargeback, err := scanChargeback(r.db.QueryRowContext(ctx,
+		`SELECT `+chargebackColumns+` FROM chargebacks WHERE id = $1 AND deleted_at IS NULL`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chargeback: %w", err)
+	}
+	return chargeback, nil
+}
+
+// FindByTransactionID retrieves a chargeback by transaction ID
+func (r *SQLChargebackRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+	chargeback, err := scanChargeback(r.db.QueryRowContext(ctx,
+		`SELECT `+chargebackColumns+` FROM chargebacks WHERE transaction_id = $1 AND deleted_at IS NULL`, transactionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargeback by transaction ID: %w", err)
+	}
+	return chargeback, nil
+}
+
+// FindByMerchantID retrieves all chargebacks for a specific merchant
+func (r *SQLChargebackRepository) FindByMerchantID(ctx context.Context, merchantID string) ([]*entity.Chargeback, error) {
+	chargebacks, err := r.query(ctx, `SELECT `+chargebackColumns+` FROM chargebacks WHERE merchant_id = $1 AND deleted_at IS NULL ORDER BY id`, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargebacks by merchant ID: %w", err)
+	}
+	return chargebacks, nil
+}
+
+// Update updates an existing chargeback in the database
+func (r *SQLChargebackRepository) Update(ctx context.Context, chargeback *entity.Chargeback) error {
+	chargeback.UpdatedAt = time.Now().Truncate(time.Microsecond)
+
+	result, err := r.db.ExecContext(ctx, `UPDATE chargebacks SET
+			transaction_id = $2, merchant_id = $3, amount = $4, currency = $5, card_number = $6,
+			reason = $7, status = $8, description = $9, transaction_date = $10,
+			chargeback_date = $11, created_at = $12, updated_at = $13, deleted_at = $14
+		WHERE id = $1 AND deleted_at IS NULL`,
+		chargebackValues(chargeback)...)
+	if err != nil {
+		if r.isUniqueViolation(err) {
+			return fmt.Errorf("failed to update chargeback %s: %w: %w", chargeback.ID, repository.ErrChargebackExists, err)
+		}
+		return fmt.Errorf("failed to update chargeback: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to update chargeback: %w", err)
+	} else if rows == 0 {
+		return fmt.Errorf("failed to update chargeback %s: %w", chargeback.ID, repository.ErrChargebackNotFound)
+	}
+	return nil
+}
+
+// Delete soft deletes a chargeback, setting its deleted_at
+func (r *SQLChargebackRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE chargebacks SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		id, sqlTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to delete chargeback: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete chargeback: %w", err)
+	} else if rows == 0 {
+		return fmt.Errorf("failed to delete chargeback %s: %w", id, repository.ErrChargebackNotFound)
+	}
+	return nil
+}
+
+// Restore brings back a soft deleted chargeback, clearing its deleted_at
+func (r *SQLChargebackRepository) Restore(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE chargebacks SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore chargeback: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to restore chargeback: %w", err)
+	} else if rows == 0 {
+		return fmt.Errorf("failed to restore chargeback %s: %w", id, repository.ErrChargebackNotFound)
+	}
+	return nil
+}
+
+// Purge deletes the rows of the chargebacks in a single transaction
+func (r *SQLChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to purge chargebacks: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `DELETE FROM chargebacks WHERE id = $1`)
+	if err != nil {
+		return fmt.Errorf("failed to purge chargebacks: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, chargeback := range chargebacks {
+		if _, err := stmt.ExecContext(ctx, chargeback.ID); err != nil {
+			return fmt.Errorf("failed to purge chargeback %s: %w", chargeback.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to purge chargebacks: %w", err)
+	}
+	return nil
+}
+
+// FindByStatus retrieves chargebacks by their status
+func (r *SQLChargebackRepository) FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error) {
+	chargebacks, err := r.query(ctx, `SELECT `+chargebackColumns+` FROM chargebacks WHERE status = $1 AND deleted_at IS NULL ORDER BY id`, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chargebacks by status: %w", err)
+	}
+	return chargebacks, nil
+}
+
+// List retrieves chargebacks with pagination support
+func (r *SQLChargebackRepository) List(ctx context.Context, offset, limit int) ([]*entity.Chargeback, error) {
+	chargebacks, err := r.query(ctx, `SELECT `+chargebackColumns+` FROM chargebacks WHERE deleted_at IS NULL ORDER BY id LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chargebacks: %w", err)
+	}
+	return chargebacks, nil
+}
+
+// ListPage retrieves a page of chargebacks matching the filter
+// Every criterion is applied by the query, so only the last page holds fewer than
+// limit chargebacks. The cursor is the encoded ID of the last chargeback of the page.
+func (r *SQLChargebackRepository) ListPage(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return nil, repository.ErrInvalidCursor
+		}
+		where("id > %s", string(after))
+	}
+	if len(filter.MerchantIDs) > 0 {
+		values := make([]interface{}, len(filter.MerchantIDs))
+		for i, merchantID := range filter.MerchantIDs {
+			values[i] = merchantID
+		}
+		where("merchant_id IN ("+strings.TrimSuffix(strings.Repeat("%s, ", len(values)), ", ")+")", values...)
+	}
+	if filter.Status != "" {
+		where("status = %s", string(filter.Status))
+	}
+	if filter.Reason != "" {
+		where("reason = %s", string(filter.Reason))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		where("created_at >= %s", sqlTime(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		where("created_at < %s", sqlTime(filter.CreatedTo))
+	}
+	if !filter.IncludeDeleted {
+		where("deleted_at IS NULL")
+	}
+
+	query := `SELECT ` + chargebackColumns + ` FROM chargebacks`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	// One more row than needed tells whether there is a next page
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY id LIMIT $%d`, len(args))
+
+	chargebacks, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chargebacks: %w", err)
+	}
+
+	page := &repository.ChargebackPage{Chargebacks: chargebacks}
+	if len(chargebacks) > limit {
+		page.Chargebacks = chargebacks[:limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(chargebacks[limit-1].ID))
+	}
+	return page, nil
+}
+
+// query runs a query returning chargebacks
+func (r *SQLChargebackRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Chargeback, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chargebacks := make([]*entity.Chargeback, 0)
+	for rows.Next() {
+		chargeback, err := scanChargeback(rows)
+		if err != nil {
+			return nil, err
+		}
+		chargebacks = append(chargebacks, chargeback)
+	}
+	return chargebacks, rows.Err()
+}
+
+// chargebackValues returns the values of a chargeback in column order
+func chargebackValues(chargeback *entity.Chargeback) []interface{} {
+	return []interface{}{
+		chargeback.ID,
+		chargeback.TransactionID,
+		chargeback.MerchantID,
+		chargeback.Amount,
+		chargeback.Currency,
+		chargeback.CardNumber,
+		string(chargeback.Reason),
+		string(chargeback.Status),
+		chargeback.Description,
+		sqlTime(chargeback.TransactionDate),
+		sqlTime(chargeback.ChargebackDate),
+		sqlTime(chargeback.CreatedAt),
+		sqlTime(chargeback.UpdatedAt),
+		sqlNullTime(chargeback.DeletedAt),
+	}
+}
+
+// sqlTime normalizes a timestamp to UTC and microseconds
+// SQLite keeps timestamps as text, so every value must share one zone for
+// comparisons to follow time order.
+func sqlTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+// sqlNullTime is sqlTime for a nullable column, NULL when the timestamp is zero
+func sqlNullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return sqlTime(t)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanChargeback reads a row in column order
+func scanChargeback(row rowScanner) (*entity.Chargeback, error) {
+	var chargeback entity.Chargeback
+	var reason, status string
+	var deletedAt sql.NullTime
+	err := row.Scan(
+		&chargeback.ID,
+		&chargeback.TransactionID,
+		&chargeback.MerchantID,
+		&chargeback.Amount,
+		&chargeback.Currency,
+		&chargeback.CardNumber,
+		&reason,
+		&status,
+		&chargeback.Description,
+		&chargeback.TransactionDate,
+		&chargeback.ChargebackDate,
+		&chargeback.CreatedAt,
+		&chargeback.UpdatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	chargeback.Reason = entity.ChargebackReason(reason)
+	chargeback.Status = entity.ChargebackStatus(status)
+	chargeback.DeletedAt = deletedAt.Time
+	return &chargeback, nil
+}
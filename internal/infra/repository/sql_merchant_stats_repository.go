@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// merchantStatsColumns lists the columns of the merchant_stats table in scan order
+const merchantStatsColumns = `merchant_id, month, chargeback_count, chargeback_amount, rejected_count, rejected_amount,
+	transaction_count, transaction_amount, updated_at`
+
+// SQLMerchantStatsRepository implements MerchantStatsRepository on a PostgreSQL or SQLite database
+// Counters are updated with a single upsert adding to the stored values, so concurrent
+// instances don't lose updates.
+type SQLMerchantStatsRepository struct {
+	db *sql.DB
+}
+
+// NewSQLMerchantStatsRepository creates a new merchant statistics repository on a PostgreSQL or SQLite database
+func NewSQLMerchantStatsRepository(db *sql.DB) repository.MerchantStatsRepository {
+	return &SQLMerchantStatsRepository{db: db}
+}
+
+// AddChargebacks adds the delta to a merchant's month and returns the updated statistics
+func (r *SQLMerchantStatsRepository) AddChargebacks(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) (*entity.MerchantMonthlyStats, error) {
+	stats, err := scanMerchantStats(r.db.QueryRowContext(ctx, `INSERT INTO merchant_stats
+			(merchant_id, month, chargeback_count, chargeback_amount, rejected_count, rejected_amount, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (merchant_id, month) DO UPDATE SET
+			chargeback_count = merchant_stats.chargeback_count + EXCLUDED.chargeback_count,
+			chargeback_amount = merchant_stats.chargeback_amount + EXCLUDED.chargeback_amount,
+			rejected_count = merchant_stats.rejected_count + EXCLUDED.rejected_count,
+			rejected_amount = merchant_stats.rejected_amount + EXCLUDED.rejected_amount,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+merchantStatsColumns,
+		merchantID, month, delta.ChargebackCount, delta.ChargebackAmount, delta.RejectedCount, delta.RejectedAmount, sqlTime(time.Now())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update merchant statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// SetTransactionVolume replaces a merchant's sales for a month and returns the updated statistics
+func (r *SQLMerchantStatsRepository) SetTransactionVolume(ctx context.Context, merchantID, month string, count int, amount float64) (*entity.MerchantMonthlyStats, error) {
+	stats, err := scanMerchantStats(r.db.QueryRowContext(ctx, `INSERT INTO merchant_stats
+			(merchant_id, month, transaction_count, transaction_amount, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (merchant_id, month) DO UPDATE SET
+			transaction_count = EXCLUDED.transaction_count,
+			transaction_amount = EXCLUDED.transaction_amount,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+merchantStatsColumns,
+		merchantID, month, count, amount, sqlTime(time.Now())))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set transaction volume: %w", err)
+	}
+	return stats, nil
+}
+
+// Find retrieves a merchant's statistics for a month, nil when there are none
+func (r *SQLMerchantStatsRepository) Find(ctx context.Context, merchantID, month string) (*entity.MerchantMonthlyStats, error) {
+	stats, err := scanMerchantStats(r.db.QueryRowContext(ctx, `SELECT `+merchantStatsColumns+`
+		FROM merchant_stats WHERE merchant_id = $1 AND month = $2`, merchantID, month))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merchant statistics: %w", err)
+	}
+	return stats, nil
+}
+
+// ListByMerchant retrieves a merchant's statistics from one month to another, oldest first
+func (r *SQLMerchantStatsRepository) ListByMerchant(ctx context.Context, merchantID, fromMonth, toMonth string) ([]*entity.MerchantMonthlyStats, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+merchantStatsColumns+`
+		FROM merchant_stats WHERE merchant_id = $1 AND month >= $2 AND month <= $3
+		ORDER BY month`, merchantID, fromMonth, toMonth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchant statistics: %w", err)
+	}
+	defer rows.Close()
+
+	var months []*entity.MerchantMonthlyStats
+	for rows.Next() {
+		stats, err := scanMerchantStats(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merchant statistics: %w", err)
+		}
+		months = append(months, stats)
+	}
+	return months, rows.Err()
+}
+
+// scanMerchantStats reads a row in column order
+func scanMerchantStats(row rowScanner) (*entity.MerchantMonthlyStats, error) {
+	var stats entity.MerchantMonthlyStats
+	err := row.Scan(
+		&stats.MerchantID,
+		&stats.Month,
+		&stats.ChargebackCount,
+		&stats.ChargebackAmount,
+		&stats.RejectedCount,
+		&stats.RejectedAmount,
+		&stats.TransactionCount,
+		&stats.TransactionAmount,
+		&stats.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
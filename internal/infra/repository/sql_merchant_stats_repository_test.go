@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestSQLMerchantStatsRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("add chargebacks and set volume", func(t *testing.T) {
+		repo := NewSQLMerchantStatsRepository(openTestSQLiteDB(t))
+
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: 99.75})
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 1, ChargebackAmount: 0.25})
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: -1, ChargebackAmount: -50, RejectedCount: 1, RejectedAmount: 50})
+		stats, err := repo.SetTransactionVolume(ctx, "merchant-789", "2023-01", 100, 10000)
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.ChargebackCount != 1 || stats.ChargebackAmount != 50 || stats.RejectedCount != 1 || stats.TransactionCount != 100 {
+			t.Errorf("Unexpected statistics %+v", stats)
+		}
+		if stats.UpdatedAt.IsZero() {
+			t.Error("Expected the update time to be set")
+		}
+
+		found, err := repo.Find(ctx, "merchant-789", "2023-01")
+		if err != nil || found == nil || *found != *stats {
+			t.Errorf("Expected the stored statistics %+v, got %+v, %v", stats, found, err)
+		}
+	})
+
+	t.Run("find missing month", func(t *testing.T) {
+		repo := NewSQLMerchantStatsRepository(openTestSQLiteDB(t))
+
+		stats, err := repo.Find(ctx, "merchant-789", "2023-01")
+
+		if err != nil || stats != nil {
+			t.Errorf("Expected nil statistics, got %v, %v", stats, err)
+		}
+	})
+
+	t.Run("list by merchant", func(t *testing.T) {
+		repo := NewSQLMerchantStatsRepository(openTestSQLiteDB(t))
+		delta := entity.MerchantStatsDelta{ChargebackCount: 1}
+		repo.AddChargebacks(ctx, "merchant-789", "2023-03", delta)
+		repo.AddChargebacks(ctx, "merchant-789", "2023-01", delta)
+		repo.AddChargebacks(ctx, "merchant-789", "2022-12", delta)
+		repo.AddChargebacks(ctx, "merchant-000", "2023-02", delta)
+
+		months, err := repo.ListByMerchant(ctx, "merchant-789", "2023-01", "2023-03")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(months) != 2 || months[0].Month != "2023-01" || months[1].Month != "2023-03" {
+			t.Errorf("Expected 2023-01 and 2023-03, got %v", months)
+		}
+	})
+}
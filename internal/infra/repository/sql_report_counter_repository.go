@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// SQLReportCounterRepository implements ReportCounterRepository on a PostgreSQL or SQLite database
+// Counters are updated with a single upsert adding to the stored values, so concurrent
+// instances don't lose updates.
+type SQLReportCounterRepository struct {
+	db *sql.DB
+}
+
+// NewSQLReportCounterRepository creates a new report counter repository on a PostgreSQL or SQLite database
+func NewSQLReportCounterRepository(db *sql.DB) repository.ReportCounterRepository {
+	return &SQLReportCounterRepository{db: db}
+}
+
+// Add adds to the number and total amount of the chargebacks in a cell
+func (r *SQLReportCounterRepository) Add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO report_counters (day, merchant_id, reason, status, currency, count, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (day, merchant_id, reason, status, currency) DO UPDATE SET
+			count = report_counters.count + EXCLUDED.count,
+			amount = report_counters.amount + EXCLUDED.amount`,
+		cell.Day, cell.MerchantID, string(cell.Reason), string(cell.Status), cell.Currency, count, amount)
+	if err != nil {
+		return fmt.Errorf("failed to update report counter: %w", err)
+	}
+	return nil
+}
+
+// List retrieves the counters from one day to another, both included
+func (r *SQLReportCounterRepository) List(ctx context.Context, fromDay, toDay string) ([]*entity.ReportCounter, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT day, merchant_id, reason, status, currency, count, amount
+		FROM report_counters WHERE day >= $1 AND day <= $2`, fromDay, toDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report counters: %w", err)
+	}
+	defer rows.Close()
+
+	var counters []*entity.ReportCounter
+	for rows.Next() {
+		var counter entity.ReportCounter
+		var reason, status string
+		if err := rows.Scan(&counter.Day, &counter.MerchantID, &reason, &status, &counter.Currency, &counter.Count, &counter.Amount); err != nil {
+			return nil, fmt.Errorf("failed to list report counters: %w", err)
+		}
+		counter.Reason = entity.ChargebackReason(reason)
+		counter.Status = entity.ChargebackStatus(status)
+		counters = append(counters, &counter)
+	}
+	return counters, rows.Err()
+}
+
+// ReplaceAll replaces every counter with the given ones in a single transaction, so
+// reports never read a partly replaced set
+func (r *SQLReportCounterRepository) ReplaceAll(ctx context.Context, counters []*entity.ReportCounter) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to replace report counters: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM report_counters`); err != nil {
+		return fmt.Errorf("failed to replace report counters: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO report_counters (day, merchant_id, reason, status, currency, count, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+	if err != nil {
+		return fmt.Errorf("failed to replace report counters: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, counter := range counters {
+		if _, err := stmt.ExecContext(ctx, counter.Day, counter.MerchantID, string(counter.Reason), string(counter.Status),
+			counter.Currency, counter.Count, counter.Amount); err != nil {
+			return fmt.Errorf("failed to replace report counters: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to replace report counters: %w", err)
+	}
+	return nil
+}
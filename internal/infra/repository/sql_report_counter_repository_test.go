@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+)
+
+func TestSQLReportCounterRepository(t *testing.T) {
+	ctx := context.Background()
+	cell := entity.ReportCell{Day: "2023-10-16", MerchantID: "merchant-789", Reason: entity.ReasonFraud, Status: entity.StatusPending, Currency: "USD"}
+
+	t.Run("add and list", func(t *testing.T) {
+		repo := NewSQLReportCounterRepository(openTestSQLiteDB(t))
+		repo.Add(ctx, cell, 1, 99.75)
+		repo.Add(ctx, cell, 1, 0.25)
+		later := cell
+		later.Day = "2023-11-01"
+		repo.Add(ctx, later, 1, 10)
+
+		counters, err := repo.List(ctx, "2023-10-01", "2023-10-31")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(counters) != 1 || counters[0].ReportCell != cell || counters[0].Count != 2 || counters[0].Amount != 100 {
+			t.Errorf("Expected a single October counter of 2 chargebacks, got %v", counters)
+		}
+	})
+
+	t.Run("replace all", func(t *testing.T) {
+		repo := NewSQLReportCounterRepository(openTestSQLiteDB(t))
+		repo.Add(ctx, cell, 5, 500)
+		rebuilt := cell
+		rebuilt.Status = entity.StatusApproved
+
+		err := repo.ReplaceAll(ctx, []*entity.ReportCounter{{ReportCell: rebuilt, Count: 1, Amount: 100}})
+		counters, _ := repo.List(ctx, "2023-10-16", "2023-10-16")
+
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(counters) != 1 || counters[0].Status != entity.StatusApproved || counters[0].Count != 1 {
+			t.Errorf("Expected only the rebuilt counter, got %v", counters)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// NewSQLiteChargebackRepository creates a new SQLite chargeback repository
+func NewSQLiteChargebackRepository(db *sql.DB) repository.ChargebackRepository {
+	return &SQLChargebackRepository{
+		db:                db,
+		isUniqueViolation: isSQLiteUniqueViolation,
+	}
+}
+
+// isSQLiteUniqueViolation reports whether an error was caused by the primary key or a
+// unique constraint
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository/repositorytest"
+	"github.com/DiegoSantos90/chargeback-api/internal/infra/db"
+)
+
+// openTestSQLiteDB opens a migrated SQLite database in a temporary directory
+func openTestSQLiteDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	ctx := context.Background()
+	sqlDB, err := db.NewSQLiteDB(ctx, db.SQLiteConfig{Path: filepath.Join(t.TempDir(), "chargebacks.db")})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrations, err := db.SQLiteMigrations()
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+	if _, err := db.NewMigrator(sqlDB, migrations).Up(ctx); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return sqlDB
+}
+
+func TestSQLiteChargebackRepository_Contract(t *testing.T) {
+	repositorytest.TestChargebackRepository(t, func(t *testing.T) repository.ChargebackRepository {
+		return NewSQLiteChargebackRepository(openTestSQLiteDB(t))
+	})
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// publicPaths lists routes that never require authentication
+var publicPaths = map[string]bool{
+	"/health": true,
+	"/livez":  true,
+	"/readyz": true,
+
+	openAPIPath: true,
+}
+
+// Authenticator resolves the credentials carried by a request into a principal
+// It returns a nil principal and nil error when the request carries no credentials it understands
+type Authenticator interface {
+	Authenticate(r *http.Request) (*auth.Principal, error)
+
+	// Scheme returns the authentication scheme advertised in WWW-Authenticate challenges
+	Scheme() string
+}
+
+// AuthenticateAPIKeyUseCase interface defines the contract for validating API keys
+type AuthenticateAPIKeyUseCase interface {
+	Execute(ctx context.Context, rawKey string) (*auth.Principal, error)
+}
+
+// APIKeyAuthenticator authenticates requests using the X-API-Key header
+// or an "Authorization: ApiKey <key>" header
+type APIKeyAuthenticator struct {
+	authenticateUC AuthenticateAPIKeyUseCase
+}
+
+// NewAPIKeyAuthenticator creates a new API key authenticator
+func NewAPIKeyAuthenticator(authenticateUC AuthenticateAPIKeyUseCase) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		authenticateUC: authenticateUC,
+	}
+}
+
+// Authenticate validates the API key carried by the request, if any
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	rawKey := r.Header.Get("X-API-Key")
+	if rawKey == "" {
+		scheme, credentials, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "ApiKey") {
+			return nil, nil
+		}
+		rawKey = strings.TrimSpace(credentials)
+	}
+
+	return a.authenticateUC.Execute(r.Context(), rawKey)
+}
+
+// Scheme returns the API key authentication scheme
+func (a *APIKeyAuthenticator) Scheme() string {
+	return "ApiKey"
+}
+
+// TokenVerifier interface defines the contract for validating bearer tokens
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*auth.Principal, error)
+}
+
+// BearerTokenAuthenticator authenticates requests using an "Authorization: Bearer <token>" header
+type BearerTokenAuthenticator struct {
+	verifier TokenVerifier
+}
+
+// NewBearerTokenAuthenticator creates a new bearer token authenticator
+func NewBearerTokenAuthenticator(verifier TokenVerifier) *BearerTokenAuthenticator {
+	return &BearerTokenAuthenticator{
+		verifier: verifier,
+	}
+}
+
+// Authenticate validates the bearer token carried by the request, if any
+func (a *BearerTokenAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	return a.verifier.Verify(r.Context(), strings.TrimSpace(token))
+}
+
+// Scheme returns the bearer authentication scheme
+func (a *BearerTokenAuthenticator) Scheme() string {
+	return "Bearer"
+}
+
+// ClientCertAuthenticator authenticates requests by the client certificate verified
+// during the mutual TLS handshake, mapping the certificate subject to a merchant
+type ClientCertAuthenticator struct {
+	merchants map[string]string
+	scopes    []auth.Scope
+}
+
+// NewClientCertAuthenticator creates a new client certificate authenticator
+// merchants maps certificate subject common names to merchant IDs; when it is empty
+// the common name itself is used as the merchant ID. scopes are granted to every client
+func NewClientCertAuthenticator(merchants map[string]string, scopes []auth.Scope) *ClientCertAuthenticator {
+	return &ClientCertAuthenticator{
+		merchants: merchants,
+		scopes:    scopes,
+	}
+}
+
+// Authenticate resolves the verified client certificate of the connection, if any
+func (a *ClientCertAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	// Only chains verified against the client CA bundle are trusted; PeerCertificates
+	// alone may hold an unverified certificate
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	commonName := subject.CommonName
+	if commonName == "" {
+		return nil, fmt.Errorf("%w: client certificate has no subject common name", auth.ErrUnauthenticated)
+	}
+
+	merchantID := commonName
+	if len(a.merchants) > 0 {
+		mapped, ok := a.merchants[commonName]
+		if !ok {
+			return nil, fmt.Errorf("%w: client certificate '%s' is not mapped to a merchant", auth.ErrUnauthenticated, commonName)
+		}
+		merchantID = mapped
+	}
+
+	return &auth.Principal{
+		Subject:     "cert:" + subject.String(),
+		MerchantIDs: []string{merchantID},
+		Scopes:      a.scopes,
+	}, nil
+}
+
+// Scheme returns no scheme, since client certificates are not requested through
+// WWW-Authenticate challenges
+func (a *ClientCertAuthenticator) Scheme() string {
+	return ""
+}
+
+// WithAuthenticator enables authentication on all non-public routes
+// Multiple authenticators are tried in order until one recognizes the request credentials
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(s *Server) {
+		s.authenticators = append(s.authenticators, authenticator)
+	}
+}
+
+// WithAPIKeyAdmin enables the API key administration endpoints under /admin/api-keys
+func WithAPIKeyAdmin(issueAPIKeyUC handler.IssueAPIKeyUseCase, rotateAPIKeyUC, revokeAPIKeyUC handler.ManageAPIKeyUseCase) Option {
+	return func(s *Server) {
+		s.apiKeyHandler = handler.NewAPIKeyHandler(issueAPIKeyUC, rotateAPIKeyUC, revokeAPIKeyUC)
+	}
+}
+
+// authenticate attaches the request principal to its context
+// Requests pass through unchanged when no authenticator is configured or the route is public
+func (s *Server) authenticate(r *http.Request) (*http.Request, error) {
+	if len(s.authenticators) == 0 || publicPaths[r.URL.Path] {
+		return r, nil
+	}
+
+	for _, authenticator := range s.authenticators {
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if principal != nil {
+			return r.WithContext(auth.ContextWithPrincipal(r.Context(), principal)), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: missing credentials", auth.ErrUnauthenticated)
+}
+
+// writeAuthError writes a 401 response for rejected credentials and a 500 response
+// for credential store failures, hiding internal details from the client
+func (s *Server) writeAuthError(w http.ResponseWriter, r *http.Request, err error) {
+	message := err.Error()
+	statusCode := http.StatusUnauthorized
+	if errors.Is(err, auth.ErrUnauthenticated) {
+		for _, authenticator := range s.authenticators {
+			if scheme := authenticator.Scheme(); scheme != "" {
+				w.Header().Add("WWW-Authenticate", scheme+` realm="chargeback-api"`)
+			}
+		}
+	} else {
+		s.logger.Error(r.Context(), "Authentication failed", map[string]interface{}{
+			"error": message,
+		})
+		message = "authentication unavailable"
+		statusCode = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
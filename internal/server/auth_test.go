@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockAuthenticateAPIKeyUseCase accepts a fixed set of raw keys
+type MockAuthenticateAPIKeyUseCase struct {
+	keys map[string]*auth.Principal
+	err  error
+}
+
+func (m *MockAuthenticateAPIKeyUseCase) Execute(ctx context.Context, rawKey string) (*auth.Principal, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if principal, ok := m.keys[rawKey]; ok {
+		return principal, nil
+	}
+	return nil, fmt.Errorf("%w: invalid API key", auth.ErrUnauthenticated)
+}
+
+// MockGetChargebackUseCase records the principal it was called with
+type MockGetChargebackUseCase struct {
+	principal *auth.Principal
+}
+
+func (m *MockGetChargebackUseCase) Execute(ctx context.Context, id string) (*usecase.CreateChargebackResponse, error) {
+	m.principal, _ = auth.PrincipalFromContext(ctx)
+	return &usecase.CreateChargebackResponse{ID: id}, nil
+}
+
+func TestServer_APIKeyAuthentication(t *testing.T) {
+	merchantKey := &auth.Principal{Subject: "key-1", MerchantIDs: []string{"merchant-789"}, Scopes: []auth.Scope{auth.ScopeChargebacksRead}}
+	authenticateUC := &MockAuthenticateAPIKeyUseCase{keys: map[string]*auth.Principal{"valid-key": merchantKey}}
+
+	tests := []struct {
+		name         string
+		path         string
+		headers      map[string]string
+		expectedCode int
+	}{
+		{
+			name:         "accepts X-API-Key header",
+			path:         "/chargebacks/cb_1",
+			headers:      map[string]string{"X-API-Key": "valid-key"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "accepts ApiKey authorization scheme",
+			path:         "/chargebacks/cb_1",
+			headers:      map[string]string{"Authorization": "ApiKey valid-key"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "rejects missing credentials",
+			path:         "/chargebacks/cb_1",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "rejects invalid key",
+			path:         "/chargebacks/cb_1",
+			headers:      map[string]string{"X-API-Key": "stolen-key"},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "leaves health checks public",
+			path:         "/livez",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getUC := &MockGetChargebackUseCase{}
+			server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+				WithGetChargebackUseCase(getUC),
+				WithAuthenticator(NewAPIKeyAuthenticator(authenticateUC)),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+
+			if tt.expectedCode == http.StatusUnauthorized && recorder.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header on 401")
+			}
+
+			if tt.expectedCode == http.StatusOK && tt.path != "/livez" && getUC.principal != merchantKey {
+				t.Error("Expected principal to be attached to the request context")
+			}
+		})
+	}
+}
+
+func TestServer_APIKeyAuthentication_StoreFailure(t *testing.T) {
+	authenticateUC := &MockAuthenticateAPIKeyUseCase{err: errors.New("failed to find API key: DynamoDB error")}
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithAuthenticator(NewAPIKeyAuthenticator(authenticateUC)),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+	req.Header.Set("X-API-Key", "cbk_key_secret")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, recorder.Code)
+	}
+}
+
+func TestServer_AdminRoutesRequireOption(t *testing.T) {
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", nil)
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d without admin option, got %d", http.StatusNotFound, recorder.Code)
+	}
+}
+
+// MockTokenVerifier accepts a fixed set of bearer tokens
+type MockTokenVerifier struct {
+	tokens map[string]*auth.Principal
+}
+
+func (m *MockTokenVerifier) Verify(ctx context.Context, token string) (*auth.Principal, error) {
+	if principal, ok := m.tokens[token]; ok {
+		return principal, nil
+	}
+	return nil, fmt.Errorf("%w: invalid token signature", auth.ErrUnauthenticated)
+}
+
+func TestServer_BearerTokenAuthentication(t *testing.T) {
+	analyst := &auth.Principal{Subject: "analyst@example.com", AllMerchants: true, Roles: []auth.Role{auth.RoleAnalyst}}
+	merchantKey := &auth.Principal{Subject: "key-1", MerchantIDs: []string{"merchant-789"}}
+
+	tests := []struct {
+		name              string
+		authorization     string
+		apiKey            string
+		expectedCode      int
+		expectedPrincipal *auth.Principal
+	}{
+		{
+			name:              "accepts valid bearer token",
+			authorization:     "Bearer valid-token",
+			expectedCode:      http.StatusOK,
+			expectedPrincipal: analyst,
+		},
+		{
+			name:          "rejects invalid bearer token",
+			authorization: "Bearer forged-token",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:              "still accepts API keys",
+			apiKey:            "valid-key",
+			expectedCode:      http.StatusOK,
+			expectedPrincipal: merchantKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getUC := &MockGetChargebackUseCase{}
+			server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+				WithGetChargebackUseCase(getUC),
+				WithAuthenticator(NewAPIKeyAuthenticator(&MockAuthenticateAPIKeyUseCase{keys: map[string]*auth.Principal{"valid-key": merchantKey}})),
+				WithAuthenticator(NewBearerTokenAuthenticator(&MockTokenVerifier{tokens: map[string]*auth.Principal{"valid-token": analyst}})),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+
+			if tt.expectedCode == http.StatusUnauthorized {
+				challenges := recorder.Header().Values("WWW-Authenticate")
+				if len(challenges) != 2 {
+					t.Errorf("Expected a challenge per authentication scheme, got %v", challenges)
+				}
+			}
+
+			if tt.expectedPrincipal != nil && getUC.principal != tt.expectedPrincipal {
+				t.Errorf("Expected principal %s, got %v", tt.expectedPrincipal.Subject, getUC.principal)
+			}
+		})
+	}
+}
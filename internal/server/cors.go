@@ -0,0 +1,230 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default CORS methods and headers used when the configuration leaves them empty
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization", "X-API-Key"}
+)
+
+// CORSConfig holds the Cross-Origin Resource Sharing policy
+// CORS is disabled, and no CORS headers are sent, when AllowedOrigins is empty
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API; "*" allows any origin
+	// and a single "*" inside an origin matches one or more subdomains, e.g. "https://*.example.com"
+	AllowedOrigins []string `json:"allowed_origins"`
+
+	// AllowedMethods lists the methods allowed in cross-origin requests
+	AllowedMethods []string `json:"allowed_methods"`
+
+	// AllowedHeaders lists the request headers allowed in cross-origin requests
+	AllowedHeaders []string `json:"allowed_headers"`
+
+	// ExposedHeaders lists the response headers readable by browser clients
+	ExposedHeaders []string `json:"exposed_headers"`
+
+	// AllowCredentials allows cookies and authorization headers to be sent cross-origin
+	AllowCredentials bool `json:"allow_credentials"`
+
+	// MaxAge is how long browsers may cache preflight results; zero omits the header
+	MaxAge time.Duration `json:"max_age"`
+}
+
+// Enabled reports whether a CORS policy is configured
+func (c CORSConfig) Enabled() bool {
+	return len(c.AllowedOrigins) > 0
+}
+
+// Validate validates the CORS configuration
+func (c CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return fmt.Errorf("CORS credentials cannot be allowed for wildcard origin '*'")
+			}
+			continue
+		}
+		if strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("CORS origin '%s' may contain at most one wildcard", origin)
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("CORS origin '%s' must include the http or https scheme", origin)
+		}
+	}
+
+	if c.MaxAge < 0 {
+		return fmt.Errorf("CORS max age cannot be negative")
+	}
+
+	return nil
+}
+
+// allowsAnyOrigin reports whether the policy answers every origin with "*"
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsOrigin checks the request origin against the allowed origins
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		prefix, suffix, ok := strings.Cut(allowed, "*")
+		if ok && len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// methods returns the allowed methods, falling back to the defaults
+func (c CORSConfig) methods() []string {
+	if len(c.AllowedMethods) == 0 {
+		return defaultCORSMethods
+	}
+	return c.AllowedMethods
+}
+
+// headers returns the allowed request headers, falling back to the defaults
+func (c CORSConfig) headers() []string {
+	if len(c.AllowedHeaders) == 0 {
+		return defaultCORSHeaders
+	}
+	return c.AllowedHeaders
+}
+
+// allowsMethod checks a preflight's requested method
+func (c CORSConfig) allowsMethod(method string) bool {
+	for _, allowed := range c.methods() {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsHeaders checks a preflight's comma separated requested headers
+func (c CORSConfig) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		allowed := false
+		for _, candidate := range c.headers() {
+			if strings.EqualFold(candidate, header) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// isPreflight reports whether the request is a CORS preflight request
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// handlePreflight answers a CORS preflight request for a registered route
+func (s *Server) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	cors := s.config.CORS
+	w.Header().Add("Vary", "Origin")
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if !s.routeExists(r) {
+		writeCORSError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if !cors.allowsOrigin(r.Header.Get("Origin")) {
+		writeCORSError(w, http.StatusForbidden, "Origin not allowed")
+		return
+	}
+
+	if !cors.allowsMethod(r.Header.Get("Access-Control-Request-Method")) ||
+		!cors.allowsHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+		writeCORSError(w, http.StatusForbidden, "CORS request not allowed")
+		return
+	}
+
+	s.setAllowOrigin(w, r)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(cors.methods(), ", "))
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(cors.headers(), ", "))
+	if cors.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(cors.MaxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyCORS sets the CORS headers of an actual request
+// It returns false, after writing a 403 response, when the request origin is not allowed
+func (s *Server) applyCORS(w http.ResponseWriter, r *http.Request) bool {
+	cors := s.config.CORS
+	if !cors.Enabled() {
+		return true
+	}
+
+	if !cors.allowsAnyOrigin() {
+		w.Header().Add("Vary", "Origin")
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true // Not a cross-origin browser request
+	}
+
+	if !cors.allowsOrigin(origin) {
+		writeCORSError(w, http.StatusForbidden, "Origin not allowed")
+		return false
+	}
+
+	s.setAllowOrigin(w, r)
+	if len(cors.ExposedHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(cors.ExposedHeaders, ", "))
+	}
+	return true
+}
+
+// setAllowOrigin sets the allowed origin and credentials headers for an allowed origin
+func (s *Server) setAllowOrigin(w http.ResponseWriter, r *http.Request) {
+	if s.config.CORS.allowsAnyOrigin() {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+	}
+
+	if s.config.CORS.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// writeCORSError writes a JSON error response for a rejected CORS request
+func writeCORSError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
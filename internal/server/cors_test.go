@@ -0,0 +1,250 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCORSTestServer(cors CORSConfig) *Server {
+	return NewServer(ServerConfig{Port: "8080", CORS: cors}, &MockCreateChargebackUseCase{}, createTestLogger())
+}
+
+func TestServer_CORS_Preflight(t *testing.T) {
+	server := newCORSTestServer(CORSConfig{
+		AllowedOrigins:   []string{"https://dashboard.example.com", "https://*.acme.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		origin         string
+		method         string
+		headers        string
+		expectedCode   int
+		expectedOrigin string
+	}{
+		{
+			name:           "allows exact origin",
+			path:           "/chargebacks",
+			origin:         "https://dashboard.example.com",
+			method:         http.MethodPost,
+			headers:        "content-type, authorization",
+			expectedCode:   http.StatusNoContent,
+			expectedOrigin: "https://dashboard.example.com",
+		},
+		{
+			name:           "allows wildcard subdomain",
+			path:           "/chargebacks",
+			origin:         "https://eu.acme.com",
+			method:         http.MethodPost,
+			expectedCode:   http.StatusNoContent,
+			expectedOrigin: "https://eu.acme.com",
+		},
+		{
+			name:         "wildcard does not match the bare domain",
+			path:         "/chargebacks",
+			origin:       "https://.acme.com",
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "rejects disallowed origin",
+			path:         "/chargebacks",
+			origin:       "https://evil.example.net",
+			method:       http.MethodPost,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "rejects disallowed method",
+			path:         "/chargebacks",
+			origin:       "https://dashboard.example.com",
+			method:       http.MethodDelete,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "rejects disallowed header",
+			path:         "/chargebacks",
+			origin:       "https://dashboard.example.com",
+			method:       http.MethodPost,
+			headers:      "Content-Type, X-Debug",
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "returns not found for unknown route",
+			path:         "/unknown",
+			origin:       "https://dashboard.example.com",
+			method:       http.MethodGet,
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin '%s', got '%s'", tt.expectedOrigin, got)
+			}
+			if !strings.Contains(strings.Join(recorder.Header().Values("Vary"), ","), "Origin") {
+				t.Error("Expected Vary: Origin on preflight response")
+			}
+
+			if tt.expectedCode == http.StatusNoContent {
+				expectedHeaders := map[string]string{
+					"Access-Control-Allow-Methods":     "GET, POST",
+					"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+					"Access-Control-Allow-Credentials": "true",
+					"Access-Control-Max-Age":           "600",
+				}
+				for header, expectedValue := range expectedHeaders {
+					if actualValue := recorder.Header().Get(header); actualValue != expectedValue {
+						t.Errorf("Expected header %s: '%s', got '%s'", header, expectedValue, actualValue)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestServer_CORS_ActualRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		cors           CORSConfig
+		origin         string
+		expectedCode   int
+		expectedOrigin string
+		expectVary     bool
+	}{
+		{
+			name:           "echoes allowed origin",
+			cors:           CORSConfig{AllowedOrigins: []string{"https://dashboard.example.com"}, ExposedHeaders: []string{"X-Request-ID"}},
+			origin:         "https://dashboard.example.com",
+			expectedCode:   http.StatusOK,
+			expectedOrigin: "https://dashboard.example.com",
+			expectVary:     true,
+		},
+		{
+			name:         "rejects disallowed origin",
+			cors:         CORSConfig{AllowedOrigins: []string{"https://dashboard.example.com"}},
+			origin:       "https://evil.example.net",
+			expectedCode: http.StatusForbidden,
+			expectVary:   true,
+		},
+		{
+			name:         "serves requests without origin",
+			cors:         CORSConfig{AllowedOrigins: []string{"https://dashboard.example.com"}},
+			expectedCode: http.StatusOK,
+			expectVary:   true,
+		},
+		{
+			name:           "answers any origin with wildcard",
+			cors:           CORSConfig{AllowedOrigins: []string{"*"}},
+			origin:         "https://anywhere.example.org",
+			expectedCode:   http.StatusOK,
+			expectedOrigin: "*",
+		},
+		{
+			name:         "sends no CORS headers when disabled",
+			origin:       "https://dashboard.example.com",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := newCORSTestServer(tt.cors)
+			req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			recorder := httptest.NewRecorder()
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, recorder.Code)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin '%s', got '%s'", tt.expectedOrigin, got)
+			}
+			if got := recorder.Header().Get("Vary") == "Origin"; got != tt.expectVary {
+				t.Errorf("Expected Vary: Origin to be %t, got %t", tt.expectVary, got)
+			}
+			if len(tt.cors.ExposedHeaders) > 0 && recorder.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+				t.Error("Expected exposed headers to be listed")
+			}
+		})
+	}
+}
+
+func TestServer_CORS_PreflightDisabled(t *testing.T) {
+	// Arrange
+	server := newCORSTestServer(CORSConfig{})
+	req := httptest.NewRequest(http.MethodOptions, "/chargebacks", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, req)
+
+	// Assert
+	if recorder.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Error("Expected no CORS headers when CORS is disabled")
+	}
+	if recorder.Code == http.StatusOK || recorder.Code == http.StatusNoContent {
+		t.Errorf("Expected preflight not to succeed, got %d", recorder.Code)
+	}
+}
+
+func TestCORSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    CORSConfig
+		shouldErr bool
+	}{
+		{"empty config", CORSConfig{}, false},
+		{"exact and wildcard origins", CORSConfig{AllowedOrigins: []string{"https://a.example.com", "https://*.example.com"}}, false},
+		{"wildcard with credentials", CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, true},
+		{"origin without scheme", CORSConfig{AllowedOrigins: []string{"example.com"}}, true},
+		{"multiple wildcards", CORSConfig{AllowedOrigins: []string{"https://*.*.example.com"}}, true},
+		{"negative max age", CORSConfig{AllowedOrigins: []string{"*"}, MaxAge: -time.Second}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.shouldErr && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthStatus represents the outcome of a health check
+type HealthStatus string
+
+const (
+	HealthStatusOK           HealthStatus = "ok"
+	HealthStatusUnavailable  HealthStatus = "unavailable"
+	HealthStatusShuttingDown HealthStatus = "shutting_down"
+)
+
+// HealthChecker defines the contract for a dependency health check
+type HealthChecker interface {
+	// Name returns the dependency name used as key in the health report
+	Name() string
+
+	// Check verifies the dependency is reachable, returning an error if it is not
+	Check(ctx context.Context) error
+}
+
+// DependencyHealth holds the result of a single dependency check
+type DependencyHealth struct {
+	Status    HealthStatus `json:"status"`
+	LatencyMS float64      `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// HealthReport represents the aggregated health of all registered dependencies
+type HealthReport struct {
+	Service   string                      `json:"service"`
+	Status    HealthStatus                `json:"status"`
+	Timestamp string                      `json:"timestamp"`
+	Checks    map[string]DependencyHealth `json:"checks"`
+}
+
+// HealthRegistry holds the health checkers used by the readiness endpoint
+type HealthRegistry struct {
+	mu       sync.RWMutex
+	checkers []HealthChecker
+}
+
+// NewHealthRegistry creates an empty health registry
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{}
+}
+
+// Register adds a health checker to the registry
+func (r *HealthRegistry) Register(checker HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checker)
+}
+
+// CheckAll runs all registered checks concurrently and aggregates the results
+func (r *HealthRegistry) CheckAll(ctx context.Context) HealthReport {
+	r.mu.RLock()
+	checkers := make([]HealthChecker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mu.RUnlock()
+
+	report := HealthReport{
+		Service:   "chargeback-api",
+		Status:    HealthStatusOK,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Checks:    make(map[string]DependencyHealth, len(checkers)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker HealthChecker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := checker.Check(ctx)
+			result := DependencyHealth{
+				Status:    HealthStatusOK,
+				LatencyMS: float64(time.Since(start).Nanoseconds()) / 1000000,
+			}
+			if err != nil {
+				result.Status = HealthStatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name()] = result
+			if err != nil {
+				report.Status = HealthStatusUnavailable
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,374 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/api/openapi"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// openAPIPath serves the OpenAPI document of the mounted routes
+const openAPIPath = "/openapi.json"
+
+// Documented describes the route in the OpenAPI document; Path is filled in from the
+// route pattern
+func Documented(endpoint openapi.Endpoint) RouteOption {
+	return func(o *routeOptions) {
+		o.endpoint = &endpoint
+	}
+}
+
+// v1Endpoints documents the v1 routes keyed by their unversioned pattern
+var v1Endpoints = map[string]openapi.Endpoint{
+	"/chargebacks": {
+		Method:      http.MethodPost,
+		OperationID: "createChargeback",
+		Summary:     "Create a chargeback",
+		Tags:        []string{"chargebacks"},
+		Request:     handler.CreateChargebackRequest{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:              usecase.CreateChargebackResponse{},
+			http.StatusBadRequest:           handler.ErrorResponse{},
+			http.StatusForbidden:            handler.ErrorResponse{},
+			http.StatusConflict:             handler.ErrorResponse{},
+			http.StatusUnsupportedMediaType: handler.ErrorResponse{},
+		},
+	},
+	"/chargebacks/batch": {
+		Method:      http.MethodPost,
+		OperationID: "createChargebacksBatch",
+		Summary:     "Create chargebacks in bulk",
+		Tags:        []string{"chargebacks"},
+		Request:     []handler.CreateChargebackRequest{},
+		AlternateRequests: map[string]interface{}{
+			"application/x-ndjson": handler.CreateChargebackRequest{},
+		},
+		Responses: map[int]interface{}{
+			http.StatusMultiStatus:          handler.BatchChargebackResponse{},
+			http.StatusForbidden:            handler.ErrorResponse{},
+			http.StatusUnsupportedMediaType: handler.ErrorResponse{},
+		},
+	},
+	"/chargebacks/export": {
+		Method:      http.MethodGet,
+		OperationID: "exportChargebacks",
+		Summary:     "Export chargebacks as CSV, newline-delimited JSON or Parquet",
+		Tags:        []string{"chargebacks"},
+		Parameters: []openapi.Parameter{
+			{Name: "merchant_id", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "status", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "reason", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "created_from", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "created_to", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:            "",
+			http.StatusBadRequest:    handler.ErrorResponse{},
+			http.StatusForbidden:     handler.ErrorResponse{},
+			http.StatusNotAcceptable: handler.ErrorResponse{},
+		},
+		ContentTypes: map[int]string{
+			http.StatusOK: handler.ContentTypeCSV,
+		},
+		AlternateResponses: map[int]map[string]interface{}{
+			http.StatusOK: {
+				handler.ContentTypeNDJSON:  usecase.CreateChargebackResponse{},
+				handler.ContentTypeParquet: []byte{},
+			},
+		},
+	},
+	"/chargebacks/{id}": {
+		Method:      http.MethodGet,
+		OperationID: "getChargeback",
+		Summary:     "Get a chargeback",
+		Tags:        []string{"chargebacks"},
+		Responses: map[int]interface{}{
+			http.StatusOK:         usecase.CreateChargebackResponse{},
+			http.StatusBadRequest: handler.ErrorResponse{},
+			http.StatusForbidden:  handler.ErrorResponse{},
+			http.StatusNotFound:   handler.ErrorResponse{},
+		},
+	},
+	"/chargebacks/{id}/approve": {
+		Method:      http.MethodPost,
+		OperationID: "approveChargeback",
+		Summary:     "Approve a pending chargeback",
+		Tags:        []string{"chargebacks"},
+		Responses: map[int]interface{}{
+			http.StatusOK:         usecase.CreateChargebackResponse{},
+			http.StatusBadRequest: handler.ErrorResponse{},
+			http.StatusForbidden:  handler.ErrorResponse{},
+			http.StatusNotFound:   handler.ErrorResponse{},
+			http.StatusConflict:   handler.ErrorResponse{},
+		},
+	},
+	"/chargebacks/{id}/reject": {
+		Method:      http.MethodPost,
+		OperationID: "rejectChargeback",
+		Summary:     "Reject a pending chargeback",
+		Tags:        []string{"chargebacks"},
+		Responses: map[int]interface{}{
+			http.StatusOK:         usecase.CreateChargebackResponse{},
+			http.StatusBadRequest: handler.ErrorResponse{},
+			http.StatusForbidden:  handler.ErrorResponse{},
+			http.StatusNotFound:   handler.ErrorResponse{},
+			http.StatusConflict:   handler.ErrorResponse{},
+		},
+	},
+	"/merchants/{merchant_id}/volumes/{month}": {
+		Method:      http.MethodPut,
+		OperationID: "recordTransactionVolume",
+		Summary:     "Report a merchant's transaction volume for a month",
+		Tags:        []string{"merchants"},
+		Request:     handler.TransactionVolumeRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:                   usecase.MerchantStatsResponse{},
+			http.StatusBadRequest:           handler.ErrorResponse{},
+			http.StatusForbidden:            handler.ErrorResponse{},
+			http.StatusUnsupportedMediaType: handler.ErrorResponse{},
+		},
+	},
+	"/merchants/{merchant_id}/stats": {
+		Method:      http.MethodGet,
+		OperationID: "getMerchantStats",
+		Summary:     "Get a merchant's monthly chargeback ratios",
+		Tags:        []string{"merchants"},
+		Parameters: []openapi.Parameter{
+			{Name: "from", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "to", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:         usecase.MerchantStatsHistoryResponse{},
+			http.StatusBadRequest: handler.ErrorResponse{},
+			http.StatusForbidden:  handler.ErrorResponse{},
+		},
+	},
+	"/reports/summary": {
+		Method:      http.MethodGet,
+		OperationID: "getReportSummary",
+		Summary:     "Summarize chargebacks grouped by merchant, reason, status, currency and period",
+		Tags:        []string{"reports"},
+		Parameters: []openapi.Parameter{
+			{Name: "group_by", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "period", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "from", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "to", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "merchant_id", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "status", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "reason", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "currency", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusOK:         usecase.ReportSummaryResponse{},
+			http.StatusBadRequest: handler.ErrorResponse{},
+			http.StatusForbidden:  handler.ErrorResponse{},
+		},
+	},
+	"/imports": {
+		Method:      http.MethodPost,
+		OperationID: "importChargebacks",
+		Summary:     "Import a CSV dispute file in the background",
+		Tags:        []string{"imports"},
+		AlternateRequests: map[string]interface{}{
+			"text/csv": "",
+		},
+		Parameters: []openapi.Parameter{
+			{Name: "map", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "date_format", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "decimal_separator", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "thousands_separator", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "minor_units", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "delimiter", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "dry_run", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+		},
+		Responses: map[int]interface{}{
+			http.StatusAccepted:              handler.ImportJobResponse{},
+			http.StatusBadRequest:            handler.ErrorResponse{},
+			http.StatusForbidden:             handler.ErrorResponse{},
+			http.StatusRequestEntityTooLarge: handler.ErrorResponse{},
+			http.StatusUnsupportedMediaType:  handler.ErrorResponse{},
+		},
+	},
+	"/imports/{id}": {
+		Method:      http.MethodGet,
+		OperationID: "getImport",
+		Summary:     "Get the progress of an import",
+		Tags:        []string{"imports"},
+		Responses: map[int]interface{}{
+			http.StatusOK:        handler.ImportJobResponse{},
+			http.StatusForbidden: handler.ErrorResponse{},
+			http.StatusNotFound:  handler.ErrorResponse{},
+		},
+	},
+	"/admin/api-keys": {
+		Method:      http.MethodPost,
+		OperationID: "issueAPIKey",
+		Summary:     "Issue an API key",
+		Tags:        []string{"admin"},
+		Request:     handler.IssueAPIKeyRequest{},
+		Responses: map[int]interface{}{
+			http.StatusCreated:              usecase.APIKeyResponse{},
+			http.StatusBadRequest:           handler.ErrorResponse{},
+			http.StatusForbidden:            handler.ErrorResponse{},
+			http.StatusUnsupportedMediaType: handler.ErrorResponse{},
+		},
+	},
+	"/admin/api-keys/{id}": {
+		Method:      http.MethodDelete,
+		OperationID: "revokeAPIKey",
+		Summary:     "Revoke an API key",
+		Tags:        []string{"admin"},
+		Responses: map[int]interface{}{
+			http.StatusOK:        usecase.APIKeyResponse{},
+			http.StatusForbidden: handler.ErrorResponse{},
+			http.StatusNotFound:  handler.ErrorResponse{},
+			http.StatusConflict:  handler.ErrorResponse{},
+		},
+	},
+	"/admin/api-keys/{id}/rotate": {
+		Method:      http.MethodPost,
+		OperationID: "rotateAPIKey",
+		Summary:     "Rotate an API key",
+		Tags:        []string{"admin"},
+		Responses: map[int]interface{}{
+			http.StatusOK:        usecase.APIKeyResponse{},
+			http.StatusForbidden: handler.ErrorResponse{},
+			http.StatusNotFound:  handler.ErrorResponse{},
+			http.StatusConflict:  handler.ErrorResponse{},
+		},
+	},
+}
+
+// newOpenAPIDocument creates the document routes are described in as they are registered
+func newOpenAPIDocument() *openapi.Document {
+	document := openapi.NewDocument(openapi.Info{
+		Title:   "Chargeback API",
+		Version: "1.0.0",
+	})
+	document.AddSecurityScheme("ApiKey", openapi.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: "X-API-Key",
+	})
+	document.AddSecurityScheme("Bearer", openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+	return document
+}
+
+// documentRoute records the route's request schema for validation, unless the route
+// opted out, and, for versioned routes, adds its endpoint to the OpenAPI document;
+// legacy aliases are left out in favour of their versioned route
+// Responses produced by the server itself, rather than by handlers, are added to every operation
+func (s *Server) documentRoute(pattern string, versioned bool, options routeOptions) {
+	endpoint := *options.endpoint
+	if endpoint.Request != nil && !options.skipBodyValidation {
+		s.requestSchemas[endpoint.Method+" "+pattern] = s.openAPIDocument.SchemaFor(endpoint.Request)
+	}
+	if !versioned {
+		return
+	}
+
+	responses := make(map[int]interface{})
+	maps.Copy(responses, endpoint.Responses)
+	responses[http.StatusUnauthorized] = handler.ErrorResponse{}
+	responses[http.StatusTooManyRequests] = ProblemDetails{}
+	responses[http.StatusInternalServerError] = handler.ErrorResponse{}
+	if endpoint.Request != nil {
+		responses[http.StatusBadRequest] = handler.ErrorResponse{}
+		responses[http.StatusRequestEntityTooLarge] = handler.ErrorResponse{}
+	}
+
+	contentTypes := make(map[int]string)
+	maps.Copy(contentTypes, endpoint.ContentTypes)
+	contentTypes[http.StatusTooManyRequests] = "application/problem+json"
+
+	endpoint.Path = pattern
+	endpoint.Deprecated = !options.deprecation.IsZero()
+	endpoint.Responses = responses
+	endpoint.ContentTypes = contentTypes
+	s.openAPIDocument.AddEndpoint(endpoint)
+}
+
+// validateRequestBody checks JSON request bodies of documented routes against their schema
+// before the handler runs, and leaves the body in place for the handler to decode
+// It returns false, after writing a 400 or 413 response with field-level errors, when
+// the body is invalid
+func (s *Server) validateRequestBody(w http.ResponseWriter, r *http.Request) bool {
+	_, pattern := s.mux.Handler(r)
+	schema, ok := s.requestSchemas[r.Method+" "+pattern]
+	// Handlers reject unsupported content types themselves
+	if !ok || !strings.Contains(r.Header.Get("Content-Type"), "application/json") {
+		return true
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, handler.MaxRequestBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, handler.ErrorResponse{
+				Error: fmt.Sprintf("Request body must not exceed %d bytes", handler.MaxRequestBodyBytes),
+			})
+			return false
+		}
+		writeJSONError(w, http.StatusBadRequest, handler.ErrorResponse{Error: "Failed to read request body"})
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.Decode(&struct{}{}) != io.EOF {
+		writeJSONError(w, http.StatusBadRequest, handler.ErrorResponse{Error: "Invalid JSON format"})
+		return false
+	}
+
+	fieldErrors := s.openAPIDocument.Validate(schema, value)
+	if len(fieldErrors) == 0 {
+		return true
+	}
+
+	response := handler.ErrorResponse{Error: "Invalid request body"}
+	for _, fieldError := range fieldErrors {
+		response.Fields = append(response.Fields, handler.FieldError{
+			Field:   fieldError.Field,
+			Message: fieldError.Message,
+		})
+	}
+	writeJSONError(w, http.StatusBadRequest, response)
+	return false
+}
+
+// writeJSONError writes a JSON error response
+func writeJSONError(w http.ResponseWriter, statusCode int, response handler.ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleOpenAPI serves the OpenAPI document
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(s.openAPIDocument)
+}
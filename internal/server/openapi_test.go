@@ -0,0 +1,394 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/api/openapi"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/importer"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// openAPISpecFile is the committed OpenAPI document client teams generate code from
+const openAPISpecFile = "../../docs/openapi.json"
+
+var updateOpenAPI = flag.Bool("update-openapi", false, "rewrite docs/openapi.json from the current routes and types")
+
+// MockIssueAPIKeyUseCase satisfies the API key admin use cases for route registration
+type MockIssueAPIKeyUseCase struct{}
+
+func (m *MockIssueAPIKeyUseCase) Execute(ctx context.Context, req usecase.IssueAPIKeyRequest) (*usecase.APIKeyResponse, error) {
+	return &usecase.APIKeyResponse{}, nil
+}
+
+// MockManageAPIKeyUseCase satisfies the API key rotate and revoke use cases
+type MockManageAPIKeyUseCase struct{}
+
+func (m *MockManageAPIKeyUseCase) Execute(ctx context.Context, id string) (*usecase.APIKeyResponse, error) {
+	return &usecase.APIKeyResponse{ID: id}, nil
+}
+
+// MockBatchCreateChargebacksUseCase reports every batch item as created
+type MockBatchCreateChargebacksUseCase struct{}
+
+func (m *MockBatchCreateChargebacksUseCase) Execute(ctx context.Context, reqs []usecase.CreateChargebackRequest) ([]usecase.BatchItemResult, error) {
+	results := make([]usecase.BatchItemResult, len(reqs))
+	for i := range results {
+		results[i] = usecase.BatchItemResult{Status: usecase.BatchItemCreated, Chargeback: &usecase.CreateChargebackResponse{}}
+	}
+	return results, nil
+}
+
+// MockExportChargebacksUseCase exports a single chargeback
+type MockExportChargebacksUseCase struct{}
+
+func (m *MockExportChargebacksUseCase) Execute(ctx context.Context, filter repository.ChargebackFilter, emit func(*usecase.CreateChargebackResponse) error) error {
+	return emit(&usecase.CreateChargebackResponse{ID: "cb_1", MerchantID: "merchant-789", CardNumber: "************1111"})
+}
+
+// MockRecordTransactionVolumeUseCase echoes the reported volume
+type MockRecordTransactionVolumeUseCase struct{}
+
+func (m *MockRecordTransactionVolumeUseCase) Execute(ctx context.Context, merchantID, month string, req usecase.RecordTransactionVolumeRequest) (*usecase.MerchantStatsResponse, error) {
+	response := &usecase.MerchantStatsResponse{ThresholdsExceeded: []string{}}
+	response.MerchantID, response.Month = merchantID, month
+	response.TransactionCount, response.TransactionAmount = req.TransactionCount, req.TransactionAmount
+	return response, nil
+}
+
+// MockGetMerchantStatsUseCase returns no months
+type MockGetMerchantStatsUseCase struct{}
+
+func (m *MockGetMerchantStatsUseCase) Execute(ctx context.Context, merchantID, fromMonth, toMonth string) (*usecase.MerchantStatsHistoryResponse, error) {
+	return &usecase.MerchantStatsHistoryResponse{MerchantID: merchantID, From: fromMonth, To: toMonth, Months: []*usecase.MerchantStatsResponse{}}, nil
+}
+
+// MockGetReportSummaryUseCase returns no groups
+type MockGetReportSummaryUseCase struct{}
+
+func (m *MockGetReportSummaryUseCase) Execute(ctx context.Context, req usecase.ReportSummaryRequest) (*usecase.ReportSummaryResponse, error) {
+	return &usecase.ReportSummaryResponse{GroupBy: req.GroupBy, Groups: []*usecase.ReportGroup{}}, nil
+}
+
+// newFullyConfiguredServer mounts every optional route
+func newFullyConfiguredServer() *Server {
+	return NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithBatchCreateUseCase(&MockBatchCreateChargebacksUseCase{}, 10),
+		WithExportUseCase(&MockExportChargebacksUseCase{}),
+		WithReviewUseCases(&MockGetChargebackUseCase{}, &MockGetChargebackUseCase{}),
+		WithMerchantStatsUseCases(&MockRecordTransactionVolumeUseCase{}, &MockGetMerchantStatsUseCase{}),
+		WithReportSummaryUseCase(&MockGetReportSummaryUseCase{}),
+		WithImportJobs(importer.NewJobManager(importer.NewImporter(&MockCreateChargebackUseCase{}))),
+		WithAPIKeyAdmin(&MockIssueAPIKeyUseCase{}, &MockManageAPIKeyUseCase{}, &MockManageAPIKeyUseCase{}),
+	)
+}
+
+// fetchOpenAPIDocument requests the served OpenAPI document
+func fetchOpenAPIDocument(t *testing.T, server *Server) []byte {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+	return recorder.Body.Bytes()
+}
+
+// TestOpenAPI_MatchesCommittedSpec fails when routes or request/response fields change
+// without regenerating the spec:
+//
+//	go test ./internal/server -run TestOpenAPI_MatchesCommittedSpec -update-openapi
+func TestOpenAPI_MatchesCommittedSpec(t *testing.T) {
+	// Arrange
+	server := newFullyConfiguredServer()
+
+	// Act
+	generated := fetchOpenAPIDocument(t, server)
+
+	// Assert
+	if *updateOpenAPI {
+		if err := os.WriteFile(openAPISpecFile, generated, 0o644); err != nil {
+			t.Fatalf("Failed to update %s: %v", openAPISpecFile, err)
+		}
+		return
+	}
+
+	committed, err := os.ReadFile(openAPISpecFile)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", openAPISpecFile, err)
+	}
+	if !bytes.Equal(generated, committed) {
+		t.Errorf("%s is out of date with the routes and DTOs; regenerate it with -update-openapi", openAPISpecFile)
+	}
+}
+
+func TestOpenAPI_DocumentsEveryVersionedRoute(t *testing.T) {
+	// Arrange
+	server := newFullyConfiguredServer()
+
+	var document openapi.Document
+	if err := json.Unmarshal(fetchOpenAPIDocument(t, server), &document); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	// Act & Assert
+	for pattern, name := range server.routeNames {
+		documented := document.Paths[pattern] != nil
+		versioned := pattern != name
+		if versioned && !documented {
+			t.Errorf("Route %s is not in the OpenAPI document", pattern)
+		}
+		if !versioned && documented {
+			t.Errorf("Legacy route %s should not be in the OpenAPI document", pattern)
+		}
+	}
+
+	for path, item := range document.Paths {
+		for method, operation := range item {
+			req := httptest.NewRequest(strings.ToUpper(method), path, nil)
+			if _, pattern := server.mux.Handler(req); pattern != path {
+				t.Errorf("Documented operation %s %s is not routed", method, path)
+			}
+			if operation.OperationID == "" {
+				t.Errorf("Operation %s %s has no operation ID", method, path)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_IsPublic(t *testing.T) {
+	// Arrange
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithAuthenticator(NewAPIKeyAuthenticator(&MockAuthenticateAPIKeyUseCase{})),
+	)
+
+	// Act
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	// Assert
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected status code %d without credentials, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got '%s'", contentType)
+	}
+}
+
+func TestServer_RequestBodyValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedFields []handler.FieldError
+	}{
+		{
+			name:           "field-level errors on versioned route",
+			path:           "/v1/chargebacks",
+			contentType:    "application/json",
+			body:           `{"transaction_id": 123, "merchant_id": "m", "amount": 1, "currency": "USD", "reason": "fraud", "transaction_date": "2023-10-01T10:00:00Z", "extra": true}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []handler.FieldError{
+				{Field: "card_number", Message: "is required"},
+				{Field: "extra", Message: "is not allowed"},
+				{Field: "transaction_id", Message: "must be a string"},
+			},
+		},
+		{
+			name:           "legacy alias is validated too",
+			path:           "/chargebacks",
+			contentType:    "application/json",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []handler.FieldError{
+				{Field: "amount", Message: "is required"},
+				{Field: "card_number", Message: "is required"},
+				{Field: "currency", Message: "is required"},
+				{Field: "merchant_id", Message: "is required"},
+				{Field: "reason", Message: "is required"},
+				{Field: "transaction_date", Message: "is required"},
+				{Field: "transaction_id", Message: "is required"},
+			},
+		},
+		{
+			name:           "malformed JSON",
+			path:           "/v1/chargebacks",
+			contentType:    "application/json",
+			body:           `{"amount": `,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "oversized body",
+			path:           "/v1/chargebacks",
+			contentType:    "application/json",
+			body:           `{"description": "` + strings.Repeat("a", handler.MaxRequestBodyBytes) + `"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "unsupported content type is left to the handler",
+			path:           "/v1/chargebacks",
+			contentType:    "text/plain",
+			body:           `{}`,
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			called := false
+			server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{
+				ExecuteFunc: func(ctx context.Context, req usecase.CreateChargebackRequest) (*usecase.CreateChargebackResponse, error) {
+					called = true
+					return &usecase.CreateChargebackResponse{}, nil
+				},
+			}, createTestLogger())
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if called {
+				t.Error("Expected use case not to run for an invalid body")
+			}
+
+			var response handler.ErrorResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !reflect.DeepEqual(response.Fields, tt.expectedFields) {
+				t.Errorf("Expected fields %+v, got %+v", tt.expectedFields, response.Fields)
+			}
+		})
+	}
+}
+
+func TestServer_BatchRouteReportsErrorsPerItem(t *testing.T) {
+	// Arrange
+	server := newFullyConfiguredServer()
+	body := `[{"transaction_id": "tx-1", "merchant_id": "merchant-789", "amount": 1, "currency": "USD",` +
+		` "card_number": "4111111111111111", "reason": "fraud", "transaction_date": "2023-10-01T10:00:00Z"}, {"extra": true}]`
+	req := httptest.NewRequest(http.MethodPost, "/v1/chargebacks/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, req)
+
+	// Assert
+	if recorder.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusMultiStatus, recorder.Code, recorder.Body.String())
+	}
+
+	var response handler.BatchChargebackResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	expected := handler.BatchSummary{Total: 2, Created: 1, Invalid: 1}
+	if response.Summary != expected {
+		t.Errorf("Expected summary %+v, got %+v", expected, response.Summary)
+	}
+}
+
+func TestServer_ImportRouteAcceptsCSV(t *testing.T) {
+	// Arrange
+	server := newFullyConfiguredServer()
+	body := "transaction_id,merchant_id,amount,currency,card_number,reason,transaction_date\n" +
+		"tx-1,merchant-789,1,USD,4111111111111111,fraud,2023-10-01T10:00:00Z\n"
+	req := httptest.NewRequest(http.MethodPost, "/v1/imports?dry_run=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, req)
+
+	// Assert
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusAccepted, recorder.Code, recorder.Body.String())
+	}
+
+	location := recorder.Header().Get("Location")
+	if !strings.HasPrefix(location, "/v1/imports/imp_") {
+		t.Fatalf("Expected Location of the job, got '%s'", location)
+	}
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, location, nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected job to be found at its Location, got status code %d", recorder.Code)
+	}
+}
+
+func TestServer_ExportRouteStreamsNegotiatedFormat(t *testing.T) {
+	// Arrange
+	server := newFullyConfiguredServer()
+	req := httptest.NewRequest(http.MethodGet, "/v1/chargebacks/export?status=pending", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	recorder := httptest.NewRecorder()
+
+	// Act
+	server.ServeHTTP(recorder, req)
+
+	// Assert
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected NDJSON, got %s", contentType)
+	}
+	if !strings.Contains(recorder.Body.String(), `"id":"cb_1"`) {
+		t.Errorf("Expected the exported chargeback rather than GET /chargebacks/{id}, got %s", recorder.Body.String())
+	}
+}
+
+func TestServer_MerchantVolumeRoute(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{"records volume", `{"transaction_count":1000,"transaction_amount":50000}`, http.StatusOK},
+		{"missing field", `{"transaction_count":1000}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := newFullyConfiguredServer()
+			req := httptest.NewRequest(http.MethodPut, "/v1/merchants/merchant-789/volumes/2023-01", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, recorder.Code, recorder.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK && !strings.Contains(recorder.Body.String(), `"month":"2023-01"`) {
+				t.Errorf("Expected the month's statistics, got %s", recorder.Body.String())
+			}
+		})
+	}
+}
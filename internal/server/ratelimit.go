@@ -0,0 +1,233 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// RateLimitKey selects what requests are counted against
+type RateLimitKey string
+
+const (
+	// RateLimitByAPIKey counts requests per authenticated client
+	RateLimitByAPIKey RateLimitKey = "api_key"
+
+	// RateLimitByMerchant counts requests per merchant, shared by all of a merchant's keys
+	RateLimitByMerchant RateLimitKey = "merchant"
+
+	// RateLimitByIP counts requests per client IP address
+	RateLimitByIP RateLimitKey = "ip"
+)
+
+// IsValid checks if the rate limit key is one of the known keys
+func (k RateLimitKey) IsValid() bool {
+	switch k {
+	case RateLimitByAPIKey, RateLimitByMerchant, RateLimitByIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// RateLimitConfig holds the rate limiting policy
+type RateLimitConfig struct {
+	// KeyBy selects what requests are counted against; unauthenticated requests
+	// are always counted per client IP
+	KeyBy RateLimitKey
+
+	// Default applies to routes without a specific limit
+	Default service.RateLimit
+
+	// Routes holds per-route limits keyed by "METHOD /pattern" or "/pattern", using
+	// unversioned patterns that apply to every API version, e.g. "POST /chargebacks"
+	Routes map[string]service.RateLimit
+
+	// PerIP limits the requests of each client IP to any non-public route before they
+	// are authenticated, so requests with invalid credentials are throttled too and
+	// can't flood the credential lookups; zero disables it
+	PerIP service.RateLimit
+
+	// TrustForwardedFor uses the first X-Forwarded-For address as the client IP;
+	// only enable it behind a proxy that sets the header
+	TrustForwardedFor bool
+}
+
+// Validate validates the rate limit configuration
+func (c RateLimitConfig) Validate() error {
+	if !c.KeyBy.IsValid() {
+		return fmt.Errorf("rate limit key must be 'api_key', 'merchant' or 'ip', got '%s'", c.KeyBy)
+	}
+	return nil
+}
+
+// WithRateLimiter enables rate limiting on all non-public routes
+func WithRateLimiter(limiter service.RateLimiter, config RateLimitConfig) Option {
+	return func(s *Server) {
+		s.rateLimiter = limiter
+		s.rateLimit = config
+	}
+}
+
+// limitFor returns the limit and route name that apply to the request
+func (c RateLimitConfig) limitFor(method, pattern string) (service.RateLimit, string) {
+	if limit, ok := c.Routes[method+" "+pattern]; ok {
+		return limit, method + " " + pattern
+	}
+	if limit, ok := c.Routes[pattern]; ok {
+		return limit, pattern
+	}
+	return c.Default, "default"
+}
+
+// limitCheck is the outcome of counting a request against one limit
+type limitCheck struct {
+	limit  service.RateLimit
+	result service.RateLimitResult
+}
+
+// allowClient checks the request against the per-IP limit applied before authentication
+// It returns false, after writing a 429 response, when the client IP is over the limit;
+// otherwise it returns the check, if any, for allowRequest to weigh against the route's
+func (s *Server) allowClient(w http.ResponseWriter, r *http.Request) (*limitCheck, bool) {
+	if s.rateLimiter == nil || publicPaths[r.URL.Path] || s.rateLimit.PerIP.IsUnlimited() {
+		return nil, true
+	}
+
+	check := s.checkLimit(r, s.rateLimit.PerIP, "per-ip", "ip:"+s.clientIP(r))
+	if check != nil && !check.result.Allowed {
+		s.rejectRequest(w, check)
+		return nil, false
+	}
+	return check, true
+}
+
+// allowRequest checks the authenticated request against its route's rate limit and sets the
+// RateLimit headers from the more restrictive of that limit and the client's per-IP check
+// It returns false, after writing a 429 response, when the client is over the limit
+func (s *Server) allowRequest(w http.ResponseWriter, r *http.Request, client *limitCheck) bool {
+	if s.rateLimiter == nil || publicPaths[r.URL.Path] {
+		return true
+	}
+
+	var route *limitCheck
+	_, pattern := s.mux.Handler(r)
+	limit, name := s.rateLimit.limitFor(r.Method, s.routeName(pattern))
+	if !limit.IsUnlimited() {
+		route = s.checkLimit(r, limit, name, s.rateLimitKey(r))
+	}
+
+	if route != nil && !route.result.Allowed {
+		s.rejectRequest(w, route)
+		return false
+	}
+	setRateLimitHeaders(w, moreRestrictive(client, route))
+	return true
+}
+
+// checkLimit counts the request against the limit under the route and client key
+// It returns nil when the rate limiter is unavailable, letting the request through
+func (s *Server) checkLimit(r *http.Request, limit service.RateLimit, route, key string) *limitCheck {
+	result, err := s.rateLimiter.Allow(r.Context(), route+"|"+key, limit)
+	if err != nil {
+		// Fail open: an unavailable counter store must not take the API down
+		s.logger.Error(r.Context(), "Rate limiter unavailable", map[string]interface{}{
+			"error": err.Error(),
+			"route": route,
+		})
+		return nil
+	}
+	return &limitCheck{limit: limit, result: result}
+}
+
+// moreRestrictive returns the check with fewer requests remaining, ignoring missing checks
+func moreRestrictive(a, b *limitCheck) *limitCheck {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.result.Remaining < b.result.Remaining {
+		return a
+	}
+	return b
+}
+
+// setRateLimitHeaders sets the RateLimit headers from the check, if any
+func setRateLimitHeaders(w http.ResponseWriter, check *limitCheck) {
+	if check == nil {
+		return
+	}
+
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", check.limit.Requests, int(check.limit.Window.Seconds())))
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(check.result.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(check.result.Remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(check.result.ResetAfter)))
+}
+
+// rejectRequest writes the 429 response for a check that is over its limit
+func (s *Server) rejectRequest(w http.ResponseWriter, check *limitCheck) {
+	setRateLimitHeaders(w, check)
+	w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(check.result.RetryAfter)))
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusTooManyRequests),
+		Status: http.StatusTooManyRequests,
+		Detail: fmt.Sprintf("Rate limit of %d requests per %s exceeded", check.limit.Requests, check.limit.Window),
+	})
+}
+
+// ProblemDetails represents an RFC 9457 problem response
+type ProblemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// rateLimitKey identifies the client the request is counted against
+func (s *Server) rateLimitKey(r *http.Request) string {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		return "ip:" + s.clientIP(r)
+	}
+
+	switch s.rateLimit.KeyBy {
+	case RateLimitByIP:
+		return "ip:" + s.clientIP(r)
+	case RateLimitByMerchant:
+		if !principal.AllMerchants && len(principal.MerchantIDs) > 0 {
+			return "merchant:" + strings.Join(principal.MerchantIDs, ",")
+		}
+	}
+	return "client:" + principal.Subject
+}
+
+// clientIP returns the address of the client that sent the request
+func (s *Server) clientIP(r *http.Request) string {
+	if s.rateLimit.TrustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ceilSeconds rounds a duration up to whole seconds for header values
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
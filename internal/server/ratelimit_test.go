@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// MockRateLimiter allows a fixed number of requests per key and records the keys it saw
+type MockRateLimiter struct {
+	counts map[string]int
+	limits map[string]service.RateLimit
+	err    error
+}
+
+func NewMockRateLimiter() *MockRateLimiter {
+	return &MockRateLimiter{counts: map[string]int{}, limits: map[string]service.RateLimit{}}
+}
+
+func (m *MockRateLimiter) Allow(ctx context.Context, key string, limit service.RateLimit) (service.RateLimitResult, error) {
+	if m.err != nil {
+		return service.RateLimitResult{}, m.err
+	}
+
+	m.counts[key]++
+	m.limits[key] = limit
+	remaining := limit.Requests - m.counts[key]
+	if remaining < 0 {
+		return service.RateLimitResult{Limit: limit.Requests, ResetAfter: limit.Window, RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return service.RateLimitResult{Allowed: true, Limit: limit.Requests, Remaining: remaining, ResetAfter: limit.Window}, nil
+}
+
+func TestServer_RateLimiting(t *testing.T) {
+	// Arrange
+	limiter := NewMockRateLimiter()
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithRateLimiter(limiter, RateLimitConfig{
+			KeyBy:   RateLimitByIP,
+			Default: service.RateLimit{Requests: 100, Window: time.Minute},
+			Routes: map[string]service.RateLimit{
+				"GET /chargebacks/{id}": {Requests: 2, Window: time.Minute},
+			},
+		}),
+	)
+
+	send := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		server.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// Act
+	first := send()
+	send()
+	limited := send()
+
+	// Assert
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, first.Code)
+	}
+	expectedHeaders := map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "1",
+		"RateLimit-Reset":     "60",
+		"RateLimit-Policy":    "2;w=60",
+	}
+	for header, expectedValue := range expectedHeaders {
+		if actualValue := first.Header().Get(header); actualValue != expectedValue {
+			t.Errorf("Expected header %s: '%s', got '%s'", header, expectedValue, actualValue)
+		}
+	}
+
+	if limited.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status code %d, got %d", http.StatusTooManyRequests, limited.Code)
+	}
+	if limited.Header().Get("Retry-After") != "2" {
+		t.Errorf("Expected Retry-After rounded up to 2, got '%s'", limited.Header().Get("Retry-After"))
+	}
+	if limited.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("Expected problem content type, got '%s'", limited.Header().Get("Content-Type"))
+	}
+
+	var problem ProblemDetails
+	if err := json.NewDecoder(limited.Body).Decode(&problem); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if problem.Status != http.StatusTooManyRequests || problem.Title != "Too Many Requests" {
+		t.Errorf("Unexpected problem response: %+v", problem)
+	}
+
+	if limiter.counts["GET /chargebacks/{id}|ip:203.0.113.7"] != 3 {
+		t.Errorf("Expected requests to be counted per route and client IP, got %v", limiter.counts)
+	}
+}
+
+func TestServer_RateLimiting_Keys(t *testing.T) {
+	merchantKey := &auth.Principal{Subject: "key-1", MerchantIDs: []string{"merchant-789"}}
+	authenticateUC := &MockAuthenticateAPIKeyUseCase{keys: map[string]*auth.Principal{"valid-key": merchantKey}}
+
+	tests := []struct {
+		name        string
+		keyBy       RateLimitKey
+		trustProxy  bool
+		apiKey      string
+		expectedKey string
+	}{
+		{"per API key", RateLimitByAPIKey, false, "valid-key", "default|client:key-1"},
+		{"per merchant", RateLimitByMerchant, false, "valid-key", "default|merchant:merchant-789"},
+		{"per IP", RateLimitByIP, false, "valid-key", "default|ip:203.0.113.7"},
+		{"per forwarded IP behind proxy", RateLimitByIP, true, "valid-key", "default|ip:198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			limiter := NewMockRateLimiter()
+			server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+				WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+				WithAuthenticator(NewAPIKeyAuthenticator(authenticateUC)),
+				WithRateLimiter(limiter, RateLimitConfig{
+					KeyBy:             tt.keyBy,
+					Default:           service.RateLimit{Requests: 10, Window: time.Minute},
+					TrustForwardedFor: tt.trustProxy,
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+			req.RemoteAddr = "203.0.113.7:51234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.1, 10.0.0.1")
+			req.Header.Set("X-API-Key", tt.apiKey)
+
+			// Act
+			server.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			if limiter.counts[tt.expectedKey] != 1 {
+				t.Errorf("Expected request counted under %s, got %v", tt.expectedKey, limiter.counts)
+			}
+		})
+	}
+}
+
+func TestServer_RateLimiting_Exemptions(t *testing.T) {
+	t.Run("does not limit health checks", func(t *testing.T) {
+		limiter := NewMockRateLimiter()
+		server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+			WithRateLimiter(limiter, RateLimitConfig{KeyBy: RateLimitByIP, Default: service.RateLimit{Requests: 1, Window: time.Minute}}),
+		)
+
+		for i := 0; i < 3; i++ {
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/livez", nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+			}
+		}
+	})
+
+	t.Run("allows requests when the limiter fails", func(t *testing.T) {
+		limiter := NewMockRateLimiter()
+		limiter.err = errors.New("failed to update rate limit counter: DynamoDB error")
+		server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+			WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+			WithRateLimiter(limiter, RateLimitConfig{KeyBy: RateLimitByIP, Default: service.RateLimit{Requests: 1, Window: time.Minute}}),
+		)
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil))
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+	})
+}
+
+func TestServer_RateLimiting_HeadersFromMoreRestrictiveLimit(t *testing.T) {
+	tests := []struct {
+		name              string
+		perIP             service.RateLimit
+		route             service.RateLimit
+		expectedLimit     string
+		expectedRemaining string
+		expectedPolicy    string
+	}{
+		{
+			name:              "per-IP limit is closer to running out",
+			perIP:             service.RateLimit{Requests: 2, Window: time.Minute},
+			route:             service.RateLimit{Requests: 100, Window: time.Hour},
+			expectedLimit:     "2",
+			expectedRemaining: "1",
+			expectedPolicy:    "2;w=60",
+		},
+		{
+			name:              "route limit is closer to running out",
+			perIP:             service.RateLimit{Requests: 100, Window: time.Minute},
+			route:             service.RateLimit{Requests: 3, Window: time.Hour},
+			expectedLimit:     "3",
+			expectedRemaining: "2",
+			expectedPolicy:    "3;w=3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+				WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+				WithRateLimiter(NewMockRateLimiter(), RateLimitConfig{
+					KeyBy:   RateLimitByIP,
+					Default: tt.route,
+					PerIP:   tt.perIP,
+				}),
+			)
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+			req.RemoteAddr = "203.0.113.7:51234"
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+			}
+			expectedHeaders := map[string]string{
+				"RateLimit-Limit":     tt.expectedLimit,
+				"RateLimit-Remaining": tt.expectedRemaining,
+				"RateLimit-Policy":    tt.expectedPolicy,
+			}
+			for header, expectedValue := range expectedHeaders {
+				if actualValue := recorder.Header().Get(header); actualValue != expectedValue {
+					t.Errorf("Expected header %s: '%s', got '%s'", header, expectedValue, actualValue)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_RateLimiting_PerIPBeforeAuthentication(t *testing.T) {
+	// Arrange
+	limiter := NewMockRateLimiter()
+	lookups := 0
+	authenticateUC := &MockAuthenticateAPIKeyUseCase{keys: map[string]*auth.Principal{}}
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithAuthenticator(countingAuthenticator{Authenticator: NewAPIKeyAuthenticator(authenticateUC), calls: &lookups}),
+		WithRateLimiter(limiter, RateLimitConfig{
+			KeyBy:   RateLimitByAPIKey,
+			Default: service.RateLimit{Requests: 100, Window: time.Minute},
+			PerIP:   service.RateLimit{Requests: 2, Window: time.Minute},
+		}),
+	)
+
+	send := func() int {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/chargebacks/cb_1", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		req.Header.Set("X-API-Key", "guessed-key")
+		server.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	// Act
+	codes := []int{send(), send(), send()}
+
+	// Assert
+	expected := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i, code := range codes {
+		if code != expected[i] {
+			t.Errorf("Request %d: expected status code %d, got %d", i, expected[i], code)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("Expected throttled requests not to look up credentials, got %d lookups", lookups)
+	}
+	if limiter.counts["per-ip|ip:203.0.113.7"] != 3 {
+		t.Errorf("Expected requests to be counted per client IP, got %v", limiter.counts)
+	}
+}
+
+// countingAuthenticator counts the requests it authenticates
+type countingAuthenticator struct {
+	Authenticator
+	calls *int
+}
+
+func (a countingAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	*a.calls++
+	return a.Authenticator.Authenticate(r)
+}
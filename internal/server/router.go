@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/api/openapi"
+)
+
+// CurrentAPIVersion is the version the unversioned legacy routes alias
+const CurrentAPIVersion = "v1"
+
+// Deprecation describes the retirement schedule of a route
+// Routes with a zero Deprecation are not deprecated
+type Deprecation struct {
+	// Since is when the route was deprecated, sent in the Deprecation header (RFC 9745)
+	Since time.Time `json:"since"`
+
+	// Sunset is when the route will stop responding, sent in the Sunset header (RFC 8594)
+	Sunset time.Time `json:"sunset"`
+
+	// SuccessorVersion is the API version replacing the route, advertised in a
+	// successor-version Link header, e.g. "v2"
+	SuccessorVersion string `json:"successor_version"`
+}
+
+// IsZero reports whether the route is not deprecated
+func (d Deprecation) IsZero() bool {
+	return d.Since.IsZero() && d.Sunset.IsZero()
+}
+
+// Validate validates the deprecation schedule
+func (d Deprecation) Validate() error {
+	if !d.Since.IsZero() && !d.Sunset.IsZero() && d.Sunset.Before(d.Since) {
+		return fmt.Errorf("sunset %s is before deprecation %s", d.Sunset.Format(time.RFC3339), d.Since.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// RouteOption configures a route registered on a RouteGroup
+type RouteOption func(*routeOptions)
+
+// routeOptions holds the settings applied by RouteOptions
+type routeOptions struct {
+	deprecation        Deprecation
+	endpoint           *openapi.Endpoint
+	skipBodyValidation bool
+}
+
+// Deprecated marks a route as deprecated
+func Deprecated(deprecation Deprecation) RouteOption {
+	return func(o *routeOptions) {
+		o.deprecation = deprecation
+	}
+}
+
+// WithoutBodyValidation leaves request body validation to the handler, for documented
+// routes whose handler reports errors in its own format
+func WithoutBodyValidation() RouteOption {
+	return func(o *routeOptions) {
+		o.skipBodyValidation = true
+	}
+}
+
+// RouteGroup registers routes under an API version prefix
+type RouteGroup struct {
+	server  *Server
+	version string
+	prefix  string
+}
+
+// Version returns the API version of the group, empty for legacy routes
+func (g *RouteGroup) Version() string {
+	return g.version
+}
+
+// HandleFunc registers the handler for the pattern under the group prefix
+// Patterns are written without the version, e.g. "/chargebacks/{id}"
+func (g *RouteGroup) HandleFunc(pattern string, handler http.HandlerFunc, opts ...RouteOption) {
+	var options routeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if !options.deprecation.IsZero() {
+		handler = deprecationMiddleware(handler, g.prefix, options.deprecation)
+	}
+
+	fullPattern := g.prefix + pattern
+
+	if options.endpoint != nil {
+		g.server.documentRoute(fullPattern, g.version != "", options)
+	}
+
+	g.server.mux.HandleFunc(fullPattern, handler)
+	g.server.routeNames[fullPattern] = pattern
+}
+
+// WithAPIVersion mounts an additional API version, e.g. "v2", whose handlers may use
+// their own request and response types; register is called once while routes are set up
+func WithAPIVersion(version string, register func(*RouteGroup)) Option {
+	return func(s *Server) {
+		s.apiVersions = append(s.apiVersions, apiVersion{version: version, register: register})
+	}
+}
+
+// apiVersion holds an additional API version registered through WithAPIVersion
+type apiVersion struct {
+	version  string
+	register func(*RouteGroup)
+}
+
+// newRouteGroup returns a group mounting routes under "/<version>", or at the
+// root for the legacy unversioned routes when version is empty
+func (s *Server) newRouteGroup(version string) *RouteGroup {
+	prefix := ""
+	if version != "" {
+		prefix = "/" + version
+	}
+	return &RouteGroup{server: s, version: version, prefix: prefix}
+}
+
+// routeName returns the unversioned pattern of a registered route, so the same
+// endpoint is identified consistently across API versions
+func (s *Server) routeName(pattern string) string {
+	if name, ok := s.routeNames[pattern]; ok {
+		return name
+	}
+	return pattern
+}
+
+// deprecationMiddleware adds the Deprecation, Sunset and successor Link headers
+func deprecationMiddleware(next http.HandlerFunc, prefix string, deprecation Deprecation) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !deprecation.Since.IsZero() {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", deprecation.Since.Unix()))
+		}
+		if !deprecation.Sunset.IsZero() {
+			w.Header().Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if deprecation.SuccessorVersion != "" {
+			successor := "/" + deprecation.SuccessorVersion + strings.TrimPrefix(r.URL.Path, prefix)
+			w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+func TestServer_VersionedRoutes(t *testing.T) {
+	legacy := Deprecation{
+		Since:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name              string
+		legacy            Deprecation
+		path              string
+		expectDeprecation string
+		expectSunset      string
+		expectLink        string
+	}{
+		{
+			name:   "v1 route is not deprecated",
+			legacy: legacy,
+			path:   "/v1/chargebacks/cb_1",
+		},
+		{
+			name:              "legacy alias carries deprecation headers",
+			legacy:            legacy,
+			path:              "/chargebacks/cb_1",
+			expectDeprecation: "@1767225600",
+			expectSunset:      "Wed, 01 Jul 2026 00:00:00 GMT",
+			expectLink:        `</v1/chargebacks/cb_1>; rel="successor-version"`,
+		},
+		{
+			name: "legacy alias without a schedule is a plain alias",
+			path: "/chargebacks/cb_1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			getUC := &MockGetChargebackUseCase{}
+			server := NewServer(ServerConfig{Port: "8080", LegacyRoutes: tt.legacy}, &MockCreateChargebackUseCase{}, createTestLogger(),
+				WithGetChargebackUseCase(getUC),
+			)
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			// Act
+			server.ServeHTTP(recorder, req)
+
+			// Assert
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
+			}
+
+			var response map[string]interface{}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response["id"] != "cb_1" {
+				t.Errorf("Expected chargeback 'cb_1', got %v", response["id"])
+			}
+
+			expectedHeaders := map[string]string{
+				"Deprecation": tt.expectDeprecation,
+				"Sunset":      tt.expectSunset,
+				"Link":        tt.expectLink,
+			}
+			for header, expectedValue := range expectedHeaders {
+				if actualValue := recorder.Header().Get(header); actualValue != expectedValue {
+					t.Errorf("Expected header %s: '%s', got '%s'", header, expectedValue, actualValue)
+				}
+			}
+		})
+	}
+}
+
+// createChargebackV2Request is a request shape that differs from the v1 DTO
+type createChargebackV2Request struct {
+	Amount struct {
+		Value    int64  `json:"value"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+}
+
+func TestServer_AdditionalAPIVersion(t *testing.T) {
+	// Arrange
+	var received createChargebackV2Request
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithAPIVersion("v2", func(group *RouteGroup) {
+			group.HandleFunc("/chargebacks", func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&received)
+				w.WriteHeader(http.StatusAccepted)
+			})
+		}),
+	)
+
+	// Act
+	v2 := httptest.NewRecorder()
+	server.ServeHTTP(v2, httptest.NewRequest(http.MethodPost, "/v2/chargebacks",
+		strings.NewReader(`{"amount":{"value":9999,"currency":"USD"}}`)))
+
+	v1 := httptest.NewRecorder()
+	server.ServeHTTP(v1, httptest.NewRequest(http.MethodGet, "/v1/chargebacks/cb_1", nil))
+
+	v2Get := httptest.NewRecorder()
+	server.ServeHTTP(v2Get, httptest.NewRequest(http.MethodGet, "/v2/chargebacks/cb_1", nil))
+
+	// Assert
+	if v2.Code != http.StatusAccepted {
+		t.Fatalf("Expected v2 status code %d, got %d", http.StatusAccepted, v2.Code)
+	}
+	if received.Amount.Value != 9999 || received.Amount.Currency != "USD" {
+		t.Errorf("Expected v2 handler to decode its own request, got %+v", received)
+	}
+	if v1.Code != http.StatusOK {
+		t.Errorf("Expected v1 routes to remain mounted, got status code %d", v1.Code)
+	}
+	if v2Get.Code != http.StatusNotFound {
+		t.Errorf("Expected v2 to only serve its own routes, got status code %d", v2Get.Code)
+	}
+}
+
+func TestServer_VersionedRoutes_ShareRateLimit(t *testing.T) {
+	// Arrange
+	limiter := NewMockRateLimiter()
+	server := NewServer(ServerConfig{Port: "8080"}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(&MockGetChargebackUseCase{}),
+		WithRateLimiter(limiter, RateLimitConfig{
+			KeyBy: RateLimitByIP,
+			Routes: map[string]service.RateLimit{
+				"GET /chargebacks/{id}": {Requests: 10, Window: time.Minute},
+			},
+		}),
+	)
+
+	// Act
+	for _, path := range []string{"/v1/chargebacks/cb_1", "/chargebacks/cb_1"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		server.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Assert
+	if limiter.counts["GET /chargebacks/{id}|ip:203.0.113.7"] != 2 {
+		t.Errorf("Expected versioned and legacy paths to share a counter, got %v", limiter.counts)
+	}
+}
+
+func TestDeprecation_Validate(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		deprecation Deprecation
+		shouldErr   bool
+	}{
+		{"not deprecated", Deprecation{}, false},
+		{"sunset after deprecation", Deprecation{Since: since, Sunset: since.AddDate(0, 6, 0)}, false},
+		{"sunset only", Deprecation{Sunset: since}, false},
+		{"sunset before deprecation", Deprecation{Since: since, Sunset: since.AddDate(0, -1, 0)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.deprecation.Validate()
+
+			// Assert
+			if tt.shouldErr && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DiegoSantos90/chargeback-api/internal/api/http/handler"
+	"github.com/DiegoSantos90/chargeback-api/internal/api/openapi"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
 	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
 )
@@ -23,12 +26,41 @@ type Server struct {
 	config            ServerConfig
 	mux               *http.ServeMux
 	chargebackHandler *handler.ChargebackHandler
+	queryHandler      *handler.ChargebackQueryHandler
+	batchHandler      *handler.ChargebackBatchHandler
+	exportHandler     *handler.ChargebackExportHandler
+	importHandler     *handler.ImportHandler
+	reviewHandler     *handler.ChargebackReviewHandler
+	merchantHandler   *handler.MerchantStatsHandler
+	reportHandler     *handler.ReportHandler
+	apiKeyHandler     *handler.APIKeyHandler
+	authenticators    []Authenticator
+	rateLimiter       service.RateLimiter
+	rateLimit         RateLimitConfig
+	apiVersions       []apiVersion
+	routeNames        map[string]string
+	openAPIDocument   *openapi.Document
+	requestSchemas    map[string]*openapi.Schema
 	logger            service.Logger
+	health            *HealthRegistry
+	shuttingDown      atomic.Bool
+
+	mu         sync.Mutex
+	httpServer *http.Server
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string `json:"port"`
+	Port string     `json:"port"`
+	CORS CORSConfig `json:"cors"`
+	TLS  TLSConfig  `json:"tls"`
+
+	// LegacyRoutes is the deprecation schedule of the unversioned route aliases
+	LegacyRoutes Deprecation `json:"legacy_routes"`
+
+	// ShutdownDrainDelay is how long /readyz reports shutting_down before the listener
+	// closes, so load balancers stop routing to the instance first
+	ShutdownDrainDelay time.Duration `json:"shutdown_drain_delay"`
 }
 
 // Validate validates the server configuration
@@ -42,16 +74,89 @@ func (c ServerConfig) Validate() error {
 		return fmt.Errorf("port must be a valid number")
 	}
 
+	if err := c.CORS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if c.ShutdownDrainDelay < 0 {
+		return fmt.Errorf("shutdown drain delay must not be negative")
+	}
+
 	return nil
 }
 
+// Option configures optional server features
+type Option func(*Server)
+
+// WithGetChargebackUseCase enables GET /chargebacks/{id}
+func WithGetChargebackUseCase(getChargebackUC handler.GetChargebackUseCase) Option {
+	return func(s *Server) {
+		s.queryHandler = handler.NewChargebackQueryHandler(getChargebackUC)
+	}
+}
+
+// WithBatchCreateUseCase enables POST /chargebacks/batch for batches of up to maxItems
+func WithBatchCreateUseCase(batchCreateUC handler.BatchCreateChargebacksUseCase, maxItems int) Option {
+	return func(s *Server) {
+		s.batchHandler = handler.NewChargebackBatchHandler(batchCreateUC, maxItems)
+	}
+}
+
+// WithExportUseCase enables GET /chargebacks/export
+func WithExportUseCase(exportUC handler.ExportChargebacksUseCase) Option {
+	return func(s *Server) {
+		s.exportHandler = handler.NewChargebackExportHandler(exportUC)
+	}
+}
+
+// WithImportJobs enables POST /imports and GET /imports/{id}
+func WithImportJobs(jobs handler.ImportJobService) Option {
+	return func(s *Server) {
+		s.importHandler = handler.NewImportHandler(jobs)
+	}
+}
+
+// WithReviewUseCases enables POST /chargebacks/{id}/approve and /chargebacks/{id}/reject
+func WithReviewUseCases(approveChargebackUC, rejectChargebackUC handler.ReviewChargebackUseCase) Option {
+	return func(s *Server) {
+		s.reviewHandler = handler.NewChargebackReviewHandler(approveChargebackUC, rejectChargebackUC)
+	}
+}
+
+// WithMerchantStatsUseCases enables PUT /merchants/{merchant_id}/volumes/{month} and
+// GET /merchants/{merchant_id}/stats
+func WithMerchantStatsUseCases(recordVolumeUC handler.RecordTransactionVolumeUseCase, getMerchantStatsUC handler.GetMerchantStatsUseCase) Option {
+	return func(s *Server) {
+		s.merchantHandler = handler.NewMerchantStatsHandler(recordVolumeUC, getMerchantStatsUC)
+	}
+}
+
+// WithReportSummaryUseCase enables GET /reports/summary
+func WithReportSummaryUseCase(getReportSummaryUC handler.GetReportSummaryUseCase) Option {
+	return func(s *Server) {
+		s.reportHandler = handler.NewReportHandler(getReportSummaryUC)
+	}
+}
+
 // NewServer creates a new HTTP server
-func NewServer(config ServerConfig, createChargebackUC CreateChargebackUseCase, logger service.Logger) *Server {
+func NewServer(config ServerConfig, createChargebackUC CreateChargebackUseCase, logger service.Logger, opts ...Option) *Server {
 	server := &Server{
 		config:            config,
 		mux:               http.NewServeMux(),
 		chargebackHandler: handler.NewChargebackHandler(createChargebackUC),
 		logger:            logger,
+		health:            NewHealthRegistry(),
+		routeNames:        make(map[string]string),
+		openAPIDocument:   newOpenAPIDocument(),
+		requestSchemas:    make(map[string]*openapi.Schema),
+	}
+
+	for _, opt := range opts {
+		opt(server)
 	}
 
 	server.setupRoutes()
@@ -62,11 +167,73 @@ func NewServer(config ServerConfig, createChargebackUC CreateChargebackUseCase,
 
 // setupRoutes configures the HTTP routes
 func (s *Server) setupRoutes() {
-	// Health check endpoint
+	// Health check endpoints are unversioned
 	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/livez", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReadiness)
+	s.mux.HandleFunc(openAPIPath, s.handleOpenAPI)
+
+	// The current version is also served at the legacy unversioned paths
+	s.registerV1Routes(s.newRouteGroup(CurrentAPIVersion))
+
+	legacy := s.config.LegacyRoutes
+	if !legacy.IsZero() && legacy.SuccessorVersion == "" {
+		legacy.SuccessorVersion = CurrentAPIVersion
+	}
+	s.registerV1Routes(s.newRouteGroup(""), Deprecated(legacy))
+
+	for _, version := range s.apiVersions {
+		version.register(s.newRouteGroup(version.version))
+	}
+}
+
+// registerV1Routes registers the v1 endpoints on the group
+func (s *Server) registerV1Routes(group *RouteGroup, opts ...RouteOption) {
+	handle := func(pattern string, handler http.HandlerFunc, routeOpts ...RouteOption) {
+		routeOpts = append([]RouteOption{Documented(v1Endpoints[pattern])}, routeOpts...)
+		group.HandleFunc(pattern, handler, append(routeOpts, opts...)...)
+	}
 
 	// Chargeback endpoints
-	s.mux.HandleFunc("/chargebacks", s.chargebackHandler.CreateChargeback)
+	handle("/chargebacks", s.chargebackHandler.CreateChargeback)
+	if s.batchHandler != nil {
+		// Batch items are validated one by one, so a bad item doesn't reject the batch
+		handle("/chargebacks/batch", s.batchHandler.CreateChargebacks, WithoutBodyValidation())
+	}
+	if s.exportHandler != nil {
+		handle("/chargebacks/export", s.exportHandler.ExportChargebacks)
+	}
+	if s.queryHandler != nil {
+		handle("/chargebacks/{id}", s.queryHandler.GetChargeback)
+	}
+	if s.reviewHandler != nil {
+		handle("/chargebacks/{id}/approve", s.reviewHandler.ApproveChargeback)
+		handle("/chargebacks/{id}/reject", s.reviewHandler.RejectChargeback)
+	}
+
+	// Merchant endpoints
+	if s.merchantHandler != nil {
+		handle("/merchants/{merchant_id}/volumes/{month}", s.merchantHandler.RecordTransactionVolume)
+		handle("/merchants/{merchant_id}/stats", s.merchantHandler.GetMerchantStats)
+	}
+
+	// Report endpoints
+	if s.reportHandler != nil {
+		handle("/reports/summary", s.reportHandler.GetReportSummary)
+	}
+
+	// Import endpoints
+	if s.importHandler != nil {
+		handle("/imports", s.importHandler.StartImport)
+		handle("/imports/{id}", s.importHandler.GetImport)
+	}
+
+	// Admin endpoints
+	if s.apiKeyHandler != nil {
+		handle("/admin/api-keys", s.apiKeyHandler.IssueAPIKey)
+		handle("/admin/api-keys/{id}", s.apiKeyHandler.RevokeAPIKey)
+		handle("/admin/api-keys/{id}/rotate", s.apiKeyHandler.RotateAPIKey)
+	}
 }
 
 // setupMiddleware applies middleware to the server
@@ -76,26 +243,17 @@ func (s *Server) setupMiddleware() {
 
 // ServeHTTP implements http.Handler interface
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Apply CORS middleware
-	s.corsMiddleware(w, r)
-
-	// Return early for OPTIONS requests (handled by CORS middleware)
-	if r.Method == http.MethodOptions {
-		return
-	}
-
 	// Create a response writer wrapper to capture status code
 	wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
 	start := time.Now()
 
-	// Check if route exists
-	if !s.routeExists(r.URL.Path) {
-		wrapped.Header().Set("Content-Type", "application/json")
-		wrapped.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(wrapped).Encode(map[string]string{"error": "Not found"})
-	} else {
-		s.mux.ServeHTTP(wrapped, r)
+	// Answer CORS preflights and reject disallowed origins, then check if the
+	// route exists and authenticate non-public routes
+	if s.config.CORS.Enabled() && isPreflight(r) {
+		s.handlePreflight(wrapped, r)
+	} else if s.applyCORS(wrapped, r) {
+		s.serveRoute(wrapped, r)
 	}
 
 	// Log the request
@@ -110,18 +268,50 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// routeExists checks if a route exists
-func (s *Server) routeExists(path string) bool {
-	validRoutes := []string{"/health", "/chargebacks"}
-	for _, route := range validRoutes {
-		if path == route {
-			return true
-		}
+// serveRoute dispatches the request to its route, authenticating and rate limiting non-public routes
+func (s *Server) serveRoute(w http.ResponseWriter, r *http.Request) {
+	if !s.routeExists(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Not found"})
+		return
+	}
+
+	client, ok := s.allowClient(w, r)
+	if !ok {
+		return
+	}
+
+	authenticated, err := s.authenticate(r)
+	if err != nil {
+		setRateLimitHeaders(w, client)
+		s.writeAuthError(w, r, err)
+		return
+	}
+
+	if !s.allowRequest(w, authenticated, client) {
+		return
+	}
+
+	if !s.validateRequestBody(w, authenticated) {
+		return
 	}
-	return false
+
+	s.mux.ServeHTTP(w, authenticated)
+}
+
+// routeExists checks if a route is registered for the request path
+func (s *Server) routeExists(r *http.Request) bool {
+	_, pattern := s.mux.Handler(r)
+	return pattern != ""
+}
+
+// RegisterHealthChecker adds a dependency check to the readiness endpoint
+func (s *Server) RegisterHealthChecker(checker HealthChecker) {
+	s.health.Register(checker)
 }
 
-// handleHealth handles health check requests
+// handleHealth handles liveness requests; it only reports that the process is serving
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -139,18 +329,35 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// corsMiddleware handles CORS (Cross-Origin Resource Sharing)
-func (s *Server) corsMiddleware(w http.ResponseWriter, r *http.Request) {
-	// Set CORS headers
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-	// Handle preflight requests
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+// handleReadiness handles readiness requests by checking every registered dependency
+func (s *Server) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
 		return
 	}
+
+	var report HealthReport
+	if s.shuttingDown.Load() {
+		report = HealthReport{
+			Service:   "chargeback-api",
+			Status:    HealthStatusShuttingDown,
+			Timestamp: time.Now().Format(time.RFC3339),
+			Checks:    map[string]DependencyHealth{},
+		}
+	} else {
+		report = s.health.CheckAll(r.Context())
+	}
+
+	statusCode := http.StatusOK
+	if report.Status != HealthStatusOK {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(report)
 }
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -164,18 +371,19 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Start starts the HTTP server
+// Unwrap gives http.ResponseController access to the underlying writer, e.g. to flush
+// streamed responses
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Start starts the HTTP server, terminating TLS when it is configured
 func (s *Server) Start() error {
 	if err := s.config.Validate(); err != nil {
 		return fmt.Errorf("invalid server configuration: %w", err)
 	}
 
 	addr := ":" + s.config.Port
-	s.logger.Info(context.Background(), "Starting HTTP server", map[string]interface{}{
-		"address": addr,
-		"port":    s.config.Port,
-	})
-
 	server := &http.Server{
 		Addr:         addr,
 		Handler:      s,
@@ -184,5 +392,55 @@ func (s *Server) Start() error {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if s.config.TLS.Enabled() {
+		reloader, err := newCertificateReloader(s.config.TLS, s.logger)
+		if err != nil {
+			return fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		server.TLSConfig = reloader.TLSConfig()
+	}
+
+	s.logger.Info(context.Background(), "Starting HTTP server", map[string]interface{}{
+		"address":    addr,
+		"port":       s.config.Port,
+		"tls":        s.config.TLS.Enabled(),
+		"mutual_tls": s.config.TLS.ClientCAFile != "",
+	})
+
+	s.mu.Lock()
+	s.httpServer = server
+	s.mu.Unlock()
+
+	if server.TLSConfig != nil {
+		// Certificates are served by the reloader, so no files are passed here
+		return server.ListenAndServeTLS("", "")
+	}
 	return server.ListenAndServe()
 }
+
+// Shutdown marks the server as not ready, keeps serving for the drain delay and then
+// gracefully stops accepting requests
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shuttingDown.Store(true)
+
+	s.mu.Lock()
+	server := s.httpServer
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	if delay := s.config.ShutdownDrainDelay; delay > 0 {
+		s.logger.Info(ctx, "Draining before stopping HTTP server", map[string]interface{}{
+			"delay": delay.String(),
+		})
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	s.logger.Info(ctx, "Stopping HTTP server", nil)
+	return server.Shutdown(ctx)
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// Client certificate verification modes
+const (
+	// ClientAuthRequire rejects TLS handshakes without a valid client certificate
+	ClientAuthRequire = "require"
+
+	// ClientAuthOptional verifies client certificates when presented, so clients
+	// may authenticate with API keys or bearer tokens instead
+	ClientAuthOptional = "optional"
+)
+
+// TLSConfig holds the TLS termination configuration
+// TLS is disabled, and the server listens on plain HTTP, when CertFile is empty
+type TLSConfig struct {
+	// CertFile and KeyFile are the PEM encoded server certificate chain and private key
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// MinVersion is the minimum TLS version, "1.2" or "1.3"; defaults to "1.2"
+	MinVersion string `json:"min_version"`
+
+	// CipherSuites restricts the TLS 1.2 cipher suites by their standard names;
+	// empty uses Go's secure defaults. TLS 1.3 suites are not configurable
+	CipherSuites []string `json:"cipher_suites"`
+
+	// ClientCAFile is a PEM bundle of CAs used to verify client certificates;
+	// setting it enables mutual TLS
+	ClientCAFile string `json:"client_ca_file"`
+
+	// ClientAuth is the client certificate verification mode, "require" or "optional";
+	// defaults to "require"
+	ClientAuth string `json:"client_auth"`
+
+	// ReloadInterval is how often the certificate files are checked for changes
+	ReloadInterval time.Duration `json:"reload_interval"`
+}
+
+// Enabled reports whether TLS termination is configured
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// Validate validates the TLS configuration
+func (c TLSConfig) Validate() error {
+	if !c.Enabled() {
+		if c.KeyFile != "" || c.ClientCAFile != "" {
+			return fmt.Errorf("TLS certificate file is required when a key or client CA file is set")
+		}
+		return nil
+	}
+
+	if c.KeyFile == "" {
+		return fmt.Errorf("TLS key file is required")
+	}
+
+	if _, err := c.minVersion(); err != nil {
+		return err
+	}
+
+	if _, err := c.cipherSuites(); err != nil {
+		return err
+	}
+
+	if _, err := c.clientAuth(); err != nil {
+		return err
+	}
+
+	if c.ReloadInterval < 0 {
+		return fmt.Errorf("TLS reload interval cannot be negative")
+	}
+
+	return nil
+}
+
+// minVersion converts the configured minimum version to its tls constant
+func (c TLSConfig) minVersion() (uint16, error) {
+	switch c.MinVersion {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("TLS minimum version must be '1.2' or '1.3', got '%s'", c.MinVersion)
+	}
+}
+
+// cipherSuites resolves the configured cipher suite names to their IDs
+// Only suites Go considers secure are accepted
+func (c TLSConfig) cipherSuites() ([]uint16, error) {
+	if len(c.CipherSuites) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(c.CipherSuites))
+	for _, name := range c.CipherSuites {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure TLS cipher suite '%s'", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// clientAuth converts the configured client certificate mode to its tls constant
+func (c TLSConfig) clientAuth() (tls.ClientAuthType, error) {
+	if c.ClientCAFile == "" {
+		return tls.NoClientCert, nil
+	}
+
+	switch c.ClientAuth {
+	case "", ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven, nil
+	default:
+		return 0, fmt.Errorf("TLS client auth must be '%s' or '%s', got '%s'", ClientAuthRequire, ClientAuthOptional, c.ClientAuth)
+	}
+}
+
+// certificateReloader serves the TLS configuration and reloads the certificate,
+// key and client CA files when they change on disk
+// Files are checked at most once per interval during handshakes; if a reload fails,
+// for example while a new certificate is only partially written, the previous
+// certificates are kept and the reload is retried on the next check
+type certificateReloader struct {
+	config   TLSConfig
+	base     *tls.Config
+	interval time.Duration
+	logger   service.Logger
+	now      func() time.Time
+
+	mu          sync.RWMutex
+	current     *tls.Config
+	modTimes    map[string]time.Time
+	lastChecked time.Time
+}
+
+// newCertificateReloader loads the certificates and returns a reloader serving them
+func newCertificateReloader(config TLSConfig, logger service.Logger) (*certificateReloader, error) {
+	minVersion, err := config.minVersion()
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := config.cipherSuites()
+	if err != nil {
+		return nil, err
+	}
+	clientAuth, err := config.clientAuth()
+	if err != nil {
+		return nil, err
+	}
+
+	interval := config.ReloadInterval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	r := &certificateReloader{
+		config: config,
+		base: &tls.Config{
+			MinVersion:   minVersion,
+			CipherSuites: cipherSuites,
+			ClientAuth:   clientAuth,
+			NextProtos:   []string{"h2", "http/1.1"},
+		},
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the listener configuration, which resolves the current
+// certificates for each handshake
+func (r *certificateReloader) TLSConfig() *tls.Config {
+	config := r.base.Clone()
+	config.GetConfigForClient = r.getConfigForClient
+	return config
+}
+
+// getConfigForClient returns the current configuration, reloading changed files first
+func (r *certificateReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.maybeReload()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current, nil
+}
+
+// maybeReload reloads the certificates if the check interval has passed and a file changed
+func (r *certificateReloader) maybeReload() {
+	r.mu.RLock()
+	due := r.now().Sub(r.lastChecked) >= r.interval
+	r.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	changed, err := r.filesChanged()
+	if err == nil && !changed {
+		return
+	}
+	if err == nil {
+		err = r.reload()
+	}
+	if err != nil {
+		r.mu.Lock()
+		r.lastChecked = r.now()
+		r.mu.Unlock()
+
+		r.logger.Error(context.Background(), "Failed to reload TLS certificates; keeping previous certificates", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	r.logger.Info(context.Background(), "Reloaded TLS certificates", map[string]interface{}{
+		"cert_file": r.config.CertFile,
+	})
+}
+
+// filesChanged checks the certificate files' modification times against the loaded ones
+func (r *certificateReloader) filesChanged() (bool, error) {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastChecked = r.now()
+
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reload reads the certificate files and swaps in a new configuration
+func (r *certificateReloader) reload() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	config := r.base.Clone()
+	config.Certificates = []tls.Certificate{certificate}
+
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("failed to parse client CA file: no certificates found")
+		}
+		config.ClientCAs = pool
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = config
+	r.modTimes = modTimes
+	r.lastChecked = r.now()
+	return nil
+}
+
+// statFiles returns the modification times of the configured files
+func (r *certificateReloader) statFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, path := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat TLS file: %w", err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
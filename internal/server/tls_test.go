@@ -0,0 +1,344 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+)
+
+// testCA is a throwaway certificate authority for issuing test certificates
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue creates a certificate signed by the CA, returning PEM encoded certificate and key
+func (ca *testCA) issue(t *testing.T, commonName string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"Acme Acquiring"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes test data into the directory and returns its path
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// startTLSServer serves the server over TLS on a local port and returns its address
+func startTLSServer(t *testing.T, server *Server, reloader *certificateReloader) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	httpServer := &http.Server{Handler: server}
+	go httpServer.Serve(tls.NewListener(listener, reloader.TLSConfig()))
+	t.Cleanup(func() { httpServer.Close() })
+
+	return "https://" + listener.Addr().String()
+}
+
+// newTLSClient returns a client trusting the CA and presenting the given certificate, if any
+func newTLSClient(t *testing.T, ca *testCA, certPEM, keyPEM []byte) *http.Client {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+
+	config := &tls.Config{RootCAs: roots}
+	if certPEM != nil {
+		certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			t.Fatalf("Failed to load client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	// Arrange
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	dir := t.TempDir()
+
+	serverCert, serverKey := ca.issue(t, "chargeback-api", 2, x509.ExtKeyUsageServerAuth)
+	acquirerCert, acquirerKey := ca.issue(t, "acquirer-1", 3, x509.ExtKeyUsageClientAuth)
+	unmappedCert, unmappedKey := ca.issue(t, "acquirer-2", 4, x509.ExtKeyUsageClientAuth)
+	foreignCert, foreignKey := otherCA.issue(t, "acquirer-1", 5, x509.ExtKeyUsageClientAuth)
+
+	tlsConfig := TLSConfig{
+		CertFile:     writeFile(t, dir, "server.crt", serverCert),
+		KeyFile:      writeFile(t, dir, "server.key", serverKey),
+		ClientCAFile: writeFile(t, dir, "ca.crt", ca.pem),
+		ClientAuth:   ClientAuthOptional,
+	}
+
+	getUC := &MockGetChargebackUseCase{}
+	server := NewServer(ServerConfig{Port: "8443", TLS: tlsConfig}, &MockCreateChargebackUseCase{}, createTestLogger(),
+		WithGetChargebackUseCase(getUC),
+		WithAuthenticator(NewClientCertAuthenticator(
+			map[string]string{"acquirer-1": "merchant-789"},
+			[]auth.Scope{auth.ScopeChargebacksRead},
+		)),
+	)
+
+	reloader, err := newCertificateReloader(tlsConfig, createTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	baseURL := startTLSServer(t, server, reloader)
+
+	tests := []struct {
+		name                 string
+		certPEM              []byte
+		keyPEM               []byte
+		expectedCode         int
+		expectHandshakeError bool
+	}{
+		{name: "maps client certificate to merchant", certPEM: acquirerCert, keyPEM: acquirerKey, expectedCode: http.StatusOK},
+		{name: "rejects unmapped client certificate", certPEM: unmappedCert, keyPEM: unmappedKey, expectedCode: http.StatusUnauthorized},
+		{name: "requires credentials without client certificate", expectedCode: http.StatusUnauthorized},
+		{name: "rejects certificate from unknown CA", certPEM: foreignCert, keyPEM: foreignKey, expectHandshakeError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getUC.principal = nil
+
+			// Act
+			resp, err := newTLSClient(t, ca, tt.certPEM, tt.keyPEM).Get(baseURL + "/chargebacks/cb_1")
+
+			// Assert
+			if tt.expectHandshakeError {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("Expected TLS handshake to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedCode, resp.StatusCode)
+			}
+			if tt.expectedCode == http.StatusOK {
+				if getUC.principal == nil || len(getUC.principal.MerchantIDs) != 1 || getUC.principal.MerchantIDs[0] != "merchant-789" {
+					t.Errorf("Expected principal bound to merchant-789, got %+v", getUC.principal)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_MutualTLS_Required(t *testing.T) {
+	// Arrange
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	serverCert, serverKey := ca.issue(t, "chargeback-api", 2, x509.ExtKeyUsageServerAuth)
+
+	tlsConfig := TLSConfig{
+		CertFile:     writeFile(t, dir, "server.crt", serverCert),
+		KeyFile:      writeFile(t, dir, "server.key", serverKey),
+		ClientCAFile: writeFile(t, dir, "ca.crt", ca.pem),
+	}
+	reloader, err := newCertificateReloader(tlsConfig, createTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	server := NewServer(ServerConfig{Port: "8443", TLS: tlsConfig}, &MockCreateChargebackUseCase{}, createTestLogger())
+	baseURL := startTLSServer(t, server, reloader)
+
+	// Act
+	resp, err := newTLSClient(t, ca, nil, nil).Get(baseURL + "/livez")
+
+	// Assert
+	if err == nil {
+		resp.Body.Close()
+		t.Error("Expected handshake without client certificate to fail")
+	}
+}
+
+func TestCertificateReloader_ReloadsChangedCertificate(t *testing.T) {
+	// Arrange
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	firstCert, firstKey := ca.issue(t, "chargeback-api", 10, x509.ExtKeyUsageServerAuth)
+	certFile := writeFile(t, dir, "server.crt", firstCert)
+	keyFile := writeFile(t, dir, "server.key", firstKey)
+
+	reloader, err := newCertificateReloader(TLSConfig{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Minute}, createTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	server := NewServer(ServerConfig{Port: "8443"}, &MockCreateChargebackUseCase{}, createTestLogger())
+	baseURL := startTLSServer(t, server, reloader)
+	client := newTLSClient(t, ca, nil, nil)
+
+	servedSerial := func() int64 {
+		t.Helper()
+		resp, err := client.Get(baseURL + "/livez")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	// Act: rotate the certificate files on disk
+	secondCert, secondKey := ca.issue(t, "chargeback-api", 11, x509.ExtKeyUsageServerAuth)
+	writeFile(t, dir, "server.crt", secondCert)
+	writeFile(t, dir, "server.key", secondKey)
+	later := time.Now().Add(time.Second)
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+
+	beforeInterval := servedSerial()
+	now = now.Add(2 * time.Minute)
+	afterInterval := servedSerial()
+
+	// Assert
+	if beforeInterval != 10 {
+		t.Errorf("Expected original certificate before the check interval, got serial %d", beforeInterval)
+	}
+	if afterInterval != 11 {
+		t.Errorf("Expected rotated certificate after the check interval, got serial %d", afterInterval)
+	}
+}
+
+func TestCertificateReloader_KeepsCertificateOnInvalidFiles(t *testing.T) {
+	// Arrange
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cert, key := ca.issue(t, "chargeback-api", 20, x509.ExtKeyUsageServerAuth)
+	certFile := writeFile(t, dir, "server.crt", cert)
+	keyFile := writeFile(t, dir, "server.key", key)
+
+	reloader, err := newCertificateReloader(TLSConfig{CertFile: certFile, KeyFile: keyFile}, createTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to load TLS configuration: %v", err)
+	}
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	// Act: a partially written certificate
+	writeFile(t, dir, "server.crt", []byte("-----BEGIN CERTIFICATE-----\n"))
+	later := time.Now().Add(time.Second)
+	os.Chtimes(certFile, later, later)
+	now = now.Add(time.Hour)
+	config, err := reloader.getConfigForClient(nil)
+
+	// Assert
+	if err != nil || config == nil || len(config.Certificates) != 1 {
+		t.Fatalf("Expected previous certificate to be kept, got %v, %v", config, err)
+	}
+	leaf, _ := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if leaf.SerialNumber.Int64() != 20 {
+		t.Errorf("Expected serial 20, got %d", leaf.SerialNumber.Int64())
+	}
+}
+
+func TestTLSConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    TLSConfig
+		shouldErr bool
+	}{
+		{"disabled", TLSConfig{}, false},
+		{"certificate and key", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", MinVersion: "1.3"}, false},
+		{"secure cipher suite", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}}, false},
+		{"mutual TLS", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt", ClientAuth: "optional"}, false},
+		{"missing key", TLSConfig{CertFile: "server.crt"}, true},
+		{"client CA without certificate", TLSConfig{ClientCAFile: "ca.crt"}, true},
+		{"unsupported version", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", MinVersion: "1.0"}, true},
+		{"insecure cipher suite", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, true},
+		{"unknown client auth", TLSConfig{CertFile: "server.crt", KeyFile: "server.key", ClientCAFile: "ca.crt", ClientAuth: "sometimes"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.shouldErr && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
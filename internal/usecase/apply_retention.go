@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// ApplyRetentionResult represents the outcome of applying the retention policy
+type ApplyRetentionResult struct {
+	// Cutoff is the creation time before which chargebacks were purged
+	Cutoff time.Time `json:"cutoff"`
+	// Purged counts the chargebacks archived and purged, or that would be in a dry run
+	Purged int `json:"purged"`
+	// Archives lists where the purged chargebacks were archived
+	Archives []string `json:"archives"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// ApplyRetentionUseCase handles purging chargebacks kept longer than the retention period
+// Card schemes require chargebacks to be kept for years, deleted ones included, and to
+// be purged after that. Each page of expired chargebacks is archived to cold storage
+// before it is purged, so a failed run leaves nothing purged that isn't archived; running
+// it again archives the remaining pages anew.
+type ApplyRetentionUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+	archive        service.ChargebackArchive
+	years          int
+	pageSize       int
+	now            func() time.Time
+}
+
+// NewApplyRetentionUseCase creates a new instance of ApplyRetentionUseCase keeping
+// chargebacks for the given number of years
+func NewApplyRetentionUseCase(chargebackRepo repository.ChargebackRepository, archive service.ChargebackArchive, years, pageSize int) *ApplyRetentionUseCase {
+	return &ApplyRetentionUseCase{
+		chargebackRepo: chargebackRepo,
+		archive:        archive,
+		years:          years,
+		pageSize:       pageSize,
+		now:            time.Now,
+	}
+}
+
+// Execute archives, then purges, the chargebacks created before the retention period,
+// a page at a time; a dry run only counts them
+func (uc *ApplyRetentionUseCase) Execute(ctx context.Context, dryRun bool) (*ApplyRetentionResult, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeAdmin); err != nil {
+		return nil, err
+	}
+	if uc.years <= 0 {
+		return nil, fmt.Errorf("retention period must be at least one year, got %d", uc.years)
+	}
+
+	result := &ApplyRetentionResult{
+		Cutoff:   uc.now().UTC().AddDate(-uc.years, 0, 0),
+		Archives: []string{},
+		DryRun:   dryRun,
+	}
+	filter := repository.ChargebackFilter{CreatedTo: result.Cutoff, IncludeDeleted: true}
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := uc.chargebackRepo.ListPage(ctx, filter, cursor, uc.pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chargebacks: %w", err)
+		}
+
+		if len(page.Chargebacks) > 0 && !dryRun {
+			if err := uc.archiveAndPurge(ctx, page.Chargebacks, result); err != nil {
+				return nil, err
+			}
+		}
+		result.Purged += len(page.Chargebacks)
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	return result, nil
+}
+
+// archiveAndPurge purges the chargebacks once they are archived
+func (uc *ApplyRetentionUseCase) archiveAndPurge(ctx context.Context, chargebacks []*entity.Chargeback, result *ApplyRetentionResult) error {
+	location, err := uc.archive.Archive(ctx, chargebacks)
+	if err != nil {
+		return fmt.Errorf("failed to archive chargebacks: %w", err)
+	}
+	result.Archives = append(result.Archives, location)
+
+	if err := uc.chargebackRepo.Purge(ctx, chargebacks); err != nil {
+		return fmt.Errorf("failed to purge chargebacks archived to %s: %w", location, err)
+	}
+	return nil
+}
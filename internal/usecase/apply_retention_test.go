@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockChargebackArchive is a mock implementation of ChargebackArchive
+type MockChargebackArchive struct {
+	ArchiveFunc func(ctx context.Context, chargebacks []*entity.Chargeback) (string, error)
+}
+
+func (m *MockChargebackArchive) Archive(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+	return m.ArchiveFunc(ctx, chargebacks)
+}
+
+// chargebackIDs joins the IDs of the chargebacks
+func chargebackIDs(chargebacks []*entity.Chargeback) string {
+	ids := make([]string, 0, len(chargebacks))
+	for _, chargeback := range chargebacks {
+		ids = append(ids, chargeback.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestApplyRetentionUseCase_Execute(t *testing.T) {
+	old := time.Now().AddDate(-8, 0, 0)
+	var filters []repository.ChargebackFilter
+	chargebackRepo := &MockChargebackRepository{
+		ListPageFunc: func(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+			filters = append(filters, filter)
+			if cursor == "" {
+				return &repository.ChargebackPage{
+					Chargebacks: []*entity.Chargeback{{ID: "cb_1", CreatedAt: old}, {ID: "cb_2", CreatedAt: old, DeletedAt: old}},
+					NextCursor:  "page-2",
+				}, nil
+			}
+			return &repository.ChargebackPage{Chargebacks: []*entity.Chargeback{{ID: "cb_3", CreatedAt: old}}}, nil
+		},
+	}
+
+	t.Run("archives then purges each page", func(t *testing.T) {
+		// Arrange
+		filters = nil
+		var steps []string
+		chargebackRepo.PurgeFunc = func(ctx context.Context, chargebacks []*entity.Chargeback) error {
+			steps = append(steps, "purge:"+chargebackIDs(chargebacks))
+			return nil
+		}
+		archive := &MockChargebackArchive{
+			ArchiveFunc: func(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+				steps = append(steps, "archive:"+chargebackIDs(chargebacks))
+				return "archive/" + chargebacks[0].ID, nil
+			},
+		}
+		uc := usecase.NewApplyRetentionUseCase(chargebackRepo, archive, 7, 2)
+
+		// Act
+		result, err := uc.Execute(context.Background(), false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "archive:cb_1,cb_2 purge:cb_1,cb_2 archive:cb_3 purge:cb_3"
+		if strings.Join(steps, " ") != expected {
+			t.Errorf("Expected steps %s, got %v", expected, steps)
+		}
+		if result.Purged != 3 || strings.Join(result.Archives, ",") != "archive/cb_1,archive/cb_3" {
+			t.Errorf("Unexpected result %+v", result)
+		}
+		cutoff := time.Now().AddDate(-7, 0, 0)
+		if len(filters) != 2 || !filters[0].IncludeDeleted || filters[0].CreatedTo.Sub(cutoff).Abs() > time.Minute {
+			t.Errorf("Expected deleted chargebacks created before %v to be listed, got %+v", cutoff, filters)
+		}
+	})
+
+	t.Run("dry run only counts", func(t *testing.T) {
+		// Arrange
+		chargebackRepo.PurgeFunc = func(ctx context.Context, chargebacks []*entity.Chargeback) error {
+			t.Error("Expected nothing to be purged")
+			return nil
+		}
+		archive := &MockChargebackArchive{
+			ArchiveFunc: func(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+				t.Error("Expected nothing to be archived")
+				return "", nil
+			},
+		}
+		uc := usecase.NewApplyRetentionUseCase(chargebackRepo, archive, 7, 2)
+
+		// Act
+		result, err := uc.Execute(context.Background(), true)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Purged != 3 || !result.DryRun || len(result.Archives) != 0 {
+			t.Errorf("Unexpected result %+v", result)
+		}
+	})
+
+	t.Run("doesn't purge what failed to archive", func(t *testing.T) {
+		// Arrange
+		chargebackRepo.PurgeFunc = func(ctx context.Context, chargebacks []*entity.Chargeback) error {
+			t.Error("Expected nothing to be purged")
+			return nil
+		}
+		archive := &MockChargebackArchive{
+			ArchiveFunc: func(ctx context.Context, chargebacks []*entity.Chargeback) (string, error) {
+				return "", errors.New("bucket unavailable")
+			},
+		}
+		uc := usecase.NewApplyRetentionUseCase(chargebackRepo, archive, 7, 2)
+
+		// Act
+		_, err := uc.Execute(context.Background(), false)
+
+		// Assert
+		if err == nil || !strings.Contains(err.Error(), "failed to archive chargebacks") {
+			t.Errorf("Expected archive error, got %v", err)
+		}
+	})
+
+	t.Run("rejects a retention period under a year", func(t *testing.T) {
+		// Arrange
+		uc := usecase.NewApplyRetentionUseCase(chargebackRepo, &MockChargebackArchive{}, 0, 2)
+
+		// Act
+		_, err := uc.Execute(context.Background(), true)
+
+		// Assert
+		if err == nil {
+			t.Error("Expected error but got none")
+		}
+	})
+
+	t.Run("requires admin scope", func(t *testing.T) {
+		// Arrange
+		uc := usecase.NewApplyRetentionUseCase(chargebackRepo, &MockChargebackArchive{}, 7, 2)
+
+		// Act
+		_, err := uc.Execute(merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead), true)
+
+		// Assert
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Fatalf("Expected forbidden error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// ErrBatchTooLarge is returned when a batch holds more items than the use case accepts
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// BatchItemStatus describes the outcome of a single batch item
+type BatchItemStatus string
+
+const (
+	// BatchItemCreated means the chargeback was saved
+	BatchItemCreated BatchItemStatus = "created"
+	// BatchItemDuplicate means a chargeback already exists for the transaction,
+	// either stored or earlier in the same batch
+	BatchItemDuplicate BatchItemStatus = "duplicate"
+	// BatchItemInvalid means the item failed validation or authorization
+	BatchItemInvalid BatchItemStatus = "invalid"
+	// BatchItemFailed means the item was valid but could not be checked or saved
+	BatchItemFailed BatchItemStatus = "failed"
+)
+
+// BatchItemResult is the outcome of one item, in the same position as the request
+type BatchItemResult struct {
+	Status     BatchItemStatus
+	Chargeback *CreateChargebackResponse
+	Error      string
+}
+
+// BatchCreateChargebacksUseCase creates many chargebacks in one call
+// Items are validated and checked independently, so one bad item doesn't fail the batch
+type BatchCreateChargebacksUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+	ids            service.IDGenerator
+	maxItems       int
+}
+
+// NewBatchCreateChargebacksUseCase creates a new instance of BatchCreateChargebacksUseCase
+// Batches with more than maxItems items are rejected with ErrBatchTooLarge. New
+// chargebacks get their ID from ids.
+func NewBatchCreateChargebacksUseCase(chargebackRepo repository.ChargebackRepository, ids service.IDGenerator, maxItems int) *BatchCreateChargebacksUseCase {
+	return &BatchCreateChargebacksUseCase{
+		chargebackRepo: chargebackRepo,
+		ids:            ids,
+		maxItems:       maxItems,
+	}
+}
+
+// Execute creates the chargebacks and returns one result per request
+// An error is only returned when the batch as a whole is rejected
+func (uc *BatchCreateChargebacksUseCase) Execute(ctx context.Context, reqs []CreateChargebackRequest) ([]BatchItemResult, error) {
+	// 1. Reject oversized batches and callers that can't create chargebacks at all
+	if len(reqs) > uc.maxItems {
+		return nil, fmt.Errorf("%w: %d items, at most %d allowed", ErrBatchTooLarge, len(reqs), uc.maxItems)
+	}
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksWrite); err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchItemResult, len(reqs))
+	pending := make([]*entity.Chargeback, 0, len(reqs))
+	positions := make(map[*entity.Chargeback]int, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+
+	for i, req := range reqs {
+		// 2. Validate each item the way a single create would
+		if err := auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksWrite, req.MerchantID); err != nil {
+			results[i] = BatchItemResult{Status: BatchItemInvalid, Error: err.Error()}
+			continue
+		}
+
+		chargeback, err := entity.NewChargeback(req.entityRequest())
+		if err != nil {
+			results[i] = BatchItemResult{Status: BatchItemInvalid, Error: err.Error()}
+			continue
+		}
+
+		// 3. Skip transactions that already have a chargeback
+		if seen[req.TransactionID] {
+			results[i] = BatchItemResult{
+				Status: BatchItemDuplicate,
+				Error:  fmt.Sprintf("transaction %s appears more than once in the batch", req.TransactionID),
+			}
+			continue
+		}
+		seen[req.TransactionID] = true
+
+		existing, err := uc.chargebackRepo.FindByTransactionID(ctx, req.TransactionID)
+		if err != nil {
+			results[i] = BatchItemResult{Status: BatchItemFailed, Error: fmt.Sprintf("failed to check existing chargeback: %v", err)}
+			continue
+		}
+		if existing != nil {
+			results[i] = BatchItemResult{
+				Status: BatchItemDuplicate,
+				Error:  fmt.Sprintf("%v for transaction %s", ErrChargebackAlreadyExists, req.TransactionID),
+			}
+			continue
+		}
+
+		chargeback.ID = uc.ids.NewChargebackID().String()
+		positions[chargeback] = i
+		pending = append(pending, chargeback)
+	}
+
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	// 4. Save the remaining items together, reporting the ones the repository couldn't write
+	unprocessed, saveErr := uc.chargebackRepo.SaveBatch(ctx, pending)
+	failed := make(map[*entity.Chargeback]bool, len(unprocessed))
+	for _, chargeback := range unprocessed {
+		failed[chargeback] = true
+	}
+
+	for _, chargeback := range pending {
+		i := positions[chargeback]
+		if failed[chargeback] {
+			message := "failed to save chargeback"
+			if saveErr != nil {
+				message = fmt.Sprintf("failed to save chargeback: %v", saveErr)
+			}
+			results[i] = BatchItemResult{Status: BatchItemFailed, Error: message}
+			continue
+		}
+		results[i] = BatchItemResult{Status: BatchItemCreated, Chargeback: toChargebackResponse(chargeback)}
+	}
+
+	return results, nil
+}
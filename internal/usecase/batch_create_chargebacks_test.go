@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+func batchRequest(transactionID, merchantID string) usecase.CreateChargebackRequest {
+	return usecase.CreateChargebackRequest{
+		TransactionID:   transactionID,
+		MerchantID:      merchantID,
+		Amount:          100.50,
+		Currency:        "USD",
+		CardNumber:      "4111111111111111",
+		Reason:          entity.ReasonFraud,
+		TransactionDate: time.Now().Add(-24 * time.Hour),
+	}
+}
+
+func TestBatchCreateChargebacksUseCase_Execute(t *testing.T) {
+	invalid := batchRequest("tx-invalid", "merchant-1")
+	invalid.Amount = -1
+
+	// Arrange
+	var saved []*entity.Chargeback
+	mockRepo := &MockChargebackRepository{
+		FindByTransactionIDFunc: func(ctx context.Context, transactionID string) (*entity.Chargeback, error) {
+			switch transactionID {
+			case "tx-existing":
+				return &entity.Chargeback{ID: "cb_existing", TransactionID: transactionID}, nil
+			case "tx-lookup-error":
+				return nil, errors.New("DynamoDB error")
+			}
+			return nil, nil
+		},
+		SaveBatchFunc: func(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+			saved = chargebacks
+			for i, chargeback := range chargebacks {
+				if chargeback.TransactionID == "tx-throttled" {
+					return chargebacks[i:], errors.New("1 items unprocessed")
+				}
+			}
+			return nil, nil
+		},
+	}
+	uc := usecase.NewBatchCreateChargebacksUseCase(mockRepo, &MockIDGenerator{}, 10)
+	ctx := merchantContext([]string{"merchant-1"}, auth.ScopeChargebacksWrite)
+
+	reqs := []usecase.CreateChargebackRequest{
+		batchRequest("tx-1", "merchant-1"),
+		invalid,
+		batchRequest("tx-1", "merchant-1"),
+		batchRequest("tx-existing", "merchant-1"),
+		batchRequest("tx-other", "merchant-2"),
+		batchRequest("tx-lookup-error", "merchant-1"),
+		batchRequest("tx-throttled", "merchant-1"),
+	}
+
+	// Act
+	results, err := uc.Execute(ctx, reqs)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []usecase.BatchItemStatus{
+		usecase.BatchItemCreated,
+		usecase.BatchItemInvalid,
+		usecase.BatchItemDuplicate,
+		usecase.BatchItemDuplicate,
+		usecase.BatchItemInvalid,
+		usecase.BatchItemFailed,
+		usecase.BatchItemFailed,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, status := range expected {
+		if results[i].Status != status {
+			t.Errorf("Item %d: expected status %s, got %s (%s)", i, status, results[i].Status, results[i].Error)
+		}
+		if status != usecase.BatchItemCreated && results[i].Error == "" {
+			t.Errorf("Item %d: expected an error message", i)
+		}
+	}
+
+	if results[0].Chargeback == nil || results[0].Chargeback.ID != "cb_1" {
+		t.Errorf("Expected created chargeback with a generated ID in result, got %+v", results[0].Chargeback)
+	}
+	if len(saved) != 2 || saved[1].ID != "cb_2" {
+		t.Errorf("Expected 2 chargebacks with generated IDs to be saved together, got %+v", saved)
+	}
+}
+
+func TestBatchCreateChargebacksUseCase_Execute_RejectsBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		items       int
+		expectedErr error
+	}{
+		{
+			name:        "too many items",
+			ctx:         context.Background(),
+			items:       3,
+			expectedErr: usecase.ErrBatchTooLarge,
+		},
+		{
+			name:        "missing write scope",
+			ctx:         merchantContext([]string{"merchant-1"}, auth.ScopeChargebacksRead),
+			items:       1,
+			expectedErr: auth.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := &MockChargebackRepository{
+				SaveBatchFunc: func(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+					t.Error("Expected nothing to be saved")
+					return nil, nil
+				},
+			}
+			uc := usecase.NewBatchCreateChargebacksUseCase(mockRepo, &MockIDGenerator{}, 2)
+
+			reqs := make([]usecase.CreateChargebackRequest, tt.items)
+			for i := range reqs {
+				reqs[i] = batchRequest("tx-1", "merchant-1")
+			}
+
+			// Act
+			results, err := uc.Execute(tt.ctx, reqs)
+
+			// Assert
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if results != nil {
+				t.Errorf("Expected no results, got %d", len(results))
+			}
+		})
+	}
+}
@@ -2,13 +2,19 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
 )
 
+// ErrChargebackAlreadyExists is returned when the transaction already has a chargeback
+var ErrChargebackAlreadyExists = errors.New("chargeback already exists")
+
 // CreateChargebackRequest represents the input for creating a chargeback
 type CreateChargebackRequest struct {
 	TransactionID   string                  `json:"transaction_id"`
@@ -21,6 +27,26 @@ type CreateChargebackRequest struct {
 	TransactionDate time.Time               `json:"transaction_date"`
 }
 
+// Validate applies the entity's validation rules without creating the chargeback
+func (req CreateChargebackRequest) Validate() error {
+	entityReq := req.entityRequest()
+	return entityReq.Validate()
+}
+
+// entityRequest converts the request into the entity's creation request
+func (req CreateChargebackRequest) entityRequest() entity.CreateChargebackRequest {
+	return entity.CreateChargebackRequest{
+		TransactionID:   req.TransactionID,
+		MerchantID:      req.MerchantID,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		CardNumber:      req.CardNumber,
+		Reason:          req.Reason,
+		Description:     req.Description,
+		TransactionDate: req.TransactionDate,
+	}
+}
+
 // CreateChargebackResponse represents the output of creating a chargeback
 type CreateChargebackResponse struct {
 	ID              string                  `json:"id"`
@@ -41,63 +67,47 @@ type CreateChargebackResponse struct {
 // CreateChargebackUseCase handles the creation of chargebacks
 type CreateChargebackUseCase struct {
 	chargebackRepo repository.ChargebackRepository
+	ids            service.IDGenerator
 }
 
 // NewCreateChargebackUseCase creates a new instance of CreateChargebackUseCase
-func NewCreateChargebackUseCase(chargebackRepo repository.ChargebackRepository) *CreateChargebackUseCase {
+// New chargebacks get their ID from ids.
+func NewCreateChargebackUseCase(chargebackRepo repository.ChargebackRepository, ids service.IDGenerator) *CreateChargebackUseCase {
 	return &CreateChargebackUseCase{
 		chargebackRepo: chargebackRepo,
+		ids:            ids,
 	}
 }
 
 // Execute creates a new chargeback following business rules
 func (uc *CreateChargebackUseCase) Execute(ctx context.Context, req CreateChargebackRequest) (*CreateChargebackResponse, error) {
-	// 1. Check if chargeback already exists for this transaction
+	// 1. Ensure the caller may create chargebacks for this merchant
+	if err := auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksWrite, req.MerchantID); err != nil {
+		return nil, err
+	}
+
+	// 2. Check if chargeback already exists for this transaction
 	existingChargeback, err := uc.chargebackRepo.FindByTransactionID(ctx, req.TransactionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing chargeback: %w", err)
 	}
 
 	if existingChargeback != nil {
-		return nil, fmt.Errorf("chargeback already exists for transaction %s", req.TransactionID)
-	}
-
-	// 2. Create chargeback entity from request
-	chargebackReq := entity.CreateChargebackRequest{
-		TransactionID:   req.TransactionID,
-		MerchantID:      req.MerchantID,
-		Amount:          req.Amount,
-		Currency:        req.Currency,
-		CardNumber:      req.CardNumber,
-		Reason:          req.Reason,
-		Description:     req.Description,
-		TransactionDate: req.TransactionDate,
+		return nil, fmt.Errorf("%w for transaction %s", ErrChargebackAlreadyExists, req.TransactionID)
 	}
 
-	chargeback, err := entity.NewChargeback(chargebackReq)
+	// 3. Create chargeback entity from request
+	chargeback, err := entity.NewChargeback(req.entityRequest())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chargeback entity: %w", err)
 	}
+	chargeback.ID = uc.ids.NewChargebackID().String()
 
-	// 3. Save chargeback to repository
+	// 4. Save chargeback to repository
 	if err := uc.chargebackRepo.Save(ctx, chargeback); err != nil {
 		return nil, fmt.Errorf("failed to save chargeback: %w", err)
 	}
 
-	// 4. Return response
-	return &CreateChargebackResponse{
-		ID:              chargeback.ID,
-		TransactionID:   chargeback.TransactionID,
-		MerchantID:      chargeback.MerchantID,
-		Amount:          chargeback.Amount,
-		Currency:        chargeback.Currency,
-		CardNumber:      chargeback.CardNumber,
-		Reason:          chargeback.Reason,
-		Status:          chargeback.Status,
-		Description:     chargeback.Description,
-		TransactionDate: chargeback.TransactionDate,
-		ChargebackDate:  chargeback.ChargebackDate,
-		CreatedAt:       chargeback.CreatedAt,
-		UpdatedAt:       chargeback.UpdatedAt,
-	}, nil
+	// 5. Return response
+	return toChargebackResponse(chargeback), nil
 }
@@ -3,10 +3,13 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
 	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
 	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
 )
 
@@ -18,8 +21,12 @@ type MockChargebackRepository struct {
 	FindByMerchantIDFunc    func(ctx context.Context, merchantID string) ([]*entity.Chargeback, error)
 	UpdateFunc              func(ctx context.Context, chargeback *entity.Chargeback) error
 	DeleteFunc              func(ctx context.Context, id string) error
+	RestoreFunc             func(ctx context.Context, id string) error
+	PurgeFunc               func(ctx context.Context, chargebacks []*entity.Chargeback) error
 	FindByStatusFunc        func(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error)
 	ListFunc                func(ctx context.Context, offset, limit int) ([]*entity.Chargeback, error)
+	SaveBatchFunc           func(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error)
+	ListPageFunc            func(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error)
 }
 
 func (m *MockChargebackRepository) Save(ctx context.Context, chargeback *entity.Chargeback) error {
@@ -29,6 +36,13 @@ func (m *MockChargebackRepository) Save(ctx context.Context, chargeback *entity.
 	return nil
 }
 
+func (m *MockChargebackRepository) SaveBatch(ctx context.Context, chargebacks []*entity.Chargeback) ([]*entity.Chargeback, error) {
+	if m.SaveBatchFunc != nil {
+		return m.SaveBatchFunc(ctx, chargebacks)
+	}
+	return nil, nil
+}
+
 func (m *MockChargebackRepository) FindByID(ctx context.Context, id string) (*entity.Chargeback, error) {
 	if m.FindByIDFunc != nil {
 		return m.FindByIDFunc(ctx, id)
@@ -64,6 +78,20 @@ func (m *MockChargebackRepository) Delete(ctx context.Context, id string) error
 	return nil
 }
 
+func (m *MockChargebackRepository) Restore(ctx context.Context, id string) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockChargebackRepository) Purge(ctx context.Context, chargebacks []*entity.Chargeback) error {
+	if m.PurgeFunc != nil {
+		return m.PurgeFunc(ctx, chargebacks)
+	}
+	return nil
+}
+
 func (m *MockChargebackRepository) FindByStatus(ctx context.Context, status entity.ChargebackStatus) ([]*entity.Chargeback, error) {
 	if m.FindByStatusFunc != nil {
 		return m.FindByStatusFunc(ctx, status)
@@ -78,6 +106,28 @@ func (m *MockChargebackRepository) List(ctx context.Context, offset, limit int)
 	return nil, nil
 }
 
+func (m *MockChargebackRepository) ListPage(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+	if m.ListPageFunc != nil {
+		return m.ListPageFunc(ctx, filter, cursor, limit)
+	}
+	return &repository.ChargebackPage{}, nil
+}
+
+// MockIDGenerator is a mock implementation of IDGenerator
+// Without NewChargebackIDFunc, it returns cb_1, cb_2 and so on.
+type MockIDGenerator struct {
+	NewChargebackIDFunc func() entity.ChargebackID
+	calls               int
+}
+
+func (m *MockIDGenerator) NewChargebackID() entity.ChargebackID {
+	m.calls++
+	if m.NewChargebackIDFunc != nil {
+		return m.NewChargebackIDFunc()
+	}
+	return entity.ChargebackID(fmt.Sprintf("cb_%d", m.calls))
+}
+
 func TestCreateChargebackUseCase_Execute_Success(t *testing.T) {
 	// Arrange
 	mockRepo := &MockChargebackRepository{
@@ -85,13 +135,17 @@ func TestCreateChargebackUseCase_Execute_Success(t *testing.T) {
 			return nil, nil // No existing chargeback found
 		},
 		SaveFunc: func(ctx context.Context, chargeback *entity.Chargeback) error {
-			// Simulate successful save
-			chargeback.ID = "cb_12345"
+			if chargeback.ID != "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+				t.Errorf("Expected the generated ID to be saved, got %q", chargeback.ID)
+			}
 			return nil
 		},
 	}
+	ids := &MockIDGenerator{
+		NewChargebackIDFunc: func() entity.ChargebackID { return "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV" },
+	}
 
-	useCase := usecase.NewCreateChargebackUseCase(mockRepo)
+	useCase := usecase.NewCreateChargebackUseCase(mockRepo, ids)
 	ctx := context.Background()
 
 	request := usecase.CreateChargebackRequest{
@@ -117,8 +171,8 @@ func TestCreateChargebackUseCase_Execute_Success(t *testing.T) {
 		t.Fatal("Expected response, got nil")
 	}
 
-	if response.ID == "" {
-		t.Error("Expected chargeback ID to be set")
+	if response.ID != "cb_01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("Expected the generated chargeback ID, got %q", response.ID)
 	}
 
 	if response.TransactionID != request.TransactionID {
@@ -148,7 +202,7 @@ func TestCreateChargebackUseCase_Execute_DuplicateTransaction(t *testing.T) {
 		},
 	}
 
-	useCase := usecase.NewCreateChargebackUseCase(mockRepo)
+	useCase := usecase.NewCreateChargebackUseCase(mockRepo, &MockIDGenerator{})
 	ctx := context.Background()
 
 	request := usecase.CreateChargebackRequest{
@@ -177,12 +231,16 @@ func TestCreateChargebackUseCase_Execute_DuplicateTransaction(t *testing.T) {
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', got '%s'", expectedError, err.Error())
 	}
+
+	if !errors.Is(err, usecase.ErrChargebackAlreadyExists) {
+		t.Errorf("Expected ErrChargebackAlreadyExists, got %v", err)
+	}
 }
 
 func TestCreateChargebackUseCase_Execute_InvalidRequest(t *testing.T) {
 	// Arrange
 	mockRepo := &MockChargebackRepository{}
-	useCase := usecase.NewCreateChargebackUseCase(mockRepo)
+	useCase := usecase.NewCreateChargebackUseCase(mockRepo, &MockIDGenerator{})
 	ctx := context.Background()
 
 	// Test cases for invalid requests
@@ -250,7 +308,7 @@ func TestCreateChargebackUseCase_Execute_RepositoryError(t *testing.T) {
 		},
 	}
 
-	useCase := usecase.NewCreateChargebackUseCase(mockRepo)
+	useCase := usecase.NewCreateChargebackUseCase(mockRepo, &MockIDGenerator{})
 	ctx := context.Background()
 
 	request := usecase.CreateChargebackRequest{
@@ -287,7 +345,7 @@ func TestCreateChargebackUseCase_Execute_SaveError(t *testing.T) {
 		},
 	}
 
-	useCase := usecase.NewCreateChargebackUseCase(mockRepo)
+	useCase := usecase.NewCreateChargebackUseCase(mockRepo, &MockIDGenerator{})
 	ctx := context.Background()
 
 	request := usecase.CreateChargebackRequest{
@@ -312,3 +370,54 @@ func TestCreateChargebackUseCase_Execute_SaveError(t *testing.T) {
 		t.Error("Expected nil response when save error occurs")
 	}
 }
+
+func TestCreateChargebackUseCase_Execute_MerchantScoping(t *testing.T) {
+	request := usecase.CreateChargebackRequest{
+		TransactionID:   "tx-12345",
+		MerchantID:      "merchant-789",
+		Amount:          150.75,
+		Currency:        "USD",
+		CardNumber:      "4111111111111111",
+		Reason:          entity.ReasonFraud,
+		TransactionDate: time.Now().AddDate(0, 0, -5),
+	}
+
+	t.Run("allows key bound to the merchant", func(t *testing.T) {
+		useCase := usecase.NewCreateChargebackUseCase(&MockChargebackRepository{}, &MockIDGenerator{})
+		ctx := merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksWrite)
+
+		if _, err := useCase.Execute(ctx, request); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects key bound to another merchant", func(t *testing.T) {
+		saved := false
+		mockRepo := &MockChargebackRepository{
+			SaveFunc: func(ctx context.Context, chargeback *entity.Chargeback) error {
+				saved = true
+				return nil
+			},
+		}
+		useCase := usecase.NewCreateChargebackUseCase(mockRepo, &MockIDGenerator{})
+		ctx := merchantContext([]string{"merchant-000"}, auth.ScopeChargebacksWrite)
+
+		_, err := useCase.Execute(ctx, request)
+
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden, got %v", err)
+		}
+		if saved {
+			t.Error("Expected chargeback not to be saved")
+		}
+	})
+
+	t.Run("rejects key without write scope", func(t *testing.T) {
+		useCase := usecase.NewCreateChargebackUseCase(&MockChargebackRepository{}, &MockIDGenerator{})
+		ctx := merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead)
+
+		if _, err := useCase.Execute(ctx, request); !errors.Is(err, auth.ErrForbidden) {
+			t.Errorf("Expected ErrForbidden, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// ExportChargebacksUseCase streams every chargeback matching a filter
+// Chargebacks are read a page at a time, so an export of any size holds a single page in memory
+type ExportChargebacksUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+	pageSize       int
+}
+
+// NewExportChargebacksUseCase creates a new instance of ExportChargebacksUseCase
+func NewExportChargebacksUseCase(chargebackRepo repository.ChargebackRepository, pageSize int) *ExportChargebacksUseCase {
+	return &ExportChargebacksUseCase{
+		chargebackRepo: chargebackRepo,
+		pageSize:       pageSize,
+	}
+}
+
+// Execute passes each chargeback matching the filter to emit, stopping at the first error
+// Callers limited to some merchants only export those merchants' chargebacks. Access is
+// checked before anything is emitted, so an error before the first call to emit means
+// nothing was exported.
+func (uc *ExportChargebacksUseCase) Execute(ctx context.Context, filter repository.ChargebackFilter, emit func(*CreateChargebackResponse) error) error {
+	merchantIDs, err := readableMerchants(ctx, filter.MerchantIDs)
+	if err != nil {
+		return err
+	}
+	filter.MerchantIDs = merchantIDs
+
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := uc.chargebackRepo.ListPage(ctx, filter, cursor, uc.pageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list chargebacks: %w", err)
+		}
+
+		for _, chargeback := range page.Chargebacks {
+			response := toChargebackResponse(chargeback)
+			// Card numbers are masked when chargebacks are created; mask again in case
+			// a chargeback was stored some other way
+			response.CardNumber = entity.MaskCardNumber(chargeback.CardNumber)
+			if err := emit(response); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// readableMerchants checks the caller may read the chargebacks of the requested merchants
+// With no merchants requested, callers limited to some merchants get theirs; nil means
+// every merchant.
+func readableMerchants(ctx context.Context, merchantIDs []string) ([]string, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksRead); err != nil {
+		return nil, err
+	}
+	for _, merchantID := range merchantIDs {
+		if err := auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksRead, merchantID); err != nil {
+			return nil, err
+		}
+	}
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || len(merchantIDs) > 0 || principal.AllMerchants || principal.HasScope(auth.ScopeAdmin) {
+		return merchantIDs, nil
+	}
+	if len(principal.MerchantIDs) == 0 {
+		return nil, fmt.Errorf("%w: no merchants to read", auth.ErrForbidden)
+	}
+	return principal.MerchantIDs, nil
+}
@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+func TestExportChargebacksUseCase_Execute(t *testing.T) {
+	pages := map[string]*repository.ChargebackPage{
+		"": {
+			Chargebacks: []*entity.Chargeback{
+				{ID: "cb_1", MerchantID: "merchant-789", CardNumber: "4111111111111111"},
+				{ID: "cb_2", MerchantID: "merchant-789", CardNumber: "************1111"},
+			},
+			NextCursor: "page-2",
+		},
+		"page-2": {},
+		"page-3": {
+			Chargebacks: []*entity.Chargeback{{ID: "cb_3", MerchantID: "merchant-789", CardNumber: "4111111111111111"}},
+		},
+	}
+	pages["page-2"].NextCursor = "page-3"
+
+	tests := []struct {
+		name              string
+		ctx               context.Context
+		filter            repository.ChargebackFilter
+		expectedMerchants string
+		expectedErr       error
+	}{
+		{
+			name: "exports every merchant without authentication",
+			ctx:  context.Background(),
+		},
+		{
+			name:              "limits export to own merchants",
+			ctx:               merchantContext([]string{"merchant-789", "merchant-790"}, auth.ScopeChargebacksRead),
+			expectedMerchants: "merchant-789,merchant-790",
+		},
+		{
+			name:              "exports requested merchant",
+			ctx:               merchantContext([]string{"merchant-789", "merchant-790"}, auth.ScopeChargebacksRead),
+			filter:            repository.ChargebackFilter{MerchantIDs: []string{"merchant-789"}},
+			expectedMerchants: "merchant-789",
+		},
+		{
+			name:        "rejects other merchant",
+			ctx:         merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead),
+			filter:      repository.ChargebackFilter{MerchantIDs: []string{"merchant-000"}},
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:        "rejects key without merchants",
+			ctx:         merchantContext(nil, auth.ScopeChargebacksRead),
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:        "rejects key without read scope",
+			ctx:         merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksWrite),
+			expectedErr: auth.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var merchants []string
+			mockRepo := &MockChargebackRepository{
+				ListPageFunc: func(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+					merchants = filter.MerchantIDs
+					return pages[cursor], nil
+				},
+			}
+			useCase := usecase.NewExportChargebacksUseCase(mockRepo, 2)
+			var exported []string
+
+			// Act
+			err := useCase.Execute(tt.ctx, tt.filter, func(response *usecase.CreateChargebackResponse) error {
+				exported = append(exported, response.ID+":"+response.CardNumber)
+				return nil
+			})
+
+			// Assert
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				if len(exported) != 0 {
+					t.Errorf("Expected nothing to be exported, got %v", exported)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if strings.Join(merchants, ",") != tt.expectedMerchants {
+				t.Errorf("Expected merchants %q, got %v", tt.expectedMerchants, merchants)
+			}
+			expected := "cb_1:************1111,cb_2:************1111,cb_3:************1111"
+			if strings.Join(exported, ",") != expected {
+				t.Errorf("Expected %s, got %v", expected, exported)
+			}
+		})
+	}
+}
+
+func TestExportChargebacksUseCase_Execute_StopsOnError(t *testing.T) {
+	// Arrange
+	calls := 0
+	mockRepo := &MockChargebackRepository{
+		ListPageFunc: func(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+			calls++
+			return &repository.ChargebackPage{
+				Chargebacks: []*entity.Chargeback{{ID: "cb_1"}, {ID: "cb_2"}},
+				NextCursor:  "next",
+			}, nil
+		},
+	}
+	writeErr := errors.New("client went away")
+
+	// Act
+	err := usecase.NewExportChargebacksUseCase(mockRepo, 2).Execute(context.Background(), repository.ChargebackFilter{},
+		func(response *usecase.CreateChargebackResponse) error { return writeErr })
+
+	// Assert
+	if !errors.Is(err, writeErr) {
+		t.Errorf("Expected emit error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected export to stop after the first page, got %d pages", calls)
+	}
+}
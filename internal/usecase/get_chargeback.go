@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// ErrChargebackNotFound is returned when a chargeback does not exist
+var ErrChargebackNotFound = errors.New("chargeback not found")
+
+// GetChargebackUseCase handles retrieving a single chargeback
+type GetChargebackUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+}
+
+// NewGetChargebackUseCase creates a new instance of GetChargebackUseCase
+func NewGetChargebackUseCase(chargebackRepo repository.ChargebackRepository) *GetChargebackUseCase {
+	return &GetChargebackUseCase{
+		chargebackRepo: chargebackRepo,
+	}
+}
+
+// Execute retrieves a chargeback by ID, hiding chargebacks of other merchants from the caller
+func (uc *GetChargebackUseCase) Execute(ctx context.Context, id string) (*CreateChargebackResponse, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksRead); err != nil {
+		return nil, err
+	}
+
+	chargeback, err := uc.chargebackRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chargeback: %w", err)
+	}
+
+	// Report chargebacks of other merchants as missing so their IDs can't be probed
+	if chargeback == nil || auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksRead, chargeback.MerchantID) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChargebackNotFound, id)
+	}
+
+	return toChargebackResponse(chargeback), nil
+}
+
+// toChargebackResponse converts a chargeback entity into its response representation
+func toChargebackResponse(chargeback *entity.Chargeback) *CreateChargebackResponse {
+	return &CreateChargebackResponse{
+		ID:              chargeback.ID,
+		TransactionID:   chargeback.TransactionID,
+		MerchantID:      chargeback.MerchantID,
+		Amount:          chargeback.Amount,
+		Currency:        chargeback.Currency,
+		CardNumber:      chargeback.CardNumber,
+		Reason:          chargeback.Reason,
+		Status:          chargeback.Status,
+		Description:     chargeback.Description,
+		TransactionDate: chargeback.TransactionDate,
+		ChargebackDate:  chargeback.ChargebackDate,
+		CreatedAt:       chargeback.CreatedAt,
+		UpdatedAt:       chargeback.UpdatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+func TestGetChargebackUseCase_Execute(t *testing.T) {
+	stored := &entity.Chargeback{
+		ID:            "cb_12345",
+		TransactionID: "tx-12345",
+		MerchantID:    "merchant-789",
+		Amount:        150.75,
+		Currency:      "USD",
+		Status:        entity.StatusPending,
+	}
+
+	mockRepo := &MockChargebackRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Chargeback, error) {
+			if id == stored.ID {
+				return stored, nil
+			}
+			return nil, nil
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		id          string
+		expectedErr error
+	}{
+		{
+			name: "returns chargeback without authentication",
+			ctx:  context.Background(),
+			id:   "cb_12345",
+		},
+		{
+			name: "returns chargeback of own merchant",
+			ctx:  merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead),
+			id:   "cb_12345",
+		},
+		{
+			name:        "hides chargeback of other merchant",
+			ctx:         merchantContext([]string{"merchant-000"}, auth.ScopeChargebacksRead),
+			id:          "cb_12345",
+			expectedErr: usecase.ErrChargebackNotFound,
+		},
+		{
+			name:        "rejects key without read scope",
+			ctx:         merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksWrite),
+			id:          "cb_12345",
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:        "reports missing chargeback",
+			ctx:         context.Background(),
+			id:          "cb_missing",
+			expectedErr: usecase.ErrChargebackNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := usecase.NewGetChargebackUseCase(mockRepo)
+
+			response, err := useCase.Execute(tt.ctx, tt.id)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if response.ID != stored.ID || response.MerchantID != stored.MerchantID {
+				t.Errorf("Expected chargeback %s, got %+v", stored.ID, response)
+			}
+		})
+	}
+}
+
+func TestGetChargebackUseCase_Execute_RepositoryError(t *testing.T) {
+	mockRepo := &MockChargebackRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Chargeback, error) {
+			return nil, errors.New("database connection failed")
+		},
+	}
+
+	_, err := usecase.NewGetChargebackUseCase(mockRepo).Execute(context.Background(), "cb_12345")
+
+	if err == nil || errors.Is(err, usecase.ErrChargebackNotFound) {
+		t.Errorf("Expected repository error, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// ErrAPIKeyNotFound is returned when an API key does not exist
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// IssueAPIKeyRequest represents the input for issuing an API key
+type IssueAPIKeyRequest struct {
+	Name        string       `json:"name"`
+	MerchantIDs []string     `json:"merchant_ids"`
+	Scopes      []auth.Scope `json:"scopes"`
+}
+
+// APIKeyResponse represents an API key returned to administrators
+// Key holds the raw key and is only populated on issue and rotation
+type APIKeyResponse struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Key         string              `json:"key,omitempty"`
+	MerchantIDs []string            `json:"merchant_ids"`
+	Scopes      []auth.Scope        `json:"scopes"`
+	Status      entity.APIKeyStatus `json:"status"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	RotatedAt   *time.Time          `json:"rotated_at,omitempty"`
+	RevokedAt   *time.Time          `json:"revoked_at,omitempty"`
+}
+
+// IssueAPIKeyUseCase handles issuing new API keys
+type IssueAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewIssueAPIKeyUseCase creates a new instance of IssueAPIKeyUseCase
+func NewIssueAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository) *IssueAPIKeyUseCase {
+	return &IssueAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute issues a new API key bound to the requested merchants and scopes
+func (uc *IssueAPIKeyUseCase) Execute(ctx context.Context, req IssueAPIKeyRequest) (*APIKeyResponse, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	key, rawKey, err := entity.NewAPIKey(entity.CreateAPIKeyRequest{
+		Name:        req.Name,
+		MerchantIDs: req.MerchantIDs,
+		Scopes:      req.Scopes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API key entity: %w", err)
+	}
+
+	if err := uc.apiKeyRepo.Save(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+
+	response := toAPIKeyResponse(key)
+	response.Key = rawKey
+	return response, nil
+}
+
+// RotateAPIKeyUseCase handles replacing the secret of an API key
+type RotateAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewRotateAPIKeyUseCase creates a new instance of RotateAPIKeyUseCase
+func NewRotateAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository) *RotateAPIKeyUseCase {
+	return &RotateAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute rotates the API key secret; the previous raw key stops working immediately
+func (uc *RotateAPIKeyUseCase) Execute(ctx context.Context, id string) (*APIKeyResponse, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	key, err := findAPIKey(ctx, uc.apiKeyRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := key.Rotate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate API key: %w", err)
+	}
+
+	if err := uc.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	response := toAPIKeyResponse(key)
+	response.Key = rawKey
+	return response, nil
+}
+
+// RevokeAPIKeyUseCase handles revoking API keys
+type RevokeAPIKeyUseCase struct {
+	apiKeyRepo repository.APIKeyRepository
+}
+
+// NewRevokeAPIKeyUseCase creates a new instance of RevokeAPIKeyUseCase
+func NewRevokeAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+}
+
+// Execute permanently revokes the API key
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, id string) (*APIKeyResponse, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	key, err := findAPIKey(ctx, uc.apiKeyRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := key.Revoke(); err != nil {
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	if err := uc.apiKeyRepo.Update(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	return toAPIKeyResponse(key), nil
+}
+
+// AuthenticateAPIKeyUseCase resolves raw API keys into principals
+type AuthenticateAPIKeyUseCase struct {
+	apiKeyRepo     repository.APIKeyRepository
+	adminKeyHash   [sha256.Size]byte
+	adminKeyActive bool
+}
+
+// NewAuthenticateAPIKeyUseCase creates a new instance of AuthenticateAPIKeyUseCase
+// A non-empty adminKey is accepted as a bootstrap credential with the admin scope,
+// so the first keys can be issued before any exist in the store
+func NewAuthenticateAPIKeyUseCase(apiKeyRepo repository.APIKeyRepository, adminKey string) *AuthenticateAPIKeyUseCase {
+	uc := &AuthenticateAPIKeyUseCase{
+		apiKeyRepo: apiKeyRepo,
+	}
+
+	if adminKey != "" {
+		uc.adminKeyHash = sha256.Sum256([]byte(adminKey))
+		uc.adminKeyActive = true
+	}
+
+	return uc
+}
+
+// Execute validates the raw API key and returns the principal it grants
+func (uc *AuthenticateAPIKeyUseCase) Execute(ctx context.Context, rawKey string) (*auth.Principal, error) {
+	if uc.adminKeyActive {
+		hash := sha256.Sum256([]byte(rawKey))
+		if subtle.ConstantTimeCompare(hash[:], uc.adminKeyHash[:]) == 1 {
+			return &auth.Principal{
+				Subject: "bootstrap-admin",
+				Scopes:  []auth.Scope{auth.ScopeAdmin},
+			}, nil
+		}
+	}
+
+	id, secret, err := entity.ParseAPIKey(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+	}
+
+	key, err := uc.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	if key == nil || !key.Verify(secret) {
+		return nil, fmt.Errorf("%w: invalid API key", auth.ErrUnauthenticated)
+	}
+
+	if !key.IsActive() {
+		return nil, fmt.Errorf("%w: API key has been revoked", auth.ErrUnauthenticated)
+	}
+
+	return key.Principal(), nil
+}
+
+// findAPIKey loads an API key, translating a missing key into ErrAPIKeyNotFound
+func findAPIKey(ctx context.Context, apiKeyRepo repository.APIKeyRepository, id string) (*entity.APIKey, error) {
+	key, err := apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, id)
+	}
+
+	return key, nil
+}
+
+// toAPIKeyResponse converts an API key entity into its response representation
+func toAPIKeyResponse(key *entity.APIKey) *APIKeyResponse {
+	response := &APIKeyResponse{
+		ID:          key.ID,
+		Name:        key.Name,
+		MerchantIDs: key.MerchantIDs,
+		Scopes:      key.Scopes,
+		Status:      key.Status,
+		CreatedAt:   key.CreatedAt,
+		UpdatedAt:   key.UpdatedAt,
+	}
+
+	if !key.RotatedAt.IsZero() {
+		rotatedAt := key.RotatedAt
+		response.RotatedAt = &rotatedAt
+	}
+
+	if !key.RevokedAt.IsZero() {
+		revokedAt := key.RevokedAt
+		response.RevokedAt = &revokedAt
+	}
+
+	return response
+}
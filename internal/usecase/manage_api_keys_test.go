@@ -0,0 +1,210 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockAPIKeyRepository is a map-backed implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	keys    map[string]*entity.APIKey
+	FindErr error
+}
+
+func NewMockAPIKeyRepository() *MockAPIKeyRepository {
+	return &MockAPIKeyRepository{keys: make(map[string]*entity.APIKey)}
+}
+
+func (m *MockAPIKeyRepository) Save(ctx context.Context, key *entity.APIKey) error {
+	clone := *key
+	m.keys[key.ID] = &clone
+	return nil
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id string) (*entity.APIKey, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *key
+	return &clone, nil
+}
+
+func (m *MockAPIKeyRepository) Update(ctx context.Context, key *entity.APIKey) error {
+	clone := *key
+	m.keys[key.ID] = &clone
+	return nil
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*entity.APIKey, error) {
+	keys := make([]*entity.APIKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func adminContext() context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{
+		Subject: "admin",
+		Scopes:  []auth.Scope{auth.ScopeAdmin},
+	})
+}
+
+func merchantContext(merchantIDs []string, scopes ...auth.Scope) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{
+		Subject:     "merchant-key",
+		MerchantIDs: merchantIDs,
+		Scopes:      scopes,
+	})
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	// Arrange
+	repo := NewMockAPIKeyRepository()
+	issueUC := usecase.NewIssueAPIKeyUseCase(repo)
+	rotateUC := usecase.NewRotateAPIKeyUseCase(repo)
+	revokeUC := usecase.NewRevokeAPIKeyUseCase(repo)
+	authenticateUC := usecase.NewAuthenticateAPIKeyUseCase(repo, "")
+	ctx := adminContext()
+
+	// Act - issue
+	issued, err := issueUC.Execute(ctx, usecase.IssueAPIKeyRequest{
+		Name:        "acme integration",
+		MerchantIDs: []string{"merchant-789"},
+		Scopes:      []auth.Scope{auth.ScopeChargebacksWrite},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error issuing key, got %v", err)
+	}
+	if issued.Key == "" {
+		t.Fatal("Expected raw key in issue response")
+	}
+
+	// Assert - the raw key authenticates to a merchant-scoped principal
+	principal, err := authenticateUC.Execute(context.Background(), issued.Key)
+	if err != nil {
+		t.Fatalf("Expected key to authenticate, got %v", err)
+	}
+	if !principal.CanAccessMerchant("merchant-789") || principal.CanAccessMerchant("merchant-000") {
+		t.Errorf("Expected principal scoped to merchant-789, got %v", principal.MerchantIDs)
+	}
+
+	// Act - rotate
+	rotated, err := rotateUC.Execute(ctx, issued.ID)
+	if err != nil {
+		t.Fatalf("Expected no error rotating key, got %v", err)
+	}
+	if rotated.Key == "" || rotated.Key == issued.Key || rotated.RotatedAt == nil {
+		t.Error("Expected a new raw key and rotation timestamp")
+	}
+
+	if _, err := authenticateUC.Execute(context.Background(), issued.Key); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Expected old key to be rejected after rotation, got %v", err)
+	}
+	if _, err := authenticateUC.Execute(context.Background(), rotated.Key); err != nil {
+		t.Errorf("Expected rotated key to authenticate, got %v", err)
+	}
+
+	// Act - revoke
+	revoked, err := revokeUC.Execute(ctx, issued.ID)
+	if err != nil {
+		t.Fatalf("Expected no error revoking key, got %v", err)
+	}
+	if revoked.Status != entity.APIKeyStatusRevoked || revoked.Key != "" {
+		t.Errorf("Expected revoked key without raw key, got %+v", revoked)
+	}
+
+	if _, err := authenticateUC.Execute(context.Background(), rotated.Key); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Expected revoked key to be rejected, got %v", err)
+	}
+}
+
+func TestIssueAPIKeyUseCase_Execute_Forbidden(t *testing.T) {
+	useCase := usecase.NewIssueAPIKeyUseCase(NewMockAPIKeyRepository())
+	ctx := merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksWrite)
+
+	_, err := useCase.Execute(ctx, usecase.IssueAPIKeyRequest{
+		Name:        "escalation",
+		MerchantIDs: []string{"merchant-789"},
+		Scopes:      []auth.Scope{auth.ScopeAdmin},
+	})
+
+	if !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestIssueAPIKeyUseCase_Execute_ValidationError(t *testing.T) {
+	useCase := usecase.NewIssueAPIKeyUseCase(NewMockAPIKeyRepository())
+
+	_, err := useCase.Execute(adminContext(), usecase.IssueAPIKeyRequest{
+		Scopes: []auth.Scope{auth.ScopeChargebacksRead},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "validation errors") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestRotateAPIKeyUseCase_Execute_NotFound(t *testing.T) {
+	useCase := usecase.NewRotateAPIKeyUseCase(NewMockAPIKeyRepository())
+
+	_, err := useCase.Execute(adminContext(), "missing")
+
+	if !errors.Is(err, usecase.ErrAPIKeyNotFound) {
+		t.Errorf("Expected ErrAPIKeyNotFound, got %v", err)
+	}
+}
+
+func TestAuthenticateAPIKeyUseCase_Execute(t *testing.T) {
+	t.Run("accepts bootstrap admin key", func(t *testing.T) {
+		useCase := usecase.NewAuthenticateAPIKeyUseCase(NewMockAPIKeyRepository(), "bootstrap-secret")
+
+		principal, err := useCase.Execute(context.Background(), "bootstrap-secret")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !principal.HasScope(auth.ScopeAdmin) {
+			t.Error("Expected bootstrap key to grant admin scope")
+		}
+	})
+
+	t.Run("rejects malformed key", func(t *testing.T) {
+		useCase := usecase.NewAuthenticateAPIKeyUseCase(NewMockAPIKeyRepository(), "")
+
+		_, err := useCase.Execute(context.Background(), "not-a-key")
+		if !errors.Is(err, auth.ErrUnauthenticated) {
+			t.Errorf("Expected ErrUnauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown key", func(t *testing.T) {
+		useCase := usecase.NewAuthenticateAPIKeyUseCase(NewMockAPIKeyRepository(), "")
+
+		_, err := useCase.Execute(context.Background(), "cbk_0123456789abcdef_secret")
+		if !errors.Is(err, auth.ErrUnauthenticated) {
+			t.Errorf("Expected ErrUnauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("propagates store errors", func(t *testing.T) {
+		repo := NewMockAPIKeyRepository()
+		repo.FindErr = errors.New("DynamoDB error")
+		useCase := usecase.NewAuthenticateAPIKeyUseCase(repo, "")
+
+		_, err := useCase.Execute(context.Background(), "cbk_0123456789abcdef_secret")
+		if err == nil || errors.Is(err, auth.ErrUnauthenticated) {
+			t.Errorf("Expected store error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+)
+
+// RecordTransactionVolumeRequest represents a merchant's sales for a month
+type RecordTransactionVolumeRequest struct {
+	TransactionCount  int     `json:"transaction_count"`
+	TransactionAmount float64 `json:"transaction_amount"`
+}
+
+// MerchantStatsResponse represents a merchant's statistics for a month
+type MerchantStatsResponse struct {
+	entity.MerchantMonthlyStats
+	CountRatio         float64  `json:"count_ratio"`
+	AmountRatio        float64  `json:"amount_ratio"`
+	ThresholdsExceeded []string `json:"thresholds_exceeded"`
+}
+
+// MerchantStatsHistoryResponse represents a merchant's statistics over a range of months
+// Months without chargebacks or transaction volume are omitted
+type MerchantStatsHistoryResponse struct {
+	MerchantID string                   `json:"merchant_id"`
+	From       string                   `json:"from"`
+	To         string                   `json:"to"`
+	Months     []*MerchantStatsResponse `json:"months"`
+}
+
+// merchantRatioMonitor raises alerts when merchants' statistics cross the thresholds
+type merchantRatioMonitor struct {
+	thresholds []entity.RatioThreshold
+	logger     service.Logger
+	sink       service.AlertSink
+	now        func() time.Time
+}
+
+// check raises an alert for each threshold the statistics reach that they did not reach before
+// Alerting only on crossings keeps every later chargeback of the month from alerting again.
+func (m *merchantRatioMonitor) check(ctx context.Context, before, after *entity.MerchantMonthlyStats) {
+	for _, threshold := range m.thresholds {
+		if !threshold.ExceededBy(after) || (before != nil && threshold.ExceededBy(before)) {
+			continue
+		}
+
+		alert := entity.MerchantRatioAlert{
+			MerchantID:       after.MerchantID,
+			Month:            after.Month,
+			Threshold:        threshold.Name,
+			ThresholdRatio:   threshold.Ratio,
+			Ratio:            after.CountRatio(),
+			ChargebackCount:  after.ChargebackCount,
+			TransactionCount: after.TransactionCount,
+			RaisedAt:         m.now(),
+		}
+		fields := map[string]interface{}{
+			"merchant_id":       alert.MerchantID,
+			"month":             alert.Month,
+			"threshold":         alert.Threshold,
+			"threshold_ratio":   alert.ThresholdRatio,
+			"ratio":             alert.Ratio,
+			"chargeback_count":  alert.ChargebackCount,
+			"transaction_count": alert.TransactionCount,
+		}
+		m.logger.Warn(ctx, "Merchant chargeback ratio threshold crossed", fields)
+
+		if m.sink == nil {
+			continue
+		}
+		if err := m.sink.Send(ctx, alert); err != nil {
+			fields["error"] = err.Error()
+			m.logger.Error(ctx, "Failed to send merchant ratio alert", fields)
+		}
+	}
+}
+
+// exceeded returns the names of the thresholds the statistics reach
+func (m *merchantRatioMonitor) exceeded(stats *entity.MerchantMonthlyStats) []string {
+	names := []string{}
+	for _, threshold := range m.thresholds {
+		if threshold.ExceededBy(stats) {
+			names = append(names, threshold.Name)
+		}
+	}
+	return names
+}
+
+// toMerchantStatsResponse converts merchant statistics to a response
+func (m *merchantRatioMonitor) toMerchantStatsResponse(stats *entity.MerchantMonthlyStats) *MerchantStatsResponse {
+	return &MerchantStatsResponse{
+		MerchantMonthlyStats: *stats,
+		CountRatio:           stats.CountRatio(),
+		AmountRatio:          stats.AmountRatio(),
+		ThresholdsExceeded:   m.exceeded(stats),
+	}
+}
+
+// newMerchantRatioMonitor creates a monitor for the thresholds; the sink may be nil
+func newMerchantRatioMonitor(thresholds []entity.RatioThreshold, logger service.Logger, sink service.AlertSink) *merchantRatioMonitor {
+	return &merchantRatioMonitor{
+		thresholds: thresholds,
+		logger:     logger,
+		sink:       sink,
+		now:        time.Now,
+	}
+}
+
+// MerchantStatsRecorder keeps merchants' monthly statistics up to date as chargebacks are
+// saved, change status or are deleted, and raises alerts when ratios cross the thresholds
+// It is registered as an observer of the chargeback repository.
+type MerchantStatsRecorder struct {
+	statsRepo repository.MerchantStatsRepository
+	monitor   *merchantRatioMonitor
+}
+
+// NewMerchantStatsRecorder creates a new instance of MerchantStatsRecorder
+// Alerts are logged and, when sink is not nil, sent to the sink
+func NewMerchantStatsRecorder(statsRepo repository.MerchantStatsRepository, thresholds []entity.RatioThreshold, logger service.Logger, sink service.AlertSink) *MerchantStatsRecorder {
+	return &MerchantStatsRecorder{
+		statsRepo: statsRepo,
+		monitor:   newMerchantRatioMonitor(thresholds, logger, sink),
+	}
+}
+
+// ChargebackSaved counts a new chargeback toward its merchant's month
+func (r *MerchantStatsRecorder) ChargebackSaved(ctx context.Context, chargeback *entity.Chargeback) error {
+	month, delta := chargeback.StatsContribution()
+	return r.add(ctx, chargeback.MerchantID, month, delta)
+}
+
+// ChargebackUpdated moves a changed chargeback's contribution to its merchant's month
+func (r *MerchantStatsRecorder) ChargebackUpdated(ctx context.Context, previous, current *entity.Chargeback) error {
+	previousMonth, previousDelta := previous.StatsContribution()
+	currentMonth, currentDelta := current.StatsContribution()
+
+	if previous.MerchantID == current.MerchantID && previousMonth == currentMonth {
+		return r.add(ctx, current.MerchantID, currentMonth, previousDelta.Negate().Add(currentDelta))
+	}
+
+	if err := r.add(ctx, previous.MerchantID, previousMonth, previousDelta.Negate()); err != nil {
+		return err
+	}
+	return r.add(ctx, current.MerchantID, currentMonth, currentDelta)
+}
+
+// ChargebackDeleted removes a deleted chargeback from its merchant's month
+func (r *MerchantStatsRecorder) ChargebackDeleted(ctx context.Context, chargeback *entity.Chargeback) error {
+	month, delta := chargeback.StatsContribution()
+	return r.add(ctx, chargeback.MerchantID, month, delta.Negate())
+}
+
+// add applies the delta to a merchant's month and checks the thresholds
+func (r *MerchantStatsRecorder) add(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) error {
+	if delta.IsZero() {
+		return nil
+	}
+
+	after, err := r.statsRepo.AddChargebacks(ctx, merchantID, month, delta)
+	if err != nil {
+		return fmt.Errorf("failed to record merchant statistics: %w", err)
+	}
+
+	before := *after
+	before.Apply(delta.Negate())
+	r.monitor.check(ctx, &before, after)
+	return nil
+}
+
+// RecordTransactionVolumeUseCase handles reporting a merchant's sales for a month
+type RecordTransactionVolumeUseCase struct {
+	statsRepo repository.MerchantStatsRepository
+	monitor   *merchantRatioMonitor
+}
+
+// NewRecordTransactionVolumeUseCase creates a new instance of RecordTransactionVolumeUseCase
+func NewRecordTransactionVolumeUseCase(statsRepo repository.MerchantStatsRepository, thresholds []entity.RatioThreshold, logger service.Logger, sink service.AlertSink) *RecordTransactionVolumeUseCase {
+	return &RecordTransactionVolumeUseCase{
+		statsRepo: statsRepo,
+		monitor:   newMerchantRatioMonitor(thresholds, logger, sink),
+	}
+}
+
+// Execute replaces the merchant's sales for the month and checks the thresholds
+func (uc *RecordTransactionVolumeUseCase) Execute(ctx context.Context, merchantID, month string, req RecordTransactionVolumeRequest) (*MerchantStatsResponse, error) {
+	if err := auth.RequireMerchantAccess(ctx, auth.ScopeMerchantsWrite, merchantID); err != nil {
+		return nil, err
+	}
+
+	var errors []string
+	month, err := entity.ParseStatsMonth(month)
+	if err != nil {
+		errors = append(errors, err.Error())
+	}
+	if req.TransactionCount < 0 {
+		errors = append(errors, "transaction_count must not be negative")
+	}
+	if req.TransactionAmount < 0 {
+		errors = append(errors, "transaction_amount must not be negative")
+	}
+	if len(errors) > 0 {
+		return nil, fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
+	}
+
+	before, err := uc.statsRepo.Find(ctx, merchantID, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merchant statistics: %w", err)
+	}
+
+	after, err := uc.statsRepo.SetTransactionVolume(ctx, merchantID, month, req.TransactionCount, req.TransactionAmount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record transaction volume: %w", err)
+	}
+
+	uc.monitor.check(ctx, before, after)
+	return uc.monitor.toMerchantStatsResponse(after), nil
+}
+
+// GetMerchantStatsUseCase handles retrieving a merchant's statistics over a range of months
+type GetMerchantStatsUseCase struct {
+	statsRepo repository.MerchantStatsRepository
+	monitor   *merchantRatioMonitor
+	now       func() time.Time
+}
+
+// NewGetMerchantStatsUseCase creates a new instance of GetMerchantStatsUseCase
+func NewGetMerchantStatsUseCase(statsRepo repository.MerchantStatsRepository, thresholds []entity.RatioThreshold) *GetMerchantStatsUseCase {
+	return &GetMerchantStatsUseCase{
+		statsRepo: statsRepo,
+		monitor:   newMerchantRatioMonitor(thresholds, nil, nil),
+		now:       time.Now,
+	}
+}
+
+// Execute retrieves the merchant's statistics from one month to another
+// The range defaults to the current month and the five before it
+func (uc *GetMerchantStatsUseCase) Execute(ctx context.Context, merchantID, fromMonth, toMonth string) (*MerchantStatsHistoryResponse, error) {
+	if err := auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksRead, merchantID); err != nil {
+		return nil, err
+	}
+
+	if toMonth == "" {
+		toMonth = entity.StatsMonth(uc.now())
+	}
+	to, err := entity.ParseStatsMonth(toMonth)
+	if err != nil {
+		return nil, fmt.Errorf("validation errors: %w", err)
+	}
+	if fromMonth == "" {
+		toStart, _ := time.Parse(entity.StatsMonthLayout, to)
+		fromMonth = entity.StatsMonth(toStart.AddDate(0, -5, 0))
+	}
+	from, err := entity.ParseStatsMonth(fromMonth)
+	if err != nil {
+		return nil, fmt.Errorf("validation errors: %w", err)
+	}
+	if from > to {
+		return nil, fmt.Errorf("validation errors: from must not be after to")
+	}
+
+	months, err := uc.statsRepo.ListByMerchant(ctx, merchantID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchant statistics: %w", err)
+	}
+
+	response := &MerchantStatsHistoryResponse{
+		MerchantID: merchantID,
+		From:       from,
+		To:         to,
+		Months:     make([]*MerchantStatsResponse, 0, len(months)),
+	}
+	for _, stats := range months {
+		response.Months = append(response.Months, uc.monitor.toMerchantStatsResponse(stats))
+	}
+	return response, nil
+}
@@ -0,0 +1,340 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/service"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockMerchantStatsRepository keeps merchant statistics in a map
+type MockMerchantStatsRepository struct {
+	stats map[string]*entity.MerchantMonthlyStats
+}
+
+func NewMockMerchantStatsRepository() *MockMerchantStatsRepository {
+	return &MockMerchantStatsRepository{stats: make(map[string]*entity.MerchantMonthlyStats)}
+}
+
+func (m *MockMerchantStatsRepository) month(merchantID, month string) *entity.MerchantMonthlyStats {
+	key := merchantID + "|" + month
+	if _, ok := m.stats[key]; !ok {
+		m.stats[key] = &entity.MerchantMonthlyStats{MerchantID: merchantID, Month: month}
+	}
+	return m.stats[key]
+}
+
+func (m *MockMerchantStatsRepository) AddChargebacks(ctx context.Context, merchantID, month string, delta entity.MerchantStatsDelta) (*entity.MerchantMonthlyStats, error) {
+	stats := m.month(merchantID, month)
+	stats.Apply(delta)
+	clone := *stats
+	return &clone, nil
+}
+
+func (m *MockMerchantStatsRepository) SetTransactionVolume(ctx context.Context, merchantID, month string, count int, amount float64) (*entity.MerchantMonthlyStats, error) {
+	stats := m.month(merchantID, month)
+	stats.TransactionCount = count
+	stats.TransactionAmount = amount
+	clone := *stats
+	return &clone, nil
+}
+
+func (m *MockMerchantStatsRepository) Find(ctx context.Context, merchantID, month string) (*entity.MerchantMonthlyStats, error) {
+	stats, ok := m.stats[merchantID+"|"+month]
+	if !ok {
+		return nil, nil
+	}
+	clone := *stats
+	return &clone, nil
+}
+
+func (m *MockMerchantStatsRepository) ListByMerchant(ctx context.Context, merchantID, fromMonth, toMonth string) ([]*entity.MerchantMonthlyStats, error) {
+	var months []*entity.MerchantMonthlyStats
+	for _, stats := range m.stats {
+		if stats.MerchantID == merchantID && stats.Month >= fromMonth && stats.Month <= toMonth {
+			clone := *stats
+			months = append(months, &clone)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Month < months[j].Month })
+	return months, nil
+}
+
+// MockLogger records the messages of warnings and errors
+type MockLogger struct {
+	warnings []string
+	errors   []string
+}
+
+func (l *MockLogger) Log(ctx context.Context, entry service.LogEntry) error { return nil }
+func (l *MockLogger) Debug(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *MockLogger) Info(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	return nil
+}
+func (l *MockLogger) Warn(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	l.warnings = append(l.warnings, message)
+	return nil
+}
+func (l *MockLogger) Error(ctx context.Context, message string, fields ...map[string]interface{}) error {
+	l.errors = append(l.errors, message)
+	return nil
+}
+func (l *MockLogger) WithContext(ctx context.Context) service.Logger { return l }
+
+// MockAlertSink records the alerts it is sent
+type MockAlertSink struct {
+	alerts []entity.MerchantRatioAlert
+	err    error
+}
+
+func (s *MockAlertSink) Send(ctx context.Context, alert entity.MerchantRatioAlert) error {
+	s.alerts = append(s.alerts, alert)
+	return s.err
+}
+
+var testRatioThresholds = []entity.RatioThreshold{
+	{Name: "early_warning", Ratio: 0.01, MinChargebacks: 2},
+	{Name: "excessive", Ratio: 0.03, MinChargebacks: 2},
+}
+
+func testStatsChargeback(id string, status entity.ChargebackStatus) *entity.Chargeback {
+	return &entity.Chargeback{
+		ID:             id,
+		MerchantID:     "merchant-789",
+		Amount:         100,
+		Status:         status,
+		ChargebackDate: time.Date(2023, 1, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMerchantStatsRecorder(t *testing.T) {
+	// Arrange
+	repo := NewMockMerchantStatsRepository()
+	repo.SetTransactionVolume(context.Background(), "merchant-789", "2023-01", 100, 10000)
+	logger := &MockLogger{}
+	sink := &MockAlertSink{}
+	recorder := usecase.NewMerchantStatsRecorder(repo, testRatioThresholds, logger, sink)
+	ctx := context.Background()
+
+	// Act
+	recorder.ChargebackSaved(ctx, testStatsChargeback("cb_1", entity.StatusPending))
+	recorder.ChargebackSaved(ctx, testStatsChargeback("cb_2", entity.StatusPending))
+	recorder.ChargebackSaved(ctx, testStatsChargeback("cb_3", entity.StatusRejected))
+	recorder.ChargebackSaved(ctx, testStatsChargeback("cb_4", entity.StatusPending))
+	recorder.ChargebackUpdated(ctx, testStatsChargeback("cb_4", entity.StatusPending), testStatsChargeback("cb_4", entity.StatusRejected))
+	recorder.ChargebackDeleted(ctx, testStatsChargeback("cb_1", entity.StatusPending))
+
+	// Assert
+	stats, _ := repo.Find(ctx, "merchant-789", "2023-01")
+	if stats.ChargebackCount != 1 || stats.RejectedCount != 2 || stats.RejectedAmount != 200 {
+		t.Errorf("Unexpected statistics %+v", stats)
+	}
+
+	var crossed []string
+	for _, alert := range sink.alerts {
+		crossed = append(crossed, alert.Threshold)
+	}
+	// cb_2 crosses the early warning and cb_4 the excessive threshold, as the rejected cb_3
+	// does not count; falling back below a threshold raises nothing
+	if strings.Join(crossed, ",") != "early_warning,excessive" {
+		t.Errorf("Expected early_warning then excessive alerts, got %v", crossed)
+	}
+	if len(logger.warnings) != 2 {
+		t.Errorf("Expected each alert to be logged, got %v", logger.warnings)
+	}
+	if sink.alerts[0].Ratio != 0.02 || sink.alerts[0].TransactionCount != 100 {
+		t.Errorf("Unexpected alert %+v", sink.alerts[0])
+	}
+}
+
+func TestMerchantStatsRecorder_SinkErrorsAreLogged(t *testing.T) {
+	// Arrange
+	repo := NewMockMerchantStatsRepository()
+	repo.SetTransactionVolume(context.Background(), "merchant-789", "2023-01", 100, 10000)
+	logger := &MockLogger{}
+	sink := &MockAlertSink{err: errors.New("webhook unavailable")}
+	recorder := usecase.NewMerchantStatsRecorder(repo, testRatioThresholds, logger, sink)
+
+	// Act
+	recorder.ChargebackSaved(context.Background(), testStatsChargeback("cb_1", entity.StatusPending))
+	err := recorder.ChargebackSaved(context.Background(), testStatsChargeback("cb_2", entity.StatusPending))
+
+	// Assert
+	if err != nil {
+		t.Errorf("Expected sink errors not to fail recording, got %v", err)
+	}
+	if len(logger.errors) != 1 {
+		t.Errorf("Expected the sink error to be logged, got %v", logger.errors)
+	}
+}
+
+func TestRecordTransactionVolumeUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		month          string
+		request        usecase.RecordTransactionVolumeRequest
+		expectedAlerts int
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name:           "lower volume crosses the thresholds",
+			ctx:            context.Background(),
+			month:          "2023-01",
+			request:        usecase.RecordTransactionVolumeRequest{TransactionCount: 100, TransactionAmount: 10000},
+			expectedAlerts: 2,
+		},
+		{
+			name:    "higher volume stays below the thresholds",
+			ctx:     merchantContext([]string{"merchant-789"}, auth.ScopeMerchantsWrite),
+			month:   "2023-01",
+			request: usecase.RecordTransactionVolumeRequest{TransactionCount: 10000, TransactionAmount: 1000000},
+		},
+		{
+			name:           "rejects invalid volume",
+			ctx:            context.Background(),
+			month:          "2023-13",
+			request:        usecase.RecordTransactionVolumeRequest{TransactionCount: -1},
+			expectedErrMsg: "validation errors: invalid month \"2023-13\", expected YYYY-MM; transaction_count must not be negative",
+		},
+		{
+			name:        "requires merchants write scope",
+			ctx:         merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead),
+			month:       "2023-01",
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:        "rejects other merchant",
+			ctx:         merchantContext([]string{"merchant-000"}, auth.ScopeMerchantsWrite),
+			month:       "2023-01",
+			expectedErr: auth.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := NewMockMerchantStatsRepository()
+			repo.AddChargebacks(context.Background(), "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 5, ChargebackAmount: 500})
+			sink := &MockAlertSink{}
+			uc := usecase.NewRecordTransactionVolumeUseCase(repo, testRatioThresholds, &MockLogger{}, sink)
+
+			// Act
+			response, err := uc.Execute(tt.ctx, "merchant-789", tt.month, tt.request)
+
+			// Assert
+			if tt.expectedErr != nil || tt.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				if tt.expectedErrMsg != "" && err.Error() != tt.expectedErrMsg {
+					t.Errorf("Expected error message %q, got %q", tt.expectedErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if response.TransactionCount != tt.request.TransactionCount || response.ChargebackCount != 5 {
+				t.Errorf("Unexpected response %+v", response)
+			}
+			if len(sink.alerts) != tt.expectedAlerts || len(response.ThresholdsExceeded) != tt.expectedAlerts {
+				t.Errorf("Expected %d alerts and exceeded thresholds, got %v and %v", tt.expectedAlerts, sink.alerts, response.ThresholdsExceeded)
+			}
+		})
+	}
+}
+
+func TestGetMerchantStatsUseCase_Execute(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		from           string
+		to             string
+		expectedMonths string
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		{
+			name:           "returns requested range",
+			ctx:            merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead),
+			from:           "2023-01",
+			to:             "2023-02",
+			expectedMonths: "2023-01,2023-02",
+		},
+		{
+			name:           "defaults to the six months up to to",
+			ctx:            context.Background(),
+			to:             "2023-06",
+			expectedMonths: "2023-01,2023-02,2023-06",
+		},
+		{
+			name:           "rejects reversed range",
+			ctx:            context.Background(),
+			from:           "2023-06",
+			to:             "2023-01",
+			expectedErrMsg: "validation errors: from must not be after to",
+		},
+		{
+			name:        "rejects other merchant",
+			ctx:         merchantContext([]string{"merchant-000"}, auth.ScopeChargebacksRead),
+			expectedErr: auth.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := NewMockMerchantStatsRepository()
+			for _, month := range []string{"2022-12", "2023-01", "2023-02", "2023-06"} {
+				repo.SetTransactionVolume(context.Background(), "merchant-789", month, 100, 10000)
+			}
+			repo.AddChargebacks(context.Background(), "merchant-789", "2023-01", entity.MerchantStatsDelta{ChargebackCount: 3, ChargebackAmount: 300})
+			uc := usecase.NewGetMerchantStatsUseCase(repo, testRatioThresholds)
+
+			// Act
+			response, err := uc.Execute(tt.ctx, "merchant-789", tt.from, tt.to)
+
+			// Assert
+			if tt.expectedErr != nil || tt.expectedErrMsg != "" {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				if tt.expectedErrMsg != "" && err.Error() != tt.expectedErrMsg {
+					t.Errorf("Expected error message %q, got %q", tt.expectedErrMsg, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			var months []string
+			for _, stats := range response.Months {
+				months = append(months, stats.Month)
+			}
+			if strings.Join(months, ",") != tt.expectedMonths {
+				t.Errorf("Expected months %s, got %v", tt.expectedMonths, months)
+			}
+			january := response.Months[0]
+			if january.CountRatio != 0.03 || strings.Join(january.ThresholdsExceeded, ",") != "early_warning,excessive" {
+				t.Errorf("Unexpected January statistics %+v", january)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// Dimensions report summaries can be grouped by, besides the period
+const (
+	ReportByMerchant = "merchant"
+	ReportByReason   = "reason"
+	ReportByStatus   = "status"
+	ReportByCurrency = "currency"
+)
+
+// maxReportDays bounds the range of a report summary
+const maxReportDays = 366
+
+// ReportSummaryRequest represents the grouping and filters of a report summary
+type ReportSummaryRequest struct {
+	// GroupBy lists the dimensions to group by: merchant, reason, status and currency
+	GroupBy []string
+	// Period groups by day, week or month when set
+	Period entity.ReportPeriod
+	// From and To are the first and last days of the report, YYYY-MM-DD; they default
+	// to the 30 days up to today
+	From string
+	To   string
+
+	MerchantIDs []string
+	Status      entity.ChargebackStatus
+	Reason      entity.ChargebackReason
+	Currency    string
+}
+
+// ReportGroup represents the chargebacks of a group; only the grouped dimensions are set
+type ReportGroup struct {
+	Period        string                  `json:"period,omitempty"`
+	MerchantID    string                  `json:"merchant_id,omitempty"`
+	Reason        entity.ChargebackReason `json:"reason,omitempty"`
+	Status        entity.ChargebackStatus `json:"status,omitempty"`
+	Currency      string                  `json:"currency,omitempty"`
+	Count         int                     `json:"count"`
+	TotalAmount   float64                 `json:"total_amount"`
+	AverageAmount float64                 `json:"average_amount"`
+}
+
+// ReportSummaryResponse represents a report summary
+type ReportSummaryResponse struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Period  string         `json:"period,omitempty"`
+	GroupBy []string       `json:"group_by"`
+	Groups  []*ReportGroup `json:"groups"`
+}
+
+// GetReportSummaryUseCase handles summarizing chargebacks from the report counters
+type GetReportSummaryUseCase struct {
+	counterRepo repository.ReportCounterRepository
+	now         func() time.Time
+}
+
+// NewGetReportSummaryUseCase creates a new instance of GetReportSummaryUseCase
+func NewGetReportSummaryUseCase(counterRepo repository.ReportCounterRepository) *GetReportSummaryUseCase {
+	return &GetReportSummaryUseCase{
+		counterRepo: counterRepo,
+		now:         time.Now,
+	}
+}
+
+// Execute returns the count, total and average amount of the chargebacks of each group
+// Callers limited to some merchants only see those merchants' chargebacks. Amounts of
+// different currencies are added up unless grouped by currency.
+func (uc *GetReportSummaryUseCase) Execute(ctx context.Context, req ReportSummaryRequest) (*ReportSummaryResponse, error) {
+	merchantIDs, err := readableMerchants(ctx, req.MerchantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := uc.validate(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := uc.counterRepo.List(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report counters: %w", err)
+	}
+
+	merchants := make(map[string]bool, len(merchantIDs))
+	for _, merchantID := range merchantIDs {
+		merchants[merchantID] = true
+	}
+	grouped := make(map[ReportGroup]*ReportGroup)
+	for _, counter := range counters {
+		if counter.Count <= 0 ||
+			(len(merchants) > 0 && !merchants[counter.MerchantID]) ||
+			(req.Status != "" && counter.Status != req.Status) ||
+			(req.Reason != "" && counter.Reason != req.Reason) ||
+			(req.Currency != "" && counter.Currency != req.Currency) {
+			continue
+		}
+
+		key, err := reportGroupKey(counter, req)
+		if err != nil {
+			return nil, err
+		}
+		group, ok := grouped[key]
+		if !ok {
+			group = &key
+			grouped[key] = group
+		}
+		group.Count += counter.Count
+		group.TotalAmount += counter.Amount
+	}
+
+	response := &ReportSummaryResponse{
+		From:    from,
+		To:      to,
+		Period:  string(req.Period),
+		GroupBy: req.GroupBy,
+		Groups:  make([]*ReportGroup, 0, len(grouped)),
+	}
+	for _, group := range grouped {
+		group.AverageAmount = group.TotalAmount / float64(group.Count)
+		response.Groups = append(response.Groups, group)
+	}
+	sort.Slice(response.Groups, func(i, j int) bool {
+		a, b := response.Groups[i], response.Groups[j]
+		return reportGroupSortKey(a) < reportGroupSortKey(b)
+	})
+	return response, nil
+}
+
+// validate checks the request and returns its range, defaulting to the last 30 days
+func (uc *GetReportSummaryUseCase) validate(req *ReportSummaryRequest) (string, string, error) {
+	var errors []string
+
+	if req.GroupBy == nil {
+		req.GroupBy = []string{}
+	}
+	seen := make(map[string]bool, len(req.GroupBy))
+	for _, dimension := range req.GroupBy {
+		switch dimension {
+		case ReportByMerchant, ReportByReason, ReportByStatus, ReportByCurrency:
+		default:
+			errors = append(errors, fmt.Sprintf("cannot group by %q, expected merchant, reason, status or currency", dimension))
+		}
+		if seen[dimension] {
+			errors = append(errors, fmt.Sprintf("%s is grouped by more than once", dimension))
+		}
+		seen[dimension] = true
+	}
+	if req.Period != "" && !req.Period.IsValid() {
+		errors = append(errors, fmt.Sprintf("invalid period %q, expected day, week or month", req.Period))
+	}
+
+	to := uc.now().UTC()
+	if req.To != "" {
+		parsed, err := time.Parse(entity.ReportDayLayout, req.To)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid to %q, expected YYYY-MM-DD", req.To))
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.From != "" {
+		parsed, err := time.Parse(entity.ReportDayLayout, req.From)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid from %q, expected YYYY-MM-DD", req.From))
+		}
+		from = parsed
+	}
+	if len(errors) == 0 {
+		if from.After(to) {
+			errors = append(errors, "from must not be after to")
+		} else if to.Sub(from) >= maxReportDays*24*time.Hour {
+			errors = append(errors, fmt.Sprintf("range must not exceed %d days", maxReportDays))
+		}
+	}
+
+	if len(errors) > 0 {
+		return "", "", fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
+	}
+	return from.Format(entity.ReportDayLayout), to.Format(entity.ReportDayLayout), nil
+}
+
+// reportGroupKey returns the group a counter is added to
+func reportGroupKey(counter *entity.ReportCounter, req ReportSummaryRequest) (ReportGroup, error) {
+	var key ReportGroup
+	if req.Period != "" {
+		period, err := req.Period.Label(counter.Day)
+		if err != nil {
+			return key, fmt.Errorf("failed to group report counter: %w", err)
+		}
+		key.Period = period
+	}
+	for _, dimension := range req.GroupBy {
+		switch dimension {
+		case ReportByMerchant:
+			key.MerchantID = counter.MerchantID
+		case ReportByReason:
+			key.Reason = counter.Reason
+		case ReportByStatus:
+			key.Status = counter.Status
+		case ReportByCurrency:
+			key.Currency = counter.Currency
+		}
+	}
+	return key, nil
+}
+
+// reportGroupSortKey orders groups by period, then by their dimensions
+func reportGroupSortKey(group *ReportGroup) string {
+	return strings.Join([]string{group.Period, group.MerchantID, string(group.Reason), string(group.Status), group.Currency}, "\x00")
+}
+
+// ReportCounterRecorder keeps the report counters up to date as chargebacks are saved,
+// updated or deleted
+// It is registered as an observer of the chargeback repository.
+type ReportCounterRecorder struct {
+	counterRepo repository.ReportCounterRepository
+}
+
+// NewReportCounterRecorder creates a new instance of ReportCounterRecorder
+func NewReportCounterRecorder(counterRepo repository.ReportCounterRepository) *ReportCounterRecorder {
+	return &ReportCounterRecorder{
+		counterRepo: counterRepo,
+	}
+}
+
+// ChargebackSaved counts a new chargeback
+func (r *ReportCounterRecorder) ChargebackSaved(ctx context.Context, chargeback *entity.Chargeback) error {
+	return r.add(ctx, chargeback.ReportCell(), 1, chargeback.Amount)
+}
+
+// ChargebackUpdated moves a changed chargeback to its new cell
+func (r *ReportCounterRecorder) ChargebackUpdated(ctx context.Context, previous, current *entity.Chargeback) error {
+	previousCell, currentCell := previous.ReportCell(), current.ReportCell()
+	if previousCell == currentCell {
+		if previous.Amount == current.Amount {
+			return nil
+		}
+		return r.add(ctx, currentCell, 0, current.Amount-previous.Amount)
+	}
+
+	if err := r.add(ctx, previousCell, -1, -previous.Amount); err != nil {
+		return err
+	}
+	return r.add(ctx, currentCell, 1, current.Amount)
+}
+
+// ChargebackDeleted removes a deleted chargeback
+func (r *ReportCounterRecorder) ChargebackDeleted(ctx context.Context, chargeback *entity.Chargeback) error {
+	return r.add(ctx, chargeback.ReportCell(), -1, -chargeback.Amount)
+}
+
+// add updates a counter
+func (r *ReportCounterRecorder) add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error {
+	if err := r.counterRepo.Add(ctx, cell, count, amount); err != nil {
+		return fmt.Errorf("failed to record report counter: %w", err)
+	}
+	return nil
+}
+
+// RebuildReportCountersResult represents the outcome of a rebuild
+type RebuildReportCountersResult struct {
+	Chargebacks int `json:"chargebacks"`
+	Counters    int `json:"counters"`
+}
+
+// RebuildReportCountersUseCase handles recomputing the report counters from every chargeback
+// It repairs counters that drifted, e.g. when a write was made while they could not be
+// updated, and fills them in for chargebacks written before reports were enabled.
+type RebuildReportCountersUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+	counterRepo    repository.ReportCounterRepository
+	pageSize       int
+}
+
+// NewRebuildReportCountersUseCase creates a new instance of RebuildReportCountersUseCase
+func NewRebuildReportCountersUseCase(chargebackRepo repository.ChargebackRepository, counterRepo repository.ReportCounterRepository, pageSize int) *RebuildReportCountersUseCase {
+	return &RebuildReportCountersUseCase{
+		chargebackRepo: chargebackRepo,
+		counterRepo:    counterRepo,
+		pageSize:       pageSize,
+	}
+}
+
+// Execute reads every chargeback a page at a time, then replaces the counters
+func (uc *RebuildReportCountersUseCase) Execute(ctx context.Context) (*RebuildReportCountersResult, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeAdmin); err != nil {
+		return nil, err
+	}
+
+	result := &RebuildReportCountersResult{}
+	counters := make(map[entity.ReportCell]*entity.ReportCounter)
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page, err := uc.chargebackRepo.ListPage(ctx, repository.ChargebackFilter{}, cursor, uc.pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chargebacks: %w", err)
+		}
+
+		for _, chargeback := range page.Chargebacks {
+			cell := chargeback.ReportCell()
+			counter, ok := counters[cell]
+			if !ok {
+				counter = &entity.ReportCounter{ReportCell: cell}
+				counters[cell] = counter
+			}
+			counter.Count++
+			counter.Amount += chargeback.Amount
+			result.Chargebacks++
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	rebuilt := make([]*entity.ReportCounter, 0, len(counters))
+	for _, counter := range counters {
+		rebuilt = append(rebuilt, counter)
+	}
+	if err := uc.counterRepo.ReplaceAll(ctx, rebuilt); err != nil {
+		return nil, fmt.Errorf("failed to replace report counters: %w", err)
+	}
+
+	result.Counters = len(rebuilt)
+	return result, nil
+}
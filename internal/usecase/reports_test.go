@@ -0,0 +1,320 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// MockReportCounterRepository is an in-memory mock implementation of ReportCounterRepository
+type MockReportCounterRepository struct {
+	counters map[entity.ReportCell]*entity.ReportCounter
+	ListErr  error
+}
+
+func NewMockReportCounterRepository(counters ...*entity.ReportCounter) *MockReportCounterRepository {
+	m := &MockReportCounterRepository{counters: make(map[entity.ReportCell]*entity.ReportCounter)}
+	for _, counter := range counters {
+		m.counters[counter.ReportCell] = counter
+	}
+	return m
+}
+
+func (m *MockReportCounterRepository) Add(ctx context.Context, cell entity.ReportCell, count int, amount float64) error {
+	counter, ok := m.counters[cell]
+	if !ok {
+		counter = &entity.ReportCounter{ReportCell: cell}
+		m.counters[cell] = counter
+	}
+	counter.Count += count
+	counter.Amount += amount
+	return nil
+}
+
+func (m *MockReportCounterRepository) List(ctx context.Context, fromDay, toDay string) ([]*entity.ReportCounter, error) {
+	if m.ListErr != nil {
+		return nil, m.ListErr
+	}
+	var counters []*entity.ReportCounter
+	for _, counter := range m.counters {
+		if counter.Day >= fromDay && counter.Day <= toDay {
+			counters = append(counters, counter)
+		}
+	}
+	return counters, nil
+}
+
+func (m *MockReportCounterRepository) ReplaceAll(ctx context.Context, counters []*entity.ReportCounter) error {
+	m.counters = make(map[entity.ReportCell]*entity.ReportCounter)
+	for _, counter := range counters {
+		m.counters[counter.ReportCell] = counter
+	}
+	return nil
+}
+
+func testReportCounter(day, merchantID string, status entity.ChargebackStatus, currency string, count int, amount float64) *entity.ReportCounter {
+	return &entity.ReportCounter{
+		ReportCell: entity.ReportCell{
+			Day:        day,
+			MerchantID: merchantID,
+			Reason:     entity.ReasonFraud,
+			Status:     status,
+			Currency:   currency,
+		},
+		Count:  count,
+		Amount: amount,
+	}
+}
+
+func TestGetReportSummaryUseCase_Execute(t *testing.T) {
+	repo := NewMockReportCounterRepository(
+		testReportCounter("2023-10-02", "merchant-789", entity.StatusPending, "USD", 2, 300),
+		testReportCounter("2023-10-16", "merchant-789", entity.StatusApproved, "USD", 1, 100),
+		testReportCounter("2023-10-17", "merchant-456", entity.StatusPending, "EUR", 3, 90),
+		testReportCounter("2023-10-18", "merchant-456", entity.StatusRejected, "EUR", 0, 0),
+		testReportCounter("2023-11-01", "merchant-789", entity.StatusPending, "USD", 5, 500),
+	)
+	uc := usecase.NewGetReportSummaryUseCase(repo)
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		req            usecase.ReportSummaryRequest
+		expectedGroups []usecase.ReportGroup
+		expectedErr    string
+	}{
+		{
+			name: "totals the range",
+			ctx:  context.Background(),
+			req:  usecase.ReportSummaryRequest{From: "2023-10-01", To: "2023-10-31"},
+			expectedGroups: []usecase.ReportGroup{
+				{Count: 6, TotalAmount: 490, AverageAmount: 490.0 / 6},
+			},
+		},
+		{
+			name: "groups by merchant and week",
+			ctx:  context.Background(),
+			req:  usecase.ReportSummaryRequest{GroupBy: []string{usecase.ReportByMerchant}, Period: entity.ReportPeriodWeek, From: "2023-10-01", To: "2023-10-31"},
+			expectedGroups: []usecase.ReportGroup{
+				{Period: "2023-W40", MerchantID: "merchant-789", Count: 2, TotalAmount: 300, AverageAmount: 150},
+				{Period: "2023-W42", MerchantID: "merchant-456", Count: 3, TotalAmount: 90, AverageAmount: 30},
+				{Period: "2023-W42", MerchantID: "merchant-789", Count: 1, TotalAmount: 100, AverageAmount: 100},
+			},
+		},
+		{
+			name: "filters by status and groups by currency and month",
+			ctx:  context.Background(),
+			req:  usecase.ReportSummaryRequest{GroupBy: []string{usecase.ReportByCurrency}, Period: entity.ReportPeriodMonth, From: "2023-10-01", To: "2023-11-30", Status: entity.StatusPending},
+			expectedGroups: []usecase.ReportGroup{
+				{Period: "2023-10", Currency: "EUR", Count: 3, TotalAmount: 90, AverageAmount: 30},
+				{Period: "2023-10", Currency: "USD", Count: 2, TotalAmount: 300, AverageAmount: 150},
+				{Period: "2023-11", Currency: "USD", Count: 5, TotalAmount: 500, AverageAmount: 100},
+			},
+		},
+		{
+			name: "limits merchant keys to their merchants",
+			ctx:  merchantContext([]string{"merchant-456"}, auth.ScopeChargebacksRead),
+			req:  usecase.ReportSummaryRequest{GroupBy: []string{usecase.ReportByMerchant, usecase.ReportByStatus}, From: "2023-10-01", To: "2023-11-30"},
+			expectedGroups: []usecase.ReportGroup{
+				{MerchantID: "merchant-456", Status: entity.StatusPending, Count: 3, TotalAmount: 90, AverageAmount: 30},
+			},
+		},
+		{
+			name:        "rejects other merchants",
+			ctx:         merchantContext([]string{"merchant-456"}, auth.ScopeChargebacksRead),
+			req:         usecase.ReportSummaryRequest{MerchantIDs: []string{"merchant-789"}, From: "2023-10-01", To: "2023-10-31"},
+			expectedErr: auth.ErrForbidden.Error(),
+		},
+		{
+			name:        "rejects unknown dimensions",
+			ctx:         context.Background(),
+			req:         usecase.ReportSummaryRequest{GroupBy: []string{"card"}, Period: "year"},
+			expectedErr: "validation errors: cannot group by \"card\", expected merchant, reason, status or currency; invalid period \"year\"",
+		},
+		{
+			name:        "rejects inverted ranges",
+			ctx:         context.Background(),
+			req:         usecase.ReportSummaryRequest{From: "2023-10-31", To: "2023-10-01"},
+			expectedErr: "validation errors: from must not be after to",
+		},
+		{
+			name:        "rejects ranges over a year",
+			ctx:         context.Background(),
+			req:         usecase.ReportSummaryRequest{From: "2022-01-01", To: "2023-10-01"},
+			expectedErr: "validation errors: range must not exceed 366 days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			response, err := uc.Execute(tt.ctx, tt.req)
+
+			// Assert
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(response.Groups) != len(tt.expectedGroups) {
+				t.Fatalf("Expected %d groups, got %d: %+v", len(tt.expectedGroups), len(response.Groups), response.Groups)
+			}
+			for i, expected := range tt.expectedGroups {
+				if *response.Groups[i] != expected {
+					t.Errorf("Expected group %d to be %+v, got %+v", i, expected, *response.Groups[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetReportSummaryUseCase_Execute_DefaultsToLast30Days(t *testing.T) {
+	// Arrange
+	uc := usecase.NewGetReportSummaryUseCase(NewMockReportCounterRepository())
+
+	// Act
+	response, err := uc.Execute(context.Background(), usecase.ReportSummaryRequest{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	today := time.Now().UTC()
+	if response.To != today.Format(entity.ReportDayLayout) || response.From != today.AddDate(0, 0, -29).Format(entity.ReportDayLayout) {
+		t.Errorf("Unexpected range %s to %s", response.From, response.To)
+	}
+	if response.Groups == nil || response.GroupBy == nil {
+		t.Error("Expected empty groups and group_by to be non-nil")
+	}
+}
+
+func TestGetReportSummaryUseCase_Execute_RepositoryError(t *testing.T) {
+	// Arrange
+	repo := NewMockReportCounterRepository()
+	repo.ListErr = errors.New("DynamoDB unavailable")
+	uc := usecase.NewGetReportSummaryUseCase(repo)
+
+	// Act
+	_, err := uc.Execute(context.Background(), usecase.ReportSummaryRequest{From: "2023-10-01", To: "2023-10-31"})
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "failed to list report counters") {
+		t.Fatalf("Expected wrapped repository error, got %v", err)
+	}
+}
+
+func TestReportCounterRecorder(t *testing.T) {
+	// Arrange
+	repo := NewMockReportCounterRepository()
+	recorder := usecase.NewReportCounterRecorder(repo)
+	ctx := context.Background()
+	date := time.Date(2023, 10, 16, 23, 30, 0, 0, time.FixedZone("BRT", -3*3600))
+	pending := &entity.Chargeback{ID: "cb_1", MerchantID: "merchant-789", Amount: 100, Currency: "USD", Status: entity.StatusPending, Reason: entity.ReasonFraud, ChargebackDate: date}
+	approved := *pending
+	approved.Status = entity.StatusApproved
+	corrected := approved
+	corrected.Amount = 80
+	other := &entity.Chargeback{ID: "cb_2", MerchantID: "merchant-789", Amount: 50, Currency: "USD", Status: entity.StatusPending, Reason: entity.ReasonFraud, ChargebackDate: date}
+
+	// Act
+	steps := []error{
+		recorder.ChargebackSaved(ctx, pending),
+		recorder.ChargebackSaved(ctx, other),
+		recorder.ChargebackUpdated(ctx, pending, &approved),
+		recorder.ChargebackUpdated(ctx, &approved, &corrected),
+		recorder.ChargebackDeleted(ctx, other),
+	}
+
+	// Assert
+	for i, err := range steps {
+		if err != nil {
+			t.Fatalf("Unexpected error at step %d: %v", i, err)
+		}
+	}
+	counters, _ := repo.List(ctx, "2023-10-17", "2023-10-17")
+	counts := make(map[entity.ChargebackStatus]*entity.ReportCounter)
+	for _, counter := range counters {
+		counts[counter.Status] = counter
+	}
+	if c := counts[entity.StatusPending]; c == nil || c.Count != 0 || c.Amount != 0 {
+		t.Errorf("Expected pending counter to be emptied, got %+v", c)
+	}
+	if c := counts[entity.StatusApproved]; c == nil || c.Count != 1 || c.Amount != 80 {
+		t.Errorf("Expected approved counter with 1 chargeback of 80, got %+v", c)
+	}
+}
+
+func TestRebuildReportCountersUseCase_Execute(t *testing.T) {
+	date := time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC)
+	chargebackRepo := &MockChargebackRepository{
+		ListPageFunc: func(ctx context.Context, filter repository.ChargebackFilter, cursor string, limit int) (*repository.ChargebackPage, error) {
+			if cursor == "" {
+				return &repository.ChargebackPage{
+					Chargebacks: []*entity.Chargeback{
+						{ID: "cb_1", MerchantID: "merchant-789", Amount: 100, Currency: "USD", Status: entity.StatusPending, Reason: entity.ReasonFraud, ChargebackDate: date},
+						{ID: "cb_2", MerchantID: "merchant-789", Amount: 50, Currency: "USD", Status: entity.StatusPending, Reason: entity.ReasonFraud, ChargebackDate: date},
+					},
+					NextCursor: "page-2",
+				}, nil
+			}
+			return &repository.ChargebackPage{
+				Chargebacks: []*entity.Chargeback{
+					{ID: "cb_3", MerchantID: "merchant-456", Amount: 30, Currency: "EUR", Status: entity.StatusApproved, Reason: entity.ReasonFraud, ChargebackDate: date},
+				},
+			}, nil
+		},
+	}
+
+	t.Run("replaces drifted counters", func(t *testing.T) {
+		// Arrange
+		counterRepo := NewMockReportCounterRepository(
+			testReportCounter("2023-10-16", "merchant-789", entity.StatusPending, "USD", 7, 999),
+			testReportCounter("2023-10-15", "merchant-123", entity.StatusPending, "USD", 1, 10),
+		)
+		uc := usecase.NewRebuildReportCountersUseCase(chargebackRepo, counterRepo, 2)
+
+		// Act
+		result, err := uc.Execute(context.Background())
+
+		// Assert
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Chargebacks != 3 || result.Counters != 2 {
+			t.Errorf("Expected 3 chargebacks in 2 counters, got %+v", result)
+		}
+		counters, _ := counterRepo.List(context.Background(), "2023-10-01", "2023-10-31")
+		if len(counters) != 2 {
+			t.Fatalf("Expected 2 counters, got %d", len(counters))
+		}
+		for _, counter := range counters {
+			if counter.MerchantID == "merchant-789" && (counter.Count != 2 || counter.Amount != 150) {
+				t.Errorf("Expected merchant-789 counter with 2 chargebacks of 150, got %+v", counter)
+			}
+		}
+	})
+
+	t.Run("requires admin scope", func(t *testing.T) {
+		// Arrange
+		uc := usecase.NewRebuildReportCountersUseCase(chargebackRepo, NewMockReportCounterRepository(), 2)
+
+		// Act
+		_, err := uc.Execute(merchantContext([]string{"merchant-789"}, auth.ScopeChargebacksRead))
+
+		// Assert
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Fatalf("Expected forbidden error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/repository"
+)
+
+// ApproveChargebackUseCase handles approving pending chargebacks
+type ApproveChargebackUseCase struct {
+	chargebackRepo     repository.ChargebackRepository
+	highValueThreshold float64
+}
+
+// NewApproveChargebackUseCase creates a new instance of ApproveChargebackUseCase
+// Chargebacks with an amount above highValueThreshold require the high-value review scope;
+// a threshold of zero or less disables the check
+func NewApproveChargebackUseCase(chargebackRepo repository.ChargebackRepository, highValueThreshold float64) *ApproveChargebackUseCase {
+	return &ApproveChargebackUseCase{
+		chargebackRepo:     chargebackRepo,
+		highValueThreshold: highValueThreshold,
+	}
+}
+
+// Execute approves the chargeback
+func (uc *ApproveChargebackUseCase) Execute(ctx context.Context, id string) (*CreateChargebackResponse, error) {
+	chargeback, err := findReviewableChargeback(ctx, uc.chargebackRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.highValueThreshold > 0 && chargeback.Amount > uc.highValueThreshold {
+		if err := auth.RequireScope(ctx, auth.ScopeChargebacksReviewHighValue); err != nil {
+			return nil, fmt.Errorf("%w: approving chargebacks above %.2f requires supervisor review", err, uc.highValueThreshold)
+		}
+	}
+
+	if err := chargeback.Approve(); err != nil {
+		return nil, fmt.Errorf("failed to approve chargeback: %w", err)
+	}
+
+	if err := uc.chargebackRepo.Update(ctx, chargeback); err != nil {
+		return nil, fmt.Errorf("failed to update chargeback: %w", err)
+	}
+
+	return toChargebackResponse(chargeback), nil
+}
+
+// RejectChargebackUseCase handles rejecting pending chargebacks
+type RejectChargebackUseCase struct {
+	chargebackRepo repository.ChargebackRepository
+}
+
+// NewRejectChargebackUseCase creates a new instance of RejectChargebackUseCase
+func NewRejectChargebackUseCase(chargebackRepo repository.ChargebackRepository) *RejectChargebackUseCase {
+	return &RejectChargebackUseCase{
+		chargebackRepo: chargebackRepo,
+	}
+}
+
+// Execute rejects the chargeback
+func (uc *RejectChargebackUseCase) Execute(ctx context.Context, id string) (*CreateChargebackResponse, error) {
+	chargeback, err := findReviewableChargeback(ctx, uc.chargebackRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chargeback.Reject(); err != nil {
+		return nil, fmt.Errorf("failed to reject chargeback: %w", err)
+	}
+
+	if err := uc.chargebackRepo.Update(ctx, chargeback); err != nil {
+		return nil, fmt.Errorf("failed to update chargeback: %w", err)
+	}
+
+	return toChargebackResponse(chargeback), nil
+}
+
+// findReviewableChargeback loads a chargeback the caller is allowed to review
+func findReviewableChargeback(ctx context.Context, chargebackRepo repository.ChargebackRepository, id string) (*entity.Chargeback, error) {
+	if err := auth.RequireScope(ctx, auth.ScopeChargebacksReview); err != nil {
+		return nil, err
+	}
+
+	chargeback, err := chargebackRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find chargeback: %w", err)
+	}
+
+	// Report chargebacks of other merchants as missing so their IDs can't be probed
+	if chargeback == nil || auth.RequireMerchantAccess(ctx, auth.ScopeChargebacksReview, chargeback.MerchantID) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrChargebackNotFound, id)
+	}
+
+	return chargeback, nil
+}
@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/auth"
+	"github.com/DiegoSantos90/chargeback-api/internal/domain/entity"
+	"github.com/DiegoSantos90/chargeback-api/internal/usecase"
+)
+
+// staffContext returns a context authenticated as an identity provider user with the roles
+func staffContext(roles ...auth.Role) context.Context {
+	return auth.ContextWithPrincipal(context.Background(), &auth.Principal{
+		Subject:      "staff@example.com",
+		AllMerchants: true,
+		Roles:        roles,
+		Scopes:       auth.ScopesForRoles(roles),
+	})
+}
+
+func TestApproveChargebackUseCase_Execute(t *testing.T) {
+	const threshold = 10000.0
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		id          string
+		amount      float64
+		expectedErr error
+	}{
+		{
+			name:   "analyst approves amount below threshold",
+			ctx:    staffContext(auth.RoleAnalyst),
+			id:     "cb_12345",
+			amount: 500,
+		},
+		{
+			name:   "analyst approves amount equal to threshold",
+			ctx:    staffContext(auth.RoleAnalyst),
+			id:     "cb_12345",
+			amount: threshold,
+		},
+		{
+			name:        "analyst cannot approve amount above threshold",
+			ctx:         staffContext(auth.RoleAnalyst),
+			id:          "cb_12345",
+			amount:      25000,
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:   "supervisor approves amount above threshold",
+			ctx:    staffContext(auth.RoleSupervisor),
+			id:     "cb_12345",
+			amount: 25000,
+		},
+		{
+			name:        "viewer cannot approve",
+			ctx:         staffContext(auth.RoleViewer),
+			id:          "cb_12345",
+			amount:      500,
+			expectedErr: auth.ErrForbidden,
+		},
+		{
+			name:        "API key of other merchant sees not found",
+			ctx:         merchantContext([]string{"merchant-000"}, auth.ScopeChargebacksReview),
+			id:          "cb_12345",
+			amount:      500,
+			expectedErr: usecase.ErrChargebackNotFound,
+		},
+		{
+			name:        "reports missing chargeback",
+			ctx:         staffContext(auth.RoleSupervisor),
+			id:          "cb_missing",
+			amount:      500,
+			expectedErr: usecase.ErrChargebackNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			var updated *entity.Chargeback
+			mockRepo := &MockChargebackRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entity.Chargeback, error) {
+					if id != "cb_12345" {
+						return nil, nil
+					}
+					return &entity.Chargeback{
+						ID:         "cb_12345",
+						MerchantID: "merchant-789",
+						Amount:     tt.amount,
+						Currency:   "USD",
+						Status:     entity.StatusPending,
+					}, nil
+				},
+				UpdateFunc: func(ctx context.Context, chargeback *entity.Chargeback) error {
+					updated = chargeback
+					return nil
+				},
+			}
+			useCase := usecase.NewApproveChargebackUseCase(mockRepo, threshold)
+
+			// Act
+			response, err := useCase.Execute(tt.ctx, tt.id)
+
+			// Assert
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				if updated != nil {
+					t.Error("Expected chargeback not to be updated")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if response.Status != entity.StatusApproved {
+				t.Errorf("Expected status %s, got %s", entity.StatusApproved, response.Status)
+			}
+			if updated == nil || updated.Status != entity.StatusApproved {
+				t.Error("Expected approved chargeback to be persisted")
+			}
+		})
+	}
+}
+
+func TestRejectChargebackUseCase_Execute(t *testing.T) {
+	// Arrange
+	chargeback := &entity.Chargeback{
+		ID:         "cb_12345",
+		MerchantID: "merchant-789",
+		Amount:     25000,
+		Status:     entity.StatusPending,
+	}
+	mockRepo := &MockChargebackRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entity.Chargeback, error) {
+			return chargeback, nil
+		},
+		UpdateFunc: func(ctx context.Context, chargeback *entity.Chargeback) error {
+			return nil
+		},
+	}
+	useCase := usecase.NewRejectChargebackUseCase(mockRepo)
+
+	// Act
+	response, err := useCase.Execute(staffContext(auth.RoleAnalyst), "cb_12345")
+
+	// Assert: rejecting is not restricted by amount
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Status != entity.StatusRejected {
+		t.Errorf("Expected status %s, got %s", entity.StatusRejected, response.Status)
+	}
+
+	// Act: a decided chargeback cannot be reviewed again
+	_, err = useCase.Execute(staffContext(auth.RoleAnalyst), "cb_12345")
+
+	// Assert
+	if err == nil {
+		t.Error("Expected error rejecting an already rejected chargeback")
+	}
+}